@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/guiyumin/vget/internal/core/downloader"
+	"github.com/guiyumin/vget/internal/core/extractor"
+)
+
+// downloadAlbum downloads every track of an album or playlist into its own directory
+// (named by outputName, i.e. -o, or "{artist} - {title}") as "{NN} {title}.{ext}",
+// with the cover art, skipping tracks recorded in the directory's archive
+func downloadAlbum(m *extractor.AlbumMedia, dl *downloader.Downloader, lang string, outputDir, outputName string) error {
+	// Info only mode
+	if info {
+		fmt.Printf("  Album: %s - %s (%d tracks)\n", m.Uploader, m.Title, len(m.Tracks))
+		for _, tr := range m.Tracks {
+			fmt.Printf("    [%02d] %s - %s (%s, %s)\n", tr.TrackNumber, tr.Uploader, tr.Title, formatEpisodeDuration(tr.Duration), tr.Ext)
+		}
+		return nil
+	}
+
+	dir := outputName
+	if dir == "" {
+		dir = extractor.SanitizeFilename(m.Title)
+		if m.Uploader != "" {
+			dir = extractor.SanitizeFilename(m.Uploader + " - " + m.Title)
+		}
+	}
+	if dir == "" {
+		dir = m.ID
+	}
+	if outputDir != "" && !filepath.IsAbs(dir) {
+		dir = filepath.Join(outputDir, dir)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	archive := downloader.LoadArchive(dir)
+
+	if m.CoverURL != "" {
+		coverPath := filepath.Join(dir, "cover."+extractor.CoverExt(m.CoverURL))
+		if _, err := os.Stat(coverPath); os.IsNotExist(err) {
+			if err := dl.Download(m.CoverURL, coverPath, m.ID); err != nil {
+				fmt.Fprintf(os.Stderr, "  Warning: failed to download cover: %v\n", err)
+			}
+		}
+	}
+
+	var pending []*extractor.AudioMedia
+	for _, tr := range m.Tracks {
+		if !archive[tr.ID] {
+			pending = append(pending, tr)
+		}
+	}
+
+	if len(pending) == 0 {
+		fmt.Printf("  %s: no new tracks\n", m.Title)
+		return nil
+	}
+
+	fmt.Printf("  %s: %d new track(s) -> %s/\n", m.Title, len(pending), dir)
+
+	var failed int
+	for i, tr := range pending {
+		fmt.Printf("\n  [%d/%d] %s\n", i+1, len(pending), tr.Title)
+		if err := downloadAudioFile(tr, filepath.Join(dir, tr.TrackFilename()), dl, lang); err != nil {
+			fmt.Fprintf(os.Stderr, "  Error: %v\n", err)
+			failed++
+			continue
+		}
+		downloader.AppendArchive(dir, tr.ID)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d track(s) failed", failed, len(pending))
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/guiyumin/vget/internal/core/downloader"
+	"github.com/guiyumin/vget/internal/core/extractor"
+	"github.com/guiyumin/vget/internal/core/i18n"
+)
+
+// downloadCollection downloads every post of a profile or collection into its own
+// directory (named by outputName, i.e. -o, or the collection's title), skipping posts
+// recorded in the directory's archive so re-runs only fetch new ones
+func downloadCollection(m *extractor.CollectionMedia, dl *downloader.Downloader, t *i18n.Translations, lang string, outputDir, outputName, quality string) error {
+	// Info only mode
+	if info {
+		fmt.Printf("  %s (%d posts)\n", m.Title, len(m.Items))
+		for i, item := range m.Items {
+			fmt.Printf("    [%d] %s  %s (%s)\n", i+1, item.GetID(), item.GetTitle(), item.Type())
+		}
+		return nil
+	}
+
+	dir := outputName
+	if dir == "" {
+		dir = extractor.SanitizeFilename(m.Title)
+	}
+	if dir == "" {
+		dir = m.ID
+	}
+	if outputDir != "" && !filepath.IsAbs(dir) {
+		dir = filepath.Join(outputDir, dir)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	archive := downloader.LoadArchive(dir)
+
+	var pending []extractor.Media
+	for _, item := range m.Items {
+		if !archive[item.GetID()] {
+			pending = append(pending, item)
+		}
+	}
+
+	if len(pending) == 0 {
+		fmt.Printf("  %s: no new posts\n", m.Title)
+		return nil
+	}
+
+	fmt.Printf("  %s: %d new post(s) -> %s/\n", m.Title, len(pending), dir)
+
+	var failed int
+	for i, item := range pending {
+		fmt.Printf("\n  [%d/%d] %s\n", i+1, len(pending), item.GetTitle())
+		if err := downloadCollectionItem(item, dl, t, lang, dir, quality); err != nil {
+			fmt.Fprintf(os.Stderr, "  Error: %v\n", err)
+			failed++
+			continue
+		}
+		downloader.AppendArchive(dir, item.GetID())
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d post(s) failed", failed, len(pending))
+	}
+	return nil
+}
+
+// downloadCollectionItem downloads one post of a collection. Posts often share
+// titles, so the ID is added to keep their files apart.
+func downloadCollectionItem(item extractor.Media, dl *downloader.Downloader, t *i18n.Translations, lang, dir, quality string) error {
+	name := func(title, id string) string {
+		if title == "" {
+			return id
+		}
+		return fmt.Sprintf("%s [%s]", title, id)
+	}
+
+	switch m := item.(type) {
+	case *extractor.VideoMedia:
+		m.Title = name(m.Title, m.ID)
+		return downloadVideo(m, dl, t, lang, dir, "", quality)
+	case *extractor.ImageMedia:
+		m.Title = name(m.Title, m.ID)
+		return downloadImages(m, dl, dir, "")
+	case *extractor.MultiVideoMedia:
+		m.Title = name(m.Title, m.ID)
+		for _, video := range m.Videos {
+			video.Title = name(video.Title, m.ID)
+		}
+		return downloadMultiVideo(m, dl, t, lang, dir, "", quality)
+	default:
+		return fmt.Errorf("unsupported media type")
+	}
+}
@@ -1,8 +1,10 @@
 package cli
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
@@ -139,6 +141,13 @@ func (m extractModel) View() string {
 			for _, f := range media.Formats {
 				s += fmt.Sprintf("    • %s %dx%d (%s)\n", f.Quality, f.Width, f.Height, f.Ext)
 			}
+			if len(media.Subtitles) > 0 {
+				var langs []string
+				for _, sub := range media.Subtitles {
+					langs = append(langs, sub.Lang)
+				}
+				s += fmt.Sprintf("  Subtitles: %s\n", strings.Join(langs, ", "))
+			}
 			s += "\n"
 			s += fmt.Sprintf("  %s\n\n", extractHintStyle.Render(m.t.Download.QualityHint))
 
@@ -148,13 +157,23 @@ func (m extractModel) View() string {
 		case *extractor.ImageMedia:
 			s += fmt.Sprintf("  Images (%d):\n", len(media.Images))
 			for i, img := range media.Images {
+				live := ""
+				if img.LiveVideoURL != "" {
+					live = " +live"
+				}
 				if img.Width > 0 && img.Height > 0 {
-					s += fmt.Sprintf("    • [%d] %dx%d (%s)\n", i+1, img.Width, img.Height, img.Ext)
+					s += fmt.Sprintf("    • [%d] %dx%d (%s)%s\n", i+1, img.Width, img.Height, img.Ext, live)
 				} else {
-					s += fmt.Sprintf("    • [%d] %s\n", i+1, img.Ext)
+					s += fmt.Sprintf("    • [%d] %s%s\n", i+1, img.Ext, live)
 				}
 			}
 			s += "\n"
+
+		case *extractor.AlbumMedia:
+			s += fmt.Sprintf("  %s - %s (%d tracks)\n\n", media.Uploader, media.Title, len(media.Tracks))
+
+		case *extractor.CollectionMedia:
+			s += fmt.Sprintf("  %s (%d posts)\n\n", media.Title, len(media.Items))
 		}
 
 		return s
@@ -168,12 +187,16 @@ func (m extractModel) View() string {
 }
 
 // runExtractWithSpinner runs extraction with a spinner TUI
-func runExtractWithSpinner(ext extractor.Extractor, url, lang string) (extractor.Media, error) {
+func runExtractWithSpinner(ext extractor.Extractor, url, lang string, opts extractor.Options) (extractor.Media, error) {
 	state := &extractState{}
 
+	// Cancelled when the spinner exits, so quitting with Ctrl+C also stops browsers and requests
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
 	// Start extraction in background
 	go func() {
-		result, err := ext.Extract(url)
+		result, err := extractor.ExtractContext(ctx, ext, url, opts)
 		if err != nil {
 			state.setError(err)
 		} else {
@@ -0,0 +1,112 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/guiyumin/vget/internal/core/config"
+	"github.com/guiyumin/vget/internal/core/extractor"
+	"github.com/spf13/cobra"
+)
+
+var extractorsCmd = &cobra.Command{
+	Use:   "extractors",
+	Short: "List supported sites and what each extractor handles",
+	Long: `List every built-in extractor with its hosts, the kinds of URL it accepts
+(single item, playlist, user, live), the media it returns and what it needs
+(cookies, a login command, a browser, or the Docker image).
+
+Examples:
+  vget extractors
+  vget extractors --markdown > table.md
+  vget extractors match https://x.com/user/status/123`,
+	Run: func(cmd *cobra.Command, args []string) {
+		asJSON, _ := cmd.Flags().GetBool("json")
+		asMarkdown, _ := cmd.Flags().GetBool("markdown")
+
+		infos := extractor.Infos()
+		switch {
+		case asJSON:
+			printJSON(infos)
+		case asMarkdown:
+			fmt.Print(extractor.Markdown(infos))
+		default:
+			fmt.Printf("%-18s %-18s %-20s %-20s %s\n", "NAME", "KINDS", "TYPES", "REQUIRES", "HOSTS")
+			for _, info := range infos {
+				where := info.Matches
+				if len(info.Hosts) > 0 {
+					where = strings.Join(info.Hosts, ", ")
+				}
+				fmt.Printf("%-18s %-18s %-20s %-20s %s\n", info.Name, info.KindList(), info.MediaTypeList(), info.RequirementList(), where)
+			}
+		}
+	},
+}
+
+var extractorsMatchCmd = &cobra.Command{
+	Use:   "match <url>",
+	Short: "Show which extractor would handle a URL, and why",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		asJSON, _ := cmd.Flags().GetBool("json")
+
+		sites, err := config.LoadSites()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+
+		res, err := extractor.Resolve(args[0], sites)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if asJSON {
+			printJSON(res)
+			return
+		}
+
+		fmt.Printf("URL:       %s\n", res.URL)
+		fmt.Printf("Extractor: %s\n", res.Extractor)
+		fmt.Printf("Source:    %s\n", res.Source)
+		if res.Site != nil {
+			fmt.Printf("Rule:      %s %s\n", describeSiteMatch(res.Site), describeSiteOptions(res.Site))
+		}
+		fmt.Printf("Reason:    %s\n", res.Reason)
+
+		for _, info := range extractor.Infos() {
+			if info.Name != res.Extractor {
+				continue
+			}
+			for _, r := range info.Requires {
+				label := "Requires:"
+				if r.Optional {
+					label = "Optional:"
+				}
+				fmt.Printf("%-10s %s: %s\n", label, r.Kind, r.Detail)
+			}
+			break
+		}
+	},
+}
+
+// printJSON writes v to stdout as indented JSON
+func printJSON(v any) {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func init() {
+	extractorsCmd.Flags().Bool("json", false, "output as JSON")
+	extractorsCmd.Flags().Bool("markdown", false, "output the supported-sites table used in sites.md")
+	extractorsMatchCmd.Flags().Bool("json", false, "output as JSON")
+
+	extractorsCmd.AddCommand(extractorsMatchCmd)
+	rootCmd.AddCommand(extractorsCmd)
+}
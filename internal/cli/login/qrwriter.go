@@ -1,6 +1,8 @@
 package login
 
 import (
+	"strings"
+
 	"github.com/mattn/go-runewidth"
 	termbox "github.com/nsf/termbox-go"
 	"github.com/yeqown/go-qrcode/v2"
@@ -12,6 +14,7 @@ type compactQRWriter struct {
 	matrix [][]bool
 	width  int
 	height int
+	text   bool // keep the matrix for String instead of drawing with termbox
 }
 
 func vGetCompactQRWriter() *compactQRWriter {
@@ -30,14 +33,64 @@ func (w *compactQRWriter) Write(mat qrcode.Matrix) error {
 		w.matrix[y][x] = v.IsSet()
 	})
 
+	if w.text {
+		return nil
+	}
 	return w.render()
 }
 
 func (w *compactQRWriter) Close() error {
-	termbox.Close()
+	if !w.text {
+		termbox.Close()
+	}
 	return nil
 }
 
+// RenderQR renders content as a QR code in the same half-block layout, as
+// ANSI-colored text that can be printed (and reprinted) without taking over
+// the terminal, e.g. for codes that refresh while waiting for a scan
+func RenderQR(content string) (string, error) {
+	qr, err := qrcode.NewWith(content, qrcode.WithErrorCorrectionLevel(qrcode.ErrorCorrectionLow))
+	if err != nil {
+		return "", err
+	}
+	w := &compactQRWriter{text: true}
+	if err := qr.Save(w); err != nil {
+		return "", err
+	}
+	return w.String(), nil
+}
+
+// String returns the matrix with a quiet zone, black on white
+func (w *compactQRWriter) String() string {
+	const padding = 2
+	var b strings.Builder
+	for ty := 0; ty < (w.height+2*padding+1)/2; ty++ {
+		b.WriteString("  \x1b[30;47m")
+		for tx := 0; tx < w.width+2*padding; tx++ {
+			qrX := tx - padding
+			qrY := ty*2 - padding
+			b.WriteRune(halfBlock(w.getPixel(qrX, qrY), w.getPixel(qrX, qrY+1)))
+		}
+		b.WriteString("\x1b[0m\n")
+	}
+	return b.String()
+}
+
+// halfBlock draws two vertically stacked modules (true is black) in one character cell
+func halfBlock(top, bot bool) rune {
+	switch {
+	case top && bot:
+		return '█'
+	case top:
+		return '▀'
+	case bot:
+		return '▄'
+	default:
+		return ' '
+	}
+}
+
 func (w *compactQRWriter) getPixel(x, y int) bool {
 	if x < 0 || x >= w.width || y < 0 || y >= w.height {
 		return false // outside bounds = white (quiet zone)
@@ -75,33 +128,7 @@ func (w *compactQRWriter) render() error {
 			top := w.getPixel(qrX, qrY1)
 			bot := w.getPixel(qrX, qrY2)
 
-			var ch rune
-			var fg, bg termbox.Attribute
-
-			// QR: IsSet()=true means black module
-			if top && bot {
-				// Both black: full block
-				ch = '█'
-				fg = termbox.ColorBlack
-				bg = termbox.ColorWhite
-			} else if !top && !bot {
-				// Both white: space
-				ch = ' '
-				fg = termbox.ColorWhite
-				bg = termbox.ColorWhite
-			} else if top && !bot {
-				// Top black, bottom white: upper half block
-				ch = '▀'
-				fg = termbox.ColorBlack
-				bg = termbox.ColorWhite
-			} else {
-				// Top white, bottom black: lower half block
-				ch = '▄'
-				fg = termbox.ColorBlack
-				bg = termbox.ColorWhite
-			}
-
-			termbox.SetCell(tx, ty, ch, fg, bg)
+			termbox.SetCell(tx, ty, halfBlock(top, bot), termbox.ColorBlack, termbox.ColorWhite)
 		}
 	}
 
@@ -0,0 +1,131 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/guiyumin/vget/internal/core/downloader"
+	"github.com/guiyumin/vget/internal/core/extractor"
+)
+
+// downloadPodcast downloads every episode of a podcast (newest first) into its own directory,
+// skipping episodes older than --since and ones already downloaded
+func downloadPodcast(m *extractor.PodcastMedia, dl *downloader.Downloader, lang string, outputDir string) error {
+	sinceTime, err := parseSince(since)
+	if err != nil {
+		return err
+	}
+
+	var episodes []*extractor.AudioMedia
+	for _, ep := range m.Episodes {
+		if !sinceTime.IsZero() && !ep.PublishedAt.IsZero() && ep.PublishedAt.Before(sinceTime) {
+			continue
+		}
+		episodes = append(episodes, ep)
+	}
+
+	// Info only mode
+	if info {
+		fmt.Printf("  Podcast: %s (%d episodes)\n", m.Title, len(episodes))
+		for i, ep := range episodes {
+			fmt.Printf("    [%d] %s  %s (%s)\n", i+1, formatPubDate(ep.PublishedAt), ep.Title, formatEpisodeDuration(ep.Duration))
+		}
+		return nil
+	}
+
+	podcastDir := extractor.SanitizeFilename(m.Title)
+	if podcastDir == "" {
+		podcastDir = m.ID
+	}
+	if outputDir != "" {
+		podcastDir = filepath.Join(outputDir, podcastDir)
+	}
+	if err := os.MkdirAll(podcastDir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	archive := downloader.LoadArchive(podcastDir)
+
+	// Show cover art, once per podcast
+	if m.CoverURL != "" {
+		coverPath := filepath.Join(podcastDir, "cover."+extractor.CoverExt(m.CoverURL))
+		if _, err := os.Stat(coverPath); os.IsNotExist(err) {
+			if err := dl.Download(m.CoverURL, coverPath, m.ID); err != nil {
+				fmt.Fprintf(os.Stderr, "  Warning: failed to download cover: %v\n", err)
+			}
+		}
+	}
+
+	var pending []*extractor.AudioMedia
+	for _, ep := range episodes {
+		if archive[ep.ID] {
+			continue
+		}
+		if _, err := os.Stat(ep.EpisodePath(podcastDir)); err == nil {
+			// Downloaded before the archive existed, record it
+			archive[ep.ID] = true
+			downloader.AppendArchive(podcastDir, ep.ID)
+			continue
+		}
+		pending = append(pending, ep)
+	}
+
+	if len(pending) == 0 {
+		fmt.Printf("  %s: no new episodes\n", m.Title)
+		return nil
+	}
+
+	fmt.Printf("  %s: %d new episode(s) -> %s/\n", m.Title, len(pending), podcastDir)
+
+	var failed int
+	for i, ep := range pending {
+		fmt.Printf("\n  [%d/%d] %s\n", i+1, len(pending), ep.Title)
+		if err := downloadPodcastEpisode(ep, dl, lang, podcastDir); err != nil {
+			fmt.Fprintf(os.Stderr, "  Error: %v\n", err)
+			failed++
+			continue
+		}
+		downloader.AppendArchive(podcastDir, ep.ID)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d episode(s) failed", failed, len(pending))
+	}
+	return nil
+}
+
+// downloadPodcastEpisode downloads the audio, show notes sidecar, episode artwork,
+// and any transcript or chapters the feed publishes
+func downloadPodcastEpisode(ep *extractor.AudioMedia, dl *downloader.Downloader, lang string, podcastDir string) error {
+	warnings, err := extractor.SaveEpisode(ep, podcastDir, func(url, path string) error {
+		if downloader.IsHLSURL(url) {
+			return downloader.RunHLSDownloadTUI(url, path, ep.ID, lang)
+		}
+		return dl.Download(url, path, ep.ID)
+	})
+	for _, w := range warnings {
+		fmt.Fprintf(os.Stderr, "  Warning: %v\n", w)
+	}
+	return err
+}
+
+// parseSince parses the --since flag (YYYY-MM-DD)
+func parseSince(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --since date %q (expected YYYY-MM-DD)", value)
+	}
+	return t, nil
+}
+
+func formatPubDate(t time.Time) string {
+	if t.IsZero() {
+		return "----------"
+	}
+	return t.Format("2006-01-02")
+}
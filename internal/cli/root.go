@@ -23,6 +23,8 @@ var (
 	info      bool
 	inputFile string
 	visible   bool
+	since     string
+	referer   string
 )
 
 var rootCmd = &cobra.Command{
@@ -57,6 +59,8 @@ func init() {
 	rootCmd.Flags().BoolVar(&info, "info", false, "show video info without downloading")
 	rootCmd.Flags().StringVarP(&inputFile, "file", "f", "", "read URLs from file (one per line)")
 	rootCmd.Flags().BoolVar(&visible, "visible", false, "show browser window (for debugging)")
+	rootCmd.Flags().StringVar(&since, "since", "", "only download podcast episodes published on or after this date (YYYY-MM-DD)")
+	rootCmd.Flags().StringVar(&referer, "referer", "", "page the video is embedded on, for embeds restricted to certain domains")
 }
 
 func Execute() error {
@@ -64,9 +68,24 @@ func Execute() error {
 }
 
 func runDownload(url string) error {
+	return runDownloadInto(url, "", quality, true)
+}
+
+// runDownloadInto downloads url into folder inside the output directory, in
+// the preferred video quality (empty for the best). Without interactive,
+// questions are not asked (for unattended runs).
+func runDownloadInto(url, folder, quality string, interactive bool) error {
 	cfg := config.LoadOrDefault()
 	t := i18n.T(cfg.Language)
 
+	outputDir := cfg.OutputDir
+	if folder != "" {
+		outputDir = filepath.Join(cfg.OutputDir, folder)
+		if err := os.MkdirAll(outputDir, 0755); err != nil {
+			return fmt.Errorf("failed to create directory: %w", err)
+		}
+	}
+
 	// Check for config file and warn if missing
 	if !config.Exists() {
 		fmt.Fprintf(os.Stderr, "\033[33m%s. Run 'vget init'.\033[0m\n", t.Errors.ConfigNotFound)
@@ -92,23 +111,26 @@ func runDownload(url string) error {
 				ext = extractor.NewBrowserExtractor(site, visible)
 			}
 		}
-		// Fall back to generic m3u8 detection for unknown sites
+		// Podcast RSS/Atom feeds served from any URL (self-hosted, private-token)
 		if ext == nil {
-			ext = extractor.NewGenericBrowserExtractor(visible)
+			ext = extractor.DetectFeed(context.Background(), url, extractor.Options{Referer: referer})
 		}
-	}
-
-	// Configure Twitter extractor with auth if available
-	if twitterExt, ok := ext.(*extractor.TwitterExtractor); ok {
-		if cfg.Twitter.AuthToken != "" {
-			twitterExt.SetAuth(cfg.Twitter.AuthToken)
+		// Fall back to page metadata, then generic m3u8 detection in a browser
+		if ext == nil {
+			ext = extractor.WithFallback(extractor.NewStaticPageExtractor(), extractor.NewGenericBrowserExtractor(visible))
 		}
 	}
 
+	// Per-call options: stored credentials, --visible and --quality
+	opts := extractor.ConfigOptions(cfg, ext)
+	opts.Visible = visible
+	opts.Quality = quality
+	opts.Referer = referer
+
 	// Check Bilibili login status and prompt for confirmation if not logged in
 	if bilibiliExt, ok := ext.(*extractor.BilibiliExtractor); ok {
 		_ = bilibiliExt // Mark as used
-		if cfg.Bilibili.Cookie == "" {
+		if cfg.Bilibili.Cookie == "" && interactive {
 			if !confirmBilibiliNoLogin() {
 				return nil // User cancelled
 			}
@@ -116,7 +138,7 @@ func runDownload(url string) error {
 	}
 
 	// Extract media info with spinner
-	media, err := runExtractWithSpinner(ext, url, cfg.Language)
+	media, err := runExtractWithSpinner(ext, url, cfg.Language, opts)
 	if err != nil {
 		// YouTube Docker requirement is already displayed in the TUI, don't show again
 		var ytErr *extractor.YouTubeDockerRequiredError
@@ -154,19 +176,25 @@ func runDownload(url string) error {
 	case *extractor.YouTubeDirectDownload:
 		// YouTube: let yt-dlp handle the entire download (Docker only)
 		fmt.Printf("\n  %s Downloading with yt-dlp...\n\n", "⬇")
-		if err := extractor.DownloadWithYtdlp(m.URL, cfg.OutputDir); err != nil {
+		if err := extractor.DownloadWithYtdlp(m.URL, outputDir); err != nil {
 			return fmt.Errorf("yt-dlp download failed: %w", err)
 		}
 		fmt.Printf("\n  %s %s\n\n", "✓", t.Download.Completed)
 		return nil
 	case *extractor.VideoMedia:
-		return downloadVideo(m, dl, t, cfg.Language, cfg.OutputDir)
+		return downloadVideo(m, dl, t, cfg.Language, outputDir, output, quality)
 	case *extractor.AudioMedia:
-		return downloadAudio(m, dl, cfg.OutputDir)
+		return downloadAudio(m, dl, cfg.Language, outputDir)
 	case *extractor.ImageMedia:
-		return downloadImages(m, dl, cfg.OutputDir)
+		return downloadImages(m, dl, outputDir, output)
 	case *extractor.MultiVideoMedia:
-		return downloadMultiVideo(m, dl, t, cfg.Language, cfg.OutputDir)
+		return downloadMultiVideo(m, dl, t, cfg.Language, outputDir, output, quality)
+	case *extractor.PodcastMedia:
+		return downloadPodcast(m, dl, cfg.Language, outputDir)
+	case *extractor.AlbumMedia:
+		return downloadAlbum(m, dl, cfg.Language, outputDir, output)
+	case *extractor.CollectionMedia:
+		return downloadCollection(m, dl, t, cfg.Language, outputDir, output, quality)
 	default:
 		return fmt.Errorf("unsupported media type")
 	}
@@ -277,7 +305,9 @@ func formatSize(b int64) string {
 	return fmt.Sprintf("%.1f %cB", float64(b)/float64(div), "KMGTPE"[exp])
 }
 
-func downloadMultiVideo(m *extractor.MultiVideoMedia, dl *downloader.Downloader, t *i18n.Translations, lang string, outputDir string) error {
+// downloadMultiVideo downloads every video and image of a post. A non-empty
+// outputName (-o) names the files instead of the post's title.
+func downloadMultiVideo(m *extractor.MultiVideoMedia, dl *downloader.Downloader, t *i18n.Translations, lang string, outputDir, outputName, quality string) error {
 	// Info only mode
 	if info {
 		fmt.Printf("  Videos (%d):\n", len(m.Videos))
@@ -291,6 +321,12 @@ func downloadMultiVideo(m *extractor.MultiVideoMedia, dl *downloader.Downloader,
 				fmt.Printf("        [%d.%d] %s %dx%d (%s)%s\n", i+1, j, f.Quality, f.Width, f.Height, f.Ext, audioInfo)
 			}
 		}
+		if len(m.Images) > 0 {
+			fmt.Printf("  Images (%d):\n", len(m.Images))
+			for i, img := range m.Images {
+				fmt.Printf("    [%d] %dx%d (%s)\n", i+1, img.Width, img.Height, img.Ext)
+			}
+		}
 		return nil
 	}
 
@@ -299,14 +335,27 @@ func downloadMultiVideo(m *extractor.MultiVideoMedia, dl *downloader.Downloader,
 	for i, video := range m.Videos {
 		fmt.Printf("\n  [%d/%d] %s\n", i+1, len(m.Videos), video.Title)
 		// Pass index for multi-video to avoid filename collisions
-		if err := downloadVideoWithIndex(video, dl, t, lang, outputDir, i+1, len(m.Videos)); err != nil {
+		if err := downloadVideoWithIndex(video, dl, t, lang, outputDir, outputName, quality, i+1, len(m.Videos)); err != nil {
 			return fmt.Errorf("failed to download video %d: %w", i+1, err)
 		}
 	}
+
+	// Download images mixed in with the videos (e.g., Instagram carousels)
+	if len(m.Images) > 0 {
+		fmt.Println()
+		return downloadImages(&extractor.ImageMedia{
+			ID:       m.ID,
+			Title:    m.Title,
+			Uploader: m.Uploader,
+			Images:   m.Images,
+		}, dl, outputDir, outputName)
+	}
 	return nil
 }
 
-func downloadVideo(m *extractor.VideoMedia, dl *downloader.Downloader, t *i18n.Translations, lang string, outputDir string) error {
+// downloadVideo downloads the format of a video closest to quality. A non-empty
+// outputName (-o) is the output file instead of one named after the title.
+func downloadVideo(m *extractor.VideoMedia, dl *downloader.Downloader, t *i18n.Translations, lang string, outputDir, outputName, quality string) error {
 	// Info only mode
 	if info {
 		for i, f := range m.Formats {
@@ -316,6 +365,9 @@ func downloadVideo(m *extractor.VideoMedia, dl *downloader.Downloader, t *i18n.T
 			}
 			fmt.Printf("  [%d] %s %dx%d (%s)%s\n", i, f.Quality, f.Width, f.Height, f.Ext, audioInfo)
 		}
+		for _, s := range m.Subtitles {
+			fmt.Printf("  Subtitles: %s (%s)\n", s.Lang, s.Name)
+		}
 		return nil
 	}
 
@@ -328,14 +380,10 @@ func downloadVideo(m *extractor.VideoMedia, dl *downloader.Downloader, t *i18n.T
 	fmt.Printf("  %s: %s (%s)\n", t.Download.SelectedFormat, format.Quality, format.Ext)
 
 	// Determine output filename
-	outputFile := output
+	outputFile := outputName
 	if outputFile == "" {
 		title := extractor.SanitizeFilename(m.Title)
-		// For m3u8, output as .ts (MPEG-TS container)
-		ext := format.Ext
-		if ext == "m3u8" {
-			ext = "ts"
-		}
+		ext := hlsOutputExt(format)
 		if title != "" {
 			outputFile = fmt.Sprintf("%s.%s", title, ext)
 		} else {
@@ -365,23 +413,31 @@ func downloadVideo(m *extractor.VideoMedia, dl *downloader.Downloader, t *i18n.T
 		// Put output file inside the directory
 		outputFile = filepath.Join(baseDir, filepath.Base(outputFile))
 		fmt.Printf("  Output directory: %s/\n", baseDir)
-		return downloader.RunHLSDownloadWithHeadersTUI(format.URL, outputFile, m.ID, lang, format.Headers)
 	}
 
-	// Handle video+audio as separate downloads
-	if format.AudioURL != "" {
-		return downloadVideoAndAudio(format, outputFile, m.ID, dl)
+	var err error
+	switch {
+	case format.AudioURL != "":
+		// Handle video+audio as separate downloads
+		err = downloadVideoAndAudio(format, outputFile, m.ID, dl, lang)
+	case format.Ext == "m3u8":
+		err = downloader.RunHLSDownloadWithHeadersTUI(format.URL, outputFile, m.ID, lang, format.Headers)
+	case len(format.Headers) > 0:
+		// Use headers if provided by the extractor
+		err = dl.DownloadWithHeaders(format.URL, outputFile, m.ID, format.Headers)
+	default:
+		err = dl.Download(format.URL, outputFile, m.ID)
 	}
-
-	// Use headers if provided by the extractor
-	if len(format.Headers) > 0 {
-		return dl.DownloadWithHeaders(format.URL, outputFile, m.ID, format.Headers)
+	if err != nil {
+		return err
 	}
-	return dl.Download(format.URL, outputFile, m.ID)
+
+	downloadSubtitles(m, outputFile, dl)
+	return nil
 }
 
 // downloadVideoWithIndex downloads a video with an index suffix in the filename (for multi-video posts)
-func downloadVideoWithIndex(m *extractor.VideoMedia, dl *downloader.Downloader, t *i18n.Translations, lang string, outputDir string, index, total int) error {
+func downloadVideoWithIndex(m *extractor.VideoMedia, dl *downloader.Downloader, t *i18n.Translations, lang string, outputDir, outputName, quality string, index, total int) error {
 	// Info only mode
 	if info {
 		for i, f := range m.Formats {
@@ -403,13 +459,10 @@ func downloadVideoWithIndex(m *extractor.VideoMedia, dl *downloader.Downloader,
 	fmt.Printf("  %s: %s (%s)\n", t.Download.SelectedFormat, format.Quality, format.Ext)
 
 	// Determine output filename
-	outputFile := output
+	outputFile := outputName
 	if outputFile == "" {
 		title := extractor.SanitizeFilename(m.Title)
-		ext := format.Ext
-		if ext == "m3u8" {
-			ext = "ts"
-		}
+		ext := hlsOutputExt(format)
 		baseName := title
 		if baseName == "" {
 			baseName = m.ID
@@ -441,12 +494,15 @@ func downloadVideoWithIndex(m *extractor.VideoMedia, dl *downloader.Downloader,
 		}
 		outputFile = filepath.Join(baseDir, filepath.Base(outputFile))
 		fmt.Printf("  Output directory: %s/\n", baseDir)
+		if format.AudioURL != "" {
+			return downloadVideoAndAudio(format, outputFile, m.ID, dl, lang)
+		}
 		return downloader.RunHLSDownloadWithHeadersTUI(format.URL, outputFile, m.ID, lang, format.Headers)
 	}
 
 	// Handle video+audio as separate downloads
 	if format.AudioURL != "" {
-		return downloadVideoAndAudio(format, outputFile, m.ID, dl)
+		return downloadVideoAndAudio(format, outputFile, m.ID, dl, lang)
 	}
 
 	// Use headers if provided by the extractor
@@ -457,7 +513,7 @@ func downloadVideoWithIndex(m *extractor.VideoMedia, dl *downloader.Downloader,
 }
 
 // downloadVideoAndAudio downloads video and audio as separate files, then merges them if ffmpeg is available
-func downloadVideoAndAudio(format *extractor.VideoFormat, outputFile, videoID string, dl *downloader.Downloader) error {
+func downloadVideoAndAudio(format *extractor.VideoFormat, outputFile, videoID string, dl *downloader.Downloader, lang string) error {
 	// Determine audio extension based on video format
 	audioExt := "m4a"
 	if format.Ext == "webm" {
@@ -470,26 +526,13 @@ func downloadVideoAndAudio(format *extractor.VideoFormat, outputFile, videoID st
 	videoFile := baseName + "_video" + ext
 	audioFile := baseName + "_audio." + audioExt
 
-	// Download video with headers if provided
 	fmt.Println("  Downloading video stream...")
-	var err error
-	if len(format.Headers) > 0 {
-		err = dl.DownloadWithHeaders(format.URL, videoFile, videoID+"-video", format.Headers)
-	} else {
-		err = dl.Download(format.URL, videoFile, videoID+"-video")
-	}
-	if err != nil {
+	if err := downloadStream(format.URL, videoFile, videoID+"-video", format.Headers, dl, lang); err != nil {
 		return fmt.Errorf("failed to download video: %w", err)
 	}
 
-	// Download audio with headers if provided
 	fmt.Println("  Downloading audio stream...")
-	if len(format.Headers) > 0 {
-		err = dl.DownloadWithHeaders(format.AudioURL, audioFile, videoID+"-audio", format.Headers)
-	} else {
-		err = dl.Download(format.AudioURL, audioFile, videoID+"-audio")
-	}
-	if err != nil {
+	if err := downloadStream(format.AudioURL, audioFile, videoID+"-audio", format.Headers, dl, lang); err != nil {
 		return fmt.Errorf("failed to download audio: %w", err)
 	}
 
@@ -520,7 +563,40 @@ func downloadVideoAndAudio(format *extractor.VideoFormat, outputFile, videoID st
 	return nil
 }
 
-func downloadAudio(m *extractor.AudioMedia, dl *downloader.Downloader, outputDir string) error {
+// downloadStream downloads one stream of a split format, through the HLS downloader for playlists
+func downloadStream(url, outputFile, id string, headers map[string]string, dl *downloader.Downloader, lang string) error {
+	if downloader.IsHLSURL(url) {
+		return downloader.RunHLSDownloadWithHeadersTUI(url, outputFile, id, lang, headers)
+	}
+	// Use headers if provided
+	if len(headers) > 0 {
+		return dl.DownloadWithHeaders(url, outputFile, id, headers)
+	}
+	return dl.Download(url, outputFile, id)
+}
+
+// hlsOutputExt returns the file extension for a format: m3u8 streams are saved as
+// .ts (MPEG-TS container), or as .mp4 when a separate audio track is merged in
+func hlsOutputExt(format *extractor.VideoFormat) string {
+	if format.Ext != "m3u8" {
+		return format.Ext
+	}
+	if format.AudioURL != "" {
+		return "mp4"
+	}
+	return "ts"
+}
+
+// downloadSubtitles saves a video's text tracks next to it; failures are only warnings
+func downloadSubtitles(m *extractor.VideoMedia, videoFile string, dl *downloader.Downloader) {
+	for _, s := range m.Subtitles {
+		if err := dl.Download(s.URL, s.Path(videoFile), m.ID); err != nil {
+			fmt.Fprintf(os.Stderr, "  Warning: failed to download %s subtitles: %v\n", s.Lang, err)
+		}
+	}
+}
+
+func downloadAudio(m *extractor.AudioMedia, dl *downloader.Downloader, lang string, outputDir string) error {
 	// Info only mode
 	if info {
 		fmt.Printf("  Audio: %s (%s)\n", m.Title, m.Ext)
@@ -542,15 +618,35 @@ func downloadAudio(m *extractor.AudioMedia, dl *downloader.Downloader, outputDir
 		}
 	}
 
+	return downloadAudioFile(m, outputFile, dl, lang)
+}
+
+// downloadAudioFile downloads a track to outputFile, through the HLS downloader for playlists
+func downloadAudioFile(m *extractor.AudioMedia, outputFile string, dl *downloader.Downloader, lang string) error {
+	if downloader.IsHLSURL(m.URL) {
+		// AAC segments (e.g., Twitter Spaces) are downloaded raw, then remuxed to m4a.
+		// MP3 and Opus segments concatenate into a playable file as is.
+		if m.Ext == "m4a" || m.Ext == "aac" {
+			outputFile = strings.TrimSuffix(outputFile, filepath.Ext(outputFile)) + ".aac"
+		}
+		return downloader.RunHLSDownloadTUI(m.URL, outputFile, m.ID, lang)
+	}
+
 	return dl.Download(m.URL, outputFile, m.ID)
 }
 
-func downloadImages(m *extractor.ImageMedia, dl *downloader.Downloader, outputDir string) error {
+// downloadImages downloads the images of a post, with their Live Photo videos.
+// A non-empty outputName (-o) names the files instead of the post's title.
+func downloadImages(m *extractor.ImageMedia, dl *downloader.Downloader, outputDir, outputName string) error {
 	// Info only mode
 	if info {
 		fmt.Printf("  Images (%d):\n", len(m.Images))
 		for i, img := range m.Images {
-			fmt.Printf("    [%d] %dx%d (%s)\n", i+1, img.Width, img.Height, img.Ext)
+			live := ""
+			if img.LiveVideoURL != "" {
+				live = " [+live video]"
+			}
+			fmt.Printf("    [%d] %dx%d (%s)%s\n", i+1, img.Width, img.Height, img.Ext, live)
 		}
 		return nil
 	}
@@ -559,12 +655,12 @@ func downloadImages(m *extractor.ImageMedia, dl *downloader.Downloader, outputDi
 
 	for i, img := range m.Images {
 		var outputFile string
-		if output != "" {
+		if outputName != "" {
 			// If custom output specified, add suffix for multiple images
 			if len(m.Images) > 1 {
-				outputFile = fmt.Sprintf("%s_%d.%s", output, i+1, img.Ext)
+				outputFile = fmt.Sprintf("%s_%d.%s", outputName, i+1, img.Ext)
 			} else {
-				outputFile = fmt.Sprintf("%s.%s", output, img.Ext)
+				outputFile = fmt.Sprintf("%s.%s", outputName, img.Ext)
 			}
 		} else {
 			// Use sanitized title or ID with index suffix
@@ -583,9 +679,16 @@ func downloadImages(m *extractor.ImageMedia, dl *downloader.Downloader, outputDi
 			}
 		}
 
-		if err := dl.Download(img.URL, outputFile, m.ID); err != nil {
+		if err := dl.DownloadWithHeaders(img.URL, outputFile, m.ID, img.Headers); err != nil {
 			return fmt.Errorf("failed to download image %d: %w", i+1, err)
 		}
+
+		// Live Photos: save the motion part next to the still
+		if img.LiveVideoURL != "" {
+			if err := dl.DownloadWithHeaders(img.LiveVideoURL, img.LiveVideoPath(outputFile), m.ID, img.Headers); err != nil {
+				return fmt.Errorf("failed to download live photo video %d: %w", i+1, err)
+			}
+		}
 	}
 	return nil
 }
@@ -685,6 +788,7 @@ func runTelegramDownload(urlStr, outputPath string) error {
 			Title:    result.Title,
 			Filename: result.Filename,
 			Size:     result.Size,
+			Files:    result.Files,
 		}, nil
 	}
 
@@ -719,6 +823,7 @@ func runTelegramBatchDownload(urls []string) (succeeded, failed int, failedURLs
 				Title:    result.Title,
 				Filename: result.Filename,
 				Size:     result.Size,
+				Files:    result.Files,
 			}, nil
 		}
 
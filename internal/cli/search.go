@@ -1,9 +1,9 @@
 package cli
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 	"net/url"
 	"os"
@@ -18,6 +18,7 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/guiyumin/vget/internal/core/config"
 	"github.com/guiyumin/vget/internal/core/downloader"
+	"github.com/guiyumin/vget/internal/core/extractor"
 	"github.com/guiyumin/vget/internal/core/i18n"
 	"github.com/spf13/cobra"
 )
@@ -221,16 +222,7 @@ func searchXiaoyuzhou(query string) error {
 }
 
 func formatEpisodeDuration(seconds int) string {
-	if seconds <= 0 {
-		return "?"
-	}
-	h := seconds / 3600
-	m := (seconds % 3600) / 60
-	s := seconds % 60
-	if h > 0 {
-		return fmt.Sprintf("%d:%02d:%02d", h, m, s)
-	}
-	return fmt.Sprintf("%d:%02d", m, s)
+	return extractor.FormatDuration(seconds)
 }
 
 // iTunes API response structures
@@ -422,6 +414,7 @@ func fetchAndShowEpisodes(podcast SearchItem, source, lang string) error {
 	// Show spinner while fetching episodes
 	done := make(chan bool)
 	var episodes []SearchItem
+	var warnings []error
 	var fetchErr error
 
 	go func() {
@@ -429,7 +422,7 @@ func fetchAndShowEpisodes(podcast SearchItem, source, lang string) error {
 		case "itunes":
 			episodes, fetchErr = fetchITunesEpisodes(podcast.PodcastID)
 		case "xiaoyuzhou":
-			episodes, fetchErr = fetchXiaoyuzhouEpisodes(podcast.PodcastID)
+			episodes, warnings, fetchErr = fetchXiaoyuzhouEpisodes(podcast.PodcastID)
 		default:
 			fetchErr = fmt.Errorf("unknown source: %s", source)
 		}
@@ -451,10 +444,15 @@ func fetchAndShowEpisodes(podcast SearchItem, source, lang string) error {
 	}
 
 	// Build sections for episode selection TUI
+	var notices []string
+	for _, w := range warnings {
+		notices = append(notices, "Warning: "+w.Error())
+	}
 	sections := []SearchSection{
 		{
-			Title: fmt.Sprintf("%s - %s", t.Search.Episodes, podcast.Title),
-			Items: episodes,
+			Title:  fmt.Sprintf("%s - %s", t.Search.Episodes, podcast.Title),
+			Items:  episodes,
+			Notice: strings.Join(notices, "\n"),
 		},
 	}
 
@@ -526,85 +524,29 @@ func fetchITunesEpisodes(podcastID string) ([]SearchItem, error) {
 	return episodes, nil
 }
 
-// fetchXiaoyuzhouEpisodes fetches episodes for a Xiaoyuzhou podcast
-func fetchXiaoyuzhouEpisodes(podcastID string) ([]SearchItem, error) {
-	// Fetch podcast page which contains __NEXT_DATA__ with episodes
-	pageURL := fmt.Sprintf("https://www.xiaoyuzhoufm.com/podcast/%s", podcastID)
-
-	resp, err := http.Get(pageURL)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
+// fetchXiaoyuzhouEpisodes fetches the recent episodes of a Xiaoyuzhou podcast:
+// the podcast page and the first page of the episode list, which is enough to
+// pick from without waiting on the whole back catalog
+func fetchXiaoyuzhouEpisodes(podcastID string) ([]SearchItem, []error, error) {
+	podcast, warnings, err := extractor.FetchXiaoyuzhouPodcast(context.Background(), podcastID, 1)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	// Extract __NEXT_DATA__ JSON from the page
-	html := string(body)
-	startMarker := `<script id="__NEXT_DATA__" type="application/json">`
-	endMarker := `</script>`
-
-	startIdx := strings.Index(html, startMarker)
-	if startIdx == -1 {
-		return nil, fmt.Errorf("could not find episode data on page")
-	}
-	startIdx += len(startMarker)
-
-	endIdx := strings.Index(html[startIdx:], endMarker)
-	if endIdx == -1 {
-		return nil, fmt.Errorf("could not parse episode data")
-	}
-
-	jsonData := html[startIdx : startIdx+endIdx]
-
-	// Parse the JSON to extract episodes
-	var nextData struct {
-		Props struct {
-			PageProps struct {
-				Podcast struct {
-					Title    string `json:"title"`
-					Episodes []struct {
-						Eid       string `json:"eid"`
-						Title     string `json:"title"`
-						Duration  int    `json:"duration"`
-						Enclosure struct {
-							URL string `json:"url"`
-						} `json:"enclosure"`
-					} `json:"episodes"`
-				} `json:"podcast"`
-			} `json:"pageProps"`
-		} `json:"props"`
-	}
-
-	if err := json.Unmarshal([]byte(jsonData), &nextData); err != nil {
-		return nil, fmt.Errorf("failed to parse episode data: %v", err)
-	}
-
-	podcast := nextData.Props.PageProps.Podcast
-	if len(podcast.Episodes) == 0 {
-		return nil, fmt.Errorf("no episodes found")
-	}
-
-	podcastTitle := podcast.Title
-	episodes := podcast.Episodes
-
 	var items []SearchItem
-	for _, e := range episodes {
+	for _, e := range podcast.Episodes {
 		duration := formatEpisodeDuration(e.Duration)
 		items = append(items, SearchItem{
-			Title:       fmt.Sprintf("%s - %s", podcastTitle, e.Title),
+			Title:       fmt.Sprintf("%s - %s", podcast.Title, e.Title),
 			Subtitle:    duration,
-			URL:         fmt.Sprintf("https://www.xiaoyuzhoufm.com/episode/%s", e.Eid),
-			DownloadURL: e.Enclosure.URL,
+			URL:         fmt.Sprintf("https://www.xiaoyuzhoufm.com/episode/%s", e.ID),
+			DownloadURL: e.URL,
 			Selectable:  true,
 			Type:        ItemTypeEpisode,
 		})
 	}
 
-	return items, nil
+	return items, warnings, nil
 }
 
 // downloadSelectedEpisodes downloads the selected episodes sequentially
@@ -48,8 +48,9 @@ type SearchItem struct {
 
 // SearchSection represents a section (Podcasts or Episodes)
 type SearchSection struct {
-	Title string
-	Items []SearchItem
+	Title  string
+	Items  []SearchItem
+	Notice string // Shown above the items, e.g. warnings from fetching them
 }
 
 const maxSelections = 5
@@ -159,6 +160,9 @@ func (m searchModel) visibleLines() int {
 	}
 	// Reserve: title (2 lines) + tabs (2 lines) + footer (3 lines) + padding
 	available := m.height - 10
+	if section := m.currentSection(); section != nil && section.Notice != "" {
+		available -= strings.Count(section.Notice, "\n") + 2
+	}
 	if available > maxVisibleLines {
 		return maxVisibleLines
 	}
@@ -310,6 +314,12 @@ func (m searchModel) View() string {
 
 	// Get current section
 	section := m.currentSection()
+	if section != nil && section.Notice != "" {
+		for _, line := range strings.Split(section.Notice, "\n") {
+			b.WriteString("  " + searchDimStyle.Render(line) + "\n")
+		}
+		b.WriteString("\n")
+	}
 	if section == nil || len(section.Items) == 0 {
 		b.WriteString("  No items in this section.\n")
 	} else {
@@ -0,0 +1,254 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/guiyumin/vget/internal/core/config"
+	"github.com/guiyumin/vget/internal/core/extractor"
+	"github.com/spf13/cobra"
+)
+
+var sitesCmd = &cobra.Command{
+	Use:   "sites",
+	Short: "Manage and test browser-extraction rules (sites.yml)",
+	Long: `Manage the rules in sites.yml that tell vget how to extract media from
+sites without a built-in extractor.
+
+Rules are read from ./sites.yml and ~/.config/vget/sites.yml. Commands that
+modify rules edit ./sites.yml if it exists, otherwise the one in the config directory.`,
+}
+
+var sitesListCmd = &cobra.Command{
+	Use:     "list",
+	Short:   "List configured site rules",
+	Aliases: []string{"ls"},
+	Run: func(cmd *cobra.Command, args []string) {
+		found := false
+		for _, p := range config.SitesPaths() {
+			cfg, err := config.LoadSitesFile(p)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				continue
+			}
+			if cfg == nil || len(cfg.Sites) == 0 {
+				continue
+			}
+			found = true
+
+			fmt.Printf("%s:\n", p)
+			for _, site := range cfg.Sites {
+				fmt.Printf("  %-40s %s\n", describeSiteMatch(&site), describeSiteOptions(&site))
+			}
+		}
+
+		if !found {
+			fmt.Println("No site rules configured.")
+			fmt.Println("Add one with: vget sites add <match>")
+		}
+	},
+}
+
+var sitesAddCmd = &cobra.Command{
+	Use:   "add <match>",
+	Short: "Add a site rule",
+	Long: `Add a site rule. <match> is a URL substring unless --regex or --glob is given.
+
+Headers, cookies and page actions can be added by editing sites.yml directly.
+
+Examples:
+  vget sites add kanav.ad
+  vget sites add --glob "*.example.com/watch/*" --type mp4 --strategy video
+  vget sites add --regex '^https://example\.com/v/\d+' --referer https://example.com/`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		mediaType, _ := cmd.Flags().GetString("type")
+		isRegex, _ := cmd.Flags().GetBool("regex")
+		isGlob, _ := cmd.Flags().GetBool("glob")
+		strategy, _ := cmd.Flags().GetString("strategy")
+		referer, _ := cmd.Flags().GetString("referer")
+
+		if isRegex && isGlob {
+			fmt.Fprintln(os.Stderr, "--regex and --glob cannot be used together")
+			os.Exit(1)
+		}
+
+		site := config.Site{
+			Type:     mediaType,
+			Strategy: strategy,
+			Referer:  referer,
+		}
+		switch {
+		case isRegex:
+			site.Pattern = args[0]
+		case isGlob:
+			site.Glob = args[0]
+		default:
+			site.Match = args[0]
+		}
+		if err := site.Validate(); err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid rule: %v\n", err)
+			os.Exit(1)
+		}
+
+		cfg := loadEditableSites()
+		for _, existing := range cfg.Sites {
+			if existing.Key() == site.Key() {
+				fmt.Fprintf(os.Stderr, "Rule '%s' already exists.\n", site.Key())
+				fmt.Fprintf(os.Stderr, "Remove it first: vget sites remove '%s'\n", site.Key())
+				os.Exit(1)
+			}
+		}
+		cfg.Sites = append(cfg.Sites, site)
+
+		if err := config.SaveSites(cfg); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to save: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("Rule '%s' added to %s\n", site.Key(), config.SitesPath())
+		fmt.Println("Try it with: vget sites test <url>")
+	},
+}
+
+var sitesRemoveCmd = &cobra.Command{
+	Use:     "remove <match>",
+	Short:   "Remove a site rule",
+	Aliases: []string{"rm", "delete"},
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadEditableSites()
+		if !cfg.RemoveSite(args[0]) {
+			fmt.Fprintf(os.Stderr, "Rule '%s' not found in %s\n", args[0], config.SitesPath())
+			os.Exit(1)
+		}
+
+		if err := config.SaveSites(cfg); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to save: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("Rule '%s' removed.\n", args[0])
+	},
+}
+
+var sitesTestCmd = &cobra.Command{
+	Use:   "test <url>",
+	Short: "Show what each capture strategy finds on a page",
+	Long: `Open a page in the browser and run every capture strategy, reporting the
+candidate URLs each one found and how long it took. The matching rule from
+sites.yml is applied (actions, cookies, strategy order); without one the page
+is probed like an unknown site.
+
+Examples:
+  vget sites test https://example.com/watch/123
+  vget sites test --type mp4 --visible https://example.com/watch/123`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		mediaType, _ := cmd.Flags().GetString("type")
+		showBrowser, _ := cmd.Flags().GetBool("visible")
+
+		url, err := extractor.NormalizeURL(args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		sitesConfig, err := config.LoadSites()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		site := sitesConfig.MatchSite(url)
+		if site != nil {
+			fmt.Printf("Rule:  %s %s\n", describeSiteMatch(site), describeSiteOptions(site))
+		} else {
+			fmt.Println("Rule:  none (generic)")
+			site = &config.Site{}
+		}
+		if mediaType != "" {
+			site.Type = mediaType
+		}
+		fmt.Printf("Type:  %s\n\n", site.MediaType())
+
+		start := time.Now()
+		report, err := extractor.NewBrowserExtractor(site, showBrowser).Probe(url)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		for _, r := range report.Results {
+			fmt.Printf("%-12s %-22s %6s  %d found\n", r.Strategy, r.Method, r.Elapsed.Round(10*time.Millisecond), len(r.Candidates))
+			for _, c := range r.Candidates {
+				fmt.Printf("    %s\n", c)
+			}
+		}
+
+		fmt.Printf("\nTitle:    %s\n", report.Title)
+		if report.Selected == "" {
+			fmt.Printf("Selected: none (no %s URL found, try --type or page actions)\n", site.MediaType())
+		} else {
+			fmt.Printf("Selected: %s\n", report.Selected)
+		}
+		fmt.Printf("Total:    %s\n", time.Since(start).Round(10*time.Millisecond))
+	},
+}
+
+// loadEditableSites loads the sites file that add/remove modify
+func loadEditableSites() *config.SitesConfig {
+	cfg, err := config.LoadSitesFile(config.SitesPath())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if cfg == nil {
+		cfg = &config.SitesConfig{}
+	}
+	return cfg
+}
+
+func describeSiteMatch(site *config.Site) string {
+	switch {
+	case site.Pattern != "":
+		return "regex:" + site.Pattern
+	case site.Glob != "":
+		return "glob:" + site.Glob
+	default:
+		return site.Match
+	}
+}
+
+func describeSiteOptions(site *config.Site) string {
+	opts := []string{"type=" + site.MediaType()}
+	if site.Strategy != "" {
+		opts = append(opts, "strategy="+site.Strategy)
+	}
+	if len(site.Actions) > 0 {
+		opts = append(opts, fmt.Sprintf("actions=%d", len(site.Actions)))
+	}
+	if len(site.Headers) > 0 || site.Referer != "" || len(site.Cookies) > 0 {
+		opts = append(opts, "headers")
+	}
+	return "(" + strings.Join(opts, ", ") + ")"
+}
+
+func init() {
+	sitesAddCmd.Flags().String("type", "m3u8", "media type to capture (e.g., m3u8, mp4)")
+	sitesAddCmd.Flags().Bool("regex", false, "treat <match> as a regular expression")
+	sitesAddCmd.Flags().Bool("glob", false, "treat <match> as a glob against host/path")
+	sitesAddCmd.Flags().String("strategy", "", "capture strategy to try first (network, performance, video, source)")
+	sitesAddCmd.Flags().String("referer", "", "Referer to send with the media request")
+
+	sitesTestCmd.Flags().String("type", "", "media type to capture (overrides the rule)")
+	sitesTestCmd.Flags().Bool("visible", false, "show browser window")
+
+	sitesCmd.AddCommand(sitesListCmd)
+	sitesCmd.AddCommand(sitesAddCmd)
+	sitesCmd.AddCommand(sitesRemoveCmd)
+	sitesCmd.AddCommand(sitesTestCmd)
+	rootCmd.AddCommand(sitesCmd)
+}
@@ -0,0 +1,313 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/guiyumin/vget/internal/core/config"
+	"github.com/guiyumin/vget/internal/core/downloader"
+	tgpkg "github.com/guiyumin/vget/internal/core/extractor/telegram"
+	"github.com/guiyumin/vget/internal/core/subscription"
+	"github.com/spf13/cobra"
+)
+
+var subscribeCmd = &cobra.Command{
+	Use:   "subscribe",
+	Short: "Watch channels, podcasts and users for new posts",
+	Long: `Subscribe to a Bilibili uploader, Twitter/X user, Xiaoyuzhou podcast, podcast RSS
+feed or Telegram channel, and download what it posts from then on.
+
+'vget subscribe run' checks the subscriptions that are due and downloads their new
+items; run it from cron, or let vget-server check them in the background. Items
+already downloaded are remembered, so nothing is fetched twice.
+
+Examples:
+  vget subscribe add https://space.bilibili.com/946974 --every 2h --min-duration 5m
+  vget subscribe add https://x.com/NASA --type video --quality 720p
+  vget subscribe add https://www.xiaoyuzhoufm.com/podcast/5e280fab418a84a0461fa6c1
+  vget subscribe add @coursechannel --title "(?i)lecture" --folder courses
+  vget subscribe list
+  vget subscribe run`,
+}
+
+var subscribeAddCmd = &cobra.Command{
+	Use:   "add <url>",
+	Short: "Subscribe to a channel, podcast, user or feed",
+	Long: `Subscribe to a source. What it has already posted is marked as seen, so only
+new posts are downloaded; use --backfill to download the existing ones too.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSubscribeAdd,
+}
+
+var subscribeListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List subscriptions",
+	Args:  cobra.NoArgs,
+	RunE:  runSubscribeList,
+}
+
+var subscribeRemoveCmd = &cobra.Command{
+	Use:   "remove <id|url>",
+	Short: "Remove a subscription",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runSubscribeRemove,
+}
+
+var subscribeRunCmd = &cobra.Command{
+	Use:   "run [id|url...]",
+	Short: "Check subscriptions and download new items",
+	Long: `Check the subscriptions that are due (or the given ones, or all with --all) and
+download their new items into the output directory, each subscription in its own folder.`,
+	RunE: runSubscribeRun,
+}
+
+func runSubscribeAdd(cmd *cobra.Command, args []string) error {
+	every, _ := cmd.Flags().GetDuration("every")
+	titleFilter, _ := cmd.Flags().GetString("title")
+	minDuration, _ := cmd.Flags().GetDuration("min-duration")
+	mediaType, _ := cmd.Flags().GetString("type")
+	quality, _ := cmd.Flags().GetString("quality")
+	folder, _ := cmd.Flags().GetString("folder")
+	backfill, _ := cmd.Flags().GetBool("backfill")
+
+	store, err := subscription.OpenDefault()
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	sub := &subscription.Subscription{
+		URL:             args[0],
+		IntervalMinutes: int(every / time.Minute),
+		Filter: subscription.Filter{
+			Title:       titleFilter,
+			MinDuration: int(minDuration / time.Second),
+			MediaType:   strings.ToLower(mediaType),
+		},
+		Quality:  quality,
+		Folder:   folder,
+		Backfill: backfill,
+	}
+	if err := store.Add(sub); err != nil {
+		return err
+	}
+	fmt.Printf("  Subscribed to %s (%s, every %s)\n", sub.URL, sub.Source, sub.Interval())
+
+	if backfill {
+		fmt.Println("  What it has posted so far is downloaded on the next 'vget subscribe run'")
+		return nil
+	}
+
+	// The first check marks what is already posted as seen
+	tg := tgpkg.NewSharedClient()
+	defer tg.Close()
+	sources := &subscription.Sources{Config: config.LoadOrDefault(), Telegram: tg}
+	if _, err := subscription.Check(context.Background(), store, sources, sub); err != nil {
+		fmt.Fprintf(os.Stderr, "  Warning: first check failed: %v\n", err)
+		fmt.Fprintln(os.Stderr, "  It is tried again on the next run; see 'vget subscribe list'")
+		return nil
+	}
+	seen, _ := store.Seen(sub.ID)
+	fmt.Printf("  %s: %d existing item(s) marked as seen\n", sub.Name(), len(seen))
+	return nil
+}
+
+func runSubscribeList(cmd *cobra.Command, args []string) error {
+	store, err := subscription.OpenDefault()
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	subs, err := store.List()
+	if err != nil {
+		return err
+	}
+	if len(subs) == 0 {
+		fmt.Println("  No subscriptions. Add one with 'vget subscribe add <url>'")
+		return nil
+	}
+
+	for _, sub := range subs {
+		fmt.Printf("  %s  %s\n", sub.ID, sub.Name())
+		fmt.Printf("      %s (%s), every %s -> %s/\n", sub.URL, sub.Source, sub.Interval(), sub.Dir())
+		if filter := sub.Filter.String(); filter != "" {
+			fmt.Printf("      filter: %s\n", filter)
+		}
+		if sub.Quality != "" {
+			fmt.Printf("      quality: %s\n", sub.Quality)
+		}
+		switch {
+		case sub.LastChecked > 0:
+			fmt.Printf("      last checked: %s\n", time.Unix(sub.LastChecked, 0).Format("2006-01-02 15:04"))
+		case sub.LastAttempt == 0:
+			fmt.Println("      not checked yet")
+		}
+		if sub.LastError != "" {
+			fmt.Printf("      \033[31mlast error: %s\033[0m\n", sub.LastError)
+		}
+	}
+	return nil
+}
+
+func runSubscribeRemove(cmd *cobra.Command, args []string) error {
+	store, err := subscription.OpenDefault()
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	sub, err := store.Get(args[0])
+	if err != nil {
+		return err
+	}
+	if err := store.Remove(sub.ID); err != nil {
+		return err
+	}
+	fmt.Printf("  Unsubscribed from %s\n", sub.Name())
+	return nil
+}
+
+func runSubscribeRun(cmd *cobra.Command, args []string) error {
+	all, _ := cmd.Flags().GetBool("all")
+
+	store, err := subscription.OpenDefault()
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	var subs []*subscription.Subscription
+	if len(args) > 0 {
+		for _, ref := range args {
+			sub, err := store.Get(ref)
+			if err != nil {
+				return fmt.Errorf("%s: %w", ref, err)
+			}
+			subs = append(subs, sub)
+		}
+	} else {
+		list, err := store.List()
+		if err != nil {
+			return err
+		}
+		for _, sub := range list {
+			if all || sub.Due(time.Now()) {
+				subs = append(subs, sub)
+			}
+		}
+	}
+	if len(subs) == 0 {
+		fmt.Println("  No subscriptions are due")
+		return nil
+	}
+
+	tg := tgpkg.NewSharedClient()
+	defer tg.Close()
+	sources := &subscription.Sources{Config: config.LoadOrDefault(), Telegram: tg}
+
+	var failed int
+	for _, sub := range subs {
+		if err := runSubscription(store, sources, tg, sub); err != nil {
+			fmt.Fprintf(os.Stderr, "  %s: %v\n", sub.Name(), err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d subscription(s) failed", failed, len(subs))
+	}
+	return nil
+}
+
+// runSubscription checks one subscription and downloads its new items,
+// marking each seen once it is downloaded
+func runSubscription(store *subscription.Store, sources *subscription.Sources, tg *tgpkg.SharedClient, sub *subscription.Subscription) error {
+	items, err := subscription.Check(context.Background(), store, sources, sub)
+	if err != nil {
+		return fmt.Errorf("check failed: %w", err)
+	}
+	if len(items) == 0 {
+		fmt.Printf("  %s: no new items\n", sub.Name())
+		return nil
+	}
+	fmt.Printf("  %s: %d new item(s) -> %s/\n", sub.Name(), len(items), sub.Dir())
+
+	var failed int
+	for i, item := range items {
+		title := item.Title
+		if title == "" {
+			title = item.URL
+		}
+		fmt.Printf("\n  [%d/%d] %s\n", i+1, len(items), title)
+		if err := downloadSubscriptionItem(tg, sub, item); err != nil {
+			fmt.Fprintf(os.Stderr, "  Error: %v\n", err)
+			failed++
+			continue
+		}
+		if err := store.MarkSeen(sub.ID, item.ID); err != nil {
+			return err
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d item(s) failed; they are retried on the next run", failed, len(items))
+	}
+	return nil
+}
+
+// downloadSubscriptionItem downloads an item into the subscription's folder
+func downloadSubscriptionItem(tg *tgpkg.SharedClient, sub *subscription.Subscription, item subscription.Item) error {
+	cfg := config.LoadOrDefault()
+	dir := filepath.Join(cfg.OutputDir, sub.Dir())
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	switch {
+	case item.Audio != nil:
+		// Podcast episodes are saved like 'vget <podcast>' saves them
+		return downloadPodcastEpisode(item.Audio, downloader.New(cfg.Language), cfg.Language, dir)
+
+	case sub.Source == subscription.SourceTelegram:
+		result, err := tg.Download(context.Background(), tgpkg.DownloadOptions{URL: item.URL, OutputDir: dir})
+		if err != nil {
+			if wait, ok := tgpkg.FloodWait(err); ok {
+				return fmt.Errorf("telegram asked to wait %s: %w", wait, err)
+			}
+			return err
+		}
+		files := result.Files
+		if len(files) == 0 {
+			files = []string{result.Filename}
+		}
+		for _, f := range files {
+			fmt.Printf("  ✓ %s\n", f)
+		}
+		return nil
+
+	default:
+		// The subscription's quality applies to its downloads
+		return runDownloadInto(item.URL, sub.Dir(), sub.Quality, false)
+	}
+}
+
+func init() {
+	subscribeAddCmd.Flags().Duration("every", subscription.DefaultInterval, "how often to check for new posts (at least 5m)")
+	subscribeAddCmd.Flags().String("title", "", "only download items whose title matches this regular expression")
+	subscribeAddCmd.Flags().Duration("min-duration", 0, "skip videos and episodes shorter than this (e.g., 5m)")
+	subscribeAddCmd.Flags().String("type", "", "only download this media type: "+strings.Join(subscription.MediaTypes, ", "))
+	subscribeAddCmd.Flags().StringP("quality", "q", "", "preferred video quality (e.g., 1080p, 720p)")
+	subscribeAddCmd.Flags().String("folder", "", "folder inside the output directory (default: the source's name)")
+	subscribeAddCmd.Flags().Bool("backfill", false, "also download what is already posted")
+	subscribeRunCmd.Flags().Bool("all", false, "check every subscription, not only the due ones")
+
+	subscribeCmd.AddCommand(subscribeAddCmd)
+	subscribeCmd.AddCommand(subscribeListCmd)
+	subscribeCmd.AddCommand(subscribeRemoveCmd)
+	subscribeCmd.AddCommand(subscribeRunCmd)
+	rootCmd.AddCommand(subscribeCmd)
+}
@@ -19,8 +19,8 @@ import (
 
 var telegramCmd = &cobra.Command{
 	Use:   "telegram",
-	Short: "Manage Telegram authentication",
-	Long:  "Login, logout, and check status of Telegram session for downloading media",
+	Short: "Manage Telegram authentication and channel downloads",
+	Long:  "Login, logout, and check status of Telegram session for downloading media, or dump a whole channel",
 }
 
 var telegramLoginCmd = &cobra.Command{
@@ -29,9 +29,13 @@ var telegramLoginCmd = &cobra.Command{
 	Long: `Login to Telegram to enable media downloads.
 
 Available methods:
+  --phone             Log in with your phone number, login code and 2FA password
+  --qr                Log in by scanning a QR code with the Telegram app
   --import-desktop    Import session from Telegram Desktop app
 
-Example:
+Examples:
+  vget telegram login --phone
+  vget telegram login --qr
   vget telegram login --import-desktop`,
 	RunE: runTelegramLogin,
 }
@@ -52,11 +56,26 @@ var telegramStatusCmd = &cobra.Command{
 
 func runTelegramLogin(cmd *cobra.Command, args []string) error {
 	importDesktop, _ := cmd.Flags().GetBool("import-desktop")
+	usePhone, _ := cmd.Flags().GetBool("phone")
+	useQR, _ := cmd.Flags().GetBool("qr")
+
+	switch {
+	case usePhone:
+		return runTelegramPhoneLogin()
+	case useQR:
+		return runTelegramQRLogin()
+	}
 
 	if !importDesktop {
 		// No method specified, show help
 		fmt.Println("Please specify a login method:")
 		fmt.Println()
+		fmt.Println("  vget telegram login --phone")
+		fmt.Println("      Log in with your phone number, login code and 2FA password")
+		fmt.Println()
+		fmt.Println("  vget telegram login --qr")
+		fmt.Println("      Scan a QR code with the Telegram app on your phone")
+		fmt.Println()
 		fmt.Println("  vget telegram login --import-desktop")
 		fmt.Println("      Import session from Telegram Desktop app")
 		fmt.Println("      Requires: Telegram Desktop installed and logged in")
@@ -226,7 +245,7 @@ func runTelegramLogout(cmd *cobra.Command, args []string) error {
 func runTelegramStatus(cmd *cobra.Command, args []string) error {
 	if !tgpkg.SessionExists() {
 		fmt.Println("Not logged in to Telegram.")
-		fmt.Println("Run 'vget telegram login' to import your Telegram Desktop session.")
+		fmt.Println("Run 'vget telegram login' to log in.")
 		return nil
 	}
 
@@ -265,7 +284,7 @@ func runTelegramStatus(cmd *cobra.Command, args []string) error {
 
 	if err != nil {
 		fmt.Println("Session invalid or expired.")
-		fmt.Println("Run 'vget telegram login' to re-import your session.")
+		fmt.Println("Run 'vget telegram login' to log in again.")
 		return nil
 	}
 
@@ -365,6 +384,8 @@ func getAccountInfo(acc tdesktop.Account) (name, username string) {
 
 func init() {
 	telegramLoginCmd.Flags().Bool("import-desktop", false, "Import session from Telegram Desktop")
+	telegramLoginCmd.Flags().Bool("phone", false, "Log in with phone number, code and 2FA password")
+	telegramLoginCmd.Flags().Bool("qr", false, "Log in by scanning a QR code")
 	telegramCmd.AddCommand(telegramLoginCmd)
 	telegramCmd.AddCommand(telegramLogoutCmd)
 	telegramCmd.AddCommand(telegramStatusCmd)
@@ -0,0 +1,109 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tgpkg "github.com/guiyumin/vget/internal/core/extractor/telegram"
+	"github.com/spf13/cobra"
+)
+
+var telegramDumpCmd = &cobra.Command{
+	Use:   "dump <channel>",
+	Short: "Download all media of a Telegram channel",
+	Long: `Download every photo, video, audio and document posted in a channel or group,
+oldest first, into a directory named after it (or -o). The channel can be given as
+@username, a t.me link, or a t.me/c/<id> link for private channels you have joined.
+
+History is read through a takeout session, which Telegram rate-limits less. Downloaded
+messages are recorded in the directory, so an interrupted dump resumes where it stopped
+and re-running it only fetches new posts.
+
+Examples:
+  vget telegram dump @coursechannel
+  vget telegram dump https://t.me/c/1234567890 --from 120 --to 480
+  vget telegram dump coursechannel --since 2024-09-01 --type video,document`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTelegramDump,
+}
+
+func runTelegramDump(cmd *cobra.Command, args []string) error {
+	outputDir, _ := cmd.Flags().GetString("output")
+	fromID, _ := cmd.Flags().GetInt("from")
+	toID, _ := cmd.Flags().GetInt("to")
+	sinceFlag, _ := cmd.Flags().GetString("since")
+	types, _ := cmd.Flags().GetStringSlice("type")
+
+	sinceTime, err := parseSince(sinceFlag)
+	if err != nil {
+		return err
+	}
+	for i, t := range types {
+		types[i] = strings.ToLower(strings.TrimSpace(t))
+	}
+
+	fmt.Println("  Connecting to Telegram...")
+
+	var current string
+	result, err := tgpkg.Dump(tgpkg.DumpOptions{
+		Channel:   args[0],
+		OutputDir: outputDir,
+		FromID:    fromID,
+		ToID:      toID,
+		Since:     sinceTime,
+		Types:     types,
+		Takeout:   true,
+		OnStart: func(title, dir string, pending int) {
+			if pending == 0 {
+				fmt.Printf("  %s: no new media\n", title)
+				return
+			}
+			fmt.Printf("  %s: %d new file(s) -> %s/\n\n", title, pending, dir)
+		},
+		OnFile: func(file tgpkg.DumpFile) {
+			name := filepath.Base(file.Filename)
+			switch {
+			case file.Err != nil:
+				fmt.Printf("\r\033[K")
+				fmt.Fprintf(os.Stderr, "  [%d] Error: %v\n", file.MessageID, file.Err)
+			case file.Skipped:
+				fmt.Printf("\r\033[K  [%d] %s (already present)\n", file.MessageID, name)
+			default:
+				fmt.Printf("\r\033[K  [%d] %s (%s)\n", file.MessageID, name, formatSize(file.Size))
+			}
+			current = ""
+		},
+		ProgressFn: func(downloaded, total int64) {
+			line := formatSize(downloaded)
+			if total > 0 {
+				line = fmt.Sprintf("%s / %s (%.0f%%)", line, formatSize(total), float64(downloaded)*100/float64(total))
+			}
+			if line != current {
+				current = line
+				fmt.Printf("\r\033[K  %s", line)
+			}
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("\n  Done: %d downloaded, %d already present", result.Downloaded, result.Skipped)
+	if result.Failed > 0 {
+		fmt.Printf(", %d failed\n", result.Failed)
+		return fmt.Errorf("%d file(s) failed; run the same command again to retry them", result.Failed)
+	}
+	fmt.Println()
+	return nil
+}
+
+func init() {
+	telegramDumpCmd.Flags().StringP("output", "o", "", "output directory (default: the channel title)")
+	telegramDumpCmd.Flags().Int("from", 0, "first message ID to download")
+	telegramDumpCmd.Flags().Int("to", 0, "last message ID to download")
+	telegramDumpCmd.Flags().String("since", "", "only download media posted on or after this date (YYYY-MM-DD)")
+	telegramDumpCmd.Flags().StringSlice("type", nil, "media types to download: "+strings.Join(tgpkg.DumpTypes, ", ")+" (default: all)")
+	telegramCmd.AddCommand(telegramDumpCmd)
+}
@@ -0,0 +1,105 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/guiyumin/vget/internal/cli/login"
+	tgpkg "github.com/guiyumin/vget/internal/core/extractor/telegram"
+	"golang.org/x/term"
+)
+
+// runTelegramPhoneLogin logs in with a phone number, the code Telegram sends
+// to the account's other sessions (or by SMS) and the 2FA password if set
+func runTelegramPhoneLogin() error {
+	reader := bufio.NewReader(os.Stdin)
+	fmt.Print("Phone number (international format, e.g. +15551234567): ")
+	phone, err := reader.ReadString('\n')
+	if err != nil {
+		return fmt.Errorf("failed to read phone number: %w", err)
+	}
+	phone = strings.TrimSpace(phone)
+	if phone == "" {
+		return fmt.Errorf("phone number is required")
+	}
+
+	fmt.Println("Connecting to Telegram...")
+	return followTelegramLogin(tgpkg.StartPhoneLogin(phone), reader)
+}
+
+// runTelegramQRLogin logs in by scanning a QR code from a logged-in Telegram app
+func runTelegramQRLogin() error {
+	fmt.Println("Connecting to Telegram...")
+	return followTelegramLogin(tgpkg.StartQRLogin(), bufio.NewReader(os.Stdin))
+}
+
+// followTelegramLogin prompts for whatever the login asks for until it ends
+func followTelegramLogin(s *tgpkg.LoginSession, reader *bufio.Reader) error {
+	defer s.Cancel()
+
+	step := 0
+	for {
+		st, err := s.Next(context.Background(), step)
+		if err != nil {
+			return err
+		}
+		step = st.Step
+		if st.Error != "" && st.State != tgpkg.LoginFailed {
+			fmt.Println(st.Error)
+		}
+
+		switch st.State {
+		case tgpkg.LoginWaitingCode:
+			fmt.Printf("Login code (%s): ", st.Hint)
+			code, err := reader.ReadString('\n')
+			if err != nil {
+				return fmt.Errorf("failed to read code: %w", err)
+			}
+			if err := s.SubmitCode(code); err != nil {
+				return err
+			}
+
+		case tgpkg.LoginWaitingPassword:
+			if st.Hint != "" {
+				fmt.Printf("Two-step verification password (hint: %s): ", st.Hint)
+			} else {
+				fmt.Print("Two-step verification password: ")
+			}
+			password, err := term.ReadPassword(int(os.Stdin.Fd()))
+			fmt.Println()
+			if err != nil {
+				return fmt.Errorf("failed to read password: %w", err)
+			}
+			if err := s.SubmitPassword(string(password)); err != nil {
+				return err
+			}
+
+		case tgpkg.LoginWaitingScan:
+			qr, err := login.RenderQR(st.QRURL)
+			if err != nil {
+				return fmt.Errorf("failed to render QR code: %w", err)
+			}
+			fmt.Println()
+			fmt.Print(qr)
+			fmt.Println()
+			fmt.Println("Scan with Telegram on your phone: Settings > Devices > Link Desktop Device")
+			fmt.Printf("This code is renewed at %s.\n", st.QRExpires.Format("15:04:05"))
+
+		case tgpkg.LoginDone:
+			fmt.Println()
+			fmt.Println("Successfully logged in!")
+			fmt.Printf("Logged in as: %s\n", st.User)
+			fmt.Println()
+			fmt.Println("You can now download Telegram media:")
+			fmt.Println("  vget https://t.me/channel/123")
+			return nil
+
+		case tgpkg.LoginFailed:
+			return errors.New(st.Error)
+		}
+	}
+}
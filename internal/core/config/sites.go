@@ -3,20 +3,76 @@ package config
 import (
 	"fmt"
 	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
 	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
 
 const SitesFileName = "sites.yml"
 
-// Site represents a site configuration for browser-based extraction
+// Capture strategies for browser-based extraction, in default order
+const (
+	StrategyNetwork     = "network"     // intercept network requests
+	StrategyPerformance = "performance" // Performance API resource entries
+	StrategyVideo       = "video"       // <video> element / player source
+	StrategySource      = "source"      // regex over page HTML
+)
+
+// Strategies lists the capture strategies in their default order
+var Strategies = []string{StrategyNetwork, StrategyPerformance, StrategyVideo, StrategySource}
+
+// Site represents a site configuration for browser-based extraction.
+// Exactly one of Match, Pattern or Glob selects the URLs the rule applies to.
 type Site struct {
 	// Match is a substring to match against the URL (e.g., "kanav.ad")
-	Match string `yaml:"match"`
+	Match string `yaml:"match,omitempty"`
+
+	// Pattern is a regular expression matched against the full URL
+	Pattern string `yaml:"pattern,omitempty"`
+
+	// Glob is a shell-style pattern matched against "host/path" (e.g., "*.example.com/watch/*")
+	Glob string `yaml:"glob,omitempty"`
 
 	// Type is the media type to extract (e.g., "m3u8", "mp4")
 	Type string `yaml:"type"`
+
+	// Referer overrides the Referer sent with the media request (defaults to the page URL)
+	Referer string `yaml:"referer,omitempty"`
+
+	// Headers are extra request headers sent with the media request
+	Headers map[string]string `yaml:"headers,omitempty"`
+
+	// Cookies are set in the browser before loading the page and sent with the media request
+	Cookies map[string]string `yaml:"cookies,omitempty"`
+
+	// Actions run in order after the page loads (e.g., dismiss a dialog, press play)
+	Actions []SiteAction `yaml:"actions,omitempty"`
+
+	// TitleSelectors are CSS selectors tried in order for the title (defaults to document.title)
+	TitleSelectors []string `yaml:"title_selectors,omitempty"`
+
+	// Strategy is the capture strategy to try first: network, performance, video or source
+	Strategy string `yaml:"strategy,omitempty"`
+}
+
+// SiteAction is a single page interaction. Set exactly one field.
+type SiteAction struct {
+	// Click clicks the first element matching a CSS selector
+	Click string `yaml:"click,omitempty"`
+
+	// WaitFor waits until the page has requested a URL matching this regular expression
+	WaitFor string `yaml:"wait_for,omitempty"`
+
+	// Scroll scrolls the page: "bottom" or a number of pixels
+	Scroll string `yaml:"scroll,omitempty"`
+
+	// Sleep pauses for a duration (e.g., "2s")
+	Sleep string `yaml:"sleep,omitempty"`
 }
 
 // SitesConfig holds the sites configuration
@@ -24,35 +80,226 @@ type SitesConfig struct {
 	Sites []Site `yaml:"sites"`
 }
 
-// LoadSites reads sites.yml from the current directory
+// SitesPaths returns the sites.yml locations in priority order:
+// the current directory first, then the config directory
+func SitesPaths() []string {
+	paths := []string{SitesFileName}
+	if dir, err := ConfigDir(); err == nil {
+		paths = append(paths, filepath.Join(dir, SitesFileName))
+	}
+	return paths
+}
+
+// SitesPath returns the sites.yml to edit: the one in the current directory if it
+// exists, otherwise the one in the config directory (used by the server too)
+func SitesPath() string {
+	paths := SitesPaths()
+	if _, err := os.Stat(paths[0]); err == nil || len(paths) == 1 {
+		return paths[0]
+	}
+	return paths[1]
+}
+
+// LoadSites reads sites.yml from the current directory and the config directory.
+// Rules from the current directory take precedence.
 func LoadSites() (*SitesConfig, error) {
-	data, err := os.ReadFile(SitesFileName)
+	var merged *SitesConfig
+	for _, p := range SitesPaths() {
+		cfg, err := LoadSitesFile(p)
+		if err != nil {
+			return nil, err
+		}
+		if cfg == nil {
+			continue
+		}
+		if merged == nil {
+			merged = &SitesConfig{}
+		}
+		merged.Sites = append(merged.Sites, cfg.Sites...)
+	}
+	return merged, nil
+}
+
+// LoadSitesFile reads and validates a single sites file. Returns nil if it does not exist.
+func LoadSitesFile(p string) (*SitesConfig, error) {
+	data, err := os.ReadFile(p)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return nil, nil // No sites.yml, that's fine
 		}
-		return nil, fmt.Errorf("failed to read %s: %w", SitesFileName, err)
+		return nil, fmt.Errorf("failed to read %s: %w", p, err)
 	}
 
 	cfg := &SitesConfig{}
 	if err := yaml.Unmarshal(data, cfg); err != nil {
-		return nil, fmt.Errorf("failed to parse %s: %w", SitesFileName, err)
+		return nil, fmt.Errorf("failed to parse %s: %w", p, err)
+	}
+
+	for i := range cfg.Sites {
+		if err := cfg.Sites[i].Validate(); err != nil {
+			return nil, fmt.Errorf("%s: site %d: %w", p, i+1, err)
+		}
 	}
 
 	return cfg, nil
 }
 
-// SaveSites writes sites.yml to the current directory
+// SaveSites writes the sites file returned by SitesPath
 func SaveSites(cfg *SitesConfig) error {
 	data, err := yaml.Marshal(cfg)
 	if err != nil {
 		return fmt.Errorf("failed to serialize sites config: %w", err)
 	}
 
-	header := "# vget sites configuration\n# Sites that require browser-based extraction\n# Run 'vget config sites' to manage\n\n"
+	header := "# vget sites configuration\n# Sites that require browser-based extraction\n# Run 'vget sites' to manage\n\n"
 	content := header + string(data)
 
-	return os.WriteFile(SitesFileName, []byte(content), 0644)
+	p := SitesPath()
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	return os.WriteFile(p, []byte(content), 0644)
+}
+
+// Validate checks the rule's patterns, actions and strategy
+func (s *Site) Validate() error {
+	if s.Match == "" && s.Pattern == "" && s.Glob == "" {
+		return fmt.Errorf("one of match, pattern or glob is required")
+	}
+	if s.Pattern != "" {
+		if _, err := regexp.Compile(s.Pattern); err != nil {
+			return fmt.Errorf("invalid pattern: %w", err)
+		}
+	}
+	if s.Glob != "" {
+		if _, err := path.Match(s.Glob, ""); err != nil {
+			return fmt.Errorf("invalid glob: %w", err)
+		}
+	}
+	if s.Strategy != "" && !isStrategy(s.Strategy) {
+		return fmt.Errorf("unknown strategy %q (expected one of %s)", s.Strategy, strings.Join(Strategies, ", "))
+	}
+	for i, a := range s.Actions {
+		if err := a.Validate(); err != nil {
+			return fmt.Errorf("action %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// Validate checks that exactly one action is set and its argument parses
+func (a *SiteAction) Validate() error {
+	set := 0
+	for _, v := range []string{a.Click, a.WaitFor, a.Scroll, a.Sleep} {
+		if v != "" {
+			set++
+		}
+	}
+	if set != 1 {
+		return fmt.Errorf("exactly one of click, wait_for, scroll or sleep is required")
+	}
+	if a.WaitFor != "" {
+		if _, err := regexp.Compile(a.WaitFor); err != nil {
+			return fmt.Errorf("invalid wait_for: %w", err)
+		}
+	}
+	if a.Sleep != "" {
+		if _, err := time.ParseDuration(a.Sleep); err != nil {
+			return fmt.Errorf("invalid sleep: %w", err)
+		}
+	}
+	return nil
+}
+
+// Matches reports whether the rule applies to the URL
+func (s *Site) Matches(rawURL string) bool {
+	if s.Pattern != "" {
+		re, err := regexp.Compile(s.Pattern)
+		return err == nil && re.MatchString(rawURL)
+	}
+	if s.Glob != "" {
+		return matchGlob(s.Glob, rawURL)
+	}
+	return s.Match != "" && strings.Contains(rawURL, s.Match)
+}
+
+// Key returns the pattern that identifies the rule
+func (s *Site) Key() string {
+	switch {
+	case s.Pattern != "":
+		return s.Pattern
+	case s.Glob != "":
+		return s.Glob
+	default:
+		return s.Match
+	}
+}
+
+// MediaType returns the configured media type, defaulting to m3u8
+func (s *Site) MediaType() string {
+	if s.Type == "" {
+		return "m3u8"
+	}
+	return s.Type
+}
+
+// StrategyOrder returns the capture strategies with the preferred one first
+func (s *Site) StrategyOrder() []string {
+	if s.Strategy == "" {
+		return Strategies
+	}
+	order := []string{s.Strategy}
+	for _, st := range Strategies {
+		if st != s.Strategy {
+			order = append(order, st)
+		}
+	}
+	return order
+}
+
+// CookieHeader renders Cookies as a Cookie header value (sorted for stable output)
+func (s *Site) CookieHeader() string {
+	if len(s.Cookies) == 0 {
+		return ""
+	}
+	names := make([]string, 0, len(s.Cookies))
+	for name := range s.Cookies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, name+"="+s.Cookies[name])
+	}
+	return strings.Join(parts, "; ")
+}
+
+// matchGlob matches a glob against "host/path" of a URL; a glob without "/" matches the host only
+func matchGlob(glob, rawURL string) bool {
+	target := rawURL
+	if i := strings.Index(target, "://"); i >= 0 {
+		target = target[i+3:]
+	}
+	if i := strings.IndexAny(target, "?#"); i >= 0 {
+		target = target[:i]
+	}
+	if !strings.Contains(glob, "/") {
+		if i := strings.Index(target, "/"); i >= 0 {
+			target = target[:i]
+		}
+	}
+	ok, _ := path.Match(glob, target)
+	return ok
+}
+
+func isStrategy(name string) bool {
+	for _, s := range Strategies {
+		if s == name {
+			return true
+		}
+	}
+	return false
 }
 
 // MatchSite finds a matching site for the given URL
@@ -61,7 +308,7 @@ func (c *SitesConfig) MatchSite(url string) *Site {
 		return nil
 	}
 	for i := range c.Sites {
-		if strings.Contains(url, c.Sites[i].Match) {
+		if c.Sites[i].Matches(url) {
 			return &c.Sites[i]
 		}
 	}
@@ -76,10 +323,10 @@ func (c *SitesConfig) AddSite(match, mediaType string) {
 	})
 }
 
-// RemoveSite removes a site by match string
+// RemoveSite removes a site by its match string, pattern or glob
 func (c *SitesConfig) RemoveSite(match string) bool {
 	for i := range c.Sites {
-		if c.Sites[i].Match == match {
+		if c.Sites[i].Key() == match {
 			c.Sites = append(c.Sites[:i], c.Sites[i+1:]...)
 			return true
 		}
@@ -87,8 +334,12 @@ func (c *SitesConfig) RemoveSite(match string) bool {
 	return false
 }
 
-// SitesExist checks if sites.yml exists in current directory
+// SitesExist checks if sites.yml exists in the current directory or the config directory
 func SitesExist() bool {
-	_, err := os.Stat(SitesFileName)
-	return err == nil
+	for _, p := range SitesPaths() {
+		if _, err := os.Stat(p); err == nil {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,42 @@
+package config
+
+import "testing"
+
+func TestSiteMatches(t *testing.T) {
+	tests := []struct {
+		name string
+		site Site
+		url  string
+		want bool
+	}{
+		{"substring", Site{Match: "kanav.ad"}, "https://kanav.ad/v/1", true},
+		{"regex", Site{Pattern: `^https://[^/]+/watch/\d+$`}, "https://a.com/watch/42", true},
+		{"regex miss", Site{Pattern: `^https://[^/]+/watch/\d+$`}, "https://a.com/watch/x", false},
+		{"glob host", Site{Glob: "*.example.com"}, "https://www.example.com/v/1?x=1", true},
+		{"glob path", Site{Glob: "*.example.com/watch/*"}, "https://m.example.com/watch/abc", true},
+		{"glob path miss", Site{Glob: "*.example.com/watch/*"}, "https://m.example.com/live/abc", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.site.Matches(tt.url); got != tt.want {
+				t.Errorf("Matches(%q) = %v, want %v", tt.url, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSiteValidate(t *testing.T) {
+	if err := (&Site{Match: "a.com", Strategy: "dom"}).Validate(); err == nil {
+		t.Error("expected error for unknown strategy")
+	}
+	if err := (&Site{Match: "a.com", Actions: []SiteAction{{Click: "a", Sleep: "1s"}}}).Validate(); err == nil {
+		t.Error("expected error for action with two fields")
+	}
+	if err := (&Site{Pattern: "("}).Validate(); err == nil {
+		t.Error("expected error for invalid regex")
+	}
+	if err := (&Site{Glob: "*.a.com", Actions: []SiteAction{{WaitFor: `\.m3u8`}}}).Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,42 @@
+package downloader
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ArchiveFile records downloaded episode, track and post IDs inside a podcast, album or
+// collection directory, so re-runs (e.g., from cron) only fetch new ones even if files
+// were moved away
+const ArchiveFile = ".vget-archive"
+
+// LoadArchive returns the IDs recorded in dir's archive
+func LoadArchive(dir string) map[string]bool {
+	archive := make(map[string]bool)
+	file, err := os.Open(filepath.Join(dir, ArchiveFile))
+	if err != nil {
+		return archive
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if id := strings.TrimSpace(scanner.Text()); id != "" {
+			archive[id] = true
+		}
+	}
+	return archive
+}
+
+// AppendArchive records id in dir's archive
+func AppendArchive(dir, id string) {
+	file, err := os.OpenFile(filepath.Join(dir, ArchiveFile), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+	fmt.Fprintln(file, id)
+}
@@ -94,13 +94,13 @@ func RunHLSDownloadWithHeadersTUI(m3u8URL, output, displayID, lang string, heade
 		return downloadErr
 	}
 
-	// Convert .ts to .mp4 in Docker environment
-	mp4Path, err := convertTsToMp4(output)
+	// Remux raw .ts/.aac output into mp4/m4a
+	finalPath, err := remuxHLSOutput(output)
 	if err != nil {
-		// Log warning but don't fail - the .ts file is still usable
+		// Log warning but don't fail - the raw file is still usable
 		fmt.Printf("Warning: %v\n", err)
-	} else if mp4Path != output {
-		fmt.Printf("Converted to: %s\n", mp4Path)
+	} else if finalPath != output {
+		fmt.Printf("Converted to: %s\n", finalPath)
 	}
 
 	return nil
@@ -145,18 +145,18 @@ func downloadHLSWithHeaders(ctx context.Context, m3u8URL, output string, state *
 		}
 	}
 
-	// Create output file
-	file, err := os.Create(output)
+	// Open the output, continuing an unfinished earlier run
+	out, err := openHLSOutput(output, playlist, headers)
 	if err != nil {
-		return fmt.Errorf("failed to create output file: %w", err)
+		return err
 	}
-	defer file.Close()
+	defer out.Close()
 
 	// Set up progress tracking
 	// For HLS we estimate total size (unknown until download complete)
 	// We'll use segment count for progress
 	totalSegments := int64(len(playlist.Segments))
-	hlsState := &hlsState{totalSegments: totalSegments}
+	hlsState := &hlsState{totalSegments: totalSegments, downloaded: int64(out.next), bytesWritten: out.kept}
 
 	// Progress updater
 	progressDone := make(chan struct{})
@@ -182,16 +182,16 @@ func downloadHLSWithHeaders(ctx context.Context, m3u8URL, output string, state *
 
 	// Download segments
 	// We need to maintain order, so we download in parallel but write sequentially
-	err = downloadSegmentsOrdered(ctx, playlist.Segments, file, decryptKey, decryptIV, hlsState, config, headers)
+	err = downloadSegmentsOrdered(ctx, out.start(playlist.Segments), out, decryptKey, decryptIV, hlsState, config, headers)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	return out.finish()
 }
 
 // downloadSegmentsOrdered downloads segments in parallel but writes them in order
-func downloadSegmentsOrdered(ctx context.Context, segments []Segment, file *os.File,
+func downloadSegmentsOrdered(ctx context.Context, segments []Segment, file io.Writer,
 	decryptKey, decryptIV []byte, hlsState *hlsState, config HLSConfig, headers map[string]string) error {
 
 	type segmentResult struct {
@@ -253,6 +253,9 @@ func downloadSegmentsOrdered(ctx context.Context, segments []Segment, file *os.F
 
 	// Collect results and write in order
 	nextIndex := 0
+	if len(segments) > 0 {
+		nextIndex = segments[0].Index
+	}
 	var writeErr error
 
 	for result := range resultsChan {
@@ -288,6 +291,11 @@ func downloadSegmentsOrdered(ctx context.Context, segments []Segment, file *os.F
 		return fmt.Errorf("failed to write segment: %w", writeErr)
 	}
 
+	// Workers stop early when the download is cancelled
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -330,6 +338,23 @@ func downloadSegment(client *http.Client, url string, decryptKey, decryptIV []by
 	return data, nil
 }
 
+// writeInitSegment writes the fMP4 initialization segment (EXT-X-MAP) that must
+// precede the media segments; playlists without one are left untouched
+func writeInitSegment(file *os.File, initURL string, headers map[string]string) error {
+	if initURL == "" {
+		return nil
+	}
+	client := &http.Client{Timeout: 30 * time.Second}
+	data, err := downloadSegment(client, initURL, nil, nil, -1, headers)
+	if err != nil {
+		return fmt.Errorf("failed to download init segment: %w", err)
+	}
+	if _, err := file.Write(data); err != nil {
+		return fmt.Errorf("failed to write init segment: %w", err)
+	}
+	return nil
+}
+
 // fetchKeyWithHeaders fetches the encryption key from the URL with custom headers
 func fetchKeyWithHeaders(url string, headers map[string]string) ([]byte, error) {
 	client := &http.Client{
@@ -399,7 +424,7 @@ func decryptAES128(data, key, iv []byte, segmentIndex int) ([]byte, error) {
 }
 
 // DownloadHLSWithProgress downloads an HLS stream with a progress callback (for server use)
-// Returns the final output path (may be .mp4 if converted in Docker) and error
+// Returns the final output path (may be .mp4/.m4a after remuxing) and error
 func DownloadHLSWithProgress(ctx context.Context, m3u8URL, output string, headers map[string]string, progressFn func(downloaded, total int64)) (string, error) {
 	hlsConfig := DefaultHLSConfig()
 
@@ -438,15 +463,15 @@ func DownloadHLSWithProgress(ctx context.Context, m3u8URL, output string, header
 		}
 	}
 
-	// Create output file
-	file, err := os.Create(output)
+	// Open the output, continuing an unfinished earlier run
+	out, err := openHLSOutput(output, playlist, headers)
 	if err != nil {
-		return "", fmt.Errorf("failed to create output file: %w", err)
+		return "", err
 	}
 
 	// Set up progress tracking using segment count
 	totalSegments := int64(len(playlist.Segments))
-	hlsState := &hlsState{totalSegments: totalSegments}
+	hlsState := &hlsState{totalSegments: totalSegments, downloaded: int64(out.next), bytesWritten: out.kept}
 
 	// Progress updater goroutine
 	progressDone := make(chan struct{})
@@ -478,14 +503,16 @@ func DownloadHLSWithProgress(ctx context.Context, m3u8URL, output string, header
 	defer close(progressDone)
 
 	// Download segments
-	err = downloadSegmentsOrdered(ctx, playlist.Segments, file, decryptKey, decryptIV, hlsState, hlsConfig, headers)
+	err = downloadSegmentsOrdered(ctx, out.start(playlist.Segments), out, decryptKey, decryptIV, hlsState, hlsConfig, headers)
 	if err != nil {
-		file.Close()
+		out.Close()
 		return "", err
 	}
 
 	// Close file before conversion (ffmpeg needs exclusive access)
-	file.Close()
+	if err := out.finish(); err != nil {
+		return "", err
+	}
 
 	// Final progress update - download complete
 	if progressFn != nil {
@@ -493,10 +520,10 @@ func DownloadHLSWithProgress(ctx context.Context, m3u8URL, output string, header
 		progressFn(finalBytes, finalBytes)
 	}
 
-	// Convert .ts to .mp4 in Docker environment
-	finalPath, convErr := convertTsToMp4(output)
+	// Remux raw .ts/.aac output into mp4/m4a
+	finalPath, convErr := remuxHLSOutput(output)
 	if convErr != nil {
-		// Log warning but don't fail - the .ts file is still usable
+		// Log warning but don't fail - the raw file is still usable
 		fmt.Printf("Warning: %v\n", convErr)
 		return output, nil
 	}
@@ -504,25 +531,36 @@ func DownloadHLSWithProgress(ctx context.Context, m3u8URL, output string, header
 	return finalPath, nil
 }
 
-// convertTsToMp4 converts a .ts file to .mp4 using embedded ffmpeg (copy, no re-encoding)
-// Returns the new .mp4 path if conversion succeeded, otherwise returns original path
-func convertTsToMp4(tsPath string) (string, error) {
-	// Only convert .ts files
-	if !strings.HasSuffix(strings.ToLower(tsPath), ".ts") {
-		return tsPath, nil
+// hlsRemuxTargets maps raw HLS output extensions to the container they are remuxed into
+var hlsRemuxTargets = map[string]string{
+	".ts":  ".mp4", // MPEG-TS video segments
+	".aac": ".m4a", // ADTS audio segments (e.g., Twitter Spaces)
+}
+
+// IsHLSURL reports whether the URL points to an m3u8 playlist
+func IsHLSURL(rawURL string) bool {
+	lower := strings.ToLower(rawURL)
+	return strings.HasSuffix(lower, ".m3u8") || strings.Contains(lower, ".m3u8?")
+}
+
+// remuxHLSOutput remuxes a raw .ts/.aac file into .mp4/.m4a using embedded ffmpeg (copy, no re-encoding)
+// Returns the new path if conversion succeeded, otherwise returns original path
+func remuxHLSOutput(rawPath string) (string, error) {
+	// Only convert known raw formats
+	rawExt := filepath.Ext(rawPath)
+	targetExt, ok := hlsRemuxTargets[strings.ToLower(rawExt)]
+	if !ok {
+		return rawPath, nil
 	}
 
 	// Get absolute path for WASM filesystem mounting
-	absPath, err := filepath.Abs(tsPath)
+	absPath, err := filepath.Abs(rawPath)
 	if err != nil {
-		return tsPath, fmt.Errorf("failed to get absolute path: %w", err)
+		return rawPath, fmt.Errorf("failed to get absolute path: %w", err)
 	}
 
-	// Build mp4 output path
-	mp4Path := strings.TrimSuffix(absPath, ".ts") + ".mp4"
-	if strings.HasSuffix(absPath, ".TS") {
-		mp4Path = strings.TrimSuffix(absPath, ".TS") + ".mp4"
-	}
+	// Build output path
+	outPath := strings.TrimSuffix(absPath, rawExt) + targetExt
 
 	// Mount directory for WASM filesystem access
 	dir := filepath.Dir(absPath)
@@ -537,7 +575,7 @@ func convertTsToMp4(tsPath string) (string, error) {
 			"-i", absPath,
 			"-c", "copy",
 			"-y",
-			mp4Path,
+			outPath,
 		},
 		Config: func(cfg wazero.ModuleConfig) wazero.ModuleConfig {
 			return cfg.WithFSConfig(wazero.NewFSConfig().
@@ -547,16 +585,16 @@ func convertTsToMp4(tsPath string) (string, error) {
 
 	rc, err := ffmpreg.Ffmpeg(ctx, args)
 	if err != nil {
-		return tsPath, fmt.Errorf("ffmpeg conversion failed: %w", err)
+		return rawPath, fmt.Errorf("ffmpeg conversion failed: %w", err)
 	}
 	if rc != 0 {
-		return tsPath, fmt.Errorf("ffmpeg exited with code %d", rc)
+		return rawPath, fmt.Errorf("ffmpeg exited with code %d", rc)
 	}
 
-	// Conversion succeeded, delete the .ts file
-	if err := os.Remove(tsPath); err != nil {
-		fmt.Printf("Warning: could not remove original .ts file: %v\n", err)
+	// Conversion succeeded, delete the raw file
+	if err := os.Remove(rawPath); err != nil {
+		fmt.Printf("Warning: could not remove original %s file: %v\n", rawExt, err)
 	}
 
-	return mp4Path, nil
+	return outPath, nil
 }
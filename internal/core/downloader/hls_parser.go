@@ -21,6 +21,10 @@ type M3U8Playlist struct {
 	IsEncrypted   bool      // True if segments are encrypted
 	KeyURL        string    // URL of encryption key
 	KeyIV         string    // Initialization vector for encryption
+	InitURL       string    // fMP4 initialization segment (EXT-X-MAP), written before the media segments
+
+	// Renditions are the alternative audio/subtitle streams of a master playlist (EXT-X-MEDIA)
+	Renditions []Rendition
 }
 
 // Variant represents a stream variant in a master playlist
@@ -30,6 +34,17 @@ type Variant struct {
 	Resolution string // e.g., "1920x1080"
 	Codecs     string
 	Name       string // Name or description
+	AudioGroup string // GROUP-ID of the separate audio renditions this variant plays with
+}
+
+// Rendition is an alternative stream in a master playlist, such as a separate audio track
+type Rendition struct {
+	Type     string // "AUDIO", "SUBTITLES", "CLOSED-CAPTIONS"
+	GroupID  string
+	Name     string
+	Language string
+	URL      string // empty when the rendition is muxed into the variant
+	Default  bool
 }
 
 // Segment represents a single media segment
@@ -49,6 +64,10 @@ var (
 	keyMethodRegex   = regexp.MustCompile(`METHOD=([^,]+)`)
 	keyURIRegex      = regexp.MustCompile(`URI="([^"]+)"`)
 	keyIVRegex       = regexp.MustCompile(`IV=0x([0-9a-fA-F]+)`)
+	audioGroupRegex  = regexp.MustCompile(`AUDIO="([^"]+)"`)
+	mediaTypeRegex   = regexp.MustCompile(`TYPE=([A-Z-]+)`)
+	groupIDRegex     = regexp.MustCompile(`GROUP-ID="([^"]+)"`)
+	languageRegex    = regexp.MustCompile(`LANGUAGE="([^"]+)"`)
 )
 
 // ParseM3U8 parses an m3u8 playlist from a URL
@@ -88,11 +107,11 @@ func ParseM3U8WithHeaders(m3u8URL string, headers map[string]string) (*M3U8Playl
 		return nil, fmt.Errorf("server returned status %d", resp.StatusCode)
 	}
 
-	return parseM3U8Content(resp.Body, m3u8URL)
+	return ParseM3U8Content(resp.Body, m3u8URL)
 }
 
-// parseM3U8Content parses m3u8 content from a reader
-func parseM3U8Content(reader io.Reader, baseURL string) (*M3U8Playlist, error) {
+// ParseM3U8Content parses m3u8 content read from baseURL, which relative URLs are resolved against
+func ParseM3U8Content(reader io.Reader, baseURL string) (*M3U8Playlist, error) {
 	scanner := bufio.NewScanner(reader)
 	playlist := &M3U8Playlist{}
 
@@ -130,6 +149,30 @@ func parseM3U8Content(reader io.Reader, baseURL string) (*M3U8Playlist, error) {
 			continue
 		}
 
+		// Alternative renditions (separate audio tracks, subtitles)
+		if strings.HasPrefix(line, "#EXT-X-MEDIA:") {
+			rendition := Rendition{
+				Type:     extractRegex(mediaTypeRegex, line),
+				GroupID:  extractRegex(groupIDRegex, line),
+				Name:     extractRegex(nameRegex, line),
+				Language: extractRegex(languageRegex, line),
+				Default:  strings.Contains(line, "DEFAULT=YES"),
+			}
+			if uri := extractRegex(keyURIRegex, line); uri != "" {
+				rendition.URL = resolveURL(base, uri)
+			}
+			playlist.Renditions = append(playlist.Renditions, rendition)
+			continue
+		}
+
+		// fMP4 initialization segment
+		if strings.HasPrefix(line, "#EXT-X-MAP:") {
+			if uri := extractRegex(keyURIRegex, line); uri != "" {
+				playlist.InitURL = resolveURL(base, uri)
+			}
+			continue
+		}
+
 		// Parse encryption key
 		if strings.HasPrefix(line, "#EXT-X-KEY:") {
 			method := extractRegex(keyMethodRegex, line)
@@ -191,6 +234,7 @@ func parseVariant(line string) Variant {
 		Resolution: extractRegex(resolutionRegex, line),
 		Codecs:     extractRegex(codecsRegex, line),
 		Name:       extractRegex(nameRegex, line),
+		AudioGroup: extractRegex(audioGroupRegex, line),
 	}
 }
 
@@ -246,3 +290,25 @@ func (p *M3U8Playlist) SelectVariantByResolution(resolution string) *Variant {
 	}
 	return nil
 }
+
+// AudioRendition returns the separate audio track for a variant's audio group,
+// preferring the default one, or nil if the variant's audio is muxed in
+func (p *M3U8Playlist) AudioRendition(group string) *Rendition {
+	if group == "" {
+		return nil
+	}
+	var found *Rendition
+	for i := range p.Renditions {
+		r := &p.Renditions[i]
+		if r.Type != "AUDIO" || r.GroupID != group || r.URL == "" {
+			continue
+		}
+		if r.Default {
+			return r
+		}
+		if found == nil {
+			found = r
+		}
+	}
+	return found
+}
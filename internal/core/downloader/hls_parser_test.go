@@ -0,0 +1,94 @@
+package downloader
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/guiyumin/vget/internal/testutil/replay"
+)
+
+func TestParseM3U8(t *testing.T) {
+	srv := replay.NewServer(t, filepath.Join("testdata", "replay", "hls"))
+
+	master, err := ParseM3U8WithHeaders(srv.URL+"/master.m3u8", map[string]string{"Referer": "https://example.com/"})
+	if err != nil {
+		t.Fatalf("ParseM3U8WithHeaders: %v", err)
+	}
+	if !master.IsMaster || len(master.Variants) != 3 {
+		t.Fatalf("expected master playlist with 3 variants, got %+v", master)
+	}
+	if v := master.Variants[0]; v.URL != srv.URL+"/360p/index.m3u8" || v.Codecs != "avc1.4d401e,mp4a.40.2" {
+		t.Errorf("unexpected relative variant: %+v", v)
+	}
+	if v := master.Variants[2]; v.URL != "https://cdn.example.com/720p/index.m3u8" {
+		t.Errorf("absolute variant URL changed: %s", v.URL)
+	}
+
+	best := master.SelectBestVariant()
+	if best == nil || best.Resolution != "1920x1080" {
+		t.Fatalf("expected 1080p best variant, got %+v", best)
+	}
+	if v := master.SelectVariantByResolution("1280x720"); v == nil || v.Bandwidth != 2500000 {
+		t.Errorf("SelectVariantByResolution: %+v", v)
+	}
+
+	media, err := ParseM3U8(best.URL)
+	if err != nil {
+		t.Fatalf("ParseM3U8: %v", err)
+	}
+	if media.IsMaster || len(media.Segments) != 3 {
+		t.Fatalf("expected media playlist with 3 segments, got %+v", media)
+	}
+	if media.TotalDuration != 24.5 {
+		t.Errorf("TotalDuration = %v, want 24.5", media.TotalDuration)
+	}
+	if !strings.HasSuffix(media.Segments[2].URL, "/1080p/seg2.ts") || media.Segments[2].Index != 2 {
+		t.Errorf("unexpected segment: %+v", media.Segments[2])
+	}
+	if !media.IsEncrypted || media.KeyURL != srv.URL+"/keys/1080p.key" || media.KeyIV != "00000000000000000000000000000001" {
+		t.Errorf("unexpected key: %v %s %s", media.IsEncrypted, media.KeyURL, media.KeyIV)
+	}
+}
+
+func TestParseM3U8Renditions(t *testing.T) {
+	master, err := ParseM3U8Content(strings.NewReader(`#EXTM3U
+#EXT-X-MEDIA:TYPE=AUDIO,GROUP-ID="audio-hi",NAME="English",LANGUAGE="en",DEFAULT=NO,URI="audio/hi-en.m3u8"
+#EXT-X-MEDIA:TYPE=AUDIO,GROUP-ID="audio-hi",NAME="Original",DEFAULT=YES,URI="audio/hi.m3u8"
+#EXT-X-MEDIA:TYPE=SUBTITLES,GROUP-ID="subs",NAME="English",LANGUAGE="en",URI="subs/en.m3u8"
+#EXT-X-STREAM-INF:BANDWIDTH=5000000,RESOLUTION=1920x1080,AUDIO="audio-hi",SUBTITLES="subs"
+video/1080.m3u8
+#EXT-X-STREAM-INF:BANDWIDTH=800000,RESOLUTION=640x360
+video/360.m3u8
+`), "https://cdn.example.com/v/master.m3u8")
+	if err != nil {
+		t.Fatalf("ParseM3U8Content: %v", err)
+	}
+	if len(master.Variants) != 2 || len(master.Renditions) != 3 {
+		t.Fatalf("expected 2 variants and 3 renditions, got %+v", master)
+	}
+	if master.Variants[0].AudioGroup != "audio-hi" || master.Variants[1].AudioGroup != "" {
+		t.Errorf("unexpected audio groups: %+v", master.Variants)
+	}
+	audio := master.AudioRendition("audio-hi")
+	if audio == nil || audio.URL != "https://cdn.example.com/v/audio/hi.m3u8" || audio.Name != "Original" {
+		t.Errorf("expected the default audio rendition, got %+v", audio)
+	}
+	if master.AudioRendition("") != nil {
+		t.Error("expected no rendition for muxed audio")
+	}
+
+	media, err := ParseM3U8Content(strings.NewReader(`#EXTM3U
+#EXT-X-TARGETDURATION:6
+#EXT-X-MAP:URI="init.mp4"
+#EXTINF:6.0,
+seg0.m4s
+#EXT-X-ENDLIST
+`), "https://cdn.example.com/v/video/1080.m3u8")
+	if err != nil {
+		t.Fatalf("ParseM3U8Content: %v", err)
+	}
+	if media.InitURL != "https://cdn.example.com/v/video/init.mp4" || len(media.Segments) != 1 {
+		t.Errorf("unexpected fMP4 playlist: %+v", media)
+	}
+}
@@ -0,0 +1,159 @@
+package downloader
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+)
+
+// hlsStateSuffix names the file next to an HLS download that lists the
+// segments written so far, so a restarted download skips them
+const hlsStateSuffix = ".hls-state"
+
+// hlsOutput is the output file of an HLS download. Segments are written in
+// order; after each one the state file records its index and the file size,
+// which is where a restarted download continues from.
+type hlsOutput struct {
+	file  *os.File
+	state *os.File
+	next  int   // index of the next segment written
+	size  int64 // bytes written so far
+	kept  int64 // bytes kept from an earlier run
+}
+
+// openHLSOutput opens output for an HLS download of playlist. If an earlier
+// run of the same playlist left it unfinished, it is truncated to the last
+// segment recorded and continued; otherwise it is created and starts with the
+// init segment.
+func openHLSOutput(output string, playlist *M3U8Playlist, headers map[string]string) (*hlsOutput, error) {
+	statePath := output + hlsStateSuffix
+	header := hlsStateHeader(playlist)
+
+	if next, size, ok := readHLSState(statePath, header); ok {
+		if out, err := resumeHLSOutput(output, statePath, next, size); err == nil {
+			return out, nil
+		}
+	}
+
+	file, err := os.Create(output)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create output file: %w", err)
+	}
+	if err := writeInitSegment(file, playlist.InitURL, headers); err != nil {
+		file.Close()
+		return nil, err
+	}
+	size, err := file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	state, err := os.Create(statePath)
+	if err == nil {
+		_, err = fmt.Fprintln(state, header)
+	}
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to write download state: %w", err)
+	}
+	return &hlsOutput{file: file, state: state, size: size}, nil
+}
+
+// resumeHLSOutput reopens an unfinished output at the end of its last
+// recorded segment. Data lost after the state was written (e.g., in a crash)
+// fails the resume.
+func resumeHLSOutput(output, statePath string, next int, size int64) (*hlsOutput, error) {
+	info, err := os.Stat(output)
+	if err != nil || info.Size() < size {
+		return nil, fmt.Errorf("output is shorter than recorded")
+	}
+	file, err := os.OpenFile(output, os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	if err := file.Truncate(size); err != nil {
+		file.Close()
+		return nil, err
+	}
+	if _, err := file.Seek(size, io.SeekStart); err != nil {
+		file.Close()
+		return nil, err
+	}
+	state, err := os.OpenFile(statePath, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return &hlsOutput{file: file, state: state, next: next, size: size, kept: size}, nil
+}
+
+// hlsStateHeader identifies a playlist in the state file. Segment URLs often
+// carry expiring tokens, so only the count and the first segment's path are used.
+func hlsStateHeader(playlist *M3U8Playlist) string {
+	first := ""
+	if len(playlist.Segments) > 0 {
+		first = playlist.Segments[0].URL
+		if u, err := url.Parse(first); err == nil {
+			first = u.Path
+		}
+	}
+	return fmt.Sprintf("%d %s", len(playlist.Segments), first)
+}
+
+// readHLSState returns the segment to continue from and the size of the output
+// up to it, if the state file matches header and records any segment
+func readHLSState(path, header string) (next int, size int64, ok bool) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, 0, false
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	if !scanner.Scan() || scanner.Text() != header {
+		return 0, 0, false
+	}
+	for scanner.Scan() {
+		var index int
+		var n int64
+		if _, err := fmt.Sscanf(scanner.Text(), "%d %d", &index, &n); err == nil {
+			next, size, ok = index+1, n, true
+		}
+	}
+	return next, size, ok
+}
+
+// start returns the segments of playlist still to download
+func (o *hlsOutput) start(segments []Segment) []Segment {
+	return segments[min(o.next, len(segments)):]
+}
+
+// Write writes the next segment and records it
+func (o *hlsOutput) Write(p []byte) (int, error) {
+	n, err := o.file.Write(p)
+	o.size += int64(n)
+	if err != nil {
+		return n, err
+	}
+	if _, err := fmt.Fprintf(o.state, "%d %d\n", o.next, o.size); err != nil {
+		return n, fmt.Errorf("failed to write download state: %w", err)
+	}
+	o.next++
+	return n, nil
+}
+
+// Close closes the output, keeping the state for a later resume
+func (o *hlsOutput) Close() error {
+	o.state.Close()
+	return o.file.Close()
+}
+
+// finish closes a completed output and removes its state
+func (o *hlsOutput) finish() error {
+	err := o.Close()
+	os.Remove(o.state.Name())
+	return err
+}
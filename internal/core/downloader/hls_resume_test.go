@@ -0,0 +1,107 @@
+package downloader
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// segmentServer serves a media playlist of n segments, each "seg<i>;"
+type segmentServer struct {
+	n       int
+	mu      sync.Mutex
+	fetched []string
+}
+
+func (s *segmentServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/index.m3u8" {
+		fmt.Fprintln(w, "#EXTM3U")
+		for i := 0; i < s.n; i++ {
+			fmt.Fprintf(w, "#EXTINF:2.0,\nseg%d.ts?token=%s\n", i, r.URL.Query().Get("token"))
+		}
+		fmt.Fprintln(w, "#EXT-X-ENDLIST")
+		return
+	}
+	s.mu.Lock()
+	s.fetched = append(s.fetched, r.URL.Path)
+	s.mu.Unlock()
+	fmt.Fprintf(w, "%s;", strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/"), ".ts"))
+}
+
+func TestDownloadHLSResumes(t *testing.T) {
+	segments := &segmentServer{n: 5}
+	srv := httptest.NewServer(segments)
+	defer srv.Close()
+
+	output := filepath.Join(t.TempDir(), "stream.bin")
+
+	// An earlier run, with a since-expired token, wrote segments 0 and 1 and
+	// was stopped partway through segment 2
+	if err := os.WriteFile(output, []byte("seg0;seg1;se"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	state := "5 /seg0.ts\n0 5\n1 10\n"
+	if err := os.WriteFile(output+hlsStateSuffix, []byte(state), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var last int64
+	finalPath, err := DownloadHLSWithProgress(context.Background(), srv.URL+"/index.m3u8?token=new", output, nil, func(downloaded, total int64) {
+		last = downloaded
+	})
+	if err != nil {
+		t.Fatalf("DownloadHLSWithProgress: %v", err)
+	}
+	if finalPath != output {
+		t.Errorf("final path = %s, want %s", finalPath, output)
+	}
+
+	got, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "seg0;seg1;seg2;seg3;seg4;" {
+		t.Errorf("output = %q", got)
+	}
+	if len(segments.fetched) != 3 {
+		t.Errorf("fetched %v, want only segments 2 to 4", segments.fetched)
+	}
+	if last != int64(len(got)) {
+		t.Errorf("progress ended at %d, want %d", last, len(got))
+	}
+	if _, err := os.Stat(output + hlsStateSuffix); !os.IsNotExist(err) {
+		t.Error("state file was not removed")
+	}
+}
+
+func TestDownloadHLSIgnoresOtherState(t *testing.T) {
+	segments := &segmentServer{n: 3}
+	srv := httptest.NewServer(segments)
+	defer srv.Close()
+
+	output := filepath.Join(t.TempDir(), "stream.bin")
+
+	// State of a different playlist under the same name
+	if err := os.WriteFile(output, []byte("other;"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(output+hlsStateSuffix, []byte("9 /other.ts\n0 6\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := DownloadHLSWithProgress(context.Background(), srv.URL+"/index.m3u8", output, nil, nil); err != nil {
+		t.Fatalf("DownloadHLSWithProgress: %v", err)
+	}
+	if got, _ := os.ReadFile(output); string(got) != "seg0;seg1;seg2;" {
+		t.Errorf("output = %q", got)
+	}
+	if len(segments.fetched) != 3 {
+		t.Errorf("fetched %v, want all segments", segments.fetched)
+	}
+}
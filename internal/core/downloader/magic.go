@@ -45,6 +45,16 @@ func DetectFileType(path string) (string, error) {
 		return "gif", nil
 	}
 
+	// HEIC and AVIF: ISO media files, "ftyp" followed by the brand
+	if n >= 12 && string(header[4:8]) == "ftyp" {
+		switch string(header[8:12]) {
+		case "avif", "avis":
+			return "avif", nil
+		case "heic", "heix", "heim", "heis", "mif1", "msf1":
+			return "heic", nil
+		}
+	}
+
 	// JPEG: FF D8 FF
 	if n >= 3 && bytes.Equal(header[0:3], []byte{0xFF, 0xD8, 0xFF}) {
 		return "jpg", nil
@@ -0,0 +1,30 @@
+package downloader
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRenameByMagicBytes(t *testing.T) {
+	tests := []struct {
+		name, data, want string
+	}{
+		{"a.jpg", "\xff\xd8\xff\xe0\x00\x10JFIF\x00\x01", "a.jpg"},
+		{"b.jpg", "\x89PNG\r\n\x1a\n\x00\x00\x00\x0d", "b.png"},
+		{"c.jpg", "\x00\x00\x00\x18ftypheic\x00\x00\x00\x00", "c.heic"},
+		{"d.jpg", "\x00\x00\x00\x1cftypavif\x00\x00\x00\x00", "d.avif"},
+		{"e.mp4", "\x00\x00\x00\x18ftypisom\x00\x00\x02\x00", "e.mp4"},
+		{"f.jpg", "<html></html>", "f.jpg"},
+	}
+	dir := t.TempDir()
+	for _, tt := range tests {
+		path := filepath.Join(dir, tt.name)
+		if err := os.WriteFile(path, []byte(tt.data), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if got := RenameByMagicBytes(path); got != filepath.Join(dir, tt.want) {
+			t.Errorf("RenameByMagicBytes(%s) = %s, want %s", tt.name, filepath.Base(got), tt.want)
+		}
+	}
+}
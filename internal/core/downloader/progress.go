@@ -458,6 +458,7 @@ type TelegramDownloadResult struct {
 	Title    string
 	Filename string
 	Size     int64
+	Files    []string // every file of an album, in order
 }
 
 // TelegramDownloadFunc is the signature for the telegram download function
@@ -501,11 +502,17 @@ func RunTelegramDownloadTUI(urlStr, outputPath, lang string, downloadFn Telegram
 	}
 
 	if result != nil {
-		displayPath := result.Filename
-		if absPath, err := filepath.Abs(result.Filename); err == nil {
-			displayPath = absPath
+		files := result.Files
+		if len(files) == 0 {
+			files = []string{result.Filename}
+		}
+		for _, f := range files {
+			displayPath := f
+			if absPath, err := filepath.Abs(f); err == nil {
+				displayPath = absPath
+			}
+			fmt.Printf("  Saved: %s\n", displayPath)
 		}
-		fmt.Printf("  Saved: %s\n", displayPath)
 	}
 
 	return nil
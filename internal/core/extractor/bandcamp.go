@@ -0,0 +1,192 @@
+package extractor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	bandcampArtURL    = "https://f4.bcbits.com/img/a%010d_10.jpg" // _10 is the full-size original
+	bandcampUserAgent = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
+)
+
+var (
+	// /track/<slug> and /album/<slug> on <artist>.bandcamp.com
+	bandcampPathRegex = regexp.MustCompile(`^/(track|album)/[\w-]+/?$`)
+	// data-tralbum="{...}" and data-embed="{...}" attributes, HTML-escaped JSON
+	bandcampTralbumRegex = regexp.MustCompile(`data-tralbum="([^"]+)"`)
+	bandcampEmbedRegex   = regexp.MustCompile(`data-embed="([^"]+)"`)
+)
+
+// BandcampExtractor handles Bandcamp track and album pages. Only the free
+// 128kbps MP3 streams are available; purchased downloads need the fan's account.
+type BandcampExtractor struct {
+	client *http.Client
+}
+
+func (e *BandcampExtractor) Name() string {
+	return "bandcamp"
+}
+
+// Info describes the URLs and media this extractor handles
+func (e *BandcampExtractor) Info() Info {
+	return Info{
+		Name:       "bandcamp",
+		Title:      "Bandcamp",
+		Kinds:      []URLKind{URLKindSingle, URLKindPlaylist},
+		MediaTypes: []MediaType{MediaTypeAudio},
+	}
+}
+
+func (e *BandcampExtractor) Match(u *url.URL) bool {
+	return bandcampPathRegex.MatchString(u.Path)
+}
+
+func (e *BandcampExtractor) Extract(rawURL string) (Media, error) {
+	return e.ExtractContext(context.Background(), rawURL, Options{})
+}
+
+func (e *BandcampExtractor) ExtractContext(ctx context.Context, rawURL string, opts Options) (Media, error) {
+	client := e.client
+	if client == nil {
+		client = newHTTPClient(opts, 30*time.Second)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL: %w", err)
+	}
+	req.Header.Set("User-Agent", bandcampUserAgent)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch page: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read page: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("page request failed with status %d", resp.StatusCode)
+	}
+
+	return parseBandcampPage(string(body))
+}
+
+// bandcampTralbum is the page's data-tralbum JSON ("track or album")
+type bandcampTralbum struct {
+	ID       int64  `json:"id"`
+	ItemType string `json:"item_type"` // "track" or "album"
+	Artist   string `json:"artist"`
+	ArtID    int64  `json:"art_id"`
+	Current  struct {
+		Title       string `json:"title"`
+		ReleaseDate string `json:"release_date"`
+		About       string `json:"about"`
+	} `json:"current"`
+	Trackinfo []struct {
+		ID       int64             `json:"id"`
+		TrackID  int64             `json:"track_id"`
+		Title    string            `json:"title"`
+		Artist   string            `json:"artist"` // set on compilations
+		TrackNum int               `json:"track_num"`
+		Duration float64           `json:"duration"`
+		File     map[string]string `json:"file"` // "mp3-128" -> stream URL; null if not streamable
+	} `json:"trackinfo"`
+}
+
+// parseBandcampPage reads a track or album from its page's data attributes
+func parseBandcampPage(page string) (Media, error) {
+	m := bandcampTralbumRegex.FindStringSubmatch(page)
+	if m == nil {
+		return nil, fmt.Errorf("no track data found on page")
+	}
+	var tralbum bandcampTralbum
+	if err := json.Unmarshal([]byte(html.UnescapeString(m[1])), &tralbum); err != nil {
+		return nil, fmt.Errorf("failed to parse track data: %w", err)
+	}
+
+	// Track pages name their album only in the embed data
+	var embed struct {
+		AlbumTitle string `json:"album_title"`
+	}
+	if m := bandcampEmbedRegex.FindStringSubmatch(page); m != nil {
+		_ = json.Unmarshal([]byte(html.UnescapeString(m[1])), &embed)
+	}
+
+	var cover string
+	if tralbum.ArtID > 0 {
+		cover = fmt.Sprintf(bandcampArtURL, tralbum.ArtID)
+	}
+	published, _ := time.Parse("02 Jan 2006 15:04:05 GMT", tralbum.Current.ReleaseDate)
+
+	album := embed.AlbumTitle
+	if tralbum.ItemType == "album" {
+		album = tralbum.Current.Title
+	}
+
+	var tracks []*AudioMedia
+	for _, t := range tralbum.Trackinfo {
+		stream := t.File["mp3-128"]
+		if stream == "" {
+			continue // purchase only
+		}
+		if strings.HasPrefix(stream, "//") {
+			stream = "https:" + stream
+		}
+		artist := t.Artist
+		if artist == "" {
+			artist = tralbum.Artist
+		}
+		id := t.TrackID
+		if id == 0 {
+			id = t.ID
+		}
+		tracks = append(tracks, &AudioMedia{
+			ID:          fmt.Sprintf("%d", id),
+			Title:       t.Title,
+			Uploader:    artist,
+			Duration:    int(t.Duration),
+			URL:         stream,
+			Ext:         "mp3",
+			PublishedAt: published,
+			CoverURL:    cover,
+			Album:       album,
+			TrackNumber: t.TrackNum,
+		})
+	}
+
+	if tralbum.ItemType != "album" {
+		if len(tracks) == 0 {
+			return nil, fmt.Errorf("%q has no free stream; it is only available to buyers", tralbum.Current.Title)
+		}
+		track := tracks[0]
+		track.Description = tralbum.Current.About
+		return track, nil
+	}
+
+	if len(tracks) == 0 {
+		return nil, fmt.Errorf("no track of %q has a free stream; it is only available to buyers", tralbum.Current.Title)
+	}
+	return &AlbumMedia{
+		ID:       fmt.Sprintf("%d", tralbum.ID),
+		Title:    tralbum.Current.Title,
+		Uploader: tralbum.Artist,
+		CoverURL: cover,
+		Tracks:   tracks,
+	}, nil
+}
+
+func init() {
+	Register(&BandcampExtractor{}, "*.bandcamp.com")
+}
@@ -0,0 +1,83 @@
+package extractor
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/guiyumin/vget/internal/testutil/replay"
+)
+
+func newBandcampTestExtractor(t *testing.T) *BandcampExtractor {
+	return &BandcampExtractor{client: replay.Client(t, filepath.Join("testdata", "replay", "bandcamp"))}
+}
+
+func TestBandcampMatch(t *testing.T) {
+	tests := map[string]string{
+		"https://gopherband.bandcamp.com/album/bridges-rivers": "bandcamp",
+		"https://gopherband.bandcamp.com/track/river-song":     "bandcamp",
+		"https://gopherband.bandcamp.com/":                     "",
+		"https://gopherband.bandcamp.com/merch":                "",
+	}
+	for rawURL, want := range tests {
+		got := ""
+		if e := Match(rawURL); e != nil {
+			got = e.Name()
+		}
+		if got != want {
+			t.Errorf("Match(%q) = %q, want %q", rawURL, got, want)
+		}
+	}
+}
+
+func TestBandcampAlbum(t *testing.T) {
+	media, err := newBandcampTestExtractor(t).Extract("https://gopherband.bandcamp.com/album/bridges-rivers")
+	if err != nil {
+		t.Fatalf("Extract: %v", err)
+	}
+	album, ok := media.(*AlbumMedia)
+	if !ok {
+		t.Fatalf("expected *AlbumMedia, got %T", media)
+	}
+	if album.Title != "Bridges & Rivers" || album.Uploader != "Gopher Band" || album.CoverURL != "https://f4.bcbits.com/img/a0000271828_10.jpg" {
+		t.Errorf("unexpected album: %+v", album)
+	}
+
+	// The purchase-only track is left out, numbering follows the album
+	if len(album.Tracks) != 2 {
+		t.Fatalf("expected 2 tracks, got %d", len(album.Tracks))
+	}
+	track := album.Tracks[1]
+	if track.ID != "13" || track.TrackNumber != 3 || track.Album != "Bridges & Rivers" || track.Uploader != "Gopher Band feat. Ferris" {
+		t.Errorf("unexpected track: %+v", track)
+	}
+	if !strings.HasPrefix(track.URL, "https://t4.bcbits.com/stream/ccc/mp3-128/13") || track.Ext != "mp3" || track.Duration != 190 {
+		t.Errorf("unexpected stream: %+v", track)
+	}
+	if track.PublishedAt.Year() != 2024 {
+		t.Errorf("unexpected release date: %v", track.PublishedAt)
+	}
+	if got := track.TrackFilename(); got != "03 River Song.mp3" {
+		t.Errorf("TrackFilename = %q", got)
+	}
+}
+
+func TestBandcampTrack(t *testing.T) {
+	e := newBandcampTestExtractor(t)
+
+	media, err := e.Extract("https://gopherband.bandcamp.com/track/river-song")
+	if err != nil {
+		t.Fatalf("Extract: %v", err)
+	}
+	track, ok := media.(*AudioMedia)
+	if !ok {
+		t.Fatalf("expected *AudioMedia, got %T", media)
+	}
+	if track.Title != "River Song" || track.Album != "Bridges & Rivers" || track.Description != "Single edit." || track.CoverURL == "" {
+		t.Errorf("unexpected track: %+v", track)
+	}
+
+	if _, err := e.Extract("https://gopherband.bandcamp.com/track/paid-only"); err == nil || !strings.Contains(err.Error(), "buyers") {
+		t.Errorf("expected a purchase-only error, got %v", err)
+	}
+}
@@ -1,6 +1,7 @@
 package extractor
 
 import (
+	"context"
 	"crypto/md5"
 	"encoding/hex"
 	"encoding/json"
@@ -128,6 +129,19 @@ func (b *BilibiliExtractor) Name() string {
 	return "bilibili"
 }
 
+// Info describes the URLs and media this extractor handles
+func (b *BilibiliExtractor) Info() Info {
+	return Info{
+		Name:       "bilibili",
+		Title:      "Bilibili (哔哩哔哩)",
+		Kinds:      []URLKind{URLKindSingle},
+		MediaTypes: []MediaType{MediaTypeVideo},
+		Requires: []Requirement{
+			{Kind: RequiresLogin, Optional: true, Detail: "vget login bilibili (for member-only and high-quality streams)"},
+		},
+	}
+}
+
 // Match checks if URL is a Bilibili video URL
 func (b *BilibiliExtractor) Match(u *url.URL) bool {
 	urlStr := u.String()
@@ -138,36 +152,43 @@ func (b *BilibiliExtractor) Match(u *url.URL) bool {
 
 // Extract retrieves video information from a Bilibili URL
 func (b *BilibiliExtractor) Extract(urlStr string) (Media, error) {
-	// Initialize HTTP client
-	if b.client == nil {
-		b.client = &http.Client{
-			Timeout: 30 * time.Second,
+	cfg := config.LoadOrDefault()
+	return b.ExtractContext(context.Background(), urlStr, Options{Cookie: cfg.Bilibili.Cookie})
+}
+
+// ExtractContext retrieves video information from a Bilibili URL, sending
+// opts.Cookie if set. The WBI key fetched along the way belongs to the call.
+func (b *BilibiliExtractor) ExtractContext(ctx context.Context, urlStr string, opts Options) (Media, error) {
+	client := b.client
+	if client == nil {
+		client = &http.Client{
+			Timeout:   30 * time.Second,
+			Transport: &http.Transport{Proxy: proxyFunc(opts)},
 			CheckRedirect: func(req *http.Request, via []*http.Request) error {
 				return http.ErrUseLastResponse // Don't follow redirects automatically
 			},
 		}
 	}
+	call := &BilibiliExtractor{client: client, cookie: opts.Cookie}
+	return call.extract(ctx, urlStr)
+}
 
-	// Load cookie from config
-	cfg := config.LoadOrDefault()
-	if cfg.Bilibili.Cookie != "" {
-		b.cookie = cfg.Bilibili.Cookie
-	}
-
+// extract resolves a URL using the call's client, cookie and WBI key
+func (b *BilibiliExtractor) extract(ctx context.Context, urlStr string) (Media, error) {
 	// Resolve short URLs and extract video ID
-	aid, bvid, err := b.resolveVideoID(urlStr)
+	aid, bvid, err := b.resolveVideoID(ctx, urlStr)
 	if err != nil {
 		return nil, fmt.Errorf("failed to resolve video ID: %w", err)
 	}
 
 	// Get WBI keys for signing
-	if err := b.fetchWBIKeys(); err != nil {
+	if err := b.fetchWBIKeys(ctx); err != nil {
 		// Non-fatal: continue without WBI
 		fmt.Printf("Warning: failed to get WBI keys: %v\n", err)
 	}
 
 	// Fetch video info
-	videoInfo, err := b.fetchVideoInfo(aid)
+	videoInfo, err := b.fetchVideoInfo(ctx, aid)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch video info: %w", err)
 	}
@@ -179,7 +200,7 @@ func (b *BilibiliExtractor) Extract(urlStr string) (Media, error) {
 	cid := videoInfo.Pages[0].CID
 
 	// Fetch play URL to get stream info
-	streams, err := b.fetchPlayURL(aid, cid)
+	streams, err := b.fetchPlayURL(ctx, aid, cid)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch play URL: %w", err)
 	}
@@ -201,10 +222,10 @@ func (b *BilibiliExtractor) Extract(urlStr string) (Media, error) {
 }
 
 // resolveVideoID extracts aid and bvid from URL
-func (b *BilibiliExtractor) resolveVideoID(urlStr string) (aid int64, bvid string, err error) {
+func (b *BilibiliExtractor) resolveVideoID(ctx context.Context, urlStr string) (aid int64, bvid string, err error) {
 	// Handle short URLs
 	if strings.Contains(urlStr, "b23.tv") {
-		urlStr, err = b.resolveShortURL(urlStr)
+		urlStr, err = b.resolveShortURL(ctx, urlStr)
 		if err != nil {
 			return 0, "", err
 		}
@@ -237,8 +258,8 @@ func (b *BilibiliExtractor) resolveVideoID(urlStr string) (aid int64, bvid strin
 }
 
 // resolveShortURL follows redirects to get the full URL
-func (b *BilibiliExtractor) resolveShortURL(shortURL string) (string, error) {
-	req, err := http.NewRequest("HEAD", shortURL, nil)
+func (b *BilibiliExtractor) resolveShortURL(ctx context.Context, shortURL string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, "HEAD", shortURL, nil)
 	if err != nil {
 		return "", err
 	}
@@ -261,10 +282,10 @@ func (b *BilibiliExtractor) resolveShortURL(shortURL string) (string, error) {
 }
 
 // fetchWBIKeys obtains WBI signing keys from nav API
-func (b *BilibiliExtractor) fetchWBIKeys() error {
+func (b *BilibiliExtractor) fetchWBIKeys(ctx context.Context) error {
 	api := "https://api.bilibili.com/x/web-interface/nav"
 
-	req, err := http.NewRequest("GET", api, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", api, nil)
 	if err != nil {
 		return err
 	}
@@ -335,6 +356,9 @@ func getMixinKey(orig string) string {
 	return result.String()
 }
 
+// wbiNow is the clock used for WBI timestamps; tests replace it for stable signatures
+var wbiNow = time.Now
+
 // wbiSign signs the query parameters with WBI
 func (b *BilibiliExtractor) wbiSign(params url.Values) string {
 	if b.wbi == "" {
@@ -342,7 +366,7 @@ func (b *BilibiliExtractor) wbiSign(params url.Values) string {
 	}
 
 	// Add timestamp
-	params.Set("wts", strconv.FormatInt(time.Now().Unix(), 10))
+	params.Set("wts", strconv.FormatInt(wbiNow().Unix(), 10))
 
 	// Sort keys
 	keys := make([]string, 0, len(params))
@@ -403,10 +427,10 @@ type BilibiliVideoInfo struct {
 }
 
 // fetchVideoInfo retrieves video metadata
-func (b *BilibiliExtractor) fetchVideoInfo(aid int64) (*BilibiliVideoInfo, error) {
+func (b *BilibiliExtractor) fetchVideoInfo(ctx context.Context, aid int64) (*BilibiliVideoInfo, error) {
 	api := fmt.Sprintf("https://api.bilibili.com/x/web-interface/view?aid=%d", aid)
 
-	req, err := http.NewRequest("GET", api, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", api, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -462,7 +486,7 @@ type BilibiliStreamInfo struct {
 }
 
 // fetchPlayURL retrieves stream URLs
-func (b *BilibiliExtractor) fetchPlayURL(aid, cid int64) (*BilibiliStreamInfo, error) {
+func (b *BilibiliExtractor) fetchPlayURL(ctx context.Context, aid, cid int64) (*BilibiliStreamInfo, error) {
 	params := url.Values{}
 	params.Set("avid", strconv.FormatInt(aid, 10))
 	params.Set("cid", strconv.FormatInt(cid, 10))
@@ -476,7 +500,7 @@ func (b *BilibiliExtractor) fetchPlayURL(aid, cid int64) (*BilibiliStreamInfo, e
 
 	api := "https://api.bilibili.com/x/player/wbi/playurl?" + query
 
-	req, err := http.NewRequest("GET", api, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", api, nil)
 	if err != nil {
 		return nil, err
 	}
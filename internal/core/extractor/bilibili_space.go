@@ -0,0 +1,116 @@
+package extractor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// bilibiliSpaceRegex matches an uploader's space page, space.bilibili.com/{mid}
+var bilibiliSpaceRegex = regexp.MustCompile(`^(?:https?://)?space\.bilibili\.com/(\d+)(?:/(?:video|upload/video))?/?(?:\?.*)?$`)
+
+// bilibiliSpacePageSize is how many of the newest videos are listed
+const bilibiliSpacePageSize = 30
+
+// MatchBilibiliSpace returns the uploader ID of a space.bilibili.com URL
+func MatchBilibiliSpace(rawURL string) (string, bool) {
+	m := bilibiliSpaceRegex.FindStringSubmatch(rawURL)
+	if m == nil {
+		return "", false
+	}
+	return m[1], true
+}
+
+// FetchBilibiliUploads lists the newest videos of a Bilibili uploader
+func FetchBilibiliUploads(ctx context.Context, mid string, opts Options) (*Uploads, error) {
+	b := &BilibiliExtractor{
+		client: &http.Client{Timeout: 30 * time.Second},
+		cookie: opts.Cookie,
+	}
+	return b.fetchUploads(ctx, mid)
+}
+
+func (b *BilibiliExtractor) fetchUploads(ctx context.Context, mid string) (*Uploads, error) {
+	// The space API refuses unsigned requests
+	if err := b.fetchWBIKeys(ctx); err != nil {
+		return nil, fmt.Errorf("failed to get WBI keys: %w", err)
+	}
+
+	params := url.Values{}
+	params.Set("mid", mid)
+	params.Set("ps", strconv.Itoa(bilibiliSpacePageSize))
+	params.Set("pn", "1")
+	params.Set("order", "pubdate")
+	api := "https://api.bilibili.com/x/space/wbi/arc/search?" + b.wbiSign(params)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", api, nil)
+	if err != nil {
+		return nil, err
+	}
+	b.setHeaders(req)
+	req.Header.Set("Referer", "https://space.bilibili.com/"+mid)
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	uploads, err := parseBilibiliUploads(body)
+	if err != nil {
+		return nil, err
+	}
+	uploads.ID = mid
+	return uploads, nil
+}
+
+// parseBilibiliUploads parses a response of the space video search API
+func parseBilibiliUploads(body []byte) (*Uploads, error) {
+	var result struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Data    struct {
+			List struct {
+				Vlist []struct {
+					Bvid    string `json:"bvid"`
+					Title   string `json:"title"`
+					Author  string `json:"author"`
+					Length  string `json:"length"` // "mm:ss" or "h:mm:ss"
+					Created int64  `json:"created"`
+				} `json:"vlist"`
+			} `json:"list"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse uploads: %w", err)
+	}
+	if result.Code != 0 {
+		return nil, fmt.Errorf("API error: %s (code: %d)", result.Message, result.Code)
+	}
+
+	uploads := &Uploads{}
+	for _, v := range result.Data.List.Vlist {
+		if uploads.Title == "" {
+			uploads.Title = v.Author
+		}
+		uploads.Items = append(uploads.Items, Upload{
+			ID:          v.Bvid,
+			URL:         "https://www.bilibili.com/video/" + v.Bvid,
+			Title:       v.Title,
+			Duration:    parseFeedDuration(v.Length),
+			Type:        MediaTypeVideo,
+			PublishedAt: time.Unix(v.Created, 0),
+		})
+	}
+	return uploads, nil
+}
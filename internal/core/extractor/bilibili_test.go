@@ -0,0 +1,66 @@
+package extractor
+
+import (
+	"context"
+	"net/url"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/guiyumin/vget/internal/testutil/replay"
+)
+
+func TestWBISign(t *testing.T) {
+	defer func(now func() time.Time) { wbiNow = now }(wbiNow)
+	wbiNow = func() time.Time { return time.Unix(1702204169, 0) }
+
+	// Example from the bilibili-API-collect WBI documentation
+	b := &BilibiliExtractor{wbi: getMixinKey("7cd084941338484aae1ad9425b84077c" + "4932caff0ff746eab6f01bf08b70ac45")}
+	if b.wbi != "ea1db124af3c7062474693fa704f4ff8" {
+		t.Fatalf("unexpected mixin key %q", b.wbi)
+	}
+
+	params := url.Values{}
+	params.Set("foo", "114")
+	params.Set("bar", "514")
+	params.Set("zab", "1919810")
+
+	want := "bar=514&foo=114&wts=1702204169&zab=1919810&w_rid=8f6f2b5b3d485fe1886cec6a0be8c5d4"
+	if got := b.wbiSign(params); got != want {
+		t.Errorf("wbiSign = %q, want %q", got, want)
+	}
+}
+
+func TestBilibiliExtract(t *testing.T) {
+	b := &BilibiliExtractor{client: replay.Client(t, filepath.Join("testdata", "replay", "bilibili"), "wts", "w_rid")}
+
+	media, err := b.extract(context.Background(), "https://www.bilibili.com/video/BV1GJ411x7h7/")
+	if err != nil {
+		t.Fatalf("extract: %v", err)
+	}
+	if b.wbi != "ea1db124af3c7062474693fa704f4ff8" {
+		t.Errorf("WBI key not derived from nav: %q", b.wbi)
+	}
+
+	video, ok := media.(*VideoMedia)
+	if !ok {
+		t.Fatalf("expected *VideoMedia, got %T", media)
+	}
+	if video.ID != "BV1GJ411x7h7" || video.Uploader != "索尼音乐中国" || video.Duration != 213 {
+		t.Errorf("unexpected metadata: %+v", video)
+	}
+	if len(video.Formats) != 3 {
+		t.Fatalf("expected 3 formats, got %d", len(video.Formats))
+	}
+
+	best := video.Formats[0]
+	if best.Height != 1080 || best.Quality != "1080P [AVC]" {
+		t.Errorf("expected 1080p AVC first, got %q", best.Quality)
+	}
+	if best.AudioURL != "https://upos-sz-mirrorcos.bilivideo.com/upgcxcode/99/91/137649199/137649199-1-30280.m4s" {
+		t.Errorf("expected highest-bandwidth audio, got %s", best.AudioURL)
+	}
+	if best.Headers["Referer"] != "https://www.bilibili.com/" {
+		t.Errorf("missing Referer header")
+	}
+}
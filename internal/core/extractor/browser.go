@@ -3,10 +3,12 @@ package extractor
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -40,14 +42,34 @@ func (e *BrowserExtractor) Name() string {
 	return "browser"
 }
 
+// Info describes the URLs and media this extractor handles
+func (e *BrowserExtractor) Info() Info {
+	return Info{
+		Name:       "browser",
+		Title:      "Browser capture",
+		Matches:    "sites.yml rules, and pages without media metadata",
+		Kinds:      []URLKind{URLKindSingle},
+		MediaTypes: []MediaType{MediaTypeVideo},
+		Requires: []Requirement{
+			{Kind: RequiresBrowser, Detail: "the page is opened in a headless Chrome/Chromium"},
+		},
+	}
+}
+
 func (e *BrowserExtractor) Match(u *url.URL) bool {
 	return true // Called only when site matches
 }
 
-// extractionStrategy defines a method for finding media URLs
-type extractionStrategy func(page *rod.Page, targetExt string) string
+// extractionStrategy defines a method for finding media URLs; it returns all candidates found
+type extractionStrategy func(page *rod.Page, targetExt string) []string
 
 func (e *BrowserExtractor) Extract(rawURL string) (Media, error) {
+	return e.ExtractContext(context.Background(), rawURL, Options{Visible: e.visible})
+}
+
+// ExtractContext is Extract with cancellation (the browser is closed when ctx is done)
+// and per-call options: visibility, proxy and extra cookies
+func (e *BrowserExtractor) ExtractContext(ctx context.Context, rawURL string, opts Options) (Media, error) {
 	if e.site == nil {
 		return nil, fmt.Errorf("no site configuration provided")
 	}
@@ -60,53 +82,44 @@ func (e *BrowserExtractor) Extract(rawURL string) (Media, error) {
 	pageOrigin := fmt.Sprintf("%s://%s", pageURL.Scheme, pageURL.Host)
 
 	// Normalize extension to lowercase once for consistent matching
-	targetExt := strings.ToLower("." + e.site.Type) // e.g., ".m3u8", ".mp4"
-
-	fmt.Printf("  Trying to detecting %s stream...\n", e.site.Type)
+	mediaType := e.site.MediaType()
+	targetExt := strings.ToLower("." + mediaType) // e.g., ".m3u8", ".mp4"
 
-	// Launch browser
-	l := e.createLauncher(!e.visible) // headless unless --visible flag
-	defer l.Cleanup()
+	fmt.Printf("  Trying to detecting %s stream...\n", mediaType)
 
-	u, err := l.Launch()
+	page, cleanup, err := e.openPage(ctx, pageURL, opts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to launch browser: %w", err)
+		return nil, err
 	}
+	defer cleanup()
 
-	browser := rod.New().ControlURL(u).MustConnect()
-	defer browser.MustClose()
-
-	page := stealth.MustPage(browser)
-	defer page.MustClose()
-
-	// Try network interception first, then fallback strategies
-	mediaURL := e.captureFromNetwork(page, rawURL, targetExt)
+	// Network capture navigates the page and runs the rule's actions, so it always runs;
+	// its result is then weighed against the other strategies in the preferred order
+	networkURLs := e.captureFromNetwork(ctx, page, rawURL, targetExt, false)
 
-	// Fallback strategies if network capture didn't find anything
-	if mediaURL == "" {
-		strategies := []extractionStrategy{
-			e.findInPerformanceAPI,
-			e.findInVideoPlayer,
-			e.findInPageSource,
+	var mediaURL string
+	for _, name := range e.site.StrategyOrder() {
+		found := networkURLs
+		if name != config.StrategyNetwork {
+			found = e.strategy(name)(page, targetExt)
 		}
-
-		for _, strategy := range strategies {
-			if found := strategy(page, targetExt); found != "" {
-				mediaURL = found
-				break
-			}
+		if len(found) > 0 {
+			mediaURL = found[0]
+			break
 		}
 	}
 
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 	if mediaURL == "" {
-		return nil, fmt.Errorf("website not supported (no %s stream found)", e.site.Type)
+		return nil, fmt.Errorf("website not supported (no %s stream found)", mediaType)
 	}
 
 	fmt.Printf("Found: %s\n", mediaURL)
 
 	// Extract page title
-	title := page.MustEval(`() => document.title`).String()
-	title = strings.TrimSpace(title)
+	title := e.pageTitle(page)
 	if title == "" {
 		pageURL, _ := url.Parse(rawURL)
 		title = filepath.Base(pageURL.Path)
@@ -125,6 +138,17 @@ func (e *BrowserExtractor) Extract(rawURL string) (Media, error) {
 		id = "video"
 	}
 
+	headers := map[string]string{"Referer": rawURL, "Origin": pageOrigin}
+	if e.site.Referer != "" {
+		headers["Referer"] = e.site.Referer
+	}
+	for k, v := range e.site.Headers {
+		headers[k] = v
+	}
+	if cookie := joinCookieHeaders(e.site.CookieHeader(), opts.Cookie); cookie != "" {
+		headers["Cookie"] = cookie
+	}
+
 	return &VideoMedia{
 		ID:    id,
 		Title: title,
@@ -132,15 +156,243 @@ func (e *BrowserExtractor) Extract(rawURL string) (Media, error) {
 			{
 				URL:     mediaURL,
 				Quality: "best",
-				Ext:     e.site.Type,
-				Headers: map[string]string{"Referer": rawURL, "Origin": pageOrigin},
+				Ext:     mediaType,
+				Headers: headers,
 			},
 		},
 	}, nil
 }
 
-// captureFromNetwork intercepts network requests to find media URLs
-func (e *BrowserExtractor) captureFromNetwork(page *rod.Page, rawURL, targetExt string) string {
+// openPage launches the browser and opens a stealth page, with the site rule's and
+// the caller's cookies set. The browser is closed as soon as ctx is done, which aborts
+// any page operation in flight.
+func (e *BrowserExtractor) openPage(ctx context.Context, pageURL *url.URL, opts Options) (*rod.Page, func(), error) {
+	l := e.createLauncher(!opts.Visible, opts.Proxy) // headless unless --visible flag
+
+	u, err := l.Launch()
+	if err != nil {
+		l.Cleanup()
+		return nil, nil, fmt.Errorf("failed to launch browser: %w", err)
+	}
+
+	browser := rod.New().ControlURL(u).MustConnect()
+
+	// Cookies from the site rule and options apply to the page as well as the media request
+	var params []*proto.NetworkCookieParam
+	for name, value := range e.site.Cookies {
+		params = append(params, &proto.NetworkCookieParam{
+			Name:   name,
+			Value:  value,
+			Domain: pageURL.Hostname(),
+			Path:   "/",
+		})
+	}
+	if cookies, err := http.ParseCookie(opts.Cookie); err == nil {
+		for _, c := range cookies {
+			params = append(params, &proto.NetworkCookieParam{
+				Name:   c.Name,
+				Value:  c.Value,
+				Domain: pageURL.Hostname(),
+				Path:   "/",
+			})
+		}
+	}
+	if len(params) > 0 {
+		_ = browser.SetCookies(params)
+	}
+
+	page := stealth.MustPage(browser)
+
+	closed := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = browser.Close()
+		case <-closed:
+		}
+	}()
+
+	cleanup := func() {
+		close(closed)
+		_ = page.Close()
+		_ = browser.Close()
+		l.Cleanup()
+	}
+	return page, cleanup, nil
+}
+
+// ProbeResult is what one capture strategy found while probing a page
+type ProbeResult struct {
+	Strategy   string // sites.yml name, e.g. "network"
+	Method     string // implementing method, e.g. "captureFromNetwork"
+	Candidates []string
+	Elapsed    time.Duration
+}
+
+// ProbeReport describes how a page would be extracted under the current site rule
+type ProbeReport struct {
+	Title    string
+	Selected string        // URL Extract would pick, following the rule's strategy order
+	Results  []ProbeResult // in the rule's strategy order
+}
+
+// strategyMethods names the method behind each strategy, for probe reports
+var strategyMethods = map[string]string{
+	config.StrategyNetwork:     "captureFromNetwork",
+	config.StrategyPerformance: "findInPerformanceAPI",
+	config.StrategyVideo:       "findInVideoPlayer",
+	config.StrategySource:      "findInPageSource",
+}
+
+// Probe runs every capture strategy against a page and reports all candidates with timings.
+// Unlike Extract it does not stop at the first match, which makes it useful for writing rules.
+func (e *BrowserExtractor) Probe(rawURL string) (*ProbeReport, error) {
+	if e.site == nil {
+		return nil, fmt.Errorf("no site configuration provided")
+	}
+
+	pageURL, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL: %w", err)
+	}
+	targetExt := strings.ToLower("." + e.site.MediaType())
+
+	ctx := context.Background()
+	page, cleanup, err := e.openPage(ctx, pageURL, Options{Visible: e.visible})
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	// Network capture navigates the page, so it has to run first; its timing includes
+	// page load, the rule's actions and the full capture window
+	results := make(map[string]ProbeResult)
+	start := time.Now()
+	results[config.StrategyNetwork] = ProbeResult{
+		Candidates: e.captureFromNetwork(ctx, page, rawURL, targetExt, true),
+		Elapsed:    time.Since(start),
+	}
+	for _, name := range config.Strategies[1:] {
+		start := time.Now()
+		results[name] = ProbeResult{
+			Candidates: e.strategy(name)(page, targetExt),
+			Elapsed:    time.Since(start),
+		}
+	}
+
+	report := &ProbeReport{Title: e.pageTitle(page)}
+	for _, name := range e.site.StrategyOrder() {
+		r := results[name]
+		r.Strategy = name
+		r.Method = strategyMethods[name]
+		if report.Selected == "" && len(r.Candidates) > 0 {
+			report.Selected = r.Candidates[0]
+		}
+		report.Results = append(report.Results, r)
+	}
+	return report, nil
+}
+
+// strategy returns the fallback strategy for a sites.yml strategy name
+func (e *BrowserExtractor) strategy(name string) extractionStrategy {
+	switch name {
+	case config.StrategyPerformance:
+		return e.findInPerformanceAPI
+	case config.StrategyVideo:
+		return e.findInVideoPlayer
+	default:
+		return e.findInPageSource
+	}
+}
+
+// pageTitle returns the text of the first matching title selector, or document.title
+func (e *BrowserExtractor) pageTitle(page *rod.Page) string {
+	for _, sel := range e.site.TitleSelectors {
+		result, err := page.Eval(`(sel) => {
+			const el = document.querySelector(sel);
+			return el ? (el.content || el.textContent || '') : '';
+		}`, sel)
+		if err != nil {
+			continue
+		}
+		if title := strings.TrimSpace(result.Value.Str()); title != "" {
+			return title
+		}
+	}
+
+	result, err := page.Eval(`() => document.title`)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(result.Value.Str())
+}
+
+// runActions performs the site rule's page actions in order. Failures are reported but
+// not fatal, since the media may still be found without them.
+func (e *BrowserExtractor) runActions(ctx context.Context, page *rod.Page) {
+	for _, action := range e.site.Actions {
+		if ctx.Err() != nil {
+			return
+		}
+		switch {
+		case action.Click != "":
+			clickCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+			el, err := page.Context(clickCtx).Element(action.Click)
+			if err == nil {
+				if err = el.Click(proto.InputMouseButtonLeft, 1); err != nil {
+					// Covered or off-screen elements can still be clicked from JS
+					_, err = el.Eval(`() => this.click()`)
+				}
+			}
+			cancel()
+			if err != nil {
+				fmt.Printf("  Warning: click %q failed: %v\n", action.Click, err)
+			}
+
+		case action.WaitFor != "":
+			re := regexp.MustCompile(action.WaitFor) // validated when sites.yml is loaded
+			if !waitForRequest(ctx, page, re, 15*time.Second) {
+				fmt.Printf("  Warning: no request matching %q\n", action.WaitFor)
+			}
+
+		case action.Scroll != "":
+			if action.Scroll == "bottom" {
+				_, _ = page.Eval(`() => window.scrollTo(0, document.body.scrollHeight)`)
+			} else if px, err := strconv.Atoi(action.Scroll); err == nil {
+				_, _ = page.Eval(`(y) => window.scrollBy(0, y)`, px)
+			}
+
+		case action.Sleep != "":
+			d, _ := time.ParseDuration(action.Sleep)
+			select {
+			case <-time.After(d):
+			case <-ctx.Done():
+			}
+		}
+	}
+}
+
+// waitForRequest polls the Performance API until the page has requested a matching URL
+func waitForRequest(ctx context.Context, page *rod.Page, re *regexp.Regexp, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) && ctx.Err() == nil {
+		result, err := page.Eval(`() => performance.getEntriesByType('resource').map(r => r.name)`)
+		if err == nil {
+			for _, v := range result.Value.Arr() {
+				if re.MatchString(v.Str()) {
+					return true
+				}
+			}
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+	return false
+}
+
+// captureFromNetwork navigates to the page and intercepts network requests to find media URLs.
+// It returns on the first match unless all is set, in which case it collects every match
+// seen during the capture window.
+func (e *BrowserExtractor) captureFromNetwork(parent context.Context, page *rod.Page, rawURL, targetExt string, all bool) []string {
 	// Enable Network domain to capture requests
 	_ = proto.NetworkEnable{}.Call(page)
 
@@ -152,12 +404,10 @@ func (e *BrowserExtractor) captureFromNetwork(page *rod.Page, rawURL, targetExt
 	}.Call(page)
 
 	// Use channel for thread-safe communication
-	foundURL := make(chan string, 1)
-	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
-	defer cancel()
+	foundURL := make(chan string, 64)
 
 	// Separate context for the listener so we can stop it independently
-	listenerCtx, stopListener := context.WithCancel(context.Background())
+	listenerCtx, stopListener := context.WithCancel(parent)
 	listenerDone := make(chan struct{})
 
 	// Listen for network requests at CDP level
@@ -190,23 +440,37 @@ func (e *BrowserExtractor) captureFromNetwork(page *rod.Page, rawURL, targetExt
 	}()
 
 	// Navigate with timeout to prevent hanging on slow/broken pages
-	navCtx, navCancel := context.WithTimeout(ctx, 10*time.Second)
+	navCtx, navCancel := context.WithTimeout(parent, 10*time.Second)
 	_ = page.Context(navCtx).Navigate(rawURL)
 	_ = page.Context(navCtx).WaitLoad()
 	navCancel()
 
+	// Page actions from sites.yml (click play, wait for a request, scroll)
+	e.runActions(parent, page)
+
+	ctx, cancel := context.WithTimeout(parent, 15*time.Second)
+	defer cancel()
+
 	// Wait for capture or timeout
-	var result string
-	select {
-	case url := <-foundURL:
-		result = url
-	case <-ctx.Done():
-		// Timeout: check one more time in case URL arrived just as we timed out
+	var results []string
+	seen := make(map[string]bool)
+	collect := func(u string) {
+		if !seen[u] {
+			seen[u] = true
+			results = append(results, u)
+		}
+	}
+
+wait:
+	for {
 		select {
-		case url := <-foundURL:
-			result = url
-		default:
-			// No URL found
+		case u := <-foundURL:
+			collect(u)
+			if !all {
+				break wait
+			}
+		case <-ctx.Done():
+			break wait
 		}
 	}
 
@@ -214,11 +478,24 @@ func (e *BrowserExtractor) captureFromNetwork(page *rod.Page, rawURL, targetExt
 	stopListener()
 	<-listenerDone
 
-	return result
+	// Drain URLs that arrived just as we stopped
+	if all || len(results) == 0 {
+	drain:
+		for {
+			select {
+			case u := <-foundURL:
+				collect(u)
+			default:
+				break drain
+			}
+		}
+	}
+
+	return results
 }
 
 // findInPerformanceAPI uses the browser's Performance API to find resource requests
-func (e *BrowserExtractor) findInPerformanceAPI(page *rod.Page, targetExt string) string {
+func (e *BrowserExtractor) findInPerformanceAPI(page *rod.Page, targetExt string) []string {
 	// Pass targetExt to JavaScript for filtering (already lowercase)
 	result, err := page.Eval(`(ext) => {
 		return performance.getEntriesByType('resource')
@@ -226,65 +503,70 @@ func (e *BrowserExtractor) findInPerformanceAPI(page *rod.Page, targetExt string
 			.filter(url => url.toLowerCase().includes(ext));
 	}`, targetExt)
 	if err != nil {
-		return ""
+		return nil
 	}
 
-	arr := result.Value.Arr()
-	for _, v := range arr {
-		url := v.String()
+	var urls []string
+	for _, v := range result.Value.Arr() {
+		url := v.Str()
 		if strings.Contains(strings.ToLower(url), targetExt) {
-			return url
+			urls = append(urls, url)
 		}
 	}
-
-	return ""
+	return urls
 }
 
-// findInVideoPlayer queries the video player for its source URL
-func (e *BrowserExtractor) findInVideoPlayer(page *rod.Page, targetExt string) string {
+// findInVideoPlayer queries the video player for its source URLs
+func (e *BrowserExtractor) findInVideoPlayer(page *rod.Page, targetExt string) []string {
 	// targetExt is already lowercase
 	result, err := page.Eval(`(ext) => {
+		const found = [];
+		const add = (src) => {
+			if (src && src.toLowerCase().includes(ext) && !found.includes(src)) found.push(src);
+		};
+
 		// Check for video.js
 		const vjsPlayer = document.querySelector('.video-js');
 		if (vjsPlayer && vjsPlayer.player) {
-			const src = vjsPlayer.player.currentSrc();
-			if (src && src.toLowerCase().includes(ext)) return src;
+			add(vjsPlayer.player.currentSrc());
 		}
 
 		// Check video element sources
-		const video = document.querySelector('video');
-		if (video) {
-			if (video.src && video.src.toLowerCase().includes(ext)) return video.src;
-			const sources = video.querySelectorAll('source');
-			for (const source of sources) {
-				if (source.src && source.src.toLowerCase().includes(ext)) return source.src;
+		for (const video of document.querySelectorAll('video')) {
+			add(video.src);
+			for (const source of video.querySelectorAll('source')) {
+				add(source.src);
 			}
 		}
 
 		// Check for any global player variable
 		if (window.player && window.player.src) {
-			const src = typeof window.player.src === 'function' ? window.player.src() : window.player.src;
-			if (src && src.toLowerCase().includes(ext)) return src;
+			add(typeof window.player.src === 'function' ? window.player.src() : window.player.src);
 		}
-		return '';
+		return found;
 	}`, targetExt)
 	if err != nil {
-		return ""
+		return nil
 	}
-	return result.Value.String()
+
+	var urls []string
+	for _, v := range result.Value.Arr() {
+		urls = append(urls, v.Str())
+	}
+	return urls
 }
 
 // findInPageSource searches for media URLs in page HTML/JavaScript source
-func (e *BrowserExtractor) findInPageSource(page *rod.Page, targetExt string) string {
+func (e *BrowserExtractor) findInPageSource(page *rod.Page, targetExt string) []string {
 	html, err := page.HTML()
 	if err != nil {
-		return ""
+		return nil
 	}
 
 	// Escape special regex characters in targetExt (already lowercase)
 	escapedExt := regexp.QuoteMeta(targetExt)
 
-	// Case-insensitive patterns
+	// Case-insensitive patterns, most specific first
 	patterns := []string{
 		// Full URL with extension
 		`(?i)https?://[^"'\s<>]+` + escapedExt + `[^"'\s<>]*`,
@@ -294,6 +576,8 @@ func (e *BrowserExtractor) findInPageSource(page *rod.Page, targetExt string) st
 		`(?i)src\s*[=:]\s*["']([^"']*` + escapedExt + `[^"']*)["']`,
 	}
 
+	var urls []string
+	seen := make(map[string]bool)
 	for _, pattern := range patterns {
 		re, err := regexp.Compile(pattern)
 		if err != nil {
@@ -317,16 +601,18 @@ func (e *BrowserExtractor) findInPageSource(page *rod.Page, targetExt string) st
 			}
 
 			foundURL = strings.TrimSpace(foundURL)
-			if foundURL != "" {
-				return foundURL
+			if foundURL != "" && !seen[foundURL] {
+				seen[foundURL] = true
+				urls = append(urls, foundURL)
 			}
 		}
 	}
 
-	return ""
+	return urls
 }
 
-func (e *BrowserExtractor) createLauncher(headless bool) *launcher.Launcher {
+// createLauncher returns a launcher for the browser, through proxy if set
+func (e *BrowserExtractor) createLauncher(headless bool, proxy string) *launcher.Launcher {
 	userDataDir := e.getUserDataDir()
 
 	// Check for ROD_BROWSER env var (set in Docker)
@@ -352,6 +638,9 @@ func (e *BrowserExtractor) createLauncher(headless bool) *launcher.Launcher {
 	if browserPath != "" {
 		l = l.Bin(browserPath)
 	}
+	if proxy != "" {
+		l = l.Proxy(proxy)
+	}
 
 	return l
 }
@@ -363,3 +652,82 @@ func (e *BrowserExtractor) getUserDataDir() string {
 	}
 	return filepath.Join(configDir, "browser")
 }
+
+// fetchPageState loads a page in a stealth browser and polls the given JS expression until it
+// returns a non-empty string. Site extractors use this as a fallback when their direct API
+// approach fails (e.g., signed endpoints or bot checks). It gives up when ctx is done.
+// opts sets the browser's visibility and proxy.
+func fetchPageState(ctx context.Context, rawURL, script string, opts Options) (string, error) {
+	return fetchPageStateWithCookies(ctx, rawURL, script, opts, "")
+}
+
+// fetchPageStateWithCookies is fetchPageState with the shared cookie store for cookieSite loaded
+// before navigation and saved back afterwards, so login sessions persist between runs.
+// With a visible browser it waits longer to give the user time to log in.
+func fetchPageStateWithCookies(ctx context.Context, rawURL, script string, opts Options, cookieSite string, cookieDomains ...string) (string, error) {
+	e := &BrowserExtractor{visible: opts.Visible}
+	l := e.createLauncher(!opts.Visible, opts.Proxy)
+	defer l.Cleanup()
+
+	u, err := l.Launch()
+	if err != nil {
+		return "", fmt.Errorf("failed to launch browser: %w", err)
+	}
+
+	browser := rod.New().ControlURL(u).MustConnect()
+	defer browser.MustClose()
+
+	if cookieSite != "" {
+		if cookies := loadSiteCookies(cookieSite); len(cookies) > 0 {
+			_ = browser.SetCookies(proto.CookiesToParams(cookies))
+		}
+	}
+
+	page := stealth.MustPage(browser)
+	defer page.MustClose()
+
+	navCtx, navCancel := context.WithTimeout(ctx, 20*time.Second)
+	defer navCancel()
+	if err := page.Context(navCtx).Navigate(rawURL); err != nil {
+		return "", fmt.Errorf("failed to load page: %w", err)
+	}
+	_ = page.Context(navCtx).WaitLoad()
+
+	wait := 15 * time.Second
+	if opts.Visible {
+		wait = 120 * time.Second
+	}
+
+	deadline := time.Now().Add(wait)
+	for time.Now().Before(deadline) {
+		result, err := page.Context(ctx).Eval(script)
+		if err == nil {
+			if s := result.Value.Str(); s != "" {
+				if cookieSite != "" {
+					if cookies, err := browser.GetCookies(); err == nil {
+						_, _ = saveSiteCookies(cookieSite, cookies, cookieDomains...)
+					}
+				}
+				return s, nil
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(time.Second):
+		}
+	}
+
+	return "", fmt.Errorf("timeout waiting for page data")
+}
+
+// joinCookieHeaders merges Cookie header values, skipping empty ones
+func joinCookieHeaders(values ...string) string {
+	var parts []string
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			parts = append(parts, v)
+		}
+	}
+	return strings.Join(parts, "; ")
+}
@@ -0,0 +1,103 @@
+package extractor
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/guiyumin/vget/internal/core/config"
+)
+
+// Options are per-call extraction settings, passed to ExtractContext
+type Options struct {
+	// AuthToken is a site auth token (e.g., the Twitter/X auth_token cookie)
+	AuthToken string
+
+	// Cookie is a raw Cookie header sent with page and API requests
+	Cookie string
+
+	// Proxy is a proxy URL (http://, socks5://) for API requests and the
+	// browser; empty uses the environment
+	Proxy string
+
+	// Visible shows the browser window for browser-backed extractors
+	Visible bool
+
+	// Quality is the preferred quality (e.g., "1080p") for extractors that
+	// request a single rendition instead of listing them all
+	Quality string
+
+	// Referer is the page a video is embedded on, for sites that restrict
+	// embeds to certain domains (e.g., Vimeo)
+	Referer string
+}
+
+// ConfigOptions returns the credentials stored in the user config for extractor e
+func ConfigOptions(cfg *config.Config, e Extractor) Options {
+	var opts Options
+	switch e.Name() {
+	case "twitter":
+		opts.AuthToken = cfg.Twitter.AuthToken
+	case "bilibili":
+		opts.Cookie = cfg.Bilibili.Cookie
+	}
+	return opts
+}
+
+// ContextExtractor is an Extractor that honors cancellation and per-call options.
+// Every built-in extractor implements it; options must not be stored on the
+// extractor, which is shared by concurrent calls.
+type ContextExtractor interface {
+	Extractor
+
+	// ExtractContext retrieves media information, aborting when ctx is done
+	ExtractContext(ctx context.Context, url string, opts Options) (Media, error)
+}
+
+// ExtractContext extracts url with e, adapting legacy extractors as needed
+func ExtractContext(ctx context.Context, e Extractor, url string, opts Options) (Media, error) {
+	return Adapt(e).ExtractContext(ctx, url, opts)
+}
+
+// Adapt returns e as a ContextExtractor. An extractor that only implements
+// Extract is called as is: it can't be cancelled once started and ignores opts.
+func Adapt(e Extractor) ContextExtractor {
+	if ce, ok := e.(ContextExtractor); ok {
+		return ce
+	}
+	return &legacyExtractor{Extractor: e}
+}
+
+// legacyExtractor adapts an Extract-only extractor
+type legacyExtractor struct {
+	Extractor
+}
+
+func (l *legacyExtractor) ExtractContext(ctx context.Context, url string, opts Options) (Media, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return l.Extractor.Extract(url)
+}
+
+// newHTTPClient returns an HTTP client using opts.Proxy, or the environment's proxy
+func newHTTPClient(opts Options, timeout time.Duration) *http.Client {
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			Proxy: proxyFunc(opts),
+		},
+	}
+}
+
+// proxyFunc returns the proxy setting of an http.Transport for opts.Proxy. An
+// invalid proxy URL falls back to the environment's proxy.
+func proxyFunc(opts Options) func(*http.Request) (*url.URL, error) {
+	if opts.Proxy != "" {
+		if proxyURL, err := url.Parse(opts.Proxy); err == nil {
+			return http.ProxyURL(proxyURL)
+		}
+	}
+	return http.ProxyFromEnvironment
+}
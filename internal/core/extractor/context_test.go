@@ -0,0 +1,73 @@
+package extractor
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// legacyOnlyExtractor only implements Extract and counts the calls
+type legacyOnlyExtractor struct {
+	calls int
+}
+
+func (e *legacyOnlyExtractor) Name() string          { return "legacy" }
+func (e *legacyOnlyExtractor) Match(u *url.URL) bool { return true }
+
+func (e *legacyOnlyExtractor) Extract(url string) (Media, error) {
+	e.calls++
+	return &AudioMedia{ID: "1"}, nil
+}
+
+func TestAdaptLegacyExtractor(t *testing.T) {
+	e := &legacyOnlyExtractor{}
+
+	media, err := ExtractContext(context.Background(), e, "https://example.com", Options{Visible: true, AuthToken: "tok"})
+	if err != nil || media.GetID() != "1" {
+		t.Fatalf("ExtractContext() = %v, %v", media, err)
+	}
+	if e.calls != 1 {
+		t.Errorf("Extract called %d times, want 1", e.calls)
+	}
+}
+
+func TestAdaptLegacyExtractorCancelled(t *testing.T) {
+	e := &legacyOnlyExtractor{}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := ExtractContext(ctx, e, "https://example.com", Options{})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want canceled", err)
+	}
+	if e.calls != 0 {
+		t.Errorf("Extract called %d times after cancellation", e.calls)
+	}
+}
+
+func TestBuiltinExtractorsHonorContext(t *testing.T) {
+	all := map[string]Extractor{"(fallback)": fallbackExtractor, "(m3u8)": m3u8Extractor}
+	for host, e := range extractorsByHost {
+		all[host] = e
+	}
+
+	for host, e := range all {
+		if _, ok := e.(ContextExtractor); !ok {
+			t.Errorf("%s: %T does not implement ContextExtractor", host, e)
+		}
+	}
+}
+
+func TestNewHTTPClientProxy(t *testing.T) {
+	req, _ := http.NewRequest("GET", "https://example.com/", nil)
+
+	client := newHTTPClient(Options{Proxy: "socks5://127.0.0.1:1080"}, time.Second)
+	proxyURL, err := client.Transport.(*http.Transport).Proxy(req)
+	if err != nil || proxyURL == nil || proxyURL.String() != "socks5://127.0.0.1:1080" {
+		t.Errorf("proxy = %v, %v; want socks5://127.0.0.1:1080", proxyURL, err)
+	}
+}
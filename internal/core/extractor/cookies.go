@@ -0,0 +1,100 @@
+package extractor
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/guiyumin/vget/internal/core/config"
+)
+
+// Shared cookie store for browser-backed extractors.
+// Cookies are persisted per site at ~/.config/vget/{site}_cookies.json so a login done
+// once in the browser window can be reused by both later browser runs and plain HTTP requests.
+
+// siteCookiePath returns the cookie file path for a site (e.g., "xhs", "instagram")
+func siteCookiePath(site string) (string, error) {
+	configDir, err := config.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, site+"_cookies.json"), nil
+}
+
+// loadSiteCookies returns the saved cookies for a site, or nil if none are stored
+func loadSiteCookies(site string) []*proto.NetworkCookie {
+	cookiePath, err := siteCookiePath(site)
+	if err != nil {
+		return nil
+	}
+
+	data, err := os.ReadFile(cookiePath)
+	if err != nil {
+		return nil // No cookies file, that's fine
+	}
+
+	var cookies []*proto.NetworkCookie
+	if err := json.Unmarshal(data, &cookies); err != nil {
+		return nil
+	}
+	return cookies
+}
+
+// saveSiteCookies stores the cookies whose domain contains one of domainKeywords
+// Returns the number of cookies saved
+func saveSiteCookies(site string, cookies []*proto.NetworkCookie, domainKeywords ...string) (int, error) {
+	var filtered []*proto.NetworkCookie
+	for _, c := range cookies {
+		for _, keyword := range domainKeywords {
+			if strings.Contains(c.Domain, keyword) {
+				filtered = append(filtered, c)
+				break
+			}
+		}
+	}
+
+	if len(filtered) == 0 {
+		return 0, nil
+	}
+
+	cookiePath, err := siteCookiePath(site)
+	if err != nil {
+		return 0, err
+	}
+
+	// Ensure directory exists
+	if err := os.MkdirAll(filepath.Dir(cookiePath), 0755); err != nil {
+		return 0, err
+	}
+
+	data, err := json.MarshalIndent(filtered, "", "  ")
+	if err != nil {
+		return 0, err
+	}
+
+	if err := os.WriteFile(cookiePath, data, 0600); err != nil {
+		return 0, err
+	}
+	return len(filtered), nil
+}
+
+// siteCookieHeader formats a site's saved cookies as a Cookie header value for HTTP requests
+func siteCookieHeader(site string) string {
+	var parts []string
+	for _, c := range loadSiteCookies(site) {
+		parts = append(parts, c.Name+"="+c.Value)
+	}
+	return strings.Join(parts, "; ")
+}
+
+// siteCookieValue returns the value of a named cookie from a site's store
+func siteCookieValue(site, name string) string {
+	for _, c := range loadSiteCookies(site) {
+		if c.Name == name {
+			return c.Value
+		}
+	}
+	return ""
+}
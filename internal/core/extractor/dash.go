@@ -0,0 +1,111 @@
+package extractor
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// dashManifest is a static DASH manifest (MPD) with segment-less representations,
+// as served by Reddit: each representation is a single file at its BaseURL
+type dashManifest struct {
+	BaseURL string `xml:"BaseURL"`
+	Periods []struct {
+		BaseURL        string `xml:"BaseURL"`
+		AdaptationSets []struct {
+			ContentType     string `xml:"contentType,attr"`
+			MimeType        string `xml:"mimeType,attr"`
+			BaseURL         string `xml:"BaseURL"`
+			Representations []struct {
+				ID        string `xml:"id,attr"`
+				MimeType  string `xml:"mimeType,attr"`
+				Codecs    string `xml:"codecs,attr"`
+				Bandwidth int    `xml:"bandwidth,attr"`
+				Width     int    `xml:"width,attr"`
+				Height    int    `xml:"height,attr"`
+				BaseURL   string `xml:"BaseURL"`
+			} `xml:"Representation"`
+		} `xml:"AdaptationSet"`
+	} `xml:"Period"`
+}
+
+// dashRepresentation is one stream of a DASH manifest, with its URL resolved
+type dashRepresentation struct {
+	ID        string
+	URL       string
+	Codecs    string
+	Bandwidth int // bits per second
+	Width     int
+	Height    int
+}
+
+// parseDASHManifest returns the video streams (tallest first) and audio streams
+// (highest bandwidth first) of a manifest downloaded from manifestURL
+func parseDASHManifest(data []byte, manifestURL string) (video, audio []dashRepresentation, err error) {
+	var mpd dashManifest
+	if err := xml.Unmarshal(data, &mpd); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse DASH manifest: %w", err)
+	}
+
+	base, err := url.Parse(manifestURL)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid manifest URL: %w", err)
+	}
+	base = resolveDASHBase(base, mpd.BaseURL)
+
+	for _, period := range mpd.Periods {
+		periodBase := resolveDASHBase(base, period.BaseURL)
+		for _, set := range period.AdaptationSets {
+			setBase := resolveDASHBase(periodBase, set.BaseURL)
+			for _, r := range set.Representations {
+				if r.BaseURL == "" {
+					continue // segmented representations aren't supported
+				}
+				rep := dashRepresentation{
+					ID:        r.ID,
+					URL:       resolveDASHBase(setBase, r.BaseURL).String(),
+					Codecs:    r.Codecs,
+					Bandwidth: r.Bandwidth,
+					Width:     r.Width,
+					Height:    r.Height,
+				}
+
+				mime := r.MimeType
+				if mime == "" {
+					mime = set.MimeType
+				}
+				if set.ContentType == "audio" || strings.HasPrefix(mime, "audio/") {
+					audio = append(audio, rep)
+				} else {
+					video = append(video, rep)
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(video, func(i, j int) bool {
+		if video[i].Height != video[j].Height {
+			return video[i].Height > video[j].Height
+		}
+		return video[i].Bandwidth > video[j].Bandwidth
+	})
+	sort.SliceStable(audio, func(i, j int) bool {
+		return audio[i].Bandwidth > audio[j].Bandwidth
+	})
+	return video, audio, nil
+}
+
+// resolveDASHBase resolves a BaseURL element against its parent's base
+func resolveDASHBase(base *url.URL, ref string) *url.URL {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return base
+	}
+	u, err := base.Parse(ref)
+	if err != nil {
+		return base
+	}
+	return u
+}
@@ -1,6 +1,7 @@
 package extractor
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -20,6 +21,17 @@ func (d *DirectExtractor) Name() string {
 	return "direct"
 }
 
+// Info describes the URLs and media this extractor handles
+func (d *DirectExtractor) Info() Info {
+	return Info{
+		Name:       "direct",
+		Title:      "Direct files",
+		Matches:    "any URL ending in a media, document or archive extension",
+		Kinds:      []URLKind{URLKindSingle},
+		MediaTypes: []MediaType{MediaTypeVideo, MediaTypeAudio, MediaTypeImage},
+	}
+}
+
 // Match always returns true - this is the fallback extractor
 func (d *DirectExtractor) Match(u *url.URL) bool {
 	// Only match http/https URLs
@@ -28,30 +40,24 @@ func (d *DirectExtractor) Match(u *url.URL) bool {
 
 // Extract retrieves media information from a direct URL
 func (d *DirectExtractor) Extract(urlStr string) (Media, error) {
-	if d.client == nil {
-		d.client = &http.Client{
-			Timeout: 30 * time.Second,
-			Transport: &http.Transport{
-				Proxy: http.ProxyFromEnvironment,
-			},
-			CheckRedirect: func(req *http.Request, via []*http.Request) error {
-				// Follow redirects but limit to 10
-				if len(via) >= 10 {
-					return fmt.Errorf("too many redirects")
-				}
-				return nil
-			},
-		}
+	return d.ExtractContext(context.Background(), urlStr, Options{})
+}
+
+// ExtractContext retrieves media information from a direct URL
+func (d *DirectExtractor) ExtractContext(ctx context.Context, urlStr string, opts Options) (Media, error) {
+	client := d.client
+	if client == nil {
+		client = newHTTPClient(opts, 30*time.Second)
 	}
 
 	// HEAD request to get Content-Type and filename
-	req, err := http.NewRequest("HEAD", urlStr, nil)
+	req, err := http.NewRequestWithContext(ctx, "HEAD", urlStr, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 	req.Header.Set("User-Agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36")
 
-	resp, err := d.client.Do(req)
+	resp, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch URL: %w", err)
 	}
@@ -0,0 +1,216 @@
+package extractor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	douyinShareVideoURL = "https://www.iesdouyin.com/share/video/%s/"
+	douyinShareMusicURL = "https://www.iesdouyin.com/share/music/%s/"
+	// Share pages only embed post data for mobile user agents
+	douyinUserAgent = "Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.0 Mobile/15E148 Safari/604.1"
+)
+
+var (
+	// Matches /video/123, /note/123, /slides/123 and ?modal_id=123
+	douyinVideoIDRegex = regexp.MustCompile(`(?:/(?:video|note|slides)/|[?&]modal_id=)(\d+)`)
+	douyinMusicIDRegex = regexp.MustCompile(`/music/(\d+)`)
+	douyinRouterRegex  = regexp.MustCompile(`(?s)window\._ROUTER_DATA\s*=\s*(\{.+?\})\s*</script>`)
+)
+
+// DouyinExtractor handles Douyin (抖音) video, image post and music downloads
+type DouyinExtractor struct {
+	client *http.Client
+	opts   Options // of the call; set by ExtractContext
+}
+
+func (e *DouyinExtractor) Name() string {
+	return "douyin"
+}
+
+// Info describes the URLs and media this extractor handles
+func (e *DouyinExtractor) Info() Info {
+	return Info{
+		Name:       "douyin",
+		Title:      "Douyin (抖音)",
+		Kinds:      []URLKind{URLKindSingle},
+		MediaTypes: []MediaType{MediaTypeVideo, MediaTypeImage, MediaTypeAudio},
+		Requires: []Requirement{
+			{Kind: RequiresBrowser, Optional: true, Detail: "used when the share page does not embed the post"},
+		},
+	}
+}
+
+func (e *DouyinExtractor) Match(u *url.URL) bool {
+	// Short links (v.douyin.com/xxx) are resolved in Extract
+	if strings.ToLower(u.Hostname()) == "v.douyin.com" {
+		return true
+	}
+	return douyinVideoIDRegex.MatchString(u.String()) || douyinMusicIDRegex.MatchString(u.Path)
+}
+
+func (e *DouyinExtractor) Extract(rawURL string) (Media, error) {
+	return e.ExtractContext(context.Background(), rawURL, Options{})
+}
+
+// ExtractContext retrieves a Douyin post, showing the fallback browser if opts.Visible
+func (e *DouyinExtractor) ExtractContext(ctx context.Context, rawURL string, opts Options) (Media, error) {
+	client := e.client
+	if client == nil {
+		client = newHTTPClient(opts, 30*time.Second)
+	}
+	call := &DouyinExtractor{client: client, opts: opts}
+	return call.extract(ctx, rawURL)
+}
+
+// extract resolves a URL using the call's client and browser setting
+func (e *DouyinExtractor) extract(ctx context.Context, rawURL string) (Media, error) {
+	// Resolve short link (v.douyin.com -> iesdouyin.com/share/video/{id})
+	if strings.Contains(rawURL, "v.douyin.com") {
+		resolved, err := resolveRedirect(ctx, e.client, rawURL, douyinUserAgent)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve short URL: %w", err)
+		}
+		rawURL = resolved
+	}
+
+	if matches := douyinMusicIDRegex.FindStringSubmatch(rawURL); len(matches) > 1 {
+		return e.extractMusic(ctx, matches[1])
+	}
+
+	matches := douyinVideoIDRegex.FindStringSubmatch(rawURL)
+	if len(matches) < 2 {
+		return nil, fmt.Errorf("could not extract video ID from URL: %s", rawURL)
+	}
+	videoID := matches[1]
+	shareURL := fmt.Sprintf(douyinShareVideoURL, videoID)
+
+	item, err := e.fetchFromSharePage(ctx, shareURL)
+	if err != nil {
+		fmt.Println("  Douyin share page unavailable, trying browser...")
+		var browserErr error
+		item, browserErr = e.fetchFromBrowser(ctx, shareURL)
+		if browserErr != nil {
+			return nil, fmt.Errorf("failed to fetch Douyin video (share page: %v, browser: %w)", err, browserErr)
+		}
+	}
+
+	return item.toMedia(map[string]string{
+		"Referer":    "https://www.douyin.com/",
+		"User-Agent": douyinUserAgent,
+	})
+}
+
+// fetchFromSharePage reads window._ROUTER_DATA from the mobile share page
+func (e *DouyinExtractor) fetchFromSharePage(ctx context.Context, shareURL string) (*awemeDetail, error) {
+	body, err := fetchPage(ctx, e.client, shareURL, douyinUserAgent)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := douyinRouterRegex.FindStringSubmatch(body)
+	if len(matches) < 2 {
+		return nil, fmt.Errorf("could not find video data in page")
+	}
+	return parseDouyinRouterData(matches[1])
+}
+
+// fetchFromBrowser renders the share page in a browser and reads window._ROUTER_DATA
+func (e *DouyinExtractor) fetchFromBrowser(ctx context.Context, shareURL string) (*awemeDetail, error) {
+	state, err := fetchPageState(ctx, shareURL, `() => window._ROUTER_DATA ? JSON.stringify(window._ROUTER_DATA) : ''`, e.opts)
+	if err != nil {
+		return nil, err
+	}
+	return parseDouyinRouterData(state)
+}
+
+// extractMusic returns a music page's original sound as audio
+func (e *DouyinExtractor) extractMusic(ctx context.Context, musicID string) (Media, error) {
+	body, err := fetchPage(ctx, e.client, fmt.Sprintf(douyinShareMusicURL, musicID), douyinUserAgent)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := douyinRouterRegex.FindStringSubmatch(body)
+	if len(matches) < 2 {
+		return nil, fmt.Errorf("could not find music data in page")
+	}
+
+	var state any
+	if err := json.Unmarshal([]byte(matches[1]), &state); err != nil {
+		return nil, fmt.Errorf("failed to parse music data: %w", err)
+	}
+
+	raw := findJSONKey(state, "music_info")
+	if raw == nil {
+		return nil, fmt.Errorf("music %s not found", musicID)
+	}
+
+	// Re-marshal the located subtree into the typed aweme music model
+	b, _ := json.Marshal(raw)
+	var item awemeDetail
+	item.AwemeID = musicID
+	if err := json.Unmarshal(b, &item.Music); err != nil {
+		return nil, fmt.Errorf("failed to parse music data: %w", err)
+	}
+	return item.toAudio()
+}
+
+// parseDouyinRouterData finds the first aweme in the router loader data
+func parseDouyinRouterData(state string) (*awemeDetail, error) {
+	var data any
+	if err := json.Unmarshal([]byte(state), &data); err != nil {
+		return nil, fmt.Errorf("failed to parse video data: %w", err)
+	}
+
+	// The loader key includes the page route (e.g., "video_(id)/page"), so search by field name
+	list, ok := findJSONKey(data, "item_list").([]any)
+	if !ok || len(list) == 0 {
+		return nil, fmt.Errorf("video not found or not accessible")
+	}
+
+	b, _ := json.Marshal(list[0])
+	var item awemeDetail
+	if err := json.Unmarshal(b, &item); err != nil {
+		return nil, fmt.Errorf("failed to parse video data: %w", err)
+	}
+	return &item, nil
+}
+
+// findJSONKey does a depth-first search for the first value stored under key
+func findJSONKey(v any, key string) any {
+	switch val := v.(type) {
+	case map[string]any:
+		if found, ok := val[key]; ok && found != nil {
+			return found
+		}
+		for _, child := range val {
+			if found := findJSONKey(child, key); found != nil {
+				return found
+			}
+		}
+	case []any:
+		for _, child := range val {
+			if found := findJSONKey(child, key); found != nil {
+				return found
+			}
+		}
+	}
+	return nil
+}
+
+func init() {
+	Register(&DouyinExtractor{},
+		"douyin.com",
+		"m.douyin.com",
+		"v.douyin.com",
+		"iesdouyin.com",
+	)
+}
@@ -0,0 +1,97 @@
+package extractor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/guiyumin/vget/internal/core/downloader"
+)
+
+// EpisodePath returns where the audio of a podcast episode is saved in dir:
+// "{date} {title}.{ext}", so files sort chronologically. HLS AAC audio is
+// downloaded raw, as .aac.
+func (a *AudioMedia) EpisodePath(dir string) string {
+	path := filepath.Join(dir, a.EpisodeFilename())
+	if downloader.IsHLSURL(a.URL) && (a.Ext == "m4a" || a.Ext == "aac") {
+		path = strings.TrimSuffix(path, filepath.Ext(path)) + ".aac"
+	}
+	return path
+}
+
+// ShowNotes renders the markdown sidecar of a podcast episode
+func (a *AudioMedia) ShowNotes() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", a.Title)
+	if a.Uploader != "" {
+		fmt.Fprintf(&b, "- Podcast: %s\n", a.Uploader)
+	}
+	if !a.PublishedAt.IsZero() {
+		fmt.Fprintf(&b, "- Published: %s\n", a.PublishedAt.Format("2006-01-02"))
+	}
+	if a.Duration > 0 {
+		fmt.Fprintf(&b, "- Duration: %s\n", FormatDuration(a.Duration))
+	}
+	if a.Description != "" {
+		fmt.Fprintf(&b, "\n%s\n", a.Description)
+	}
+	return b.String()
+}
+
+// SaveEpisode saves a podcast episode into dir: the audio, a show notes sidecar,
+// the episode artwork, and any transcript or chapters the feed publishes. fetch
+// downloads a URL to a path; the audio may be an HLS playlist. Only the audio is
+// required, so the other files' failures are returned as warnings.
+func SaveEpisode(ep *AudioMedia, dir string, fetch func(url, path string) error) (warnings []error, err error) {
+	audioPath := ep.EpisodePath(dir)
+	if err := fetch(ep.URL, audioPath); err != nil {
+		return nil, err
+	}
+	basePath := strings.TrimSuffix(audioPath, filepath.Ext(audioPath))
+
+	if err := os.WriteFile(basePath+".md", []byte(ep.ShowNotes()), 0644); err != nil {
+		warnings = append(warnings, fmt.Errorf("failed to write show notes: %w", err))
+	}
+
+	if ep.CoverURL != "" {
+		if err := fetch(ep.CoverURL, basePath+"."+CoverExt(ep.CoverURL)); err != nil {
+			warnings = append(warnings, fmt.Errorf("failed to download cover: %w", err))
+		}
+	}
+
+	// Transcripts and chapters from podcast:* feed tags
+	for _, a := range ep.Attachments {
+		if err := fetch(a.URL, basePath+"."+a.Ext); err != nil {
+			warnings = append(warnings, fmt.Errorf("failed to download %s: %w", a.Kind, err))
+		}
+	}
+	return warnings, nil
+}
+
+// CoverExt returns the file extension of a cover image URL, "jpg" if it has none
+func CoverExt(coverURL string) string {
+	lower := strings.ToLower(strings.Split(coverURL, "?")[0])
+	switch {
+	case strings.HasSuffix(lower, ".png"):
+		return "png"
+	case strings.HasSuffix(lower, ".webp"):
+		return "webp"
+	default:
+		return "jpg"
+	}
+}
+
+// FormatDuration formats seconds as "m:ss" or "h:mm:ss", "?" if unknown
+func FormatDuration(seconds int) string {
+	if seconds <= 0 {
+		return "?"
+	}
+	h := seconds / 3600
+	m := (seconds % 3600) / 60
+	s := seconds % 60
+	if h > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", h, m, s)
+	}
+	return fmt.Sprintf("%d:%02d", m, s)
+}
@@ -0,0 +1,79 @@
+package extractor
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSaveEpisode(t *testing.T) {
+	dir := t.TempDir()
+	ep := &AudioMedia{
+		ID:          "ep1",
+		Title:       "Pilot",
+		Uploader:    "Show",
+		URL:         "https://example.com/ep1.mp3",
+		Ext:         "mp3",
+		Duration:    3725,
+		Description: "Notes",
+		PublishedAt: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+		CoverURL:    "https://example.com/ep1.png?w=600",
+		Attachments: []Attachment{
+			{Kind: "transcript", URL: "https://example.com/ep1.vtt", Ext: "vtt"},
+			{Kind: "chapters", URL: "https://example.com/missing", Ext: "chapters.json"},
+		},
+	}
+
+	fetched := make(map[string]string)
+	warnings, err := SaveEpisode(ep, dir, func(url, path string) error {
+		if strings.HasSuffix(url, "/missing") {
+			return errors.New("not found")
+		}
+		fetched[url] = path
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("SaveEpisode: %v", err)
+	}
+
+	base := filepath.Join(dir, "2024-05-01 Pilot")
+	want := map[string]string{
+		ep.URL:                        base + ".mp3",
+		ep.CoverURL:                   base + ".png",
+		"https://example.com/ep1.vtt": base + ".vtt",
+	}
+	for url, path := range want {
+		if fetched[url] != path {
+			t.Errorf("%s saved to %q, want %q", url, fetched[url], path)
+		}
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0].Error(), "chapters") {
+		t.Errorf("warnings = %v, want the chapters failure", warnings)
+	}
+
+	notes, err := os.ReadFile(base + ".md")
+	if err != nil {
+		t.Fatalf("show notes: %v", err)
+	}
+	wantNotes := "# Pilot\n\n- Podcast: Show\n- Published: 2024-05-01\n- Duration: 1:02:05\n\nNotes\n"
+	if string(notes) != wantNotes {
+		t.Errorf("show notes = %q, want %q", notes, wantNotes)
+	}
+
+	// Without the audio nothing else is saved
+	if _, err := SaveEpisode(ep, t.TempDir(), func(url, path string) error {
+		return errors.New("offline")
+	}); err == nil {
+		t.Error("SaveEpisode succeeded without the audio")
+	}
+}
+
+func TestEpisodePathHLS(t *testing.T) {
+	ep := &AudioMedia{ID: "1", Title: "Space", URL: "https://example.com/audio.m3u8", Ext: "m4a"}
+	if got, want := ep.EpisodePath("dir"), filepath.Join("dir", "Space.aac"); got != want {
+		t.Errorf("EpisodePath = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,138 @@
+package extractor
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+const testRSSFeed = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:itunes="http://www.itunes.com/dtds/podcast-1.0.dtd"
+	xmlns:podcast="https://podcastindex.org/namespace/1.0"
+	xmlns:content="http://purl.org/rss/1.0/modules/content/">
+<channel>
+	<title>Self Hosted Show</title>
+	<itunes:author>Jane Doe</itunes:author>
+	<description>A show</description>
+	<image><url>https://example.com/rss.jpg</url></image>
+	<itunes:image href="https://example.com/cover.jpg"/>
+	<item>
+		<title>Episode 1</title>
+		<guid>ep-1</guid>
+		<pubDate>Mon, 02 Jan 2023 10:00:00 +0000</pubDate>
+		<itunes:duration>1:02:03</itunes:duration>
+		<enclosure url="https://example.com/ep1.mp3?token=abc" type="audio/mpeg" length="1"/>
+	</item>
+	<item>
+		<title>Raw title</title>
+		<itunes:title>Episode 2</itunes:title>
+		<guid isPermaLink="false">ep-2</guid>
+		<pubDate>Tue, 3 Jan 2023 10:00:00 GMT</pubDate>
+		<itunes:duration>3600</itunes:duration>
+		<description>short</description>
+		<content:encoded><![CDATA[<p>Full <b>notes</b></p>]]></content:encoded>
+		<enclosure url="https://example.com/download?id=2" type="audio/x-m4a" length="1"/>
+		<podcast:transcript url="https://example.com/ep2.vtt" type="text/vtt"/>
+		<podcast:chapters url="https://example.com/ep2.json" type="application/json+chapters"/>
+	</item>
+	<item>
+		<title>Text only post</title>
+	</item>
+</channel>
+</rss>`
+
+const testAtomFeed = `<?xml version="1.0" encoding="utf-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+	<title>Atom Cast</title>
+	<author><name>Someone</name></author>
+	<entry>
+		<id>urn:1</id>
+		<title>First</title>
+		<published>2024-05-01T08:00:00Z</published>
+		<link rel="alternate" href="https://example.com/1"/>
+		<link rel="enclosure" href="https://example.com/1.ogg" type="audio/ogg"/>
+	</entry>
+</feed>`
+
+func TestParseFeedRSS(t *testing.T) {
+	media, err := ParseFeed([]byte(testRSSFeed))
+	if err != nil {
+		t.Fatalf("ParseFeed() error = %v", err)
+	}
+
+	if media.Title != "Self Hosted Show" || media.Uploader != "Jane Doe" {
+		t.Errorf("channel = %q by %q", media.Title, media.Uploader)
+	}
+	if media.CoverURL != "https://example.com/cover.jpg" {
+		t.Errorf("CoverURL = %q, want itunes:image", media.CoverURL)
+	}
+	if len(media.Episodes) != 2 {
+		t.Fatalf("got %d episodes, want 2", len(media.Episodes))
+	}
+
+	// Newest first
+	ep := media.Episodes[0]
+	if ep.ID != "ep-2" || ep.Title != "Episode 2" {
+		t.Errorf("first episode = %q (%q), want ep-2 (Episode 2)", ep.ID, ep.Title)
+	}
+	if ep.Ext != "m4a" || ep.Duration != 3600 || ep.Description != "Full notes" {
+		t.Errorf("episode 2 = ext %q, duration %d, notes %q", ep.Ext, ep.Duration, ep.Description)
+	}
+	if len(ep.Attachments) != 2 || ep.Attachments[0].Ext != "vtt" || ep.Attachments[1].Kind != "chapters" {
+		t.Errorf("attachments = %+v", ep.Attachments)
+	}
+
+	ep = media.Episodes[1]
+	want := time.Date(2023, 1, 2, 10, 0, 0, 0, time.UTC)
+	if !ep.PublishedAt.Equal(want) || ep.Duration != 3723 || ep.Ext != "mp3" {
+		t.Errorf("episode 1 = published %v, duration %d, ext %q", ep.PublishedAt, ep.Duration, ep.Ext)
+	}
+}
+
+func TestParseFeedAtom(t *testing.T) {
+	media, err := ParseFeed([]byte(testAtomFeed))
+	if err != nil {
+		t.Fatalf("ParseFeed() error = %v", err)
+	}
+	if media.Title != "Atom Cast" || len(media.Episodes) != 1 {
+		t.Fatalf("got %q with %d episodes", media.Title, len(media.Episodes))
+	}
+	if ep := media.Episodes[0]; ep.URL != "https://example.com/1.ogg" || ep.Ext != "ogg" {
+		t.Errorf("episode = %q (%q)", ep.URL, ep.Ext)
+	}
+}
+
+func TestParseFeedRejectsHTML(t *testing.T) {
+	if _, err := ParseFeed([]byte("<html><body>hi</body></html>")); err == nil {
+		t.Error("expected error for HTML document")
+	}
+}
+
+func TestDetectFeed(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The private feed needs its cookie
+		if r.URL.Path == "/feed" && r.Header.Get("Cookie") == "token=1" {
+			w.Header().Set("Content-Type", "application/xml")
+			w.Write([]byte(testRSSFeed))
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte("<html><body>hi</body></html>"))
+	}))
+	defer srv.Close()
+
+	if ext := DetectFeed(context.Background(), srv.URL+"/feed", Options{Cookie: "token=1"}); ext != feedExtractor {
+		t.Errorf("feed with cookie: got %v, want the feed extractor", ext)
+	}
+	if ext := DetectFeed(context.Background(), srv.URL+"/feed", Options{}); ext != nil {
+		t.Errorf("feed without cookie: got %v, want nil", ext)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if ext := DetectFeed(ctx, srv.URL+"/feed", Options{Cookie: "token=1"}); ext != nil {
+		t.Errorf("cancelled: got %v, want nil", ext)
+	}
+}
@@ -0,0 +1,188 @@
+package extractor
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/guiyumin/vget/internal/core/config"
+)
+
+// URLKind is a kind of URL an extractor accepts
+type URLKind string
+
+const (
+	URLKindSingle   URLKind = "single"   // one post, video or episode
+	URLKindPlaylist URLKind = "playlist" // a show, feed or collection
+	URLKindUser     URLKind = "user"     // everything posted by an account
+	URLKindLive     URLKind = "live"     // live streams and their replays
+)
+
+// Requirement kinds
+const (
+	RequiresCookie  = "cookie"  // a cookie or token stored with 'vget config set'
+	RequiresLogin   = "login"   // a login command
+	RequiresBrowser = "browser" // a local Chrome/Chromium
+	RequiresDocker  = "docker"  // only works in the Docker image
+)
+
+// Requirement is something an extractor needs from the user or environment
+type Requirement struct {
+	Kind     string `json:"kind"`
+	Optional bool   `json:"optional"`
+	Detail   string `json:"detail"`
+}
+
+// Info describes what an extractor handles
+type Info struct {
+	Name       string        `json:"name"`
+	Title      string        `json:"title"`
+	Hosts      []string      `json:"hosts"`
+	Matches    string        `json:"matches,omitempty"` // URLs matched by something other than host
+	Kinds      []URLKind     `json:"kinds"`
+	MediaTypes []MediaType   `json:"media_types"`
+	Requires   []Requirement `json:"requires,omitempty"`
+}
+
+// Describer is implemented by extractors that report their capabilities
+type Describer interface {
+	Info() Info
+}
+
+// Describe returns e's capabilities, with hosts filled in from the registry
+func Describe(e Extractor) Info {
+	info := Info{Name: e.Name(), Title: e.Name()}
+	if d, ok := e.(Describer); ok {
+		info = d.Info()
+	}
+
+	info.Hosts = nil
+	for host, registered := range extractorsByHost {
+		if registered == e {
+			info.Hosts = append(info.Hosts, host)
+		}
+	}
+	sort.Strings(info.Hosts)
+	return info
+}
+
+// Infos describes every built-in extractor: host-based ones sorted by name, then
+// the ones matched by extension or content, then the fallbacks for unknown sites
+func Infos() []Info {
+	var infos []Info
+	for _, e := range List() {
+		infos = append(infos, Describe(e))
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Name < infos[j].Name
+	})
+
+	for _, e := range []Extractor{feedExtractor, m3u8Extractor, fallbackExtractor, NewStaticPageExtractor(), &BrowserExtractor{}} {
+		if e != nil {
+			infos = append(infos, Describe(e))
+		}
+	}
+	return infos
+}
+
+// Resolution sources
+const (
+	SourceExtension = "extension" // direct file or HLS extension
+	SourceFeed      = "feed"      // podcast feed extension
+	SourceHost      = "host"      // built-in extractor for the host
+	SourceSite      = "site"      // sites.yml rule
+	SourceFallback  = "fallback"  // page metadata, then headless browser
+)
+
+// Resolution explains which handler takes a URL
+type Resolution struct {
+	URL       string `json:"url"`
+	Source    string `json:"source"`
+	Extractor string `json:"extractor"`
+	Rule      string `json:"rule,omitempty"` // key of the matching sites.yml rule
+	Reason    string `json:"reason"`
+
+	// Site is the matching sites.yml rule; not serialized since it may hold cookies
+	Site *config.Site `json:"-"`
+}
+
+// Resolve reports which extractor, sites.yml rule or fallback would handle rawURL
+// and why, following the same order as a download. sites may be nil.
+func Resolve(rawURL string, sites *config.SitesConfig) (*Resolution, error) {
+	normalized, err := NormalizeURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &Resolution{URL: normalized}
+	e, source, reason := explainMatch(normalized)
+	if e != nil {
+		res.Source = source
+		res.Extractor = e.Name()
+		res.Reason = reason
+		return res, nil
+	}
+
+	if site := sites.MatchSite(normalized); site != nil {
+		res.Source = SourceSite
+		res.Extractor = "browser"
+		res.Rule = site.Key()
+		res.Site = site
+		res.Reason = fmt.Sprintf("%s; matched sites.yml rule '%s', captured in a browser as %s", reason, site.Key(), site.MediaType())
+		return res, nil
+	}
+
+	res.Source = SourceFallback
+	res.Extractor = "static-page"
+	res.Reason = reason + "; no sites.yml rule matched, so the page is checked for a feed and media metadata, with a headless browser as the last resort"
+	return res, nil
+}
+
+// Markdown renders infos as the supported-sites table in sites.md
+func Markdown(infos []Info) string {
+	var b strings.Builder
+	b.WriteString("| Source | URL | URL kinds | Type | Requires |\n")
+	b.WriteString("| ------ | --- | --------- | ---- | -------- |\n")
+	for _, info := range infos {
+		where := info.Matches
+		if len(info.Hosts) > 0 {
+			where = strings.Join(info.Hosts, ", ")
+		}
+		fmt.Fprintf(&b, "| %s | %s | %s | %s | %s |\n",
+			info.Title, where, info.KindList(), info.MediaTypeList(), info.RequirementList())
+	}
+	return b.String()
+}
+
+// KindList returns the URL kinds as a comma-separated list
+func (i Info) KindList() string {
+	kinds := make([]string, len(i.Kinds))
+	for n, k := range i.Kinds {
+		kinds[n] = string(k)
+	}
+	return strings.Join(kinds, ", ")
+}
+
+// MediaTypeList returns the media types as a comma-separated list
+func (i Info) MediaTypeList() string {
+	types := make([]string, len(i.MediaTypes))
+	for n, t := range i.MediaTypes {
+		types[n] = string(t)
+	}
+	return strings.Join(types, ", ")
+}
+
+// RequirementList summarizes the requirements, marking optional ones
+func (i Info) RequirementList() string {
+	if len(i.Requires) == 0 {
+		return "-"
+	}
+	reqs := make([]string, len(i.Requires))
+	for n, r := range i.Requires {
+		reqs[n] = r.Kind
+		if r.Optional {
+			reqs[n] += " (optional)"
+		}
+	}
+	return strings.Join(reqs, ", ")
+}
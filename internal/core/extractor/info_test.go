@@ -0,0 +1,61 @@
+package extractor
+
+import (
+	"testing"
+
+	"github.com/guiyumin/vget/internal/core/config"
+)
+
+func TestResolve(t *testing.T) {
+	sites := &config.SitesConfig{Sites: []config.Site{{Match: "videos.example.org", Type: "mp4"}}}
+
+	tests := []struct {
+		url       string
+		source    string
+		extractor string
+	}{
+		{"https://x.com/user/status/123", SourceHost, "twitter"},
+		{"www.bilibili.com/video/BV1GJ411x7h7", SourceHost, "bilibili"},
+		{"https://cdn.example.com/live/index.m3u8", SourceExtension, "m3u8"},
+		{"https://cdn.example.com/file.mp4", SourceExtension, "direct"},
+		{"https://example.com/podcast.rss", SourceFeed, "feed"},
+		{"https://videos.example.org/watch/1", SourceSite, "browser"},
+		{"https://x.com/home", SourceFallback, "static-page"},
+		{"https://unknown.example.net/page", SourceFallback, "static-page"},
+	}
+
+	for _, tt := range tests {
+		res, err := Resolve(tt.url, sites)
+		if err != nil {
+			t.Fatalf("Resolve(%q): %v", tt.url, err)
+		}
+		if res.Source != tt.source || res.Extractor != tt.extractor {
+			t.Errorf("Resolve(%q) = %s/%s, want %s/%s (%s)", tt.url, res.Source, res.Extractor, tt.source, tt.extractor, res.Reason)
+		}
+		if got := Match(tt.url); (got != nil) != (tt.source != SourceSite && tt.source != SourceFallback) {
+			t.Errorf("Match(%q) disagrees with Resolve: %v", tt.url, got)
+		}
+	}
+
+	if _, err := Resolve("not a url", nil); err == nil {
+		t.Error("expected error for invalid URL")
+	}
+}
+
+func TestInfosCoverRegistry(t *testing.T) {
+	seen := map[string]bool{}
+	for _, info := range Infos() {
+		if len(info.Kinds) == 0 || len(info.MediaTypes) == 0 {
+			t.Errorf("%s: missing kinds or media types", info.Name)
+		}
+		if len(info.Hosts) == 0 && info.Matches == "" {
+			t.Errorf("%s: no hosts and no match description", info.Name)
+		}
+		seen[info.Name] = true
+	}
+	for _, e := range List() {
+		if !seen[e.Name()] {
+			t.Errorf("%s is registered but not described", e.Name())
+		}
+	}
+}
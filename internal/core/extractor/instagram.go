@@ -1,24 +1,551 @@
 package extractor
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"math/big"
+	"net/http"
 	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
 )
 
-// InstagramExtractor handles Instagram video downloads
-type InstagramExtractor struct{}
+const (
+	instagramAppID       = "936619743392459"
+	instagramGraphQLURL  = "https://www.instagram.com/graphql/query"
+	instagramPostDocID   = "8845758582119845"
+	instagramMediaInfo   = "https://i.instagram.com/api/v1/media/%s/info/"
+	instagramReelsMedia  = "https://i.instagram.com/api/v1/feed/reels_media/?reel_ids=%s"
+	instagramUserAgent   = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
+	instagramCookieSite  = "instagram"
+	instagramShortcodeAZ = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-_"
+)
+
+var (
+	// Matches /p/{code}, /reel/{code}, /reels/{code}, /tv/{code} (optionally prefixed by a username)
+	instagramPostRegex = regexp.MustCompile(`/(?:p|reels?|tv)/([A-Za-z0-9_-]+)`)
+	// Matches /stories/{user}/{pk}
+	instagramStoryRegex = regexp.MustCompile(`/stories/([^/]+)/(\d+)`)
+	// Matches /stories/highlights/{id}
+	instagramHighlightRegex = regexp.MustCompile(`/stories/highlights/(\d+)`)
+)
+
+// InstagramExtractor handles Instagram posts, reels, carousels and (when logged in) stories
+type InstagramExtractor struct {
+	client *http.Client
+	opts   Options // of the call; set by ExtractContext
+}
 
 func (e *InstagramExtractor) Name() string {
 	return "instagram"
 }
 
+// Info describes the URLs and media this extractor handles
+func (e *InstagramExtractor) Info() Info {
+	return Info{
+		Name:       "instagram",
+		Title:      "Instagram",
+		Kinds:      []URLKind{URLKindSingle, URLKindPlaylist},
+		MediaTypes: []MediaType{MediaTypeVideo, MediaTypeImage},
+		Requires: []Requirement{
+			{Kind: RequiresBrowser, Optional: true, Detail: "log in once with vget --visible <url> for private posts, stories and highlights"},
+		},
+	}
+}
+
 func (e *InstagramExtractor) Match(u *url.URL) bool {
-	// Host matching is done by registry
-	return true
+	// Host matching is done by registry, check path pattern
+	return instagramPostRegex.MatchString(u.Path) ||
+		instagramStoryRegex.MatchString(u.Path) ||
+		instagramHighlightRegex.MatchString(u.Path)
+}
+
+func (e *InstagramExtractor) Extract(rawURL string) (Media, error) {
+	return e.ExtractContext(context.Background(), rawURL, Options{})
+}
+
+// ExtractContext retrieves an Instagram post or story, showing the fallback browser
+// if opts.Visible
+func (e *InstagramExtractor) ExtractContext(ctx context.Context, rawURL string, opts Options) (Media, error) {
+	client := e.client
+	if client == nil {
+		client = newHTTPClient(opts, 30*time.Second)
+	}
+	call := &InstagramExtractor{client: client, opts: opts}
+	return call.extract(ctx, rawURL)
+}
+
+// extract resolves a URL using the call's client and browser setting
+func (e *InstagramExtractor) extract(ctx context.Context, rawURL string) (Media, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL: %w", err)
+	}
+
+	loggedIn := siteCookieValue(instagramCookieSite, "sessionid") != ""
+
+	// Stories and highlights are only visible to logged-in users
+	if matches := instagramHighlightRegex.FindStringSubmatch(u.Path); len(matches) > 1 {
+		if !loggedIn {
+			return nil, fmt.Errorf("instagram highlights require login (run with --visible and log in once)")
+		}
+		return e.extractHighlight(ctx, matches[1])
+	}
+	if matches := instagramStoryRegex.FindStringSubmatch(u.Path); len(matches) > 2 {
+		if !loggedIn {
+			return nil, fmt.Errorf("instagram stories require login (run with --visible and log in once)")
+		}
+		item, err := e.fetchMediaInfo(ctx, matches[2])
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch story: %w", err)
+		}
+		return item.toMedia()
+	}
+
+	matches := instagramPostRegex.FindStringSubmatch(u.Path)
+	if len(matches) < 2 {
+		return nil, fmt.Errorf("could not extract post shortcode from URL")
+	}
+	shortcode := matches[1]
+	mediaID := instagramShortcodeToID(shortcode)
+
+	// Logged in: the private API returns original resolutions and private posts
+	if loggedIn && mediaID != "" {
+		if item, err := e.fetchMediaInfo(ctx, mediaID); err == nil {
+			return item.toMedia()
+		}
+	}
+
+	// Anonymous GraphQL works for public posts
+	item, gqlErr := e.fetchFromGraphQL(ctx, shortcode)
+	if gqlErr == nil {
+		return item.toMedia()
+	}
+
+	// Fall back to a real browser session (handles login walls and age gates)
+	fmt.Println("  Instagram API unavailable, trying browser...")
+	item, err = e.fetchFromBrowser(ctx, rawURL, mediaID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch post (api: %v, browser: %w)", gqlErr, err)
+	}
+	return item.toMedia()
+}
+
+// fetchMediaInfo calls the private media info API using the saved session cookies
+func (e *InstagramExtractor) fetchMediaInfo(ctx context.Context, mediaID string) (*igMediaItem, error) {
+	body, err := e.apiGet(ctx, fmt.Sprintf(instagramMediaInfo, mediaID))
+	if err != nil {
+		return nil, err
+	}
+	return parseInstagramMediaInfo(body)
+}
+
+// extractHighlight returns every item of a story highlight
+func (e *InstagramExtractor) extractHighlight(ctx context.Context, highlightID string) (Media, error) {
+	reelID := "highlight:" + highlightID
+	body, err := e.apiGet(ctx, fmt.Sprintf(instagramReelsMedia, url.QueryEscape(reelID)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch highlight: %w", err)
+	}
+
+	var data struct {
+		Reels map[string]struct {
+			Title string        `json:"title"`
+			User  igUser        `json:"user"`
+			Items []igMediaItem `json:"items"`
+		} `json:"reels"`
+	}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, fmt.Errorf("failed to parse highlight response: %w", err)
+	}
+
+	reel, ok := data.Reels[reelID]
+	if !ok || len(reel.Items) == 0 {
+		return nil, fmt.Errorf("highlight %s not found or empty", highlightID)
+	}
+
+	// Treat the highlight as one carousel
+	item := &igMediaItem{
+		ID:            highlightID,
+		MediaType:     igMediaTypeCarousel,
+		User:          reel.User,
+		CarouselMedia: reel.Items,
+	}
+	if reel.Title != "" {
+		item.Caption = &igCaption{Text: reel.Title}
+	}
+	return item.toMedia()
+}
+
+// fetchFromGraphQL queries the public post GraphQL endpoint by shortcode
+func (e *InstagramExtractor) fetchFromGraphQL(ctx context.Context, shortcode string) (*igMediaItem, error) {
+	variables, _ := json.Marshal(map[string]interface{}{
+		"shortcode":               shortcode,
+		"fetch_tagged_user_count": nil,
+		"hoisted_comment_id":      nil,
+		"hoisted_reply_id":        nil,
+	})
+
+	form := url.Values{}
+	form.Set("variables", string(variables))
+	form.Set("doc_id", instagramPostDocID)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", instagramGraphQLURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	e.setHeaders(req)
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GraphQL request failed with status %d", resp.StatusCode)
+	}
+
+	var data struct {
+		Data struct {
+			Media *igShortcodeMedia `json:"xdt_shortcode_media"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return nil, fmt.Errorf("failed to parse GraphQL response: %w", err)
+	}
+	if data.Data.Media == nil {
+		return nil, fmt.Errorf("post not found, private or age-restricted")
+	}
+
+	return data.Data.Media.toMediaItem(), nil
+}
+
+// fetchFromBrowser loads the post in a browser with the shared cookie store and calls the
+// media info API from the page context so the browser's login session is used
+func (e *InstagramExtractor) fetchFromBrowser(ctx context.Context, postURL, mediaID string) (*igMediaItem, error) {
+	if mediaID == "" {
+		return nil, fmt.Errorf("could not derive media ID")
+	}
+
+	script := fmt.Sprintf(`async () => {
+		try {
+			const resp = await fetch('/api/v1/media/%s/info/', {
+				headers: {'X-IG-App-ID': '%s'},
+				credentials: 'include',
+			});
+			return resp.ok ? await resp.text() : '';
+		} catch (e) {
+			return '';
+		}
+	}`, mediaID, instagramAppID)
+
+	body, err := fetchPageStateWithCookies(ctx, postURL, script, e.opts, instagramCookieSite, "instagram")
+	if err != nil {
+		return nil, fmt.Errorf("%w (login may be required, run with --visible to log in)", err)
+	}
+	return parseInstagramMediaInfo([]byte(body))
+}
+
+// apiGet performs an authenticated GET against the private API
+func (e *InstagramExtractor) apiGet(ctx context.Context, reqURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	e.setHeaders(req)
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("request failed with status %d", resp.StatusCode)
+	}
+	return body, nil
+}
+
+// setHeaders adds the web app headers and saved session cookies
+func (e *InstagramExtractor) setHeaders(req *http.Request) {
+	req.Header.Set("User-Agent", instagramUserAgent)
+	req.Header.Set("X-IG-App-ID", instagramAppID)
+	req.Header.Set("X-Requested-With", "XMLHttpRequest")
+	req.Header.Set("Referer", "https://www.instagram.com/")
+
+	if cookie := siteCookieHeader(instagramCookieSite); cookie != "" {
+		req.Header.Set("Cookie", cookie)
+	}
+	if csrf := siteCookieValue(instagramCookieSite, "csrftoken"); csrf != "" {
+		req.Header.Set("X-CSRFToken", csrf)
+	}
+}
+
+func parseInstagramMediaInfo(body []byte) (*igMediaItem, error) {
+	var data struct {
+		Items []igMediaItem `json:"items"`
+	}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, fmt.Errorf("failed to parse media info: %w", err)
+	}
+	if len(data.Items) == 0 {
+		return nil, fmt.Errorf("media not found or not accessible")
+	}
+	return &data.Items[0], nil
+}
+
+// Media types used by the private API
+const (
+	igMediaTypeImage    = 1
+	igMediaTypeVideo    = 2
+	igMediaTypeCarousel = 8
+)
+
+type igUser struct {
+	Username string `json:"username"`
+	FullName string `json:"full_name"`
+}
+
+type igCaption struct {
+	Text string `json:"text"`
+}
+
+type igCandidate struct {
+	URL    string `json:"url"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+}
+
+// igMediaItem is the private API media model (also used for converted GraphQL results)
+type igMediaItem struct {
+	ID             string        `json:"id"`
+	Code           string        `json:"code"`
+	MediaType      int           `json:"media_type"`
+	Caption        *igCaption    `json:"caption"`
+	User           igUser        `json:"user"`
+	VideoDuration  float64       `json:"video_duration"`
+	VideoVersions  []igCandidate `json:"video_versions"`
+	ImageVersions2 struct {
+		Candidates []igCandidate `json:"candidates"`
+	} `json:"image_versions2"`
+	CarouselMedia []igMediaItem `json:"carousel_media"`
+}
+
+// igShortcodeMedia is the GraphQL shortcode media model
+type igShortcodeMedia struct {
+	ID               string  `json:"id"`
+	Shortcode        string  `json:"shortcode"`
+	IsVideo          bool    `json:"is_video"`
+	VideoURL         string  `json:"video_url"`
+	VideoDuration    float64 `json:"video_duration"`
+	DisplayURL       string  `json:"display_url"`
+	DisplayResources []struct {
+		Src          string `json:"src"`
+		ConfigWidth  int    `json:"config_width"`
+		ConfigHeight int    `json:"config_height"`
+	} `json:"display_resources"`
+	Dimensions struct {
+		Width  int `json:"width"`
+		Height int `json:"height"`
+	} `json:"dimensions"`
+	Owner              igUser `json:"owner"`
+	EdgeMediaToCaption struct {
+		Edges []struct {
+			Node struct {
+				Text string `json:"text"`
+			} `json:"node"`
+		} `json:"edges"`
+	} `json:"edge_media_to_caption"`
+	EdgeSidecarToChildren *struct {
+		Edges []struct {
+			Node igShortcodeMedia `json:"node"`
+		} `json:"edges"`
+	} `json:"edge_sidecar_to_children"`
+}
+
+// toMediaItem converts a GraphQL node into the private API model
+func (m *igShortcodeMedia) toMediaItem() *igMediaItem {
+	item := &igMediaItem{
+		ID:            m.ID,
+		Code:          m.Shortcode,
+		User:          m.Owner,
+		VideoDuration: m.VideoDuration,
+	}
+	if len(m.EdgeMediaToCaption.Edges) > 0 {
+		item.Caption = &igCaption{Text: m.EdgeMediaToCaption.Edges[0].Node.Text}
+	}
+
+	if m.EdgeSidecarToChildren != nil && len(m.EdgeSidecarToChildren.Edges) > 0 {
+		item.MediaType = igMediaTypeCarousel
+		for _, edge := range m.EdgeSidecarToChildren.Edges {
+			item.CarouselMedia = append(item.CarouselMedia, *edge.Node.toMediaItem())
+		}
+		return item
+	}
+
+	// display_url is the largest rendition; display_resources lists smaller ones
+	item.ImageVersions2.Candidates = append(item.ImageVersions2.Candidates, igCandidate{
+		URL:    m.DisplayURL,
+		Width:  m.Dimensions.Width,
+		Height: m.Dimensions.Height,
+	})
+	for _, r := range m.DisplayResources {
+		item.ImageVersions2.Candidates = append(item.ImageVersions2.Candidates, igCandidate{
+			URL:    r.Src,
+			Width:  r.ConfigWidth,
+			Height: r.ConfigHeight,
+		})
+	}
+
+	if m.IsVideo && m.VideoURL != "" {
+		item.MediaType = igMediaTypeVideo
+		item.VideoVersions = []igCandidate{{
+			URL:    m.VideoURL,
+			Width:  m.Dimensions.Width,
+			Height: m.Dimensions.Height,
+		}}
+	} else {
+		item.MediaType = igMediaTypeImage
+	}
+	return item
+}
+
+// toMedia converts a post into VideoMedia, ImageMedia or MultiVideoMedia (mixed carousels)
+func (m *igMediaItem) toMedia() (Media, error) {
+	id := m.Code
+	if id == "" {
+		id = strings.Split(m.ID, "_")[0]
+	}
+	var title string
+	if m.Caption != nil {
+		title = truncateText(m.Caption.Text, 100)
+	}
+	if title == "" {
+		title = id
+	}
+	uploader := m.User.Username
+
+	children := []igMediaItem{*m}
+	if m.MediaType == igMediaTypeCarousel {
+		children = m.CarouselMedia
+	}
+
+	var videos []*VideoMedia
+	var images []Image
+	for i := range children {
+		child := &children[i]
+		if len(child.VideoVersions) > 0 {
+			videos = append(videos, &VideoMedia{
+				ID:       fmt.Sprintf("%s_%d", id, len(videos)+1),
+				Title:    title,
+				Uploader: uploader,
+				Duration: int(child.VideoDuration),
+				Formats:  child.videoFormats(),
+			})
+			continue
+		}
+		if img := child.bestImage(); img != nil {
+			images = append(images, *img)
+		}
+	}
+
+	switch {
+	case len(videos) == 1 && len(images) == 0:
+		videos[0].ID = id
+		return videos[0], nil
+	case len(videos) > 0:
+		return &MultiVideoMedia{
+			ID:       id,
+			Title:    title,
+			Uploader: uploader,
+			Videos:   videos,
+			Images:   images,
+		}, nil
+	case len(images) > 0:
+		return &ImageMedia{
+			ID:       id,
+			Title:    title,
+			Uploader: uploader,
+			Images:   images,
+		}, nil
+	}
+	return nil, fmt.Errorf("no media found in post")
+}
+
+// videoFormats returns the distinct video renditions, largest first
+func (m *igMediaItem) videoFormats() []VideoFormat {
+	seen := make(map[string]bool)
+	var formats []VideoFormat
+	for _, v := range m.VideoVersions {
+		if v.URL == "" || seen[v.URL] {
+			continue
+		}
+		seen[v.URL] = true
+		formats = append(formats, VideoFormat{
+			URL:     v.URL,
+			Quality: qualityFromHeight(v.Height),
+			Ext:     "mp4",
+			Width:   v.Width,
+			Height:  v.Height,
+			Headers: map[string]string{"Referer": "https://www.instagram.com/"},
+		})
+	}
+	sort.SliceStable(formats, func(i, j int) bool {
+		return formats[i].Width*formats[i].Height > formats[j].Width*formats[j].Height
+	})
+	return formats
+}
+
+// bestImage returns the original-resolution image candidate
+func (m *igMediaItem) bestImage() *Image {
+	var best *igCandidate
+	for i := range m.ImageVersions2.Candidates {
+		c := &m.ImageVersions2.Candidates[i]
+		if c.URL == "" {
+			continue
+		}
+		if best == nil || c.Width*c.Height > best.Width*best.Height {
+			best = c
+		}
+	}
+	if best == nil {
+		return nil
+	}
+	return &Image{
+		URL:    best.URL,
+		Ext:    imageExtFromURL(best.URL),
+		Width:  best.Width,
+		Height: best.Height,
+	}
 }
 
-func (e *InstagramExtractor) Extract(url string) (Media, error) {
-	return nil, fmt.Errorf("instagram support coming soon")
+// instagramShortcodeToID decodes a post shortcode (base64 URL alphabet) into its numeric media ID
+func instagramShortcodeToID(shortcode string) string {
+	// Private post shortcodes carry a suffix after the first 11 characters
+	if len(shortcode) > 11 {
+		shortcode = shortcode[:11]
+	}
+	id := new(big.Int)
+	for _, c := range shortcode {
+		idx := strings.IndexRune(instagramShortcodeAZ, c)
+		if idx < 0 {
+			return ""
+		}
+		id.Mul(id, big.NewInt(64))
+		id.Add(id, big.NewInt(int64(idx)))
+	}
+	return id.String()
 }
 
 func init() {
@@ -1,20 +1,34 @@
 package extractor
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
 	"regexp"
+	"time"
 )
 
 // iTunesExtractor handles Apple Podcasts downloads via iTunes API
-type iTunesExtractor struct{}
+type iTunesExtractor struct {
+	client *http.Client // tests inject a replay client; nil uses a fresh one per call
+}
 
 func (e *iTunesExtractor) Name() string {
 	return "itunes"
 }
 
+// Info describes the URLs and media this extractor handles
+func (e *iTunesExtractor) Info() Info {
+	return Info{
+		Name:       "itunes",
+		Title:      "Apple Podcasts",
+		Kinds:      []URLKind{URLKindSingle, URLKindPlaylist},
+		MediaTypes: []MediaType{MediaTypeAudio},
+	}
+}
+
 // Match URLs like:
 // https://podcasts.apple.com/podcast/id173001861
 // https://podcasts.apple.com/us/podcast/dan-carlins-hardcore-history/id173001861
@@ -27,6 +41,16 @@ func (e *iTunesExtractor) Match(u *url.URL) bool {
 }
 
 func (e *iTunesExtractor) Extract(rawURL string) (Media, error) {
+	return e.ExtractContext(context.Background(), rawURL, Options{})
+}
+
+// ExtractContext retrieves an Apple Podcasts episode or show
+func (e *iTunesExtractor) ExtractContext(ctx context.Context, rawURL string, opts Options) (Media, error) {
+	client := e.client
+	if client == nil {
+		client = newHTTPClient(opts, 30*time.Second)
+	}
+
 	u, err := url.Parse(rawURL)
 	if err != nil {
 		return nil, fmt.Errorf("invalid URL: %w", err)
@@ -42,27 +66,21 @@ func (e *iTunesExtractor) Extract(rawURL string) (Media, error) {
 
 	// If episode ID provided, fetch that specific episode
 	if episodeID != "" {
-		return e.extractEpisode(podcastID, episodeID)
+		return e.extractEpisode(ctx, client, podcastID, episodeID)
 	}
 
-	// Otherwise list episodes from the podcast
-	return e.listEpisodes()
+	// Otherwise list episodes from the podcast's feed
+	return e.listEpisodes(ctx, client, podcastID)
 }
 
-func (e *iTunesExtractor) extractEpisode(podcastID, episodeID string) (*AudioMedia, error) {
+func (e *iTunesExtractor) extractEpisode(ctx context.Context, client *http.Client, podcastID, episodeID string) (*AudioMedia, error) {
 	// Lookup episode by ID
 	url := fmt.Sprintf("https://itunes.apple.com/lookup?id=%s&entity=podcastEpisode", podcastID)
 
-	resp, err := http.Get(url)
+	result, err := e.lookup(ctx, client, url)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
-
-	var result iTunesLookupResponse
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, err
-	}
 
 	// Find the specific episode
 	for _, item := range result.Results {
@@ -89,10 +107,51 @@ func (e *iTunesExtractor) extractEpisode(podcastID, episodeID string) (*AudioMed
 	return nil, fmt.Errorf("episode not found")
 }
 
-func (e *iTunesExtractor) listEpisodes() (*AudioMedia, error) {
-	// Return error suggesting to use specific episode URL
-	// We could list episodes here but for now keep it simple
-	return nil, fmt.Errorf("please provide a specific episode URL. Use 'vget search --podcast <name>' to find episodes, or visit the podcast page and select an episode")
+// listEpisodes resolves the show's RSS feed via the lookup API and lists episodes from it
+func (e *iTunesExtractor) listEpisodes(ctx context.Context, client *http.Client, podcastID string) (*PodcastMedia, error) {
+	result, err := e.lookup(ctx, client, fmt.Sprintf("https://itunes.apple.com/lookup?id=%s", podcastID))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range result.Results {
+		if item.FeedURL == "" {
+			continue
+		}
+		media, err := (&FeedExtractor{client: client}).fetchPodcast(ctx, item.FeedURL, Options{})
+		if err != nil {
+			return nil, err
+		}
+		media.ID = podcastID
+		if media.Title == "" {
+			media.Title = item.CollectionName
+		}
+		if media.Uploader == "" {
+			media.Uploader = item.ArtistName
+		}
+		return media, nil
+	}
+
+	return nil, fmt.Errorf("podcast %s has no public feed. Use 'vget search --podcast <name>' to find episodes", podcastID)
+}
+
+// lookup calls the iTunes lookup API
+func (e *iTunesExtractor) lookup(ctx context.Context, client *http.Client, apiURL string) (*iTunesLookupResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result iTunesLookupResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	return &result, nil
 }
 
 // iTunes API response structures
@@ -112,6 +171,7 @@ type iTunesLookupResult struct {
 	EpisodeURL           string `json:"episodeUrl"`
 	EpisodeFileExtension string `json:"episodeFileExtension"`
 	ReleaseDate          string `json:"releaseDate"`
+	FeedURL              string `json:"feedUrl"`
 }
 
 func init() {
@@ -0,0 +1,56 @@
+package extractor
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/guiyumin/vget/internal/testutil/replay"
+)
+
+func TestITunesEpisode(t *testing.T) {
+	e := &iTunesExtractor{client: replay.Client(t, filepath.Join("testdata", "replay", "itunes"))}
+
+	media, err := e.Extract("https://podcasts.apple.com/us/podcast/dan-carlins-hardcore-history/id173001861?i=1000682587885")
+	if err != nil {
+		t.Fatalf("Extract: %v", err)
+	}
+	audio, ok := media.(*AudioMedia)
+	if !ok {
+		t.Fatalf("expected *AudioMedia, got %T", media)
+	}
+	if audio.Title != SanitizeFilename("Dan Carlin's Hardcore History - Show 70 - Twilight of the Aesir") {
+		t.Errorf("unexpected title %q", audio.Title)
+	}
+	if audio.URL != "https://traffic.libsyn.com/dchha70.mp3" || audio.Duration != 21600 {
+		t.Errorf("unexpected audio: %+v", audio)
+	}
+
+	if _, err := e.Extract("https://podcasts.apple.com/us/podcast/id173001861?i=1"); err == nil {
+		t.Error("expected error for unknown episode")
+	}
+}
+
+func TestITunesShowFeed(t *testing.T) {
+	e := &iTunesExtractor{client: replay.Client(t, filepath.Join("testdata", "replay", "itunes"))}
+
+	media, err := e.Extract("https://podcasts.apple.com/us/podcast/dan-carlins-hardcore-history/id173001861")
+	if err != nil {
+		t.Fatalf("Extract: %v", err)
+	}
+	podcast, ok := media.(*PodcastMedia)
+	if !ok {
+		t.Fatalf("expected *PodcastMedia, got %T", media)
+	}
+	if podcast.ID != "173001861" || podcast.Title != "Hardcore History" || podcast.Uploader != "Dan Carlin" {
+		t.Errorf("unexpected metadata: %+v", podcast)
+	}
+	if len(podcast.Episodes) != 2 {
+		t.Fatalf("expected 2 episodes, got %d", len(podcast.Episodes))
+	}
+	if ep := podcast.Episodes[0]; ep.URL != "https://traffic.libsyn.com/dchha70.mp3" || ep.Duration != 21600 {
+		t.Errorf("expected newest episode first, got %+v", ep)
+	}
+	if ep := podcast.Episodes[1]; ep.Duration != 5*3600+46*60+36 {
+		t.Errorf("unexpected duration %d", ep.Duration)
+	}
+}
@@ -1,23 +1,31 @@
 package extractor
 
 import (
-	"net/http"
+	"context"
 	"net/url"
 	"path"
 	"strings"
-	"time"
 )
 
 // M3U8Extractor handles direct m3u8 playlist URLs
-type M3U8Extractor struct {
-	client *http.Client
-}
+type M3U8Extractor struct{}
 
 // Name returns the extractor name
 func (m *M3U8Extractor) Name() string {
 	return "m3u8"
 }
 
+// Info describes the URLs and media this extractor handles
+func (m *M3U8Extractor) Info() Info {
+	return Info{
+		Name:       "m3u8",
+		Title:      "HLS streams",
+		Matches:    "any .m3u8 URL",
+		Kinds:      []URLKind{URLKindSingle, URLKindLive},
+		MediaTypes: []MediaType{MediaTypeVideo},
+	}
+}
+
 // Match checks if the URL is an m3u8 playlist
 func (m *M3U8Extractor) Match(u *url.URL) bool {
 	// Only match http/https URLs
@@ -32,15 +40,11 @@ func (m *M3U8Extractor) Match(u *url.URL) bool {
 
 // Extract retrieves media information from an m3u8 URL
 func (m *M3U8Extractor) Extract(urlStr string) (Media, error) {
-	if m.client == nil {
-		m.client = &http.Client{
-			Timeout: 30 * time.Second,
-			Transport: &http.Transport{
-				Proxy: http.ProxyFromEnvironment,
-			},
-		}
-	}
+	return m.ExtractContext(context.Background(), urlStr, Options{})
+}
 
+// ExtractContext describes an m3u8 URL; the playlist itself is read by the downloader
+func (m *M3U8Extractor) ExtractContext(ctx context.Context, urlStr string, opts Options) (Media, error) {
 	// Parse URL to extract filename
 	parsedURL, _ := url.Parse(urlStr)
 	filename := path.Base(parsedURL.Path)
@@ -0,0 +1,504 @@
+package extractor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	redditPostURL   = "https://www.reddit.com/comments/%s/.json?raw_json=1"
+	redditVideoURL  = "https://v.redd.it/"
+	redditUserAgent = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
+)
+
+var (
+	// /r/<sub>/comments/<id>/..., /user/<name>/comments/<id>/..., /comments/<id>, /gallery/<id>
+	redditPostRegex = regexp.MustCompile(`^/(?:(?:r|u|user)/[^/]+/)?(?:comments|gallery)/([a-z0-9]+)`)
+	// /r/<sub>/s/<code> share links from the apps
+	redditShareRegex = regexp.MustCompile(`^/(?:r|u|user)/[^/]+/s/[A-Za-z0-9]+`)
+	// v.redd.it/<id> and redd.it/<id>
+	redditShortRegex = regexp.MustCompile(`^/([a-z0-9]+)/?$`)
+)
+
+// RedditExtractor handles Reddit posts: v.redd.it videos (DASH video and audio,
+// merged after download), galleries, images, GIFs and crossposts
+type RedditExtractor struct {
+	client *http.Client
+}
+
+func (e *RedditExtractor) Name() string {
+	return "reddit"
+}
+
+// Info describes the URLs and media this extractor handles
+func (e *RedditExtractor) Info() Info {
+	return Info{
+		Name:       "reddit",
+		Title:      "Reddit",
+		Kinds:      []URLKind{URLKindSingle},
+		MediaTypes: []MediaType{MediaTypeVideo, MediaTypeImage},
+	}
+}
+
+func (e *RedditExtractor) Match(u *url.URL) bool {
+	switch u.Hostname() {
+	case "v.redd.it", "redd.it":
+		return redditShortRegex.MatchString(u.Path)
+	}
+	return redditPostRegex.MatchString(u.Path) || redditShareRegex.MatchString(u.Path)
+}
+
+func (e *RedditExtractor) Extract(rawURL string) (Media, error) {
+	return e.ExtractContext(context.Background(), rawURL, Options{})
+}
+
+func (e *RedditExtractor) ExtractContext(ctx context.Context, rawURL string, opts Options) (Media, error) {
+	client := e.client
+	if client == nil {
+		client = newHTTPClient(opts, 30*time.Second)
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL: %w", err)
+	}
+
+	switch {
+	case u.Hostname() == "redd.it":
+		if m := redditShortRegex.FindStringSubmatch(u.Path); m != nil {
+			return e.extractPost(ctx, client, m[1])
+		}
+
+	case u.Hostname() == "v.redd.it":
+		m := redditShortRegex.FindStringSubmatch(u.Path)
+		if m == nil {
+			break
+		}
+		// v.redd.it links redirect to their post, which has the title
+		if target, err := e.resolveRedirect(ctx, client, rawURL); err == nil {
+			if pm := redditPostRegex.FindStringSubmatch(target.Path); pm != nil {
+				return e.extractPost(ctx, client, pm[1])
+			}
+		}
+		return e.videoMedia(ctx, client, m[1], m[1], "", &redditVideo{
+			DashURL: redditVideoURL + m[1] + "/DASHPlaylist.mpd",
+		})
+
+	case redditShareRegex.MatchString(u.Path):
+		target, err := e.resolveRedirect(ctx, client, rawURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve share link: %w", err)
+		}
+		if pm := redditPostRegex.FindStringSubmatch(target.Path); pm != nil {
+			return e.extractPost(ctx, client, pm[1])
+		}
+		return nil, fmt.Errorf("share link did not lead to a post: %s", target)
+
+	default:
+		if m := redditPostRegex.FindStringSubmatch(u.Path); m != nil {
+			return e.extractPost(ctx, client, m[1])
+		}
+	}
+	return nil, fmt.Errorf("could not extract Reddit post ID from URL")
+}
+
+// resolveRedirect returns where a share or v.redd.it link redirects to, without following it
+func (e *RedditExtractor) resolveRedirect(ctx context.Context, client *http.Client, rawURL string) (*url.URL, error) {
+	noFollow := *client
+	noFollow.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", redditUserAgent)
+
+	resp, err := noFollow.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+
+	location := resp.Header.Get("Location")
+	if location == "" {
+		return nil, fmt.Errorf("no redirect (status %d)", resp.StatusCode)
+	}
+	return req.URL.Parse(location)
+}
+
+// get fetches a URL and returns the body, failing on non-200 responses
+func (e *RedditExtractor) get(ctx context.Context, client *http.Client, rawURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", redditUserAgent)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("request failed with status %d", resp.StatusCode)
+	}
+	return body, nil
+}
+
+// extractPost fetches a post's JSON and converts its media
+func (e *RedditExtractor) extractPost(ctx context.Context, client *http.Client, id string) (Media, error) {
+	body, err := e.get(ctx, client, fmt.Sprintf(redditPostURL, id))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch post: %w", err)
+	}
+
+	// The response is the post listing followed by the comments listing
+	var listings []struct {
+		Data struct {
+			Children []struct {
+				Kind string     `json:"kind"`
+				Data redditPost `json:"data"`
+			} `json:"children"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &listings); err != nil {
+		return nil, fmt.Errorf("failed to parse post: %w", err)
+	}
+	if len(listings) == 0 || len(listings[0].Data.Children) == 0 {
+		return nil, fmt.Errorf("post %s not found", id)
+	}
+
+	post := &listings[0].Data.Children[0].Data
+	return e.postMedia(ctx, client, post)
+}
+
+// redditPost is a post ("t3") from the .json API
+type redditPost struct {
+	ID        string `json:"id"`
+	Title     string `json:"title"`
+	Author    string `json:"author"`
+	URL       string `json:"url"`
+	PostHint  string `json:"post_hint"`
+	IsVideo   bool   `json:"is_video"`
+	IsGallery bool   `json:"is_gallery"`
+	Media     *struct {
+		RedditVideo *redditVideo `json:"reddit_video"`
+	} `json:"media"`
+	SecureMedia *struct {
+		RedditVideo *redditVideo `json:"reddit_video"`
+	} `json:"secure_media"`
+	Preview *struct {
+		Images []struct {
+			Source   redditImage `json:"source"`
+			Variants struct {
+				GIF *struct {
+					Source redditImage `json:"source"`
+				} `json:"gif"`
+				MP4 *struct {
+					Source redditImage `json:"source"`
+				} `json:"mp4"`
+			} `json:"variants"`
+		} `json:"images"`
+		// Set for GIFs and videos hosted elsewhere, which Reddit transcodes
+		RedditVideoPreview *redditVideo `json:"reddit_video_preview"`
+	} `json:"preview"`
+	GalleryData *struct {
+		Items []struct {
+			MediaID string `json:"media_id"`
+		} `json:"items"`
+	} `json:"gallery_data"`
+	MediaMetadata       map[string]redditMediaMetadata `json:"media_metadata"`
+	CrosspostParentList []redditPost                   `json:"crosspost_parent_list"`
+}
+
+// redditVideo is a v.redd.it video
+type redditVideo struct {
+	FallbackURL string `json:"fallback_url"` // video only, no audio
+	DashURL     string `json:"dash_url"`
+	HLSURL      string `json:"hls_url"`
+	Width       int    `json:"width"`
+	Height      int    `json:"height"`
+	Duration    int    `json:"duration"`
+	IsGIF       bool   `json:"is_gif"`
+}
+
+// redditImage is an image or preview rendition
+type redditImage struct {
+	URL    string `json:"url"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+}
+
+// redditMediaMetadata is a gallery item; "s" holds its full-size source
+type redditMediaMetadata struct {
+	Status string `json:"status"`
+	Kind   string `json:"e"` // "Image" or "AnimatedImage"
+	Mime   string `json:"m"`
+	Source struct {
+		URL    string `json:"u"`
+		GIF    string `json:"gif"`
+		MP4    string `json:"mp4"`
+		Width  int    `json:"x"`
+		Height int    `json:"y"`
+	} `json:"s"`
+}
+
+// video returns the post's v.redd.it video, if any
+func (p *redditPost) video() *redditVideo {
+	if p.SecureMedia != nil && p.SecureMedia.RedditVideo != nil {
+		return p.SecureMedia.RedditVideo
+	}
+	if p.Media != nil && p.Media.RedditVideo != nil {
+		return p.Media.RedditVideo
+	}
+	return nil
+}
+
+// postMedia converts a post into VideoMedia or ImageMedia. Crossposts use the
+// original post's media with the crosspost's title.
+func (e *RedditExtractor) postMedia(ctx context.Context, client *http.Client, post *redditPost) (Media, error) {
+	src := post
+	if len(post.CrosspostParentList) > 0 {
+		src = &post.CrosspostParentList[0]
+	}
+
+	title := post.Title
+	if title == "" {
+		title = post.ID
+	}
+
+	if v := src.video(); v != nil {
+		return e.videoMedia(ctx, client, post.ID, title, post.Author, v)
+	}
+
+	if src.IsGallery && src.GalleryData != nil {
+		return src.galleryMedia(post.ID, title, post.Author)
+	}
+
+	// GIFs and videos hosted elsewhere come with a transcoded preview
+	if src.Preview != nil && src.Preview.RedditVideoPreview != nil {
+		return e.videoMedia(ctx, client, post.ID, title, post.Author, src.Preview.RedditVideoPreview)
+	}
+
+	if src.PostHint == "image" || redditImageExt(src.URL) != "" {
+		return src.imageMedia(post.ID, title, post.Author)
+	}
+
+	if src.URL != "" && !strings.Contains(src.URL, "reddit.com/") {
+		return nil, fmt.Errorf("post has no Reddit-hosted media; it links to %s", src.URL)
+	}
+	return nil, fmt.Errorf("post has no media")
+}
+
+// videoMedia lists the DASH renditions, or the silent fallback_url if the manifest
+// can't be read, plus the HLS playlist
+func (e *RedditExtractor) videoMedia(ctx context.Context, client *http.Client, id, title, uploader string, v *redditVideo) (Media, error) {
+	headers := map[string]string{
+		"Referer":    "https://www.reddit.com/",
+		"User-Agent": redditUserAgent,
+	}
+
+	var formats []VideoFormat
+	if v.DashURL != "" {
+		var err error
+		formats, err = e.dashFormats(ctx, client, v.DashURL, headers)
+		if err != nil && v.FallbackURL == "" && v.HLSURL == "" {
+			return nil, err
+		}
+	}
+
+	if len(formats) == 0 && v.FallbackURL != "" {
+		formats = append(formats, VideoFormat{
+			URL:     v.FallbackURL,
+			Quality: fmt.Sprintf("%dp", v.Height),
+			Ext:     "mp4",
+			Width:   v.Width,
+			Height:  v.Height,
+			Headers: headers,
+		})
+	}
+	if v.HLSURL != "" {
+		formats = append(formats, VideoFormat{
+			URL:     v.HLSURL,
+			Quality: "hls",
+			Ext:     "m3u8",
+			Headers: headers,
+		})
+	}
+
+	if len(formats) == 0 {
+		return nil, fmt.Errorf("no video formats found")
+	}
+
+	return &VideoMedia{
+		ID:       id,
+		Title:    title,
+		Uploader: uploader,
+		Duration: v.Duration,
+		Formats:  formats,
+	}, nil
+}
+
+// dashFormats lists the video renditions of a DASH manifest, each paired with the best audio track
+func (e *RedditExtractor) dashFormats(ctx context.Context, client *http.Client, manifestURL string, headers map[string]string) ([]VideoFormat, error) {
+	body, err := e.get(ctx, client, manifestURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch DASH manifest: %w", err)
+	}
+	video, audio, err := parseDASHManifest(body, manifestURL)
+	if err != nil {
+		return nil, err
+	}
+
+	// GIFs have no audio track
+	var audioURL string
+	if len(audio) > 0 {
+		audioURL = audio[0].URL
+	}
+
+	var formats []VideoFormat
+	for _, r := range video {
+		formats = append(formats, VideoFormat{
+			URL:      r.URL,
+			Quality:  fmt.Sprintf("%dp", r.Height),
+			Ext:      "mp4",
+			Width:    r.Width,
+			Height:   r.Height,
+			Bitrate:  r.Bandwidth / 1000,
+			Headers:  headers,
+			AudioURL: audioURL,
+		})
+	}
+	return formats, nil
+}
+
+// galleryMedia returns a gallery's images in order; animated items use their GIF
+func (p *redditPost) galleryMedia(id, title, uploader string) (Media, error) {
+	var images []Image
+	for _, item := range p.GalleryData.Items {
+		meta, ok := p.MediaMetadata[item.MediaID]
+		if !ok || meta.Status != "valid" {
+			continue
+		}
+
+		imgURL := meta.Source.URL
+		if meta.Kind == "AnimatedImage" && meta.Source.GIF != "" {
+			imgURL = meta.Source.GIF
+		}
+		if imgURL == "" {
+			continue
+		}
+
+		ext := redditImageExt(imgURL)
+		if ext == "" {
+			ext = strings.TrimPrefix(meta.Mime, "image/")
+		}
+		images = append(images, Image{
+			URL:    imgURL,
+			Ext:    normalizeImageExt(ext),
+			Width:  meta.Source.Width,
+			Height: meta.Source.Height,
+		})
+	}
+
+	if len(images) == 0 {
+		return nil, fmt.Errorf("no images found in gallery")
+	}
+	return &ImageMedia{ID: id, Title: title, Uploader: uploader, Images: images}, nil
+}
+
+// imageMedia returns a single-image post. GIFs are returned as their MP4 preview
+// when Reddit has one, since it is a fraction of the size.
+func (p *redditPost) imageMedia(id, title, uploader string) (Media, error) {
+	ext := redditImageExt(p.URL)
+	var width, height int
+	if p.Preview != nil && len(p.Preview.Images) > 0 {
+		img := p.Preview.Images[0]
+		width, height = img.Source.Width, img.Source.Height
+
+		if ext == "gif" && img.Variants.MP4 != nil && img.Variants.MP4.Source.URL != "" {
+			return &VideoMedia{
+				ID:       id,
+				Title:    title,
+				Uploader: uploader,
+				Formats: []VideoFormat{{
+					URL:     img.Variants.MP4.Source.URL,
+					Quality: fmt.Sprintf("%dp", height),
+					Ext:     "mp4",
+					Width:   width,
+					Height:  height,
+				}},
+			}, nil
+		}
+	}
+
+	imgURL := p.URL
+	if ext == "" {
+		// Image posts linking to a page; use the preview source
+		if p.Preview == nil || len(p.Preview.Images) == 0 {
+			return nil, fmt.Errorf("no image found in post")
+		}
+		imgURL = p.Preview.Images[0].Source.URL
+		ext = redditImageExt(imgURL)
+	}
+
+	return &ImageMedia{
+		ID:       id,
+		Title:    title,
+		Uploader: uploader,
+		Images: []Image{{
+			URL:    imgURL,
+			Ext:    normalizeImageExt(ext),
+			Width:  width,
+			Height: height,
+		}},
+	}, nil
+}
+
+// redditImageExt returns the image extension of a URL, or "" if it isn't an image
+func redditImageExt(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	switch ext := strings.ToLower(strings.TrimPrefix(path.Ext(u.Path), ".")); ext {
+	case "jpg", "jpeg", "png", "gif", "webp":
+		return ext
+	}
+	return ""
+}
+
+// normalizeImageExt maps "jpeg" to "jpg" and defaults to "jpg"
+func normalizeImageExt(ext string) string {
+	switch ext {
+	case "", "jpeg":
+		return "jpg"
+	}
+	return ext
+}
+
+func init() {
+	Register(&RedditExtractor{},
+		"reddit.com",
+		"old.reddit.com",
+		"new.reddit.com",
+		"m.reddit.com",
+		"v.redd.it",
+		"redd.it",
+	)
+}
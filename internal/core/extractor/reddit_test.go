@@ -0,0 +1,137 @@
+package extractor
+
+import (
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/guiyumin/vget/internal/testutil/replay"
+)
+
+func newRedditTestExtractor(t *testing.T) *RedditExtractor {
+	return &RedditExtractor{client: replay.Client(t, filepath.Join("testdata", "replay", "reddit"))}
+}
+
+func TestRedditMatch(t *testing.T) {
+	tests := map[string]bool{
+		"https://www.reddit.com/r/golang/comments/1abcde/gopher_builds_a_bridge/": true,
+		"https://old.reddit.com/r/golang/comments/1abcde/":                        true,
+		"https://www.reddit.com/user/gopher/comments/1abcde/title/":               true,
+		"https://www.reddit.com/gallery/1gall01":                                  true,
+		"https://www.reddit.com/r/golang/s/AbCdEf123":                             true,
+		"https://v.redd.it/vid123abc":                                             true,
+		"https://redd.it/1abcde":                                                  true,
+		"https://www.reddit.com/r/golang/":                                        false,
+		"https://www.reddit.com/user/gopher/":                                     false,
+	}
+	e := &RedditExtractor{}
+	for rawURL, want := range tests {
+		u, _ := url.Parse(rawURL)
+		if got := e.Match(u); got != want {
+			t.Errorf("Match(%q) = %v, want %v", rawURL, got, want)
+		}
+	}
+}
+
+func TestRedditVideoDASH(t *testing.T) {
+	e := newRedditTestExtractor(t)
+
+	for _, rawURL := range []string{
+		"https://www.reddit.com/r/golang/comments/1abcde/gopher_builds_a_bridge/",
+		"https://www.reddit.com/r/golang/s/AbCdEf123",
+		"https://v.redd.it/vid123abc",
+	} {
+		media, err := e.Extract(rawURL)
+		if err != nil {
+			t.Fatalf("Extract(%s): %v", rawURL, err)
+		}
+		video, ok := media.(*VideoMedia)
+		if !ok {
+			t.Fatalf("expected *VideoMedia, got %T", media)
+		}
+		if video.ID != "1abcde" || video.Title != "Gopher builds a bridge" || video.Uploader != "gopher" || video.Duration != 42 {
+			t.Errorf("%s: unexpected metadata: %+v", rawURL, video)
+		}
+
+		// 3 DASH renditions (tallest first) plus HLS
+		if len(video.Formats) != 4 {
+			t.Fatalf("expected 4 formats, got %d", len(video.Formats))
+		}
+		best := video.Formats[0]
+		if best.URL != "https://v.redd.it/vid123abc/DASH_1080.mp4" || best.Quality != "1080p" || best.Bitrate != 4800 {
+			t.Errorf("unexpected best format: %+v", best)
+		}
+		if best.AudioURL != "https://v.redd.it/vid123abc/DASH_AUDIO_128.mp4" {
+			t.Errorf("expected the 128k audio track, got %s", best.AudioURL)
+		}
+		if hls := video.Formats[3]; hls.Ext != "m3u8" || hls.AudioURL != "" {
+			t.Errorf("unexpected HLS format: %+v", hls)
+		}
+	}
+}
+
+func TestRedditCrosspost(t *testing.T) {
+	media, err := newRedditTestExtractor(t).Extract("https://www.reddit.com/r/gophers/comments/1cross1/this_is_amazing/")
+	if err != nil {
+		t.Fatalf("Extract: %v", err)
+	}
+	video, ok := media.(*VideoMedia)
+	if !ok {
+		t.Fatalf("expected *VideoMedia, got %T", media)
+	}
+	if video.ID != "1cross1" || video.Title != "This is amazing (x-post)" || len(video.Formats) != 4 {
+		t.Errorf("expected the crosspost's title with the original's video, got %+v", video)
+	}
+}
+
+func TestRedditGallery(t *testing.T) {
+	media, err := newRedditTestExtractor(t).Extract("https://www.reddit.com/gallery/1gall01")
+	if err != nil {
+		t.Fatalf("Extract: %v", err)
+	}
+	images, ok := media.(*ImageMedia)
+	if !ok {
+		t.Fatalf("expected *ImageMedia, got %T", media)
+	}
+
+	// Gallery order, failed items skipped, animated items as GIF
+	want := []struct{ prefix, ext string }{
+		{"https://preview.redd.it/img2.png", "png"},
+		{"https://preview.redd.it/img1.jpg", "jpg"},
+		{"https://i.redd.it/anim3.gif", "gif"},
+	}
+	if len(images.Images) != len(want) {
+		t.Fatalf("expected %d images, got %d", len(want), len(images.Images))
+	}
+	for i, w := range want {
+		img := images.Images[i]
+		if !strings.HasPrefix(img.URL, w.prefix) || img.Ext != w.ext {
+			t.Errorf("image %d = %s (%s), want %s (%s)", i, img.URL, img.Ext, w.prefix, w.ext)
+		}
+	}
+	if images.Images[1].Width != 4032 {
+		t.Errorf("expected source dimensions, got %dx%d", images.Images[1].Width, images.Images[1].Height)
+	}
+}
+
+func TestRedditGIFPreview(t *testing.T) {
+	media, err := newRedditTestExtractor(t).Extract("https://redd.it/1gif001")
+	if err != nil {
+		t.Fatalf("Extract: %v", err)
+	}
+	video, ok := media.(*VideoMedia)
+	if !ok {
+		t.Fatalf("expected the MP4 preview as *VideoMedia, got %T", media)
+	}
+	if f := video.Formats[0]; f.Ext != "mp4" || !strings.Contains(f.URL, "format=mp4") || f.Height != 281 {
+		t.Errorf("unexpected format: %+v", f)
+	}
+}
+
+func TestRedditLinkPost(t *testing.T) {
+	_, err := newRedditTestExtractor(t).Extract("https://www.reddit.com/r/golang/comments/1link01/interesting_article/")
+	if err == nil || !strings.Contains(err.Error(), "https://go.dev/blog/") {
+		t.Errorf("expected an error naming the linked URL, got %v", err)
+	}
+}
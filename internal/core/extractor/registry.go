@@ -37,7 +37,8 @@ var directDownloadExtensions = map[string]bool{
 	".rar": true, ".7z": true, ".dmg": true, ".iso": true,
 }
 
-// Register adds an extractor for the given hostnames
+// Register adds an extractor for the given hostnames. A leading "*." matches
+// every subdomain (e.g., "*.bandcamp.com" for artist pages).
 func Register(e Extractor, hosts ...string) {
 	for _, host := range hosts {
 		extractorsByHost[host] = e
@@ -83,49 +84,59 @@ func RegisterFallback(e Extractor) {
 // Match finds the extractor for a URL using O(1) hostname lookup
 // Returns nil for unknown hosts (caller should check sites.yml)
 func Match(rawURL string) Extractor {
-	// Normalize URL: add https:// if no scheme present
+	e, _, _ := explainMatch(rawURL)
+	return e
+}
+
+// explainMatch is Match with the reason for its decision
+func explainMatch(rawURL string) (Extractor, string, string) {
 	normalized, err := NormalizeURL(rawURL)
 	if err != nil {
-		return nil
+		return nil, "", err.Error()
 	}
 
 	u, err := url.Parse(normalized)
 	if err != nil {
-		return nil
+		return nil, "", err.Error()
 	}
 
-	// Check if it's a direct file URL first (skip host-based extractors)
+	// Direct file URLs skip host-based extractors
 	ext := strings.ToLower(path.Ext(u.Path))
 	if directDownloadExtensions[ext] {
 		// Use specialized m3u8 extractor for HLS streams (no HEAD validation needed)
 		if ext == ".m3u8" || ext == ".m3u" {
-			return m3u8Extractor
+			return m3u8Extractor, SourceExtension, "path ends in " + ext + " (HLS playlist)"
 		}
-		return fallbackExtractor
+		return fallbackExtractor, SourceExtension, "path ends in " + ext + " (direct file)"
 	}
 
-	// Lookup by hostname
-	host := strings.ToLower(u.Hostname())
+	// Podcast feeds can live on any host
+	if feedExtensions[ext] {
+		return feedExtractor, SourceFeed, "path ends in " + ext + " (podcast feed)"
+	}
 
-	// Try exact match
-	if e, ok := extractorsByHost[host]; ok {
+	host := strings.ToLower(u.Hostname())
+	candidates := []string{host, strings.TrimPrefix(host, "www.")}
+	if _, parent, ok := strings.Cut(host, "."); ok {
+		candidates = append(candidates, "*."+parent)
+	}
+	var unsupported Extractor
+	for _, h := range candidates {
+		e, ok := extractorsByHost[h]
+		if !ok {
+			continue
+		}
 		// Also check path pattern via Match() (e.g., /status/ for Twitter)
 		if e.Match(u) {
-			return e
+			return e, SourceHost, fmt.Sprintf("host %s is handled by %s", h, e.Name())
 		}
+		unsupported = e
 	}
 
-	// Try without www. prefix
-	if strings.HasPrefix(host, "www.") {
-		if e, ok := extractorsByHost[host[4:]]; ok {
-			if e.Match(u) {
-				return e
-			}
-		}
+	if unsupported != nil {
+		return nil, "", fmt.Sprintf("host %s belongs to %s, but it does not support this path", host, unsupported.Name())
 	}
-
-	// Unknown host - return nil so caller can check sites.yml
-	return nil
+	return nil, "", fmt.Sprintf("no built-in extractor for %s", host)
 }
 
 // List returns all unique registered extractors
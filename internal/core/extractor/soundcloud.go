@@ -0,0 +1,507 @@
+package extractor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	soundcloudHomeURL    = "https://soundcloud.com/"
+	soundcloudAPIURL     = "https://api-v2.soundcloud.com"
+	soundcloudUserAgent  = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
+	soundcloudTrackBatch = 50 // max ids per /tracks request
+)
+
+var (
+	// /<user>/<track>, /<user>/sets/<playlist>, /<user>/likes
+	soundcloudPathRegex = regexp.MustCompile(`^/([\w-]+)/(sets/[\w-]+|[\w-]+)/?$`)
+	// on.soundcloud.com/<code> share links
+	soundcloudShortRegex = regexp.MustCompile(`^/[A-Za-z0-9]+/?$`)
+	// <script crossorigin src="https://a-v2.sndcdn.com/assets/0-abc.js">
+	soundcloudScriptRegex = regexp.MustCompile(`<script[^>]+src="(https://[^"]+\.sndcdn\.com/assets/[^"]+\.js)"`)
+	// client_id:"..." or client_id=... inside the web app bundle
+	soundcloudClientIDRegex = regexp.MustCompile(`client_id\s*[:=]\s*"?([0-9A-Za-z]{32})`)
+)
+
+// soundcloudReservedPaths are user pages and site sections that aren't tracks
+var soundcloudReservedPaths = map[string]bool{
+	"tracks": true, "albums": true, "sets": true, "reposts": true, "popular-tracks": true,
+	"followers": true, "following": true, "comments": true, "spotlight": true,
+	"discover": true, "stream": true, "search": true, "you": true, "upload": true,
+	"charts": true, "pages": true, "settings": true, "messages": true, "notifications": true,
+}
+
+// errSoundCloudUnauthorized means the client_id was rejected and must be rediscovered
+var errSoundCloudUnauthorized = errors.New("client_id rejected")
+
+// SoundCloudExtractor handles SoundCloud tracks, sets (playlists and albums) and
+// user likes. The API needs the web app's client_id, which is read from its scripts.
+type SoundCloudExtractor struct {
+	client *http.Client
+
+	mu       sync.Mutex
+	clientID string // discovered once, refreshed when rejected
+}
+
+func (e *SoundCloudExtractor) Name() string {
+	return "soundcloud"
+}
+
+// Info describes the URLs and media this extractor handles
+func (e *SoundCloudExtractor) Info() Info {
+	return Info{
+		Name:       "soundcloud",
+		Title:      "SoundCloud",
+		Kinds:      []URLKind{URLKindSingle, URLKindPlaylist, URLKindUser},
+		MediaTypes: []MediaType{MediaTypeAudio},
+	}
+}
+
+func (e *SoundCloudExtractor) Match(u *url.URL) bool {
+	if u.Hostname() == "on.soundcloud.com" {
+		return soundcloudShortRegex.MatchString(u.Path)
+	}
+	m := soundcloudPathRegex.FindStringSubmatch(u.Path)
+	return m != nil && !soundcloudReservedPaths[m[1]] && !soundcloudReservedPaths[m[2]]
+}
+
+func (e *SoundCloudExtractor) Extract(rawURL string) (Media, error) {
+	return e.ExtractContext(context.Background(), rawURL, Options{})
+}
+
+func (e *SoundCloudExtractor) ExtractContext(ctx context.Context, rawURL string, opts Options) (Media, error) {
+	client := e.client
+	if client == nil {
+		client = newHTTPClient(opts, 30*time.Second)
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL: %w", err)
+	}
+
+	// Share links redirect to the track or set
+	if u.Hostname() == "on.soundcloud.com" {
+		if u, err = e.resolveRedirect(ctx, client, rawURL); err != nil {
+			return nil, fmt.Errorf("failed to resolve share link: %w", err)
+		}
+	}
+
+	// The API resolves canonical URLs without tracking parameters
+	page := &url.URL{Scheme: "https", Host: "soundcloud.com", Path: strings.TrimSuffix(u.Path, "/")}
+
+	if strings.HasSuffix(page.Path, "/likes") {
+		profile := *page
+		profile.Path = strings.TrimSuffix(page.Path, "/likes")
+		var user soundcloudUser
+		if err := e.api(ctx, client, "/resolve?url="+url.QueryEscape(profile.String()), &user); err != nil {
+			return nil, fmt.Errorf("failed to resolve user: %w", err)
+		}
+		return e.likes(ctx, client, &user)
+	}
+
+	var resource struct {
+		Kind string `json:"kind"`
+	}
+	var raw json.RawMessage
+	if err := e.api(ctx, client, "/resolve?url="+url.QueryEscape(page.String()), &raw); err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", page, err)
+	}
+	if err := json.Unmarshal(raw, &resource); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	switch resource.Kind {
+	case "track":
+		var track soundcloudTrack
+		if err := json.Unmarshal(raw, &track); err != nil {
+			return nil, fmt.Errorf("failed to parse track: %w", err)
+		}
+		return e.audioMedia(ctx, client, &track, soundcloudArtwork(track.User.AvatarURL))
+	case "playlist":
+		var playlist soundcloudPlaylist
+		if err := json.Unmarshal(raw, &playlist); err != nil {
+			return nil, fmt.Errorf("failed to parse playlist: %w", err)
+		}
+		return e.playlist(ctx, client, &playlist)
+	}
+	return nil, fmt.Errorf("unsupported SoundCloud page (%s)", resource.Kind)
+}
+
+// resolveRedirect returns where a share link redirects to, without following it
+func (e *SoundCloudExtractor) resolveRedirect(ctx context.Context, client *http.Client, rawURL string) (*url.URL, error) {
+	noFollow := *client
+	noFollow.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", soundcloudUserAgent)
+
+	resp, err := noFollow.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+
+	location := resp.Header.Get("Location")
+	if location == "" {
+		return nil, fmt.Errorf("no redirect (status %d)", resp.StatusCode)
+	}
+	return req.URL.Parse(location)
+}
+
+// get fetches a URL and returns the body, failing on non-200 responses
+func (e *SoundCloudExtractor) get(ctx context.Context, client *http.Client, rawURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", soundcloudUserAgent)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return body, nil
+	case http.StatusUnauthorized:
+		return nil, errSoundCloudUnauthorized
+	case http.StatusNotFound:
+		return nil, fmt.Errorf("not found (deleted or private)")
+	}
+	return nil, fmt.Errorf("request failed with status %d", resp.StatusCode)
+}
+
+// discoverClientID reads the client_id from the web app's scripts, newest bundle first
+func (e *SoundCloudExtractor) discoverClientID(ctx context.Context, client *http.Client) (string, error) {
+	body, err := e.get(ctx, client, soundcloudHomeURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to load soundcloud.com: %w", err)
+	}
+
+	scripts := soundcloudScriptRegex.FindAllStringSubmatch(string(body), -1)
+	// The app bundle with the client_id is loaded last
+	for i := len(scripts) - 1; i >= 0; i-- {
+		script, err := e.get(ctx, client, scripts[i][1])
+		if err != nil {
+			continue
+		}
+		if m := soundcloudClientIDRegex.FindSubmatch(script); m != nil {
+			return string(m[1]), nil
+		}
+	}
+	return "", fmt.Errorf("client_id not found in SoundCloud's scripts")
+}
+
+// api fetches an api-v2 path (or next_href URL) with the client_id and decodes the
+// JSON into v, rediscovering the client_id once if it was rotated
+func (e *SoundCloudExtractor) api(ctx context.Context, client *http.Client, pathOrURL string, v any) error {
+	rawURL := pathOrURL
+	if strings.HasPrefix(rawURL, "/") {
+		rawURL = soundcloudAPIURL + rawURL
+	}
+
+	for attempt := 0; ; attempt++ {
+		e.mu.Lock()
+		clientID := e.clientID
+		e.mu.Unlock()
+		if clientID == "" {
+			id, err := e.discoverClientID(ctx, client)
+			if err != nil {
+				return err
+			}
+			e.mu.Lock()
+			e.clientID = id
+			e.mu.Unlock()
+			clientID = id
+		}
+
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			return err
+		}
+		q := u.Query()
+		q.Set("client_id", clientID)
+		u.RawQuery = q.Encode()
+
+		body, err := e.get(ctx, client, u.String())
+		if errors.Is(err, errSoundCloudUnauthorized) && attempt == 0 {
+			e.mu.Lock()
+			e.clientID = ""
+			e.mu.Unlock()
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		return json.Unmarshal(body, v)
+	}
+}
+
+// soundcloudTrack is a track from api-v2. Tracks inside playlists may be stubs
+// with only an ID, which are fetched in batches.
+type soundcloudTrack struct {
+	ID                 int64          `json:"id"`
+	Title              string         `json:"title"`
+	Duration           int            `json:"duration"` // milliseconds
+	ArtworkURL         string         `json:"artwork_url"`
+	Description        string         `json:"description"`
+	DisplayDate        time.Time      `json:"display_date"`
+	TrackAuthorization string         `json:"track_authorization"`
+	Policy             string         `json:"policy"` // "ALLOW", "SNIP" (Go+ preview), "BLOCK"
+	User               soundcloudUser `json:"user"`
+	Media              struct {
+		Transcodings []soundcloudTranscoding `json:"transcodings"`
+	} `json:"media"`
+}
+
+// soundcloudTranscoding is one encoding of a track, resolved to a stream URL on request
+type soundcloudTranscoding struct {
+	URL     string `json:"url"`
+	Preset  string `json:"preset"`
+	Snipped bool   `json:"snipped"` // 30-second preview
+	Format  struct {
+		Protocol string `json:"protocol"` // "progressive" or "hls"
+		MimeType string `json:"mime_type"`
+	} `json:"format"`
+}
+
+type soundcloudUser struct {
+	ID        int64  `json:"id"`
+	Username  string `json:"username"`
+	AvatarURL string `json:"avatar_url"`
+}
+
+type soundcloudPlaylist struct {
+	ID         int64             `json:"id"`
+	Title      string            `json:"title"`
+	ArtworkURL string            `json:"artwork_url"`
+	User       soundcloudUser    `json:"user"`
+	Tracks     []soundcloudTrack `json:"tracks"`
+}
+
+// soundcloudTranscodingRank orders encodings: the progressive MP3 is a plain file,
+// then HLS AAC (often the best quality), HLS MP3 and finally Opus
+func soundcloudTranscodingRank(t *soundcloudTranscoding) int {
+	progressive := t.Format.Protocol == "progressive"
+	switch mime := t.Format.MimeType; {
+	case progressive && strings.HasPrefix(mime, "audio/mpeg"):
+		return 0
+	case strings.HasPrefix(mime, "audio/mp4"):
+		return 1
+	case strings.HasPrefix(mime, "audio/mpeg"):
+		return 2
+	case strings.HasPrefix(mime, "audio/ogg"):
+		return 3
+	}
+	return 4
+}
+
+// soundcloudExt maps a transcoding's MIME type to a file extension
+func soundcloudExt(mime string) string {
+	switch {
+	case strings.HasPrefix(mime, "audio/mp4"):
+		return "m4a"
+	case strings.HasPrefix(mime, "audio/ogg"):
+		return "opus"
+	}
+	return "mp3"
+}
+
+// pickTranscoding returns the preferred full-length transcoding
+func pickTranscoding(track *soundcloudTrack) (*soundcloudTranscoding, error) {
+	var best *soundcloudTranscoding
+	snipped := false
+	for i := range track.Media.Transcodings {
+		t := &track.Media.Transcodings[i]
+		if t.Snipped {
+			snipped = true
+			continue
+		}
+		if best == nil || soundcloudTranscodingRank(t) < soundcloudTranscodingRank(best) {
+			best = t
+		}
+	}
+	if best == nil {
+		if snipped || track.Policy == "SNIP" {
+			return nil, fmt.Errorf("%q is only available as a 30-second preview (SoundCloud Go+)", track.Title)
+		}
+		return nil, fmt.Errorf("%q has no streams (blocked in your region or removed)", track.Title)
+	}
+	return best, nil
+}
+
+// audioMedia resolves a track's stream URL. Tracks without artwork get fallbackCover
+// (the set's artwork, or the uploader's avatar as on the site).
+func (e *SoundCloudExtractor) audioMedia(ctx context.Context, client *http.Client, track *soundcloudTrack, fallbackCover string) (*AudioMedia, error) {
+	transcoding, err := pickTranscoding(track)
+	if err != nil {
+		return nil, err
+	}
+
+	streamURL := transcoding.URL
+	if track.TrackAuthorization != "" {
+		streamURL += "?track_authorization=" + url.QueryEscape(track.TrackAuthorization)
+	}
+	var stream struct {
+		URL string `json:"url"`
+	}
+	if err := e.api(ctx, client, streamURL, &stream); err != nil {
+		return nil, fmt.Errorf("failed to get stream for %q: %w", track.Title, err)
+	}
+	if stream.URL == "" {
+		return nil, fmt.Errorf("no stream URL for %q", track.Title)
+	}
+
+	cover := soundcloudArtwork(track.ArtworkURL)
+	if cover == "" {
+		cover = fallbackCover
+	}
+
+	return &AudioMedia{
+		ID:          fmt.Sprintf("%d", track.ID),
+		Title:       track.Title,
+		Uploader:    track.User.Username,
+		Duration:    track.Duration / 1000,
+		URL:         stream.URL,
+		Ext:         soundcloudExt(transcoding.Format.MimeType),
+		PublishedAt: track.DisplayDate,
+		Description: track.Description,
+		CoverURL:    cover,
+	}, nil
+}
+
+// playlist expands a set into its tracks, fetching the stubs
+func (e *SoundCloudExtractor) playlist(ctx context.Context, client *http.Client, playlist *soundcloudPlaylist) (*AlbumMedia, error) {
+	tracks, err := e.fillTracks(ctx, client, playlist.Tracks)
+	if err != nil {
+		return nil, err
+	}
+
+	album := &AlbumMedia{
+		ID:       fmt.Sprintf("%d", playlist.ID),
+		Title:    playlist.Title,
+		Uploader: playlist.User.Username,
+		CoverURL: soundcloudArtwork(playlist.ArtworkURL),
+	}
+	for i := range tracks {
+		audio, err := e.audioMedia(ctx, client, &tracks[i], album.CoverURL)
+		if err != nil {
+			continue // previews and region-blocked tracks are left out
+		}
+		audio.Album = playlist.Title
+		audio.TrackNumber = i + 1
+		album.Tracks = append(album.Tracks, audio)
+	}
+	if len(album.Tracks) == 0 {
+		return nil, fmt.Errorf("no downloadable tracks in %q", playlist.Title)
+	}
+	return album, nil
+}
+
+// fillTracks replaces track stubs (only an ID) with full tracks, keeping their order
+func (e *SoundCloudExtractor) fillTracks(ctx context.Context, client *http.Client, tracks []soundcloudTrack) ([]soundcloudTrack, error) {
+	var missing []string
+	for _, t := range tracks {
+		if t.Title == "" {
+			missing = append(missing, fmt.Sprintf("%d", t.ID))
+		}
+	}
+
+	full := make(map[int64]soundcloudTrack)
+	for start := 0; start < len(missing); start += soundcloudTrackBatch {
+		end := min(start+soundcloudTrackBatch, len(missing))
+		var batch []soundcloudTrack
+		if err := e.api(ctx, client, "/tracks?ids="+strings.Join(missing[start:end], ","), &batch); err != nil {
+			return nil, fmt.Errorf("failed to fetch playlist tracks: %w", err)
+		}
+		for _, t := range batch {
+			full[t.ID] = t
+		}
+	}
+
+	result := make([]soundcloudTrack, 0, len(tracks))
+	for _, t := range tracks {
+		if t.Title == "" {
+			var ok bool
+			if t, ok = full[t.ID]; !ok {
+				continue // deleted or private
+			}
+		}
+		result = append(result, t)
+	}
+	return result, nil
+}
+
+// likes lists the tracks a user liked, newest first; liked sets are left out
+func (e *SoundCloudExtractor) likes(ctx context.Context, client *http.Client, user *soundcloudUser) (*AlbumMedia, error) {
+	var tracks []soundcloudTrack
+	next := fmt.Sprintf("/users/%d/likes?limit=200", user.ID)
+	for next != "" {
+		var page struct {
+			Collection []struct {
+				Track *soundcloudTrack `json:"track"`
+			} `json:"collection"`
+			NextHref string `json:"next_href"`
+		}
+		if err := e.api(ctx, client, next, &page); err != nil {
+			return nil, fmt.Errorf("failed to fetch likes: %w", err)
+		}
+		for _, item := range page.Collection {
+			if item.Track != nil {
+				tracks = append(tracks, *item.Track)
+			}
+		}
+		next = page.NextHref
+	}
+
+	likes := &AlbumMedia{
+		ID:       fmt.Sprintf("%d-likes", user.ID),
+		Title:    user.Username + " - Likes",
+		Uploader: user.Username,
+	}
+	for i := range tracks {
+		audio, err := e.audioMedia(ctx, client, &tracks[i], soundcloudArtwork(tracks[i].User.AvatarURL))
+		if err != nil {
+			continue
+		}
+		likes.Tracks = append(likes.Tracks, audio)
+	}
+	if len(likes.Tracks) == 0 {
+		return nil, fmt.Errorf("no downloadable liked tracks for %s", user.Username)
+	}
+	return likes, nil
+}
+
+// soundcloudArtwork returns the 500x500 rendition of an artwork or avatar URL ("-large" is 100x100)
+func soundcloudArtwork(artworkURL string) string {
+	return strings.Replace(artworkURL, "-large.", "-t500x500.", 1)
+}
+
+func init() {
+	Register(&SoundCloudExtractor{},
+		"soundcloud.com",
+		"m.soundcloud.com",
+		"on.soundcloud.com",
+	)
+}
@@ -0,0 +1,111 @@
+package extractor
+
+import (
+	"net/url"
+	"path/filepath"
+	"testing"
+
+	"github.com/guiyumin/vget/internal/testutil/replay"
+)
+
+func newSoundCloudTestExtractor(t *testing.T) *SoundCloudExtractor {
+	return &SoundCloudExtractor{client: replay.Client(t, filepath.Join("testdata", "replay", "soundcloud"))}
+}
+
+func TestSoundCloudMatch(t *testing.T) {
+	tests := map[string]bool{
+		"https://soundcloud.com/gopher/bridge-song":             true,
+		"https://soundcloud.com/gopher/sets/bridges-ep":         true,
+		"https://soundcloud.com/gopher/likes":                   true,
+		"https://m.soundcloud.com/gopher/bridge-song?in=x":      true,
+		"https://on.soundcloud.com/AbCd123":                     true,
+		"https://soundcloud.com/gopher":                         false,
+		"https://soundcloud.com/gopher/tracks":                  false,
+		"https://soundcloud.com/gopher/sets":                    false,
+		"https://soundcloud.com/discover/sets/charts-top:all":   false,
+		"https://soundcloud.com/search/sounds?q=gopher":         false,
+		"https://soundcloud.com/gopher/bridge-song/recommended": false,
+	}
+	e := &SoundCloudExtractor{}
+	for rawURL, want := range tests {
+		u, _ := url.Parse(rawURL)
+		if got := e.Match(u); got != want {
+			t.Errorf("Match(%q) = %v, want %v", rawURL, got, want)
+		}
+	}
+}
+
+func TestSoundCloudTrack(t *testing.T) {
+	e := newSoundCloudTestExtractor(t)
+	// A rotated client_id is rejected and rediscovered from the web app's scripts
+	e.clientID = "STALEclientid00000000000000000000"
+
+	media, err := e.Extract("https://soundcloud.com/gopher/bridge-song?utm_source=clipboard")
+	if err != nil {
+		t.Fatalf("Extract: %v", err)
+	}
+	if e.clientID != "Zx9aB3cD4eF5gH6iJ7kL8mN9oP0qR1sT" {
+		t.Errorf("client_id = %q", e.clientID)
+	}
+
+	track, ok := media.(*AudioMedia)
+	if !ok {
+		t.Fatalf("expected *AudioMedia, got %T", media)
+	}
+	if track.ID != "1001" || track.Title != "Bridge Song" || track.Uploader != "Gopher Band" || track.Duration != 201 {
+		t.Errorf("unexpected metadata: %+v", track)
+	}
+	// The progressive MP3 is preferred over the HLS streams
+	if track.URL != "https://cf-media.sndcdn.com/bridgesong.128.mp3?Policy=abc&Signature=def" || track.Ext != "mp3" {
+		t.Errorf("unexpected stream: %s (%s)", track.URL, track.Ext)
+	}
+	if track.CoverURL != "https://i1.sndcdn.com/artworks-000001-abc-t500x500.jpg" {
+		t.Errorf("unexpected artwork: %s", track.CoverURL)
+	}
+}
+
+func TestSoundCloudSet(t *testing.T) {
+	media, err := newSoundCloudTestExtractor(t).Extract("https://soundcloud.com/gopher/sets/bridges-ep")
+	if err != nil {
+		t.Fatalf("Extract: %v", err)
+	}
+	album, ok := media.(*AlbumMedia)
+	if !ok {
+		t.Fatalf("expected *AlbumMedia, got %T", media)
+	}
+	if album.Title != "Bridges EP" || album.Uploader != "Gopher Band" || album.CoverURL != "https://i1.sndcdn.com/artworks-000777-set-t500x500.jpg" {
+		t.Errorf("unexpected album: %+v", album)
+	}
+
+	// The stub is fetched, the deleted track and the Go+ preview are left out
+	if len(album.Tracks) != 2 {
+		t.Fatalf("expected 2 tracks, got %d", len(album.Tracks))
+	}
+	second := album.Tracks[1]
+	if second.Title != "River Song" || second.TrackNumber != 2 || second.Album != "Bridges EP" {
+		t.Errorf("unexpected track: %+v", second)
+	}
+	// Without a progressive stream, HLS AAC wins over MP3 and Opus
+	if second.Ext != "m4a" || second.URL != "https://cf-hls-media.sndcdn.com/playlist/riversong.aac/playlist.m3u8?Policy=abc" {
+		t.Errorf("unexpected stream: %s (%s)", second.URL, second.Ext)
+	}
+	if second.CoverURL != album.CoverURL {
+		t.Errorf("expected the set's artwork for a track without its own, got %s", second.CoverURL)
+	}
+}
+
+func TestSoundCloudLikes(t *testing.T) {
+	media, err := newSoundCloudTestExtractor(t).Extract("https://soundcloud.com/gopher/likes")
+	if err != nil {
+		t.Fatalf("Extract: %v", err)
+	}
+	likes := media.(*AlbumMedia)
+
+	// Both pages, without the liked set
+	if len(likes.Tracks) != 2 || likes.Tracks[0].ID != "1001" || likes.Tracks[1].ID != "1002" {
+		t.Fatalf("unexpected likes: %+v", likes.Tracks)
+	}
+	if likes.Tracks[0].TrackNumber != 0 {
+		t.Errorf("likes aren't an album, got track number %d", likes.Tracks[0].TrackNumber)
+	}
+}
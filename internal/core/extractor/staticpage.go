@@ -0,0 +1,558 @@
+package extractor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+)
+
+// staticPageUserAgent is a desktop browser UA; many sites only emit OpenGraph tags for browsers
+const staticPageUserAgent = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
+
+// staticVideoExts and staticAudioExts are direct media extensions accepted from page metadata
+var (
+	staticVideoExts = map[string]bool{"mp4": true, "webm": true, "mov": true, "m4v": true, "m3u8": true, "mkv": true}
+	staticAudioExts = map[string]bool{"mp3": true, "m4a": true, "aac": true, "ogg": true, "opus": true, "wav": true, "flac": true}
+)
+
+// iso8601DurationRegex matches schema.org durations like PT1H2M3S
+var iso8601DurationRegex = regexp.MustCompile(`^P(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+(?:\.\d+)?)S)?)?$`)
+
+// StaticPageExtractor finds media in plain HTML without a browser: OpenGraph and
+// Twitter card tags, JSON-LD VideoObject/AudioObject and <video>/<audio> elements.
+// It is tried for unknown hosts before launching the browser extractor.
+type StaticPageExtractor struct {
+	client *http.Client
+}
+
+// NewStaticPageExtractor creates a static HTML extractor
+func NewStaticPageExtractor() *StaticPageExtractor {
+	return &StaticPageExtractor{}
+}
+
+func (e *StaticPageExtractor) Name() string {
+	return "static-page"
+}
+
+// Info describes the URLs and media this extractor handles
+func (e *StaticPageExtractor) Info() Info {
+	return Info{
+		Name:       "static-page",
+		Title:      "Page metadata",
+		Matches:    "any other page with OpenGraph, JSON-LD or video/audio tags",
+		Kinds:      []URLKind{URLKindSingle},
+		MediaTypes: []MediaType{MediaTypeVideo, MediaTypeAudio},
+	}
+}
+
+func (e *StaticPageExtractor) Match(u *url.URL) bool {
+	return true // Used only for unknown hosts
+}
+
+func (e *StaticPageExtractor) Extract(rawURL string) (Media, error) {
+	return e.ExtractContext(context.Background(), rawURL, Options{})
+}
+
+func (e *StaticPageExtractor) ExtractContext(ctx context.Context, rawURL string, opts Options) (Media, error) {
+	client := e.client
+	if client == nil {
+		client = newHTTPClient(opts, 30*time.Second)
+	}
+
+	pageURL, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", staticPageUserAgent)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+	if opts.Cookie != "" {
+		req.Header.Set("Cookie", opts.Cookie)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch page: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch page: HTTP %d", resp.StatusCode)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "" && !strings.Contains(ct, "html") {
+		return nil, fmt.Errorf("not an HTML page (%s)", ct)
+	}
+
+	// Metadata lives in <head>; cap the read for huge pages
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 5<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read page: %w", err)
+	}
+
+	meta := parseStaticPage(string(body), resp.Request.URL)
+	media := meta.toMedia(pageURL)
+	if media == nil {
+		return nil, fmt.Errorf("no media found in page metadata")
+	}
+	return media, nil
+}
+
+// staticCandidate is a media URL found in the page
+type staticCandidate struct {
+	URL      string
+	MimeType string
+	Width    int
+	Height   int
+}
+
+// staticPageMeta is everything collected from a page
+type staticPageMeta struct {
+	Title       string
+	Uploader    string
+	Thumbnail   string
+	Duration    int
+	Description string
+	PublishedAt time.Time
+	Videos      []staticCandidate
+	Audios      []staticCandidate
+}
+
+// parseStaticPage walks the HTML once, collecting meta tags, JSON-LD and media elements
+func parseStaticPage(body string, base *url.URL) *staticPageMeta {
+	meta := &staticPageMeta{}
+	tags := make(map[string]string)
+
+	var (
+		docTitle  string
+		inTitle   bool
+		inLDJSON  bool
+		ldBlocks  []string
+		mediaTag  string // "video" or "audio" while inside one
+		elemVideo []staticCandidate
+		elemAudio []staticCandidate
+	)
+
+	resolve := func(ref string) string {
+		ref = strings.TrimSpace(ref)
+		if ref == "" || strings.HasPrefix(ref, "blob:") || strings.HasPrefix(ref, "data:") {
+			return ""
+		}
+		u, err := base.Parse(ref)
+		if err != nil {
+			return ""
+		}
+		return u.String()
+	}
+
+	addElem := func(kind, src, mimeType string) {
+		src = resolve(src)
+		if src == "" {
+			return
+		}
+		c := staticCandidate{URL: src, MimeType: mimeType}
+		if kind == "audio" {
+			elemAudio = append(elemAudio, c)
+		} else {
+			elemVideo = append(elemVideo, c)
+		}
+	}
+
+	z := html.NewTokenizer(strings.NewReader(body))
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			break
+		}
+
+		switch tt {
+		case html.StartTagToken, html.SelfClosingTagToken:
+			tok := z.Token()
+			attrs := make(map[string]string, len(tok.Attr))
+			for _, a := range tok.Attr {
+				attrs[strings.ToLower(a.Key)] = a.Val
+			}
+
+			switch tok.Data {
+			case "meta":
+				key := strings.ToLower(attrs["property"])
+				if key == "" {
+					key = strings.ToLower(attrs["name"])
+				}
+				// Keep the first value; later duplicates usually describe alternate renditions
+				if key != "" && attrs["content"] != "" {
+					if _, exists := tags[key]; !exists {
+						tags[key] = strings.TrimSpace(attrs["content"])
+					}
+				}
+			case "title":
+				inTitle = true
+			case "script":
+				inLDJSON = strings.EqualFold(attrs["type"], "application/ld+json")
+			case "video", "audio":
+				mediaTag = tok.Data
+				addElem(tok.Data, attrs["src"], "")
+				if tok.Data == "video" && meta.Thumbnail == "" {
+					meta.Thumbnail = resolve(attrs["poster"])
+				}
+			case "source":
+				if mediaTag != "" {
+					addElem(mediaTag, attrs["src"], attrs["type"])
+				}
+			}
+
+		case html.EndTagToken:
+			name, _ := z.TagName()
+			switch string(name) {
+			case "title":
+				inTitle = false
+			case "script":
+				inLDJSON = false
+			case "video", "audio":
+				mediaTag = ""
+			}
+
+		case html.TextToken:
+			if inTitle && docTitle == "" {
+				docTitle = strings.TrimSpace(string(z.Text()))
+			}
+			if inLDJSON {
+				ldBlocks = append(ldBlocks, string(z.Text()))
+			}
+		}
+	}
+
+	// OpenGraph / Twitter card
+	for _, key := range []string{"og:video:secure_url", "og:video:url", "og:video", "twitter:player:stream"} {
+		if src := resolve(tags[key]); src != "" {
+			mimeType := tags["og:video:type"]
+			if key == "twitter:player:stream" {
+				mimeType = tags["twitter:player:stream:content_type"]
+			}
+			width, _ := strconv.Atoi(tags["og:video:width"])
+			height, _ := strconv.Atoi(tags["og:video:height"])
+			meta.Videos = append(meta.Videos, staticCandidate{URL: src, MimeType: mimeType, Width: width, Height: height})
+		}
+	}
+	for _, key := range []string{"og:audio:secure_url", "og:audio:url", "og:audio"} {
+		if src := resolve(tags[key]); src != "" {
+			meta.Audios = append(meta.Audios, staticCandidate{URL: src, MimeType: tags["og:audio:type"]})
+		}
+	}
+
+	// JSON-LD
+	for _, block := range ldBlocks {
+		var data any
+		if err := json.Unmarshal([]byte(strings.TrimSpace(block)), &data); err != nil {
+			continue
+		}
+		for _, obj := range findLDObjects(data) {
+			kind := ldType(obj)
+			if kind != "VideoObject" && kind != "AudioObject" {
+				continue
+			}
+			src := resolve(ldString(obj["contentUrl"]))
+			if src == "" {
+				continue
+			}
+			c := staticCandidate{URL: src, MimeType: ldString(obj["encodingFormat"])}
+			c.Width, _ = strconv.Atoi(strings.TrimSuffix(ldString(obj["width"]), "px"))
+			c.Height, _ = strconv.Atoi(strings.TrimSuffix(ldString(obj["height"]), "px"))
+			if kind == "AudioObject" {
+				meta.Audios = append(meta.Audios, c)
+			} else {
+				meta.Videos = append(meta.Videos, c)
+			}
+
+			if meta.Title == "" {
+				meta.Title = ldString(obj["name"])
+			}
+			if meta.Uploader == "" {
+				meta.Uploader = ldName(obj["author"])
+			}
+			if meta.Thumbnail == "" {
+				meta.Thumbnail = resolve(ldImage(obj["thumbnailUrl"]))
+			}
+			if meta.Duration == 0 {
+				meta.Duration = parseISO8601Duration(ldString(obj["duration"]))
+			}
+			if meta.Description == "" {
+				meta.Description = ldString(obj["description"])
+			}
+			if meta.PublishedAt.IsZero() {
+				meta.PublishedAt, _ = time.Parse(time.RFC3339, ldString(obj["uploadDate"]))
+			}
+		}
+	}
+
+	// <video>/<audio> elements last: they often point at ads or previews
+	meta.Videos = append(meta.Videos, elemVideo...)
+	meta.Audios = append(meta.Audios, elemAudio...)
+
+	// Page-level fallbacks
+	if t := firstNonEmpty(tags["og:title"], tags["twitter:title"]); t != "" {
+		meta.Title = t
+	}
+	if meta.Title == "" {
+		meta.Title = docTitle
+	}
+	if meta.Uploader == "" {
+		meta.Uploader = firstNonEmpty(tags["author"], tags["article:author"], tags["og:site_name"])
+	}
+	if img := resolve(firstNonEmpty(tags["og:image:secure_url"], tags["og:image"], tags["twitter:image"])); img != "" {
+		meta.Thumbnail = img
+	}
+	if meta.Duration == 0 {
+		meta.Duration, _ = strconv.Atoi(firstNonEmpty(tags["og:video:duration"], tags["video:duration"]))
+	}
+	if meta.Description == "" {
+		meta.Description = firstNonEmpty(tags["og:description"], tags["description"])
+	}
+
+	return meta
+}
+
+// toMedia converts the collected metadata into VideoMedia or AudioMedia, or nil if
+// the page only embeds players (HTML/Flash) rather than direct media files
+func (m *staticPageMeta) toMedia(pageURL *url.URL) Media {
+	title := SanitizeFilename(m.Title)
+	if title == "" {
+		title = path.Base(pageURL.Path)
+	}
+	headers := map[string]string{
+		"Referer":    pageURL.String(),
+		"User-Agent": staticPageUserAgent,
+	}
+
+	var formats []VideoFormat
+	seen := make(map[string]bool)
+	for _, c := range m.Videos {
+		ext := staticMediaExt(c, staticVideoExts, "video/")
+		if ext == "" || seen[c.URL] {
+			continue
+		}
+		seen[c.URL] = true
+		formats = append(formats, VideoFormat{
+			URL:     c.URL,
+			Quality: qualityFromHeight(c.Height),
+			Ext:     ext,
+			Width:   c.Width,
+			Height:  c.Height,
+			Headers: headers,
+		})
+	}
+
+	id := path.Base(pageURL.Path)
+	if id == "" || id == "/" || id == "." {
+		id = pageURL.Hostname()
+	}
+
+	if len(formats) > 0 {
+		return &VideoMedia{
+			ID:        id,
+			Title:     title,
+			Uploader:  m.Uploader,
+			Duration:  m.Duration,
+			Thumbnail: m.Thumbnail,
+			Formats:   formats,
+		}
+	}
+
+	for _, c := range m.Audios {
+		if ext := staticMediaExt(c, staticAudioExts, "audio/"); ext != "" {
+			return &AudioMedia{
+				ID:          id,
+				Title:       title,
+				Uploader:    m.Uploader,
+				Duration:    m.Duration,
+				URL:         c.URL,
+				Ext:         ext,
+				PublishedAt: m.PublishedAt,
+				Description: m.Description,
+				CoverURL:    m.Thumbnail,
+			}
+		}
+	}
+
+	return nil
+}
+
+// staticMediaExt returns the file extension for a direct media candidate, or "" for
+// embedded players and unknown types
+func staticMediaExt(c staticCandidate, exts map[string]bool, mimePrefix string) string {
+	mimeType := strings.ToLower(c.MimeType)
+	if strings.Contains(mimeType, "mpegurl") {
+		return "m3u8"
+	}
+
+	if u, err := url.Parse(c.URL); err == nil {
+		if ext := strings.TrimPrefix(strings.ToLower(path.Ext(u.Path)), "."); exts[ext] {
+			return ext
+		}
+	}
+
+	if strings.HasPrefix(mimeType, mimePrefix) {
+		switch sub := strings.TrimPrefix(mimeType, mimePrefix); sub {
+		case "mpeg":
+			return "mp3"
+		case "quicktime":
+			return "mov"
+		case "x-m4a":
+			return "m4a"
+		default:
+			if exts[sub] {
+				return sub
+			}
+			if mimePrefix == "video/" {
+				return "mp4"
+			}
+		}
+	}
+	return ""
+}
+
+// findLDObjects flattens JSON-LD (arrays and @graph) into a list of objects
+func findLDObjects(v any) []map[string]any {
+	var result []map[string]any
+	switch val := v.(type) {
+	case []any:
+		for _, item := range val {
+			result = append(result, findLDObjects(item)...)
+		}
+	case map[string]any:
+		result = append(result, val)
+		if graph, ok := val["@graph"]; ok {
+			result = append(result, findLDObjects(graph)...)
+		}
+		// VideoObject is often nested, e.g. in an Article's "video" field
+		for _, key := range []string{"video", "audio", "associatedMedia", "mainEntity"} {
+			if child, ok := val[key]; ok {
+				result = append(result, findLDObjects(child)...)
+			}
+		}
+	}
+	return result
+}
+
+// ldType returns the schema.org @type, which may be a string or an array
+func ldType(obj map[string]any) string {
+	switch t := obj["@type"].(type) {
+	case string:
+		return t
+	case []any:
+		for _, v := range t {
+			if s, ok := v.(string); ok && (s == "VideoObject" || s == "AudioObject") {
+				return s
+			}
+		}
+	}
+	return ""
+}
+
+func ldString(v any) string {
+	switch val := v.(type) {
+	case string:
+		return strings.TrimSpace(val)
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	}
+	return ""
+}
+
+// ldName reads a Person/Organization name, a plain string, or the first of an array
+func ldName(v any) string {
+	switch val := v.(type) {
+	case map[string]any:
+		return ldString(val["name"])
+	case []any:
+		if len(val) > 0 {
+			return ldName(val[0])
+		}
+	}
+	return ldString(v)
+}
+
+// ldImage reads an ImageObject, URL string, or the first of an array
+func ldImage(v any) string {
+	switch val := v.(type) {
+	case map[string]any:
+		return ldString(val["url"])
+	case []any:
+		if len(val) > 0 {
+			return ldImage(val[0])
+		}
+	}
+	return ldString(v)
+}
+
+func parseISO8601Duration(s string) int {
+	m := iso8601DurationRegex.FindStringSubmatch(s)
+	if m == nil {
+		return 0
+	}
+	days, _ := strconv.Atoi(m[1])
+	hours, _ := strconv.Atoi(m[2])
+	minutes, _ := strconv.Atoi(m[3])
+	seconds, _ := strconv.ParseFloat(m[4], 64)
+	return days*86400 + hours*3600 + minutes*60 + int(seconds)
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// FallbackExtractor tries Primary and, if it fails, Fallback. It lets cheap
+// extractors run before expensive ones (e.g., static HTML before a browser).
+type FallbackExtractor struct {
+	Primary  Extractor
+	Fallback Extractor
+}
+
+// WithFallback chains two extractors
+func WithFallback(primary, fallback Extractor) *FallbackExtractor {
+	return &FallbackExtractor{Primary: primary, Fallback: fallback}
+}
+
+func (e *FallbackExtractor) Name() string {
+	return e.Primary.Name()
+}
+
+func (e *FallbackExtractor) Match(u *url.URL) bool {
+	return e.Primary.Match(u) || e.Fallback.Match(u)
+}
+
+func (e *FallbackExtractor) Extract(rawURL string) (Media, error) {
+	media, err := e.Primary.Extract(rawURL)
+	if err == nil {
+		return media, nil
+	}
+	fmt.Printf("  %s: %v, trying %s...\n", e.Primary.Name(), err, e.Fallback.Name())
+	return e.Fallback.Extract(rawURL)
+}
+
+func (e *FallbackExtractor) ExtractContext(ctx context.Context, rawURL string, opts Options) (Media, error) {
+	media, err := ExtractContext(ctx, e.Primary, rawURL, opts)
+	if err == nil || ctx.Err() != nil {
+		return media, err
+	}
+	fmt.Printf("  %s: %v, trying %s...\n", e.Primary.Name(), err, e.Fallback.Name())
+	return ExtractContext(ctx, e.Fallback, rawURL, opts)
+}
@@ -0,0 +1,95 @@
+package extractor
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestParseStaticPage(t *testing.T) {
+	base, _ := url.Parse("https://example.com/watch/clip-1")
+
+	tests := []struct {
+		name      string
+		html      string
+		wantType  MediaType
+		wantURL   string
+		wantTitle string
+	}{
+		{
+			name: "OpenGraph video",
+			html: `<html><head><title>Fallback</title>
+				<meta property="og:title" content="My Clip">
+				<meta property="og:video" content="https://cdn.example.com/clip.mp4">
+				<meta property="og:video:height" content="720">
+				<meta property="og:image" content="/thumb.jpg"></head></html>`,
+			wantType:  MediaTypeVideo,
+			wantURL:   "https://cdn.example.com/clip.mp4",
+			wantTitle: "My Clip",
+		},
+		{
+			name: "embedded player is not media",
+			html: `<meta property="og:video" content="https://example.com/embed/1" >
+				<meta property="og:video:type" content="text/html">`,
+		},
+		{
+			name: "JSON-LD VideoObject in graph",
+			html: `<script type="application/ld+json">{"@graph":[{"@type":"WebPage"},
+				{"@type":"VideoObject","name":"LD Clip","contentUrl":"https://cdn.example.com/v/master.m3u8",
+				"duration":"PT1M30S","author":{"@type":"Person","name":"Ann"}}]}</script>`,
+			wantType:  MediaTypeVideo,
+			wantURL:   "https://cdn.example.com/v/master.m3u8",
+			wantTitle: "LD Clip",
+		},
+		{
+			name:      "video element with relative source",
+			html:      `<title>Page</title><video poster="/p.jpg"><source src="/media/clip.webm" type="video/webm"></video>`,
+			wantType:  MediaTypeVideo,
+			wantURL:   "https://example.com/media/clip.webm",
+			wantTitle: "Page",
+		},
+		{
+			name:      "audio only",
+			html:      `<meta property="og:title" content="Ep 1"><audio src="https://cdn.example.com/ep1.mp3"></audio>`,
+			wantType:  MediaTypeAudio,
+			wantURL:   "https://cdn.example.com/ep1.mp3",
+			wantTitle: "Ep 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			media := parseStaticPage(tt.html, base).toMedia(base)
+			if tt.wantURL == "" {
+				if media != nil {
+					t.Fatalf("expected no media, got %+v", media)
+				}
+				return
+			}
+			if media == nil {
+				t.Fatal("expected media, got nil")
+			}
+			if media.Type() != tt.wantType || media.GetTitle() != tt.wantTitle {
+				t.Errorf("got %v %q, want %v %q", media.Type(), media.GetTitle(), tt.wantType, tt.wantTitle)
+			}
+
+			var gotURL string
+			switch m := media.(type) {
+			case *VideoMedia:
+				gotURL = m.Formats[0].URL
+			case *AudioMedia:
+				gotURL = m.URL
+			}
+			if gotURL != tt.wantURL {
+				t.Errorf("URL = %q, want %q", gotURL, tt.wantURL)
+			}
+		})
+	}
+}
+
+func TestParseISO8601Duration(t *testing.T) {
+	for in, want := range map[string]int{"PT1H2M3S": 3723, "PT90S": 90, "P1DT1S": 86401, "bogus": 0} {
+		if got := parseISO8601Duration(in); got != want {
+			t.Errorf("parseISO8601Duration(%q) = %d, want %d", in, got, want)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package extractor
 
 import (
+	"context"
 	"net/url"
 
 	"github.com/guiyumin/vget/internal/core/extractor/telegram"
@@ -33,12 +34,30 @@ func (t *TelegramExtractor) Name() string {
 	return t.ext.Name()
 }
 
+// Info describes the URLs and media this extractor handles
+func (t *TelegramExtractor) Info() Info {
+	return Info{
+		Name:       t.Name(),
+		Title:      "Telegram",
+		Kinds:      []URLKind{URLKindSingle},
+		MediaTypes: []MediaType{MediaTypeVideo, MediaTypeImage, MediaTypeAudio},
+		Requires: []Requirement{
+			{Kind: RequiresLogin, Detail: "vget telegram login"},
+		},
+	}
+}
+
 func (t *TelegramExtractor) Match(u *url.URL) bool {
 	return t.ext.Match(u)
 }
 
 func (t *TelegramExtractor) Extract(urlStr string) (Media, error) {
-	info, err := t.ext.Extract(urlStr)
+	return t.ExtractContext(context.Background(), urlStr, Options{})
+}
+
+// ExtractContext retrieves a Telegram message's media, aborting when ctx is done
+func (t *TelegramExtractor) ExtractContext(ctx context.Context, urlStr string, opts Options) (Media, error) {
+	info, err := t.ext.ExtractContext(ctx, urlStr)
 	if err != nil {
 		return nil, err
 	}
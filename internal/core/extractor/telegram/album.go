@@ -0,0 +1,208 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gotd/td/tg"
+)
+
+// maxAlbumSize is the most items Telegram allows in one media group. The
+// items are sent together, so their message IDs are consecutive.
+const maxAlbumSize = 10
+
+// fetchAlbum returns every message of msg's media group in posting order,
+// looking at the IDs an album containing msg could span
+func fetchAlbum(ctx context.Context, api *tg.Client, channel *tg.InputChannel, msg *tg.Message) ([]*tg.Message, error) {
+	var ids []tg.InputMessageClass
+	for id := msg.ID - maxAlbumSize + 1; id < msg.ID+maxAlbumSize; id++ {
+		if id > 0 {
+			ids = append(ids, &tg.InputMessageID{ID: id})
+		}
+	}
+
+	res, err := api.ChannelsGetMessages(ctx, &tg.ChannelsGetMessagesRequest{
+		Channel: channel,
+		ID:      ids,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get album: %w", err)
+	}
+
+	var album []*tg.Message
+	if page, ok := res.AsModified(); ok {
+		for _, m := range page.GetMessages() {
+			if m, ok := m.(*tg.Message); ok && m.GroupedID == msg.GroupedID && m.Media != nil {
+				album = append(album, m)
+			}
+		}
+	}
+	if len(album) == 0 {
+		return []*tg.Message{msg}, nil
+	}
+	slices.SortFunc(album, func(a, b *tg.Message) int { return a.ID - b.ID })
+	return album, nil
+}
+
+// albumCaption returns the album's text, which Telegram keeps on whichever
+// item the sender captioned (usually the first)
+func albumCaption(album []*tg.Message) string {
+	for _, m := range album {
+		if strings.TrimSpace(m.Message) != "" {
+			return m.Message
+		}
+	}
+	return ""
+}
+
+// downloadAlbum downloads every item of a media group as "{base}_{n}.{ext}",
+// reporting progress across the whole album
+func downloadAlbum(
+	ctx context.Context,
+	api *tg.Client,
+	album []*tg.Message,
+	base string,
+	progressFn func(downloaded, total int64),
+) (*DownloadResult, error) {
+	var total int64
+	for _, m := range album {
+		total += mediaSize(m.Media)
+	}
+
+	result := &DownloadResult{Title: base, Size: total}
+	var done int64
+	for i, m := range album {
+		path := fmt.Sprintf("%s_%d.%s", base, i+1, mediaExt(m.Media))
+		res, err := downloadMedia(ctx, api, m, path, func(downloaded, _ int64) {
+			if progressFn != nil {
+				progressFn(done+downloaded, total)
+			}
+		})
+		if err != nil {
+			return nil, fmt.Errorf("album item %d of %d: %w", i+1, len(album), err)
+		}
+		done += res.Size
+		if progressFn != nil {
+			progressFn(done, total)
+		}
+		result.Files = append(result.Files, res.Filename)
+	}
+	result.Filename = result.Files[0]
+	return result, nil
+}
+
+// downloadMedia downloads a message's photo or document to outputPath
+// (named after the message when empty)
+func downloadMedia(
+	ctx context.Context,
+	api *tg.Client,
+	msg *tg.Message,
+	outputPath string,
+	progressFn func(downloaded, total int64),
+) (*DownloadResult, error) {
+	switch media := msg.Media.(type) {
+	case *tg.MessageMediaDocument:
+		return downloadDocument(ctx, api, media, msg, outputPath, progressFn)
+	case *tg.MessageMediaPhoto:
+		return downloadPhoto(ctx, api, media, msg, outputPath, progressFn)
+	default:
+		return nil, fmt.Errorf("unsupported media type: %T", media)
+	}
+}
+
+// mediaFilename names a message's file after the document's own filename,
+// or after the caption (or message ID) with the media's extension
+func mediaFilename(msg *tg.Message) string {
+	if m, ok := msg.Media.(*tg.MessageMediaDocument); ok {
+		if doc, ok := m.Document.(*tg.Document); ok {
+			info := ExtractDocumentInfo(doc, msg.Message, msg.ID)
+			if name := sanitizeFilename(info.Filename); name != "" {
+				return name
+			}
+			return fmt.Sprintf("%s.%s", sanitizeFilename(info.Title), info.Ext)
+		}
+	}
+	title := truncateText(msg.Message, 100)
+	if title == "" {
+		title = fmt.Sprintf("telegram_%d", msg.ID)
+	}
+	return fmt.Sprintf("%s.%s", sanitizeFilename(title), mediaExt(msg.Media))
+}
+
+func mediaSize(media tg.MessageMediaClass) int64 {
+	switch m := media.(type) {
+	case *tg.MessageMediaDocument:
+		if doc, ok := m.Document.(*tg.Document); ok {
+			return doc.Size
+		}
+	case *tg.MessageMediaPhoto:
+		if photo, ok := m.Photo.(*tg.Photo); ok {
+			if largest := FindLargestPhotoSize(photo.Sizes); largest != nil {
+				return int64(largest.Size)
+			}
+		}
+	}
+	return 0
+}
+
+func mediaExt(media tg.MessageMediaClass) string {
+	if m, ok := media.(*tg.MessageMediaDocument); ok {
+		if doc, ok := m.Document.(*tg.Document); ok {
+			return ExtractDocumentInfo(doc, "", 0).Ext
+		}
+	}
+	return "jpg"
+}
+
+// messageSender names who posted a message: the signed author of a channel
+// post, the user in a group, or else the channel itself
+func messageSender(msg *tg.Message, res tg.MessagesMessagesClass, channelTitle string) string {
+	if msg.PostAuthor != "" {
+		return msg.PostAuthor
+	}
+	if from, ok := msg.FromID.(*tg.PeerUser); ok {
+		if page, ok := res.AsModified(); ok {
+			for _, u := range page.GetUsers() {
+				if user, ok := u.(*tg.User); ok && user.ID == from.UserID {
+					return formatUser(user)
+				}
+			}
+		}
+	}
+	return channelTitle
+}
+
+func formatUser(user *tg.User) string {
+	name := strings.TrimSpace(user.FirstName + " " + user.LastName)
+	switch {
+	case name == "":
+		return "@" + user.Username
+	case user.Username != "":
+		return fmt.Sprintf("%s (@%s)", name, user.Username)
+	default:
+		return name
+	}
+}
+
+// captionNotes renders the markdown sidecar for a message's caption
+func captionNotes(caption, channel, sender string, posted time.Time, link string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", truncateText(strings.SplitN(caption, "\n", 2)[0], 100))
+	if channel != "" {
+		fmt.Fprintf(&b, "- Channel: %s\n", channel)
+	}
+	if sender != "" && sender != channel {
+		fmt.Fprintf(&b, "- From: %s\n", sender)
+	}
+	if !posted.IsZero() {
+		fmt.Fprintf(&b, "- Posted: %s\n", posted.Format("2006-01-02 15:04"))
+	}
+	if link != "" {
+		fmt.Fprintf(&b, "- Link: %s\n", link)
+	}
+	fmt.Fprintf(&b, "\n%s\n", strings.TrimSpace(caption))
+	return b.String()
+}
@@ -0,0 +1,63 @@
+package telegram
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gotd/td/tg"
+)
+
+func TestParseURLSingle(t *testing.T) {
+	tests := map[string]bool{
+		"https://t.me/coursechannel/120":          false,
+		"https://t.me/coursechannel/120?single":   true,
+		"https://t.me/c/1234567890/45?single=1":   true,
+		"https://t.me/c/1234567890/45?comment=10": false,
+	}
+	for rawURL, want := range tests {
+		msg, err := ParseURL(rawURL)
+		if err != nil {
+			t.Fatalf("ParseURL(%q): %v", rawURL, err)
+		}
+		if msg.Single != want {
+			t.Errorf("ParseURL(%q).Single = %v, want %v", rawURL, msg.Single, want)
+		}
+	}
+}
+
+func TestCaptionNotes(t *testing.T) {
+	album := []*tg.Message{
+		{ID: 41, GroupedID: 7},
+		{ID: 42, GroupedID: 7, Message: "Week 3: Recursion\nSlides and both recordings."},
+		{ID: 43, GroupedID: 7},
+	}
+	caption := albumCaption(album)
+	if caption != album[1].Message {
+		t.Fatalf("albumCaption = %q", caption)
+	}
+
+	res := &tg.MessagesChannelMessages{Users: []tg.UserClass{&tg.User{ID: 5, FirstName: "Ada", Username: "ada"}}}
+	sender := messageSender(&tg.Message{FromID: &tg.PeerUser{UserID: 5}}, res, "Course Channel")
+	if sender != "Ada (@ada)" {
+		t.Errorf("sender = %q", sender)
+	}
+	if got := messageSender(&tg.Message{}, res, "Course Channel"); got != "Course Channel" {
+		t.Errorf("channel post sender = %q", got)
+	}
+
+	posted := time.Date(2024, 9, 2, 18, 30, 0, 0, time.Local)
+	notes := captionNotes(caption, "Course Channel", sender, posted, "https://t.me/coursechannel/41")
+	for _, want := range []string{
+		"# Week 3: Recursion\n",
+		"- Channel: Course Channel\n",
+		"- From: Ada (@ada)\n",
+		"- Posted: 2024-09-02 18:30\n",
+		"- Link: https://t.me/coursechannel/41\n",
+		"\nWeek 3: Recursion\nSlides and both recordings.\n",
+	} {
+		if !strings.Contains(notes, want) {
+			t.Errorf("notes missing %q:\n%s", want, notes)
+		}
+	}
+}
@@ -0,0 +1,239 @@
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"runtime"
+	"sync"
+
+	"github.com/gotd/td/bin"
+	"github.com/gotd/td/crypto"
+	"github.com/gotd/td/exchange"
+	"github.com/gotd/td/mtproto"
+	"github.com/gotd/td/telegram/dcs"
+	"github.com/gotd/td/tg"
+	"github.com/gotd/td/transport"
+)
+
+// cdnDialer connects to a CDN DC. close ends the connection.
+type cdnDialer func(ctx context.Context, dcID int) (invoker tg.Invoker, close func(), err error)
+
+// dialCDN returns a dialer for the CDN DCs of the account behind api. CDN DCs
+// have their own RSA keys and addresses, which the main DC hands out.
+func dialCDN(api *tg.Client) cdnDialer {
+	return func(ctx context.Context, dcID int) (tg.Invoker, func(), error) {
+		cdnConfig, err := api.HelpGetCDNConfig(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get CDN config: %w", err)
+		}
+		var keys []exchange.PublicKey
+		for _, key := range cdnConfig.PublicKeys {
+			if key.DCID != dcID {
+				continue
+			}
+			parsed, err := crypto.ParseRSAPublicKeys([]byte(key.PublicKey))
+			if err != nil {
+				return nil, nil, fmt.Errorf("invalid key of CDN DC %d: %w", dcID, err)
+			}
+			for _, k := range parsed {
+				keys = append(keys, exchange.PublicKey{RSA: k})
+			}
+		}
+		if len(keys) == 0 {
+			return nil, nil, fmt.Errorf("no key for CDN DC %d", dcID)
+		}
+
+		config, err := api.HelpGetConfig(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get DC list: %w", err)
+		}
+		list := dcs.List{Options: config.DCOptions}
+		resolver := dcs.Plain(dcs.PlainOptions{})
+
+		conn := mtproto.New(func(ctx context.Context) (transport.Conn, error) {
+			return resolver.CDN(ctx, dcID, list)
+		}, mtproto.Options{DC: dcID, PublicKeys: keys})
+
+		runCtx, stop := context.WithCancel(context.Background())
+		ready := make(chan struct{})
+		done := make(chan struct{})
+		var runErr error
+		go func() {
+			defer close(done)
+			runErr = conn.Run(runCtx, func(ctx context.Context) error {
+				close(ready)
+				<-ctx.Done()
+				return nil
+			})
+		}()
+
+		select {
+		case <-ready:
+		case <-done:
+			stop()
+			return nil, nil, fmt.Errorf("failed to connect to CDN DC %d: %w", dcID, runErr)
+		case <-ctx.Done():
+			stop()
+			<-done
+			return nil, nil, ctx.Err()
+		}
+		return cdnInvoker{conn}, func() { stop(); <-done }, nil
+	}
+}
+
+// cdnInvoker sends requests to a CDN DC. CDN DCs don't keep a session, so
+// each request carries the layer and connection info.
+type cdnInvoker struct {
+	conn *mtproto.Conn
+}
+
+func (i cdnInvoker) Invoke(ctx context.Context, input bin.Encoder, output bin.Decoder) error {
+	query, ok := input.(bin.Object)
+	if !ok {
+		return fmt.Errorf("unexpected request %T", input)
+	}
+	return i.conn.Invoke(ctx, &tg.InvokeWithLayerRequest{
+		Layer: tg.Layer,
+		Query: &tg.InitConnectionRequest{
+			APIID:          DesktopAppID,
+			DeviceModel:    "vget",
+			SystemVersion:  runtime.GOOS,
+			AppVersion:     "vget",
+			SystemLangCode: "en",
+			LangCode:       "en",
+			Query:          query,
+		},
+	}, output)
+}
+
+// cdnFile reads a file Telegram redirected to a CDN DC. Parts come encrypted
+// and are checked against the hashes the main DC hands out, since the CDN
+// itself is not trusted.
+type cdnFile struct {
+	api      *tg.Client // the main DC: hashes and reuploads
+	cdn      *tg.Client
+	redirect *tg.UploadFileCDNRedirect
+
+	mu     sync.Mutex
+	hashes map[int64]tg.FileHash // by offset
+}
+
+func newCDNFile(api, cdn *tg.Client, redirect *tg.UploadFileCDNRedirect) *cdnFile {
+	f := &cdnFile{api: api, cdn: cdn, redirect: redirect, hashes: make(map[int64]tg.FileHash)}
+	f.addHashes(redirect.FileHashes)
+	return f
+}
+
+// chunk requests limit bytes at offset. A file not on the CDN yet is
+// uploaded there first (upload.reuploadCdnFile), which the main DC does.
+func (f *cdnFile) chunk(ctx context.Context, offset int64, limit int) ([]byte, error) {
+	for attempt := 0; attempt < 2; attempt++ {
+		res, err := f.cdn.UploadGetCDNFile(ctx, &tg.UploadGetCDNFileRequest{
+			FileToken: f.redirect.FileToken,
+			Offset:    offset,
+			Limit:     limit,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		switch r := res.(type) {
+		case *tg.UploadCDNFile:
+			data, err := f.decrypt(r.Bytes, offset)
+			if err != nil {
+				return nil, err
+			}
+			if err := f.verify(ctx, offset, data); err != nil {
+				return nil, err
+			}
+			return data, nil
+		case *tg.UploadCDNFileReuploadNeeded:
+			hashes, err := f.api.UploadReuploadCDNFile(ctx, &tg.UploadReuploadCDNFileRequest{
+				FileToken:    f.redirect.FileToken,
+				RequestToken: r.RequestToken,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to upload file to CDN: %w", err)
+			}
+			f.addHashes(hashes)
+		default:
+			return nil, fmt.Errorf("unexpected response type: %T", res)
+		}
+	}
+	return nil, fmt.Errorf("file is still not on CDN DC %d", f.redirect.DCID)
+}
+
+// decrypt decrypts a part with AES-256-CTR, the counter starting at offset/16
+func (f *cdnFile) decrypt(data []byte, offset int64) ([]byte, error) {
+	block, err := aes.NewCipher(f.redirect.EncryptionKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CDN key: %w", err)
+	}
+	if len(f.redirect.EncryptionIv) != block.BlockSize() {
+		return nil, fmt.Errorf("invalid CDN IV length %d", len(f.redirect.EncryptionIv))
+	}
+	iv := bytes.Clone(f.redirect.EncryptionIv)
+	binary.BigEndian.PutUint32(iv[len(iv)-4:], uint32(offset/16))
+
+	out := make([]byte, len(data))
+	cipher.NewCTR(block, iv).XORKeyStream(out, data)
+	return out, nil
+}
+
+// verify checks each hashed range of a part at offset
+func (f *cdnFile) verify(ctx context.Context, offset int64, data []byte) error {
+	for pos := offset; pos < offset+int64(len(data)); {
+		h, err := f.hash(ctx, pos)
+		if err != nil {
+			return err
+		}
+		end := min(pos+int64(h.Limit), offset+int64(len(data)))
+		sum := sha256.Sum256(data[pos-offset : end-offset])
+		if !bytes.Equal(sum[:], h.Hash) {
+			return fmt.Errorf("CDN part at %d does not match its hash", pos)
+		}
+		pos = end
+	}
+	return nil
+}
+
+// hash returns the hash of the range starting at offset, asking the main DC
+// for the hashes around it if they are not known yet
+func (f *cdnFile) hash(ctx context.Context, offset int64) (tg.FileHash, error) {
+	f.mu.Lock()
+	h, ok := f.hashes[offset]
+	f.mu.Unlock()
+	if ok {
+		return h, nil
+	}
+
+	hashes, err := f.api.UploadGetCDNFileHashes(ctx, &tg.UploadGetCDNFileHashesRequest{
+		FileToken: f.redirect.FileToken,
+		Offset:    offset,
+	})
+	if err != nil {
+		return h, fmt.Errorf("failed to get CDN file hashes: %w", err)
+	}
+	f.addHashes(hashes)
+
+	f.mu.Lock()
+	h, ok = f.hashes[offset]
+	f.mu.Unlock()
+	if !ok || h.Limit <= 0 {
+		return h, fmt.Errorf("no hash for CDN part at %d", offset)
+	}
+	return h, nil
+}
+
+func (f *cdnFile) addHashes(hashes []tg.FileHash) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, h := range hashes {
+		f.hashes[h.Offset] = h
+	}
+}
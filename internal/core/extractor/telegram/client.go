@@ -0,0 +1,156 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gotd/td/session"
+	"github.com/gotd/td/telegram"
+	"github.com/gotd/td/tg"
+	"github.com/gotd/td/tgerr"
+)
+
+// connectTimeout bounds connecting and checking the session of a SharedClient
+const connectTimeout = 30 * time.Second
+
+// SharedClient keeps one authorized connection open for many downloads,
+// instead of connecting again for each one. It connects on first use, and
+// again after the connection ends or Reset (needed after a login or logout).
+type SharedClient struct {
+	mu   sync.Mutex
+	conn *sharedConn
+}
+
+// sharedConn is one run of a telegram.Client; gotd clients can't be restarted
+type sharedConn struct {
+	api   *tg.Client
+	stop  context.CancelFunc
+	ready chan struct{} // closed once authorized
+	done  chan struct{} // closed when the run ends
+	err   error         // why the run ended; set before done is closed
+}
+
+// NewSharedClient returns a client that connects with the stored session on first use
+func NewSharedClient() *SharedClient {
+	return &SharedClient{}
+}
+
+// Download downloads the media of a t.me link over the shared connection
+func (c *SharedClient) Download(ctx context.Context, opts DownloadOptions) (*DownloadResult, error) {
+	msg, err := ParseURL(opts.URL)
+	if err != nil {
+		return nil, err
+	}
+	api, err := c.api(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	channel, err := resolveChannelInfo(ctx, api, msg)
+	if err != nil {
+		return nil, err
+	}
+	return downloadFromChannel(ctx, api, channel, msg, opts)
+}
+
+// Reset drops the connection; the next download connects again
+func (c *SharedClient) Reset() {
+	c.mu.Lock()
+	conn := c.conn
+	c.conn = nil
+	c.mu.Unlock()
+	if conn != nil {
+		conn.stop()
+	}
+}
+
+// Close disconnects
+func (c *SharedClient) Close() {
+	c.Reset()
+}
+
+// api returns the API of the current connection, connecting if needed
+func (c *SharedClient) api(ctx context.Context) (*tg.Client, error) {
+	c.mu.Lock()
+	conn := c.conn
+	if conn != nil {
+		select {
+		case <-conn.done:
+			conn = nil // the previous run ended, e.g. on a network error
+		default:
+		}
+	}
+	if conn == nil {
+		if !SessionExists() {
+			c.mu.Unlock()
+			return nil, fmt.Errorf("not logged in to Telegram. Run 'vget telegram login' first")
+		}
+		conn = connect()
+		c.conn = conn
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-conn.ready:
+		return conn.api, nil
+	case <-conn.done:
+		return nil, conn.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func connect() *sharedConn {
+	runCtx, stop := context.WithCancel(context.Background())
+	conn := &sharedConn{
+		stop:  stop,
+		ready: make(chan struct{}),
+		done:  make(chan struct{}),
+	}
+
+	client := telegram.NewClient(DesktopAppID, DesktopAppHash, telegram.Options{
+		SessionStorage: &session.FileStorage{Path: SessionFile()},
+	})
+
+	go func() {
+		defer close(conn.done)
+		defer stop()
+
+		// Fail the waiting downloads if the connection never comes up
+		timer := time.AfterFunc(connectTimeout, func() {
+			select {
+			case <-conn.ready:
+			default:
+				stop()
+			}
+		})
+		defer timer.Stop()
+
+		conn.err = client.Run(runCtx, func(ctx context.Context) error {
+			status, err := client.Auth().Status(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to check auth status: %w", err)
+			}
+			if !status.Authorized {
+				return fmt.Errorf("not authorized. Run 'vget telegram login' first")
+			}
+			conn.api = client.API()
+			close(conn.ready)
+
+			<-ctx.Done()
+			return nil
+		})
+		if conn.err == nil {
+			conn.err = fmt.Errorf("telegram connection closed")
+		}
+	}()
+	return conn
+}
+
+// FloodWait reports how long Telegram asked to wait before retrying, if err
+// is a FLOOD_WAIT error
+func FloodWait(err error) (time.Duration, bool) {
+	return tgerr.AsFloodWait(err)
+}
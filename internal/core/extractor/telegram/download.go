@@ -3,15 +3,14 @@ package telegram
 import (
 	"context"
 	"fmt"
-	"io"
 	"os"
+	"path/filepath"
 	"regexp"
 	"strings"
 	"time"
 
 	"github.com/gotd/td/session"
 	"github.com/gotd/td/telegram"
-	"github.com/gotd/td/telegram/downloader"
 	"github.com/gotd/td/tg"
 )
 
@@ -20,13 +19,15 @@ type DownloadResult struct {
 	Title    string
 	Filename string
 	Size     int64
+	Files    []string // every file of an album, in order
 }
 
 // DownloadOptions configures the download behavior
 type DownloadOptions struct {
 	URL        string
 	OutputPath string
-	Takeout    bool // Use takeout session for lower rate limits
+	OutputDir  string // where files named after the message go when OutputPath is empty
+	Takeout    bool   // Use takeout session for lower rate limits
 	ProgressFn func(downloaded, total int64)
 }
 
@@ -52,8 +53,9 @@ func DownloadWithOptions(opts DownloadOptions) (*DownloadResult, error) {
 		return nil, err
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
-	defer cancel()
+	// Stalled file downloads fail on their own (fileIdleTimeout), however
+	// long a large file takes
+	ctx := context.Background()
 
 	storage := &session.FileStorage{Path: SessionFile()}
 
@@ -92,64 +94,118 @@ func DownloadWithOptions(opts DownloadOptions) (*DownloadResult, error) {
 		}
 
 		// Resolve channel
-		inputChannel, err := resolveChannel(ctx, api, msg)
+		channel, err := resolveChannelInfo(ctx, api, msg)
 		if err != nil {
 			return err
 		}
 
-		// Get the message
-		msgResult, err := api.ChannelsGetMessages(ctx, &tg.ChannelsGetMessagesRequest{
-			Channel: inputChannel,
-			ID:      []tg.InputMessageClass{&tg.InputMessageID{ID: msg.MessageID}},
-		})
-		if err != nil {
-			return fmt.Errorf("failed to get message: %w", err)
-		}
-
-		// Extract message
-		tgMsg, err := extractMessage(msgResult)
-		if err != nil {
-			return err
+		// Fetch the message and file through the takeout session
+		if takeout != nil && takeout.Active() {
+			api = takeout.API(client)
 		}
 
-		if tgMsg.Media == nil {
-			return fmt.Errorf("message has no media")
-		}
+		result, err = downloadFromChannel(ctx, api, channel, msg, opts)
+		return err
+	})
 
-		// Download based on media type
-		dl := downloader.NewDownloader()
+	if err != nil {
+		return nil, err
+	}
 
-		switch media := tgMsg.Media.(type) {
-		case *tg.MessageMediaDocument:
-			result, err = downloadDocument(ctx, api, dl, media, tgMsg, opts.OutputPath, opts.ProgressFn)
-			return err
+	return result, nil
+}
 
-		case *tg.MessageMediaPhoto:
-			result, err = downloadPhoto(ctx, api, dl, media, tgMsg, opts.OutputPath, opts.ProgressFn)
-			return err
+// downloadFromChannel downloads the media of a linked message, or of its
+// whole album, and saves the caption next to it
+func downloadFromChannel(ctx context.Context, api *tg.Client, channel *ChannelInfo, msg *Message, opts DownloadOptions) (*DownloadResult, error) {
+	inputChannel := channel.Input()
 
-		default:
-			return fmt.Errorf("unsupported media type: %T", media)
-		}
+	// Get the message
+	msgResult, err := api.ChannelsGetMessages(ctx, &tg.ChannelsGetMessagesRequest{
+		Channel: inputChannel,
+		ID:      []tg.InputMessageClass{&tg.InputMessageID{ID: msg.MessageID}},
 	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get message: %w", err)
+	}
 
+	// Extract message
+	tgMsg, err := extractMessage(msgResult)
 	if err != nil {
 		return nil, err
 	}
 
+	if tgMsg.Media == nil {
+		return nil, fmt.Errorf("message has no media")
+	}
+
+	// An album is sent as one message per item sharing a grouped_id
+	album := []*tg.Message{tgMsg}
+	if tgMsg.GroupedID != 0 && !msg.Single {
+		album, err = fetchAlbum(ctx, api, inputChannel, tgMsg)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var (
+		result *DownloadResult
+		base   string
+	)
+	if len(album) == 1 {
+		outputPath := opts.OutputPath
+		if outputPath == "" && opts.OutputDir != "" {
+			outputPath = filepath.Join(opts.OutputDir, mediaFilename(tgMsg))
+		}
+		result, err = downloadMedia(ctx, api, tgMsg, outputPath, opts.ProgressFn)
+		if err != nil {
+			return nil, err
+		}
+		base = strings.TrimSuffix(result.Filename, filepath.Ext(result.Filename))
+	} else {
+		base = strings.TrimSuffix(opts.OutputPath, filepath.Ext(opts.OutputPath))
+		if base == "" {
+			title := truncateText(albumCaption(album), 100)
+			if title == "" {
+				title = fmt.Sprintf("telegram_%d", album[0].ID)
+			}
+			base = filepath.Join(opts.OutputDir, sanitizeFilename(title))
+		}
+		result, err = downloadAlbum(ctx, api, album, base, opts.ProgressFn)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Keep the full caption, which the filename only has the start of
+	if caption := albumCaption(album); caption != "" {
+		sender := messageSender(tgMsg, msgResult, channel.Title)
+		posted := time.Unix(int64(tgMsg.Date), 0)
+		notes := captionNotes(caption, channel.Title, sender, posted, opts.URL)
+		if err := os.WriteFile(base+".md", []byte(notes), 0644); err != nil {
+			fmt.Printf("Warning: failed to write caption: %v\n", err)
+		}
+	}
 	return result, nil
 }
 
 func resolveChannel(ctx context.Context, api *tg.Client, msg *Message) (*tg.InputChannel, error) {
+	channel, err := resolveChannelInfo(ctx, api, msg)
+	if err != nil {
+		return nil, err
+	}
+	return channel.Input(), nil
+}
+
+// resolveChannelInfo looks up the channel of a parsed URL, by username or,
+// for private /c/ links, among the joined channels
+func resolveChannelInfo(ctx context.Context, api *tg.Client, msg *Message) (*ChannelInfo, error) {
 	if msg.IsPrivate {
 		channel, err := resolvePrivateChannel(ctx, api, msg.ChannelID)
 		if err != nil {
 			return nil, fmt.Errorf("failed to resolve private channel: %w", err)
 		}
-		return &tg.InputChannel{
-			ChannelID:  channel.ID,
-			AccessHash: channel.AccessHash,
-		}, nil
+		return channel, nil
 	}
 
 	resolved, err := api.ContactsResolveUsername(ctx, &tg.ContactsResolveUsernameRequest{
@@ -161,9 +217,11 @@ func resolveChannel(ctx context.Context, api *tg.Client, msg *Message) (*tg.Inpu
 
 	if len(resolved.Chats) > 0 {
 		if channel, ok := resolved.Chats[0].(*tg.Channel); ok {
-			return &tg.InputChannel{
-				ChannelID:  channel.ID,
+			return &ChannelInfo{
+				ID:         channel.ID,
 				AccessHash: channel.AccessHash,
+				Title:      channel.Title,
+				Username:   channel.Username,
 			}, nil
 		}
 	}
@@ -179,6 +237,11 @@ type ChannelInfo struct {
 	Username   string
 }
 
+// Input returns the channel as an API input
+func (c *ChannelInfo) Input() *tg.InputChannel {
+	return &tg.InputChannel{ChannelID: c.ID, AccessHash: c.AccessHash}
+}
+
 func resolvePrivateChannel(ctx context.Context, api *tg.Client, channelID int64) (*ChannelInfo, error) {
 	channels, err := getAllChannels(ctx, api)
 	if err != nil {
@@ -287,7 +350,6 @@ func extractMessage(result tg.MessagesMessagesClass) (*tg.Message, error) {
 func downloadDocument(
 	ctx context.Context,
 	api *tg.Client,
-	dl *downloader.Downloader,
 	media *tg.MessageMediaDocument,
 	msg *tg.Message,
 	outputPath string,
@@ -303,33 +365,20 @@ func downloadDocument(
 	// Determine output filename
 	outFile := outputPath
 	if outFile == "" {
-		if info.Filename != "" {
-			outFile = info.Filename
-		} else {
-			outFile = fmt.Sprintf("%s.%s", sanitizeFilename(info.Title), info.Ext)
-		}
-	}
-
-	// Create output file
-	f, err := os.Create(outFile)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create output file: %w", err)
-	}
-	defer f.Close()
-
-	// Download with progress
-	var writer io.Writer = f
-	if progressFn != nil {
-		writer = &progressWriter{w: f, fn: progressFn, total: doc.Size}
+		outFile = mediaFilename(msg)
 	}
 
-	_, err = dl.Download(api, &tg.InputDocumentFileLocation{
-		ID:            doc.ID,
-		AccessHash:    doc.AccessHash,
-		FileReference: doc.FileReference,
-	}).Stream(ctx, writer)
+	err := downloadFile(ctx, api, fileSource{
+		location: &tg.InputDocumentFileLocation{
+			ID:            doc.ID,
+			AccessHash:    doc.AccessHash,
+			FileReference: doc.FileReference,
+		},
+		id:   doc.ID,
+		size: doc.Size,
+		cdn:  dialCDN(api),
+	}, outFile, progressFn)
 	if err != nil {
-		os.Remove(outFile)
 		return nil, fmt.Errorf("download failed: %w", err)
 	}
 
@@ -343,7 +392,6 @@ func downloadDocument(
 func downloadPhoto(
 	ctx context.Context,
 	api *tg.Client,
-	dl *downloader.Downloader,
 	media *tg.MessageMediaPhoto,
 	msg *tg.Message,
 	outputPath string,
@@ -366,23 +414,21 @@ func downloadPhoto(
 
 	outFile := outputPath
 	if outFile == "" {
-		outFile = fmt.Sprintf("%s.jpg", sanitizeFilename(title))
+		outFile = mediaFilename(msg)
 	}
 
-	f, err := os.Create(outFile)
+	err := downloadFile(ctx, api, fileSource{
+		location: &tg.InputPhotoFileLocation{
+			ID:            photo.ID,
+			AccessHash:    photo.AccessHash,
+			FileReference: photo.FileReference,
+			ThumbSize:     largest.Type,
+		},
+		id:   photo.ID,
+		size: int64(largest.Size),
+		cdn:  dialCDN(api),
+	}, outFile, progressFn)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create output file: %w", err)
-	}
-	defer f.Close()
-
-	_, err = dl.Download(api, &tg.InputPhotoFileLocation{
-		ID:            photo.ID,
-		AccessHash:    photo.AccessHash,
-		FileReference: photo.FileReference,
-		ThumbSize:     largest.Type,
-	}).Stream(ctx, f)
-	if err != nil {
-		os.Remove(outFile)
 		return nil, fmt.Errorf("download failed: %w", err)
 	}
 
@@ -393,23 +439,6 @@ func downloadPhoto(
 	}, nil
 }
 
-// progressWriter wraps an io.Writer to report progress
-type progressWriter struct {
-	w          io.Writer
-	fn         func(downloaded, total int64)
-	total      int64
-	downloaded int64
-}
-
-func (pw *progressWriter) Write(p []byte) (int, error) {
-	n, err := pw.w.Write(p)
-	pw.downloaded += int64(n)
-	if pw.fn != nil {
-		pw.fn(pw.downloaded, pw.total)
-	}
-	return n, err
-}
-
 func sanitizeFilename(name string) string {
 	replacer := strings.NewReplacer(
 		"/", "-",
@@ -0,0 +1,373 @@
+package telegram
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gotd/td/session"
+	"github.com/gotd/td/telegram"
+	"github.com/gotd/td/tg"
+)
+
+// DumpArchiveFile records the IDs of downloaded messages inside a dump directory,
+// so an interrupted or repeated dump only fetches what is missing
+const DumpArchiveFile = ".vget-archive"
+
+// Media types accepted by DumpOptions.Types
+const (
+	DumpTypeVideo    = "video"
+	DumpTypeAudio    = "audio"
+	DumpTypePhoto    = "photo"
+	DumpTypeDocument = "document"
+)
+
+// DumpTypes lists the media types a dump can be filtered by
+var DumpTypes = []string{DumpTypeVideo, DumpTypeAudio, DumpTypePhoto, DumpTypeDocument}
+
+// historyPageSize is the largest page messages.getHistory returns
+const historyPageSize = 100
+
+var (
+	// t.me/c/123456789 or t.me/c/123456789/45 (private channel)
+	privateChannelRegex = regexp.MustCompile(`^(?:https?://)?(?:t\.me|telegram\.me)/c/(\d+)(?:/\d+)?/?$`)
+	// t.me/channel or t.me/channel/45
+	publicChannelRegex = regexp.MustCompile(`^(?:https?://)?(?:t\.me|telegram\.me)/([A-Za-z0-9_]+)(?:/\d+)?/?$`)
+	// @channel or channel
+	usernameRegex = regexp.MustCompile(`^@?([A-Za-z][A-Za-z0-9_]{3,})$`)
+)
+
+// DumpOptions configures a bulk download of a channel's history
+type DumpOptions struct {
+	Channel    string    // @username, t.me link or t.me/c/<id> link
+	OutputDir  string    // defaults to the channel title
+	FromID     int       // first message ID to include, 0 for the oldest
+	ToID       int       // last message ID to include, 0 for the newest
+	Since      time.Time // skip messages posted before this time
+	Types      []string  // any of DumpTypes; empty for all media
+	Takeout    bool      // list and download through a takeout session
+	OnStart    func(title, dir string, pending int)
+	OnFile     func(file DumpFile)
+	ProgressFn func(downloaded, total int64)
+}
+
+// DumpFile reports the outcome for one message of a dump
+type DumpFile struct {
+	MessageID int
+	Filename  string
+	Size      int64
+	Skipped   bool // already present on disk
+	Err       error
+}
+
+// DumpResult summarizes a dump
+type DumpResult struct {
+	Title      string
+	Dir        string
+	Downloaded int
+	Skipped    int
+	Failed     int
+}
+
+// ParseChannel parses a channel reference: @username, username, a t.me link
+// to the channel or to one of its messages, or a private t.me/c/<id> link
+func ParseChannel(s string) (*Message, error) {
+	s = strings.TrimSpace(s)
+	if m := privateChannelRegex.FindStringSubmatch(s); m != nil {
+		id, err := strconv.ParseInt(m[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid channel ID: %w", err)
+		}
+		return &Message{ChannelID: id, IsPrivate: true}, nil
+	}
+	if m := publicChannelRegex.FindStringSubmatch(s); m != nil && m[1] != "c" {
+		return &Message{ChannelUsername: m[1]}, nil
+	}
+	if m := usernameRegex.FindStringSubmatch(s); m != nil {
+		return &Message{ChannelUsername: m[1]}, nil
+	}
+	return nil, fmt.Errorf("could not parse Telegram channel: %s", s)
+}
+
+// Dump downloads every media message of a channel within the options' range
+// into a directory, oldest first. Messages in the directory's archive and
+// files already on disk are skipped; partial downloads are never left behind
+// under the final name.
+func Dump(opts DumpOptions) (*DumpResult, error) {
+	if !SessionExists() {
+		return nil, fmt.Errorf("not logged in to Telegram. Run 'vget telegram login' first")
+	}
+
+	ref, err := ParseChannel(opts.Channel)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range opts.Types {
+		if !slices.Contains(DumpTypes, t) {
+			return nil, fmt.Errorf("unknown media type %q (expected %s)", t, strings.Join(DumpTypes, ", "))
+		}
+	}
+	if opts.FromID > 0 && opts.ToID > 0 && opts.FromID > opts.ToID {
+		return nil, fmt.Errorf("--from %d is after --to %d", opts.FromID, opts.ToID)
+	}
+
+	storage := &session.FileStorage{Path: SessionFile()}
+	client := telegram.NewClient(DesktopAppID, DesktopAppHash, telegram.Options{
+		SessionStorage: storage,
+	})
+
+	result := &DumpResult{}
+
+	err = client.Run(context.Background(), func(ctx context.Context) error {
+		status, err := client.Auth().Status(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to check auth status: %w", err)
+		}
+		if !status.Authorized {
+			return fmt.Errorf("not authorized. Run 'vget telegram login' first")
+		}
+
+		api := client.API()
+
+		channel, err := resolveChannelInfo(ctx, api, ref)
+		if err != nil {
+			return err
+		}
+		result.Title = channel.Title
+
+		if opts.Takeout {
+			takeout := NewTakeoutSession(api)
+			if err := takeout.Start(ctx); err != nil {
+				// Takeout has to be confirmed in another Telegram app the first time
+				fmt.Printf("Warning: could not start takeout session: %v\n", err)
+			} else {
+				defer func() {
+					if err := takeout.Finish(ctx); err != nil {
+						fmt.Printf("Warning: failed to finish takeout session: %v\n", err)
+					}
+				}()
+				api = takeout.API(client)
+			}
+		}
+
+		dir := opts.OutputDir
+		if dir == "" {
+			dir = sanitizeFilename(channel.Title)
+		}
+		if dir == "" {
+			dir = strconv.FormatInt(channel.ID, 10)
+		}
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create directory: %w", err)
+		}
+		result.Dir = dir
+
+		archive := loadDumpArchive(dir)
+
+		messages, err := listHistory(ctx, api, channel, opts)
+		if err != nil {
+			return err
+		}
+
+		var pending []*tg.Message
+		for _, msg := range messages {
+			if !archive[msg.ID] {
+				pending = append(pending, msg)
+			}
+		}
+		if opts.OnStart != nil {
+			opts.OnStart(channel.Title, dir, len(pending))
+		}
+
+		for _, msg := range pending {
+			file := dumpMessage(ctx, api, msg, dir, opts.ProgressFn)
+			switch {
+			case file.Err != nil:
+				result.Failed++
+			case file.Skipped:
+				result.Skipped++
+			default:
+				result.Downloaded++
+			}
+			if file.Err == nil {
+				appendDumpArchive(dir, msg.ID)
+			}
+			if opts.OnFile != nil {
+				opts.OnFile(file)
+			}
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// listHistory pages through a channel's history, newest first, and returns
+// the media messages that match the options, oldest first
+func listHistory(ctx context.Context, api *tg.Client, channel *ChannelInfo, opts DumpOptions) ([]*tg.Message, error) {
+	peer := &tg.InputPeerChannel{ChannelID: channel.ID, AccessHash: channel.AccessHash}
+
+	offsetID := 0 // 0 starts at the newest message
+	if opts.ToID > 0 {
+		offsetID = opts.ToID + 1 // offset_id is exclusive
+	}
+	minID := 0
+	if opts.FromID > 0 {
+		minID = opts.FromID - 1 // min_id is exclusive
+	}
+
+	var matched []*tg.Message
+	for {
+		res, err := api.MessagesGetHistory(ctx, &tg.MessagesGetHistoryRequest{
+			Peer:     peer,
+			OffsetID: offsetID,
+			MinID:    minID,
+			Limit:    historyPageSize,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get history: %w", err)
+		}
+		page, ok := res.AsModified()
+		if !ok || len(page.GetMessages()) == 0 {
+			break
+		}
+
+		done := false
+		for _, m := range page.GetMessages() {
+			offsetID = m.GetID()
+			msg, ok := m.(*tg.Message)
+			if !ok {
+				continue // service messages
+			}
+			if !opts.Since.IsZero() && time.Unix(int64(msg.Date), 0).Before(opts.Since) {
+				done = true // history is in posting order
+				break
+			}
+			if kind := dumpMediaType(msg.Media); kind != "" && (len(opts.Types) == 0 || slices.Contains(opts.Types, kind)) {
+				matched = append(matched, msg)
+			}
+		}
+		if done {
+			break
+		}
+	}
+
+	slices.Reverse(matched)
+	return matched, nil
+}
+
+// dumpMediaType classifies a message's media as one of DumpTypes, or ""
+// for messages without a downloadable file (text, polls, locations, …)
+func dumpMediaType(media tg.MessageMediaClass) string {
+	switch m := media.(type) {
+	case *tg.MessageMediaPhoto:
+		if _, ok := m.Photo.(*tg.Photo); ok {
+			return DumpTypePhoto
+		}
+	case *tg.MessageMediaDocument:
+		doc, ok := m.Document.(*tg.Document)
+		if !ok {
+			return ""
+		}
+		info := ExtractDocumentInfo(doc, "", 0)
+		switch {
+		case info.IsVideo:
+			return DumpTypeVideo
+		case info.IsAudio:
+			return DumpTypeAudio
+		default:
+			return DumpTypeDocument
+		}
+	}
+	return ""
+}
+
+// dumpFilename names a message's file "{id} {original filename}", or
+// "{id} {caption}.{ext}" when it has none; the ID prefix keeps names unique
+// and the directory in posting order
+func dumpFilename(msg *tg.Message) string {
+	switch m := msg.Media.(type) {
+	case *tg.MessageMediaPhoto:
+		if title := sanitizeFilename(truncateText(msg.Message, 80)); title != "" {
+			return fmt.Sprintf("%d %s.jpg", msg.ID, title)
+		}
+		return fmt.Sprintf("%d.jpg", msg.ID)
+	case *tg.MessageMediaDocument:
+		doc, _ := m.Document.(*tg.Document)
+		if doc == nil {
+			return ""
+		}
+		info := ExtractDocumentInfo(doc, "", msg.ID)
+		if name := sanitizeFilename(info.Filename); name != "" {
+			return fmt.Sprintf("%d %s", msg.ID, name)
+		}
+		if title := sanitizeFilename(truncateText(msg.Message, 80)); title != "" {
+			return fmt.Sprintf("%d %s.%s", msg.ID, title, info.Ext)
+		}
+		return fmt.Sprintf("%d.%s", msg.ID, info.Ext)
+	}
+	return ""
+}
+
+// dumpMessage downloads one message's media into dir
+func dumpMessage(
+	ctx context.Context,
+	api *tg.Client,
+	msg *tg.Message,
+	dir string,
+	progressFn func(downloaded, total int64),
+) DumpFile {
+	file := DumpFile{MessageID: msg.ID, Filename: filepath.Join(dir, dumpFilename(msg))}
+
+	if fi, err := os.Stat(file.Filename); err == nil {
+		file.Size = fi.Size()
+		file.Skipped = true
+		return file
+	}
+
+	res, err := downloadMedia(ctx, api, msg, file.Filename, progressFn)
+	if err != nil {
+		file.Err = err
+		return file
+	}
+	file.Size = res.Size
+	return file
+}
+
+func loadDumpArchive(dir string) map[int]bool {
+	archive := make(map[int]bool)
+	f, err := os.Open(filepath.Join(dir, DumpArchiveFile))
+	if err != nil {
+		return archive
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if id, err := strconv.Atoi(strings.TrimSpace(scanner.Text())); err == nil {
+			archive[id] = true
+		}
+	}
+	return archive
+}
+
+func appendDumpArchive(dir string, msgID int) {
+	f, err := os.OpenFile(filepath.Join(dir, DumpArchiveFile), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	fmt.Fprintln(f, msgID)
+}
@@ -0,0 +1,58 @@
+package telegram
+
+import (
+	"testing"
+
+	"github.com/gotd/td/tg"
+)
+
+func TestParseChannel(t *testing.T) {
+	tests := map[string]Message{
+		"@coursechannel":               {ChannelUsername: "coursechannel"},
+		"coursechannel":                {ChannelUsername: "coursechannel"},
+		"https://t.me/coursechannel":   {ChannelUsername: "coursechannel"},
+		"t.me/coursechannel/120":       {ChannelUsername: "coursechannel"},
+		"https://t.me/c/1234567890":    {ChannelID: 1234567890, IsPrivate: true},
+		"https://telegram.me/c/123/45": {ChannelID: 123, IsPrivate: true},
+	}
+	for input, want := range tests {
+		got, err := ParseChannel(input)
+		if err != nil {
+			t.Errorf("ParseChannel(%q): %v", input, err)
+			continue
+		}
+		if *got != want {
+			t.Errorf("ParseChannel(%q) = %+v, want %+v", input, *got, want)
+		}
+	}
+
+	for _, input := range []string{"", "https://t.me/c/", "https://example.com/coursechannel", "a b"} {
+		if _, err := ParseChannel(input); err == nil {
+			t.Errorf("ParseChannel(%q) should fail", input)
+		}
+	}
+}
+
+func TestDumpFilename(t *testing.T) {
+	video := &tg.Document{MimeType: "video/mp4", Attributes: []tg.DocumentAttributeClass{&tg.DocumentAttributeVideo{W: 1280, H: 720}}}
+	pdf := &tg.Document{MimeType: "application/pdf", Attributes: []tg.DocumentAttributeClass{&tg.DocumentAttributeFilename{FileName: "Week 1: Slides.pdf"}}}
+
+	tests := []struct {
+		msg      *tg.Message
+		wantType string
+		wantName string
+	}{
+		{&tg.Message{ID: 7, Message: "Lesson 1\nIntro", Media: &tg.MessageMediaDocument{Document: video}}, DumpTypeVideo, "7 Lesson 1 Intro.mp4"},
+		{&tg.Message{ID: 8, Media: &tg.MessageMediaDocument{Document: pdf}}, DumpTypeDocument, "8 Week 1- Slides.pdf"},
+		{&tg.Message{ID: 9, Media: &tg.MessageMediaPhoto{Photo: &tg.Photo{}}}, DumpTypePhoto, "9.jpg"},
+		{&tg.Message{ID: 10, Message: "text only"}, "", ""},
+	}
+	for _, tt := range tests {
+		if got := dumpMediaType(tt.msg.Media); got != tt.wantType {
+			t.Errorf("message %d: type = %q, want %q", tt.msg.ID, got, tt.wantType)
+		}
+		if got := dumpFilename(tt.msg); got != tt.wantName {
+			t.Errorf("message %d: filename = %q, want %q", tt.msg.ID, got, tt.wantName)
+		}
+	}
+}
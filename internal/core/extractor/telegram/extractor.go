@@ -46,6 +46,11 @@ type MediaInfo struct {
 
 // Extract retrieves media info from a Telegram URL
 func (e *Extractor) Extract(urlStr string) (*MediaInfo, error) {
+	return e.ExtractContext(context.Background(), urlStr)
+}
+
+// ExtractContext retrieves media info from a Telegram URL, aborting when ctx is done
+func (e *Extractor) ExtractContext(ctx context.Context, urlStr string) (*MediaInfo, error) {
 	if !SessionExists() {
 		return nil, fmt.Errorf("not logged in to Telegram. Run 'vget telegram login' first")
 	}
@@ -55,7 +60,7 @@ func (e *Extractor) Extract(urlStr string) (*MediaInfo, error) {
 		return nil, err
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
 	defer cancel()
 
 	return e.extractMedia(ctx, msg)
@@ -0,0 +1,312 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gotd/td/session"
+	"github.com/gotd/td/telegram"
+	"github.com/gotd/td/telegram/auth"
+	"github.com/gotd/td/telegram/auth/qrlogin"
+	"github.com/gotd/td/tg"
+	"github.com/gotd/td/tgerr"
+)
+
+// loginTimeout bounds how long a login waits for the code, password or scan
+const loginTimeout = 10 * time.Minute
+
+// LoginState is the step an interactive login is at
+type LoginState string
+
+const (
+	LoginStarting        LoginState = "starting"
+	LoginWaitingCode     LoginState = "waiting_code"     // the code was sent to the Telegram app or by SMS
+	LoginWaitingPassword LoginState = "waiting_password" // the account has two-step verification
+	LoginWaitingScan     LoginState = "waiting_scan"     // QRURL has to be scanned in the Telegram app
+	LoginDone            LoginState = "done"
+	LoginFailed          LoginState = "failed"
+)
+
+// LoginStatus is a snapshot of a login session
+type LoginStatus struct {
+	Step      int        `json:"step"` // increases with every change
+	State     LoginState `json:"state"`
+	QRURL     string     `json:"qr_url,omitempty"` // tg://login?token=…
+	QRExpires time.Time  `json:"qr_expires,omitempty"`
+	Hint      string     `json:"hint,omitempty"` // where the code was sent, or the password hint
+	User      string     `json:"user,omitempty"` // set once logged in
+	Error     string     `json:"error,omitempty"`
+}
+
+// LoginSession runs a phone or QR login in the background. It asks for
+// input by moving to a waiting state; the answer is given with SubmitCode or
+// SubmitPassword. The session file is only replaced once the login succeeds.
+type LoginSession struct {
+	mu       sync.Mutex
+	status   LoginStatus
+	changed  chan struct{} // closed on the next status change
+	code     chan string
+	password chan string
+	ctx      context.Context
+	cancel   context.CancelFunc
+}
+
+func newLoginSession() *LoginSession {
+	ctx, cancel := context.WithTimeout(context.Background(), loginTimeout)
+	return &LoginSession{
+		status:   LoginStatus{State: LoginStarting},
+		changed:  make(chan struct{}),
+		code:     make(chan string, 1),
+		password: make(chan string, 1),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+}
+
+// StartPhoneLogin sends a login code to phone (in international format)
+// and waits for SubmitCode, then SubmitPassword if two-step verification is on
+func StartPhoneLogin(phone string) *LoginSession {
+	s := newLoginSession()
+	go s.run(telegram.Options{}, func(ctx context.Context, client *telegram.Client) error {
+		return s.phoneLogin(ctx, client, phone)
+	})
+	return s
+}
+
+// StartQRLogin shows a login token as QRURL, renewed before it expires, until
+// it is scanned in Telegram's Settings > Devices > Link Desktop Device
+func StartQRLogin() *LoginSession {
+	s := newLoginSession()
+	dispatcher := tg.NewUpdateDispatcher()
+	loggedIn := qrlogin.OnLoginToken(dispatcher)
+
+	go s.run(telegram.Options{UpdateHandler: dispatcher}, func(ctx context.Context, client *telegram.Client) error {
+		_, err := client.QR().Auth(ctx, loggedIn, func(ctx context.Context, token qrlogin.Token) error {
+			s.set(LoginStatus{State: LoginWaitingScan, QRURL: token.URL(), QRExpires: token.Expires()})
+			return nil
+		})
+		if tgerr.Is(err, "SESSION_PASSWORD_NEEDED") {
+			return s.passwordLogin(ctx, client)
+		}
+		if err != nil {
+			return fmt.Errorf("QR login failed: %w", err)
+		}
+		return nil
+	})
+	return s
+}
+
+// Status returns the current state of the login
+func (s *LoginSession) Status() LoginStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.status
+}
+
+// Next waits for a status newer than step: the next step of the login,
+// a new QR code, or the verdict on a submitted code or password
+func (s *LoginSession) Next(ctx context.Context, step int) (LoginStatus, error) {
+	for {
+		s.mu.Lock()
+		st, changed := s.status, s.changed
+		s.mu.Unlock()
+		if st.Step > step {
+			return st, nil
+		}
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return st, ctx.Err()
+		}
+	}
+}
+
+// SubmitCode answers LoginWaitingCode
+func (s *LoginSession) SubmitCode(code string) error {
+	return s.submit(LoginWaitingCode, s.code, strings.ReplaceAll(strings.TrimSpace(code), " ", ""))
+}
+
+// SubmitPassword answers LoginWaitingPassword
+func (s *LoginSession) SubmitPassword(password string) error {
+	return s.submit(LoginWaitingPassword, s.password, password)
+}
+
+// Cancel aborts the login
+func (s *LoginSession) Cancel() {
+	s.cancel()
+}
+
+func (s *LoginSession) submit(want LoginState, ch chan string, value string) error {
+	if state := s.Status().State; state != want {
+		return fmt.Errorf("login is not waiting for this (state: %s)", state)
+	}
+	select {
+	case ch <- value:
+		return nil
+	default:
+		return fmt.Errorf("an answer is already being checked")
+	}
+}
+
+func (s *LoginSession) set(st LoginStatus) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st.Step = s.status.Step + 1
+	s.status = st
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+func (s *LoginSession) wait(ctx context.Context, ch chan string) (string, error) {
+	select {
+	case v := <-ch:
+		return v, nil
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+// run logs in with a client on a temporary session file, which replaces the
+// stored session once the account is authorized
+func (s *LoginSession) run(opts telegram.Options, login func(ctx context.Context, client *telegram.Client) error) {
+	defer s.cancel()
+
+	if err := os.MkdirAll(SessionPath(), 0700); err != nil {
+		s.set(LoginStatus{State: LoginFailed, Error: fmt.Sprintf("failed to create session directory: %v", err)})
+		return
+	}
+	pending := SessionFile() + ".login"
+	os.Remove(pending)
+	opts.SessionStorage = &session.FileStorage{Path: pending}
+	client := telegram.NewClient(DesktopAppID, DesktopAppHash, opts)
+
+	var user string
+	err := client.Run(s.ctx, func(ctx context.Context) error {
+		if err := login(ctx, client); err != nil {
+			return err
+		}
+		self, err := client.Self(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get user info: %w", err)
+		}
+		user = formatUser(self)
+		return nil
+	})
+	if err == nil {
+		err = os.Rename(pending, SessionFile())
+	}
+	if err != nil {
+		os.Remove(pending)
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			err = fmt.Errorf("login cancelled or timed out")
+		}
+		s.set(LoginStatus{State: LoginFailed, Error: err.Error()})
+		return
+	}
+	s.set(LoginStatus{State: LoginDone, User: user})
+}
+
+func (s *LoginSession) phoneLogin(ctx context.Context, client *telegram.Client, phone string) error {
+	sent, err := client.Auth().SendCode(ctx, phone, auth.SendCodeOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to send code: %w", err)
+	}
+	sentCode, ok := sent.(*tg.AuthSentCode)
+	if !ok {
+		return nil // already authorized
+	}
+
+	st := LoginStatus{State: LoginWaitingCode, Hint: sentCodeHint(sentCode)}
+	for {
+		s.set(st)
+		code, err := s.wait(ctx, s.code)
+		if err != nil {
+			return err
+		}
+		_, err = client.Auth().SignIn(ctx, phone, code, sentCode.PhoneCodeHash)
+		switch {
+		case err == nil:
+			return nil
+		case errors.Is(err, auth.ErrPasswordAuthNeeded):
+			return s.passwordLogin(ctx, client)
+		case tgerr.Is(err, "PHONE_CODE_INVALID"):
+			st.Error = "invalid code, try again"
+		default:
+			var signUp *auth.SignUpRequired
+			if errors.As(err, &signUp) {
+				return fmt.Errorf("%s has no Telegram account; sign up in a Telegram app first", phone)
+			}
+			return fmt.Errorf("sign in failed: %w", err)
+		}
+	}
+}
+
+// passwordLogin asks for the two-step verification password until it is right
+func (s *LoginSession) passwordLogin(ctx context.Context, client *telegram.Client) error {
+	st := LoginStatus{State: LoginWaitingPassword}
+	if pwd, err := client.API().AccountGetPassword(ctx); err == nil {
+		st.Hint = pwd.Hint
+	}
+	for {
+		s.set(st)
+		password, err := s.wait(ctx, s.password)
+		if err != nil {
+			return err
+		}
+		_, err = client.Auth().Password(ctx, password)
+		if errors.Is(err, auth.ErrPasswordInvalid) {
+			st.Error = "wrong password, try again"
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("password check failed: %w", err)
+		}
+		return nil
+	}
+}
+
+// sentCodeHint says where Telegram sent the login code
+func sentCodeHint(sent *tg.AuthSentCode) string {
+	switch sent.Type.(type) {
+	case *tg.AuthSentCodeTypeApp:
+		return "code sent to your Telegram app"
+	case *tg.AuthSentCodeTypeSMS:
+		return "code sent by SMS"
+	case *tg.AuthSentCodeTypeCall, *tg.AuthSentCodeTypeFlashCall, *tg.AuthSentCodeTypeMissedCall:
+		return "code sent by phone call"
+	case *tg.AuthSentCodeTypeEmailCode:
+		return "code sent by email"
+	default:
+		return "code sent"
+	}
+}
+
+// CurrentUser returns the account of the stored session, or an error if
+// there is none or it is no longer authorized
+func CurrentUser(ctx context.Context) (string, error) {
+	if !SessionExists() {
+		return "", fmt.Errorf("not logged in to Telegram")
+	}
+	client := telegram.NewClient(DesktopAppID, DesktopAppHash, telegram.Options{
+		SessionStorage: &session.FileStorage{Path: SessionFile()},
+	})
+
+	var user string
+	err := client.Run(ctx, func(ctx context.Context) error {
+		status, err := client.Auth().Status(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to check auth status: %w", err)
+		}
+		if !status.Authorized || status.User == nil {
+			return fmt.Errorf("session expired or invalid")
+		}
+		user = formatUser(status.User)
+		return nil
+	})
+	return user, err
+}
@@ -0,0 +1,44 @@
+package telegram
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLoginSessionSteps(t *testing.T) {
+	s := newLoginSession()
+	defer s.Cancel()
+
+	if err := s.SubmitCode("12345"); err == nil {
+		t.Fatal("expected an error submitting a code before one was asked for")
+	}
+
+	go s.set(LoginStatus{State: LoginWaitingCode, Hint: "code sent by SMS"})
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	st, err := s.Next(ctx, 0)
+	if err != nil {
+		t.Fatalf("Next: %v", err)
+	}
+	if st.State != LoginWaitingCode || st.Step != 1 {
+		t.Fatalf("unexpected status: %+v", st)
+	}
+
+	if err := s.SubmitCode(" 123 45 "); err != nil {
+		t.Fatalf("SubmitCode: %v", err)
+	}
+	if code, _ := s.wait(ctx, s.code); code != "12345" {
+		t.Errorf("code = %q", code)
+	}
+	if err := s.SubmitPassword("secret"); err == nil {
+		t.Error("expected an error submitting a password while waiting for the code")
+	}
+
+	// A status that was already seen is not returned again
+	short, cancelShort := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancelShort()
+	if _, err := s.Next(short, st.Step); err == nil {
+		t.Error("expected Next to wait for a newer status")
+	}
+}
@@ -15,6 +15,7 @@ type ExtractedMedia struct {
 	Size     int64
 	Width    int
 	Height   int
+	Duration int // seconds, for videos and audio
 	IsVideo  bool
 	IsAudio  bool
 	IsPhoto  bool
@@ -24,7 +25,7 @@ type ExtractedMedia struct {
 func ExtractDocumentInfo(doc *tg.Document, messageText string, msgID int) *ExtractedMedia {
 	var filename string
 	var isVideo, isAudio bool
-	var width, height int
+	var width, height, duration int
 
 	for _, attr := range doc.Attributes {
 		switch a := attr.(type) {
@@ -34,8 +35,10 @@ func ExtractDocumentInfo(doc *tg.Document, messageText string, msgID int) *Extra
 			isVideo = true
 			width = a.W
 			height = a.H
+			duration = int(a.Duration)
 		case *tg.DocumentAttributeAudio:
 			isAudio = true
+			duration = a.Duration
 		}
 	}
 
@@ -58,6 +61,7 @@ func ExtractDocumentInfo(doc *tg.Document, messageText string, msgID int) *Extra
 		Size:     doc.Size,
 		Width:    width,
 		Height:   height,
+		Duration: duration,
 		IsVideo:  isVideo,
 		IsAudio:  isAudio,
 	}
@@ -2,6 +2,7 @@ package telegram
 
 import (
 	"fmt"
+	"net/url"
 	"regexp"
 	"strconv"
 )
@@ -19,6 +20,7 @@ type Message struct {
 	ChannelID       int64  // For private channels (from /c/ URLs)
 	MessageID       int
 	IsPrivate       bool
+	Single          bool // ?single: only this message, not the rest of its album
 }
 
 // ParseURL parses a t.me URL into its components
@@ -37,6 +39,7 @@ func ParseURL(urlStr string) (*Message, error) {
 			ChannelID: channelID,
 			MessageID: msgID,
 			IsPrivate: true,
+			Single:    hasSingleParam(urlStr),
 		}, nil
 	}
 
@@ -50,12 +53,23 @@ func ParseURL(urlStr string) (*Message, error) {
 			ChannelUsername: matches[1],
 			MessageID:       msgID,
 			IsPrivate:       false,
+			Single:          hasSingleParam(urlStr),
 		}, nil
 	}
 
 	return nil, fmt.Errorf("could not parse Telegram URL: %s", urlStr)
 }
 
+// hasSingleParam reports whether a link carries t.me's ?single parameter,
+// which opens one message of an album on its own
+func hasSingleParam(urlStr string) bool {
+	u, err := url.Parse(urlStr)
+	if err != nil {
+		return false
+	}
+	return u.Query().Has("single")
+}
+
 // MatchURL checks if a URL string matches Telegram message patterns
 func MatchURL(urlStr string) bool {
 	return publicURLRegex.MatchString(urlStr) || privateURLRegex.MatchString(urlStr)
@@ -0,0 +1,434 @@
+package telegram
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gotd/td/tg"
+	"github.com/gotd/td/tgerr"
+)
+
+// A file is fetched in filePartSize parts by fileThreads workers into
+// "{path}.part". Finished parts are listed in "{path}.part.state", so an
+// interrupted download only fetches the parts still missing.
+const (
+	filePartSize    = 1024 * 1024 // the most upload.getFile returns at once
+	fileThreads     = 4
+	filePartRetries = 5
+	// fileIdleTimeout fails a download none of whose requests has returned a
+	// part for this long
+	fileIdleTimeout = 60 * time.Second
+)
+
+var errIdleTimeout = errors.New("no progress")
+
+// fileSource is a file to download
+type fileSource struct {
+	location tg.InputFileLocationClass
+	id       int64 // document or photo ID, to match the parts of a resumed download
+	size     int64 // 0 if unknown
+
+	// cdn connects to the CDN DC Telegram may redirect the download to; nil
+	// keeps the download on Telegram's own DCs
+	cdn cdnDialer
+}
+
+// downloadFile downloads a file to path, resuming an earlier partial
+// download of the same file. Telegram moves files to other DCs
+// (FILE_MIGRATE), which the client follows itself, and popular files to CDN
+// DCs (upload.fileCdnRedirect), which fileReader follows.
+func downloadFile(ctx context.Context, api *tg.Client, src fileSource, path string, progressFn func(downloaded, total int64)) error {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	reader := &fileReader{api: api, src: src}
+	defer reader.close()
+
+	partPath := path + ".part"
+	progress := &partProgress{fn: progressFn, total: src.size, last: time.Now()}
+	go progress.watch(ctx, cancel, fileIdleTimeout)
+
+	var err error
+	if src.size > 0 {
+		err = fetchParts(ctx, reader, partPath, progress)
+	} else {
+		err = fetchSequential(ctx, reader, partPath, progress)
+	}
+	if err != nil {
+		if errors.Is(context.Cause(ctx), errIdleTimeout) {
+			return fmt.Errorf("%w for %s", errIdleTimeout, fileIdleTimeout)
+		}
+		return err
+	}
+
+	if err := os.Rename(partPath, path); err != nil {
+		return fmt.Errorf("failed to rename download: %w", err)
+	}
+	os.Remove(partPath + ".state")
+	return nil
+}
+
+// fetchParts downloads the parts of a file of known size concurrently
+func fetchParts(ctx context.Context, reader *fileReader, partPath string, progress *partProgress) error {
+	src := reader.src
+	if _, err := os.Stat(partPath); err != nil {
+		os.Remove(partPath + ".state") // the parts it lists are gone
+	}
+	state, err := openPartState(partPath+".state", src)
+	if err != nil {
+		return err
+	}
+	defer state.Close()
+
+	flags := os.O_RDWR | os.O_CREATE
+	if len(state.done) == 0 {
+		flags |= os.O_TRUNC
+	}
+	file, err := os.OpenFile(partPath, flags, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+	defer file.Close()
+
+	parts := int((src.size + filePartSize - 1) / filePartSize)
+	partChan := make(chan int, parts)
+	for i := 0; i < parts; i++ {
+		if state.done[i] {
+			progress.add(partLen(src.size, i))
+		} else {
+			partChan <- i
+		}
+	}
+	close(partChan)
+
+	var (
+		wg       sync.WaitGroup
+		errMu    sync.Mutex
+		firstErr error
+	)
+	workCtx, stop := context.WithCancel(ctx)
+	defer stop()
+
+	for w := 0; w < fileThreads; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range partChan {
+				err := fetchPart(workCtx, reader, file, i)
+				if err == nil {
+					err = state.add(i)
+				}
+				if err != nil {
+					errMu.Lock()
+					if firstErr == nil {
+						firstErr = fmt.Errorf("part %d of %d: %w", i+1, parts, err)
+					}
+					errMu.Unlock()
+					stop() // the other parts are kept for the next attempt
+					return
+				}
+				progress.add(partLen(src.size, i))
+			}
+		}()
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	return file.Sync()
+}
+
+// fetchPart downloads part i and writes it at its offset
+func fetchPart(ctx context.Context, reader *fileReader, file *os.File, i int) error {
+	offset := int64(i) * filePartSize
+	want := partLen(reader.src.size, i)
+
+	data, err := reader.chunk(ctx, offset)
+	if err != nil {
+		return err
+	}
+	if int64(len(data)) < want {
+		return fmt.Errorf("short part: got %d of %d bytes", len(data), want)
+	}
+	if _, err := file.WriteAt(data[:want], offset); err != nil {
+		return fmt.Errorf("failed to write part: %w", err)
+	}
+	return nil
+}
+
+// fetchSequential downloads a file of unknown size part by part until a
+// short part marks its end. It can't be resumed.
+func fetchSequential(ctx context.Context, reader *fileReader, partPath string, progress *partProgress) error {
+	file, err := os.Create(partPath)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+	defer file.Close()
+
+	for offset := int64(0); ; offset += filePartSize {
+		data, err := reader.chunk(ctx, offset)
+		if err != nil {
+			file.Close()
+			os.Remove(partPath)
+			return err
+		}
+		if _, err := file.Write(data); err != nil {
+			return fmt.Errorf("failed to write part: %w", err)
+		}
+		progress.add(int64(len(data)))
+		if len(data) < filePartSize {
+			return nil
+		}
+	}
+}
+
+// fileReader fetches the parts of a file from Telegram's DCs or, once
+// Telegram redirects the download there, from a CDN DC
+type fileReader struct {
+	api *tg.Client
+	src fileSource
+
+	mu       sync.Mutex
+	cdn      *cdnFile // set once redirected
+	closeCDN func()
+	noCDN    bool // the CDN DC could not be reached; the DC serves the file itself
+}
+
+// chunk requests filePartSize bytes at offset, retrying network errors.
+// Errors from Telegram itself, such as FLOOD_WAIT or an expired file
+// reference, are returned as they are.
+func (r *fileReader) chunk(ctx context.Context, offset int64) ([]byte, error) {
+	var lastErr error
+	for attempt := 0; attempt < filePartRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(time.Duration(attempt) * time.Second):
+			}
+		}
+
+		data, err := r.fetch(ctx, offset)
+		if err == nil {
+			return data, nil
+		}
+
+		lastErr = err
+		if _, ok := tgerr.As(err); ok || ctx.Err() != nil {
+			return nil, err
+		}
+	}
+	return nil, fmt.Errorf("after %d retries: %w", filePartRetries, lastErr)
+}
+
+// fetch requests the part at offset once, following a CDN redirect
+func (r *fileReader) fetch(ctx context.Context, offset int64) ([]byte, error) {
+	if f := r.cdnFile(); f != nil {
+		data, err := f.chunk(ctx, offset, filePartSize)
+		if !tgerr.Is(err, "FILE_TOKEN_INVALID") {
+			return data, err
+		}
+		// The token expired; the DC hands out a new one
+		r.dropCDN(f)
+	}
+
+	data, redirect, err := r.fetchDC(ctx, offset)
+	if redirect == nil {
+		return data, err
+	}
+	f, err := r.useCDN(ctx, redirect)
+	if err != nil {
+		return nil, err
+	}
+	if f == nil {
+		data, _, err = r.fetchDC(ctx, offset)
+		return data, err
+	}
+	return f.chunk(ctx, offset, filePartSize)
+}
+
+// fetchDC requests the part at offset from a Telegram DC, which may redirect
+// to a CDN DC instead
+func (r *fileReader) fetchDC(ctx context.Context, offset int64) ([]byte, *tg.UploadFileCDNRedirect, error) {
+	r.mu.Lock()
+	cdnSupported := r.src.cdn != nil && !r.noCDN
+	r.mu.Unlock()
+
+	res, err := r.api.UploadGetFile(ctx, &tg.UploadGetFileRequest{
+		Precise:      true,
+		CDNSupported: cdnSupported,
+		Location:     r.src.location,
+		Offset:       offset,
+		Limit:        filePartSize,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	switch res := res.(type) {
+	case *tg.UploadFile:
+		return res.Bytes, nil, nil
+	case *tg.UploadFileCDNRedirect:
+		if !cdnSupported {
+			return nil, nil, fmt.Errorf("unexpected redirect to CDN DC %d", res.DCID)
+		}
+		return nil, res, nil
+	default:
+		return nil, nil, fmt.Errorf("unexpected response type: %T", res)
+	}
+}
+
+// useCDN connects to the CDN DC of a redirect, unless another part already
+// did. It returns nil if the CDN DC can't be reached, after which the DC is
+// asked to serve the file itself.
+func (r *fileReader) useCDN(ctx context.Context, redirect *tg.UploadFileCDNRedirect) (*cdnFile, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.cdn != nil || r.noCDN {
+		return r.cdn, nil
+	}
+	invoker, closeCDN, err := r.src.cdn(ctx, redirect.DCID)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		r.noCDN = true
+		return nil, nil
+	}
+	if r.closeCDN != nil {
+		r.closeCDN() // the CDN DC of an expired redirect
+	}
+	r.cdn = newCDNFile(r.api, tg.NewClient(invoker), redirect)
+	r.closeCDN = closeCDN
+	return r.cdn, nil
+}
+
+func (r *fileReader) cdnFile() *cdnFile {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.cdn
+}
+
+// dropCDN forgets f, so the next part asks the DC for a new redirect
+func (r *fileReader) dropCDN(f *cdnFile) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.cdn == f {
+		r.cdn = nil
+	}
+}
+
+// close ends the CDN connection, if there is one
+func (r *fileReader) close() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closeCDN != nil {
+		r.closeCDN()
+		r.closeCDN = nil
+	}
+}
+
+// partLen is the length of part i of a file of the given size
+func partLen(size int64, i int) int64 {
+	return min(filePartSize, size-int64(i)*filePartSize)
+}
+
+// partProgress sums finished parts for the progress callback and the idle watchdog
+type partProgress struct {
+	mu         sync.Mutex
+	fn         func(downloaded, total int64)
+	total      int64
+	downloaded int64
+	last       time.Time
+}
+
+func (p *partProgress) add(n int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.downloaded += n
+	p.last = time.Now()
+	if p.fn != nil {
+		p.fn(p.downloaded, p.total)
+	}
+}
+
+// watch cancels the download once no part has finished for idle
+func (p *partProgress) watch(ctx context.Context, cancel context.CancelCauseFunc, idle time.Duration) {
+	ticker := time.NewTicker(idle / 8)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			p.mu.Lock()
+			since := time.Since(p.last)
+			p.mu.Unlock()
+			if since > idle {
+				cancel(errIdleTimeout)
+				return
+			}
+		}
+	}
+}
+
+// partState is the list of finished parts of a download. Its first line
+// identifies the file, so parts of a different file (or part size) are
+// never reused.
+type partState struct {
+	mu   sync.Mutex
+	f    *os.File
+	done map[int]bool
+}
+
+func openPartState(path string, src fileSource) (*partState, error) {
+	header := fmt.Sprintf("%d %d %d", src.id, src.size, filePartSize)
+	state := &partState{done: make(map[int]bool)}
+
+	if f, err := os.Open(path); err == nil {
+		scanner := bufio.NewScanner(f)
+		if scanner.Scan() && scanner.Text() == header {
+			for scanner.Scan() {
+				if i, err := strconv.Atoi(strings.TrimSpace(scanner.Text())); err == nil {
+					state.done[i] = true
+				}
+			}
+		}
+		f.Close()
+	}
+
+	var err error
+	if len(state.done) > 0 {
+		state.f, err = os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	} else {
+		state.f, err = os.Create(path)
+		if err == nil {
+			_, err = fmt.Fprintln(state.f, header)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to write download state: %w", err)
+	}
+	return state, nil
+}
+
+func (s *partState) add(i int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := fmt.Fprintln(s.f, i); err != nil {
+		return fmt.Errorf("failed to write download state: %w", err)
+	}
+	return nil
+}
+
+func (s *partState) Close() error {
+	return s.f.Close()
+}
@@ -0,0 +1,292 @@
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/gotd/td/bin"
+	"github.com/gotd/td/tg"
+)
+
+// fileServer answers upload.getFile from an in-memory file
+type fileServer struct {
+	data    []byte
+	mu      sync.Mutex
+	offsets []int64
+}
+
+func (s *fileServer) Invoke(_ context.Context, input bin.Encoder, output bin.Decoder) error {
+	req, ok := input.(*tg.UploadGetFileRequest)
+	if !ok {
+		return fmt.Errorf("unexpected request %T", input)
+	}
+	s.mu.Lock()
+	s.offsets = append(s.offsets, req.Offset)
+	s.mu.Unlock()
+
+	end := min(req.Offset+int64(req.Limit), int64(len(s.data)))
+	output.(*tg.UploadFileBox).File = &tg.UploadFile{Bytes: s.data[req.Offset:end]}
+	return nil
+}
+
+func TestDownloadFileResumes(t *testing.T) {
+	data := make([]byte, 3*filePartSize+1234)
+	for i := range data {
+		data[i] = byte(i % 251)
+	}
+	src := fileSource{location: &tg.InputDocumentFileLocation{ID: 9}, id: 9, size: int64(len(data))}
+	path := filepath.Join(t.TempDir(), "video.mp4")
+
+	// An earlier run finished parts 0 and 2
+	part := make([]byte, len(data))
+	copy(part[:filePartSize], data)
+	copy(part[2*filePartSize:3*filePartSize], data[2*filePartSize:])
+	if err := os.WriteFile(path+".part", part, 0644); err != nil {
+		t.Fatal(err)
+	}
+	state := fmt.Sprintf("9 %d %d\n0\n2\n", len(data), filePartSize)
+	if err := os.WriteFile(path+".part.state", []byte(state), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	server := &fileServer{data: data}
+	var last int64
+	err := downloadFile(context.Background(), tg.NewClient(server), src, path, func(downloaded, total int64) {
+		last = downloaded
+	})
+	if err != nil {
+		t.Fatalf("downloadFile: %v", err)
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Fatal("downloaded file differs")
+	}
+	if len(server.offsets) != 2 {
+		t.Errorf("fetched offsets %v, want only parts 1 and 3", server.offsets)
+	}
+	if last != int64(len(data)) {
+		t.Errorf("progress ended at %d, want %d", last, len(data))
+	}
+	for _, leftover := range []string{path + ".part", path + ".part.state"} {
+		if _, err := os.Stat(leftover); !os.IsNotExist(err) {
+			t.Errorf("%s was not removed", leftover)
+		}
+	}
+}
+
+func TestDownloadFileIgnoresOtherState(t *testing.T) {
+	data := bytes.Repeat([]byte("x"), filePartSize+10)
+	src := fileSource{location: &tg.InputDocumentFileLocation{ID: 2}, id: 2, size: int64(len(data))}
+	path := filepath.Join(t.TempDir(), "doc.pdf")
+
+	// Parts of a different file under the same name
+	if err := os.WriteFile(path+".part", bytes.Repeat([]byte("y"), len(data)), 0644); err != nil {
+		t.Fatal(err)
+	}
+	state := fmt.Sprintf("1 %d %d\n0\n1\n", len(data), filePartSize)
+	if err := os.WriteFile(path+".part.state", []byte(state), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	server := &fileServer{data: data}
+	if err := downloadFile(context.Background(), tg.NewClient(server), src, path, nil); err != nil {
+		t.Fatalf("downloadFile: %v", err)
+	}
+	if got, _ := os.ReadFile(path); !bytes.Equal(got, data) {
+		t.Fatal("stale parts were reused")
+	}
+	if len(server.offsets) != 2 {
+		t.Errorf("fetched offsets %v, want both parts", server.offsets)
+	}
+}
+
+// cdnServer plays the main DC, which redirects to a CDN DC, and the CDN DC,
+// which wants the file reuploaded before serving it
+type cdnServer struct {
+	data   []byte
+	key    []byte
+	iv     []byte
+	hashes []tg.FileHash
+
+	mu          sync.Mutex
+	dcParts     int // parts served by the main DC itself
+	reuploaded  bool
+	hashQueries int
+}
+
+const cdnHashLimit = 128 * 1024
+
+func newCDNServer(data []byte) *cdnServer {
+	s := &cdnServer{data: data, key: bytes.Repeat([]byte{7}, 32), iv: bytes.Repeat([]byte{3}, 16)}
+	for off := 0; off < len(data); off += cdnHashLimit {
+		end := min(off+cdnHashLimit, len(data))
+		sum := sha256.Sum256(data[off:end])
+		s.hashes = append(s.hashes, tg.FileHash{Offset: int64(off), Limit: cdnHashLimit, Hash: sum[:]})
+	}
+	return s
+}
+
+// main answers the requests sent to the main DC
+func (s *cdnServer) main() invokerFunc {
+	return func(_ context.Context, input bin.Encoder, output bin.Decoder) error {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		switch req := input.(type) {
+		case *tg.UploadGetFileRequest:
+			if !req.CDNSupported {
+				s.dcParts++
+				end := min(req.Offset+int64(req.Limit), int64(len(s.data)))
+				output.(*tg.UploadFileBox).File = &tg.UploadFile{Bytes: s.data[req.Offset:end]}
+				return nil
+			}
+			// Only the first hashes come with the redirect
+			output.(*tg.UploadFileBox).File = &tg.UploadFileCDNRedirect{
+				DCID:          201,
+				FileToken:     []byte("token"),
+				EncryptionKey: s.key,
+				EncryptionIv:  s.iv,
+				FileHashes:    s.hashes[:2],
+			}
+		case *tg.UploadReuploadCDNFileRequest:
+			s.reuploaded = true
+			output.(*tg.FileHashVector).Elems = nil
+		case *tg.UploadGetCDNFileHashesRequest:
+			s.hashQueries++
+			var hashes []tg.FileHash
+			for _, h := range s.hashes {
+				if h.Offset >= req.Offset && len(hashes) < 8 {
+					hashes = append(hashes, h)
+				}
+			}
+			output.(*tg.FileHashVector).Elems = hashes
+		default:
+			return fmt.Errorf("unexpected request %T", input)
+		}
+		return nil
+	}
+}
+
+// cdn answers the requests sent to the CDN DC
+func (s *cdnServer) cdn() invokerFunc {
+	return func(_ context.Context, input bin.Encoder, output bin.Decoder) error {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		req, ok := input.(*tg.UploadGetCDNFileRequest)
+		if !ok || string(req.FileToken) != "token" {
+			return fmt.Errorf("unexpected request %T", input)
+		}
+		if !s.reuploaded {
+			output.(*tg.UploadCDNFileBox).CdnFile = &tg.UploadCDNFileReuploadNeeded{RequestToken: []byte("req")}
+			return nil
+		}
+
+		end := min(req.Offset+int64(req.Limit), int64(len(s.data)))
+		block, _ := aes.NewCipher(s.key)
+		iv := bytes.Clone(s.iv)
+		binary.BigEndian.PutUint32(iv[12:], uint32(req.Offset/16))
+		encrypted := make([]byte, end-req.Offset)
+		cipher.NewCTR(block, iv).XORKeyStream(encrypted, s.data[req.Offset:end])
+		output.(*tg.UploadCDNFileBox).CdnFile = &tg.UploadCDNFile{Bytes: encrypted}
+		return nil
+	}
+}
+
+type invokerFunc func(ctx context.Context, input bin.Encoder, output bin.Decoder) error
+
+func (f invokerFunc) Invoke(ctx context.Context, input bin.Encoder, output bin.Decoder) error {
+	return f(ctx, input, output)
+}
+
+func TestDownloadFileFromCDN(t *testing.T) {
+	data := make([]byte, 2*filePartSize+5000)
+	for i := range data {
+		data[i] = byte(i % 241)
+	}
+	server := newCDNServer(data)
+
+	var dialed, closed int
+	src := fileSource{
+		location: &tg.InputDocumentFileLocation{ID: 5},
+		id:       5,
+		size:     int64(len(data)),
+		cdn: func(_ context.Context, dcID int) (tg.Invoker, func(), error) {
+			if dcID != 201 {
+				return nil, nil, fmt.Errorf("unexpected DC %d", dcID)
+			}
+			dialed++
+			return server.cdn(), func() { closed++ }, nil
+		},
+	}
+	path := filepath.Join(t.TempDir(), "movie.mp4")
+
+	if err := downloadFile(context.Background(), tg.NewClient(server.main()), src, path, nil); err != nil {
+		t.Fatalf("downloadFile: %v", err)
+	}
+	if got, _ := os.ReadFile(path); !bytes.Equal(got, data) {
+		t.Fatal("downloaded file differs")
+	}
+	if dialed != 1 || closed != 1 {
+		t.Errorf("CDN dialed %d and closed %d times, want once", dialed, closed)
+	}
+	if !server.reuploaded || server.hashQueries == 0 || server.dcParts != 0 {
+		t.Errorf("reuploaded %v, %d hash queries, %d parts from the DC", server.reuploaded, server.hashQueries, server.dcParts)
+	}
+}
+
+func TestDownloadFileCDNUnreachable(t *testing.T) {
+	data := bytes.Repeat([]byte("z"), filePartSize+10)
+	server := newCDNServer(data)
+	src := fileSource{
+		location: &tg.InputDocumentFileLocation{ID: 6},
+		id:       6,
+		size:     int64(len(data)),
+		cdn: func(context.Context, int) (tg.Invoker, func(), error) {
+			return nil, nil, fmt.Errorf("connection refused")
+		},
+	}
+	path := filepath.Join(t.TempDir(), "doc.bin")
+
+	// The DC serves the file itself once the CDN can't be reached
+	if err := downloadFile(context.Background(), tg.NewClient(server.main()), src, path, nil); err != nil {
+		t.Fatalf("downloadFile: %v", err)
+	}
+	if got, _ := os.ReadFile(path); !bytes.Equal(got, data) {
+		t.Fatal("downloaded file differs")
+	}
+	if server.dcParts != 2 {
+		t.Errorf("%d parts from the DC, want 2", server.dcParts)
+	}
+}
+
+func TestCDNFileRejectsTamperedPart(t *testing.T) {
+	data := bytes.Repeat([]byte("a"), 1000)
+	server := newCDNServer(data)
+	server.reuploaded = true
+	server.hashes[0].Hash = make([]byte, 32)
+
+	f := newCDNFile(tg.NewClient(server.main()), tg.NewClient(server.cdn()), &tg.UploadFileCDNRedirect{
+		DCID:          201,
+		FileToken:     []byte("token"),
+		EncryptionKey: server.key,
+		EncryptionIv:  server.iv,
+		FileHashes:    server.hashes,
+	})
+	if _, err := f.chunk(context.Background(), 0, filePartSize); err == nil {
+		t.Error("expected a hash mismatch")
+	}
+}
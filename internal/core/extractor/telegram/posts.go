@@ -0,0 +1,100 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/gotd/td/tg"
+)
+
+// Post is a media message of a channel, or an album of them
+type Post struct {
+	ID       int    // message ID; the first message of an album
+	Link     string // t.me link that downloads the whole post
+	Caption  string
+	Type     string // one of DumpTypes; an album's first file decides
+	Duration int    // seconds, for videos and audio
+	Date     time.Time
+}
+
+// RecentPosts lists the media posts of the newest page of a channel's
+// history, oldest first, along with the channel's title
+func (c *SharedClient) RecentPosts(ctx context.Context, channel string) (string, []Post, error) {
+	ref, err := ParseChannel(channel)
+	if err != nil {
+		return "", nil, err
+	}
+	api, err := c.api(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+
+	info, err := resolveChannelInfo(ctx, api, ref)
+	if err != nil {
+		return "", nil, err
+	}
+	res, err := api.MessagesGetHistory(ctx, &tg.MessagesGetHistoryRequest{
+		Peer:  &tg.InputPeerChannel{ChannelID: info.ID, AccessHash: info.AccessHash},
+		Limit: historyPageSize,
+	})
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to get history: %w", err)
+	}
+	page, ok := res.AsModified()
+	if !ok {
+		return info.Title, nil, nil
+	}
+
+	var messages []*tg.Message
+	for _, m := range page.GetMessages() {
+		if msg, ok := m.(*tg.Message); ok && dumpMediaType(msg.Media) != "" {
+			messages = append(messages, msg)
+		}
+	}
+	slices.Reverse(messages)
+	return info.Title, groupPosts(info, messages), nil
+}
+
+// groupPosts turns media messages, oldest first, into posts with one entry
+// per album
+func groupPosts(channel *ChannelInfo, messages []*tg.Message) []Post {
+	var posts []Post
+	albums := make(map[int64]int) // grouped ID -> index in posts
+	for _, msg := range messages {
+		if msg.GroupedID != 0 {
+			if i, ok := albums[msg.GroupedID]; ok {
+				if posts[i].Caption == "" {
+					posts[i].Caption = msg.Message
+				}
+				continue
+			}
+			albums[msg.GroupedID] = len(posts)
+		}
+
+		post := Post{
+			ID:      msg.ID,
+			Link:    messageLink(channel, msg.ID),
+			Caption: msg.Message,
+			Type:    dumpMediaType(msg.Media),
+			Date:    time.Unix(int64(msg.Date), 0),
+		}
+		if m, ok := msg.Media.(*tg.MessageMediaDocument); ok {
+			if doc, ok := m.Document.(*tg.Document); ok {
+				post.Duration = ExtractDocumentInfo(doc, "", msg.ID).Duration
+			}
+		}
+		posts = append(posts, post)
+	}
+	return posts
+}
+
+// messageLink is the t.me link of a message: by username for public
+// channels, by ID for private ones
+func messageLink(channel *ChannelInfo, msgID int) string {
+	if channel.Username != "" {
+		return fmt.Sprintf("https://t.me/%s/%d", channel.Username, msgID)
+	}
+	return fmt.Sprintf("https://t.me/c/%d/%d", channel.ID, msgID)
+}
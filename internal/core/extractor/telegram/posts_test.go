@@ -0,0 +1,37 @@
+package telegram
+
+import (
+	"testing"
+
+	"github.com/gotd/td/tg"
+)
+
+func TestGroupPosts(t *testing.T) {
+	video := &tg.MessageMediaDocument{Document: &tg.Document{
+		MimeType:   "video/mp4",
+		Attributes: []tg.DocumentAttributeClass{&tg.DocumentAttributeVideo{Duration: 95.4}},
+	}}
+	photo := &tg.MessageMediaPhoto{Photo: &tg.Photo{}}
+
+	channel := &ChannelInfo{ID: 99, Username: "coursechannel"}
+	posts := groupPosts(channel, []*tg.Message{
+		{ID: 10, Media: video, Message: "Lecture 1"},
+		{ID: 11, GroupedID: 5, Media: photo},
+		{ID: 12, GroupedID: 5, Media: photo, Message: "Slides"},
+		{ID: 13, GroupedID: 5, Media: photo},
+	})
+
+	if len(posts) != 2 {
+		t.Fatalf("got %d posts, want 2: %+v", len(posts), posts)
+	}
+	if p := posts[0]; p.Link != "https://t.me/coursechannel/10" || p.Type != DumpTypeVideo || p.Duration != 95 {
+		t.Errorf("video post = %+v", p)
+	}
+	if p := posts[1]; p.ID != 11 || p.Caption != "Slides" || p.Type != DumpTypePhoto {
+		t.Errorf("album post = %+v", p)
+	}
+
+	if link := messageLink(&ChannelInfo{ID: 99}, 3); link != "https://t.me/c/99/3" {
+		t.Errorf("private link = %s", link)
+	}
+}
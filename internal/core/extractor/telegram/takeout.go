@@ -95,3 +95,9 @@ func (t takeoutMiddleware) Handle(next tg.Invoker) telegram.InvokeFunc {
 func (t *TakeoutSession) Middleware() telegram.Middleware {
 	return takeoutMiddleware{id: t.takeoutID}
 }
+
+// API returns a client that sends every request through invoker (usually the
+// *telegram.Client) within the takeout session
+func (t *TakeoutSession) API(invoker tg.Invoker) *tg.Client {
+	return tg.NewClient(t.Middleware().Handle(invoker))
+}
@@ -1,29 +1,647 @@
 package extractor
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
 	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
 )
 
-// TikTokExtractor handles TikTok video downloads
-type TikTokExtractor struct{}
+const (
+	tiktokFeedAPIURL = "https://api16-normal-c-useast1a.tiktokv.com/aweme/v1/feed/"
+	tiktokUserAgent  = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
+)
+
+var (
+	// Matches /@user/video/123, /@user/photo/123, /video/123, /v/123.html
+	tiktokVideoIDRegex = regexp.MustCompile(`/(?:video|photo|v)/(\d+)`)
+	// Matches /music/original-sound-123
+	tiktokMusicIDRegex = regexp.MustCompile(`/music/(?:[^/?#]*-)?(\d+)`)
+)
+
+// TikTokExtractor handles TikTok video, image carousel and music downloads
+type TikTokExtractor struct {
+	client *http.Client
+	opts   Options // of the call; set by ExtractContext
+}
 
 func (e *TikTokExtractor) Name() string {
 	return "tiktok"
 }
 
+// Info describes the URLs and media this extractor handles
+func (e *TikTokExtractor) Info() Info {
+	return Info{
+		Name:       "tiktok",
+		Title:      "TikTok",
+		Kinds:      []URLKind{URLKindSingle},
+		MediaTypes: []MediaType{MediaTypeVideo, MediaTypeImage, MediaTypeAudio},
+		Requires: []Requirement{
+			{Kind: RequiresBrowser, Optional: true, Detail: "used when the app API and web page are blocked"},
+		},
+	}
+}
+
 func (e *TikTokExtractor) Match(u *url.URL) bool {
-	// Host matching is done by registry
-	return true
+	// Short links (vm.tiktok.com/xxx, vt.tiktok.com/xxx, tiktok.com/t/xxx) are resolved in Extract
+	if isTikTokShortLink(u) {
+		return true
+	}
+	return tiktokVideoIDRegex.MatchString(u.Path) || tiktokMusicIDRegex.MatchString(u.Path)
+}
+
+func (e *TikTokExtractor) Extract(rawURL string) (Media, error) {
+	return e.ExtractContext(context.Background(), rawURL, Options{})
+}
+
+// ExtractContext retrieves a TikTok post, showing the fallback browser if opts.Visible
+func (e *TikTokExtractor) ExtractContext(ctx context.Context, rawURL string, opts Options) (Media, error) {
+	client := e.client
+	if client == nil {
+		client = newHTTPClient(opts, 30*time.Second)
+	}
+	call := &TikTokExtractor{client: client, opts: opts}
+	return call.extract(ctx, rawURL)
+}
+
+// extract resolves a URL using the call's client and browser setting
+func (e *TikTokExtractor) extract(ctx context.Context, rawURL string) (Media, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL: %w", err)
+	}
+
+	// Resolve short link to the canonical video URL
+	if isTikTokShortLink(u) {
+		resolved, err := resolveRedirect(ctx, e.client, rawURL, tiktokUserAgent)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve short URL: %w", err)
+		}
+		rawURL = resolved
+	}
+
+	if matches := tiktokMusicIDRegex.FindStringSubmatch(rawURL); len(matches) > 1 {
+		return e.extractMusic(ctx, rawURL, matches[1])
+	}
+
+	matches := tiktokVideoIDRegex.FindStringSubmatch(rawURL)
+	if len(matches) < 2 {
+		return nil, fmt.Errorf("could not extract video ID from URL: %s", rawURL)
+	}
+	videoID := matches[1]
+
+	// Try the app feed API first (watermark-free play URLs)
+	item, apiErr := e.fetchFromAppAPI(ctx, videoID)
+	if apiErr != nil {
+		// Fall back to the web page rehydration data, then to a real browser
+		var webErr error
+		item, webErr = e.fetchFromWebPage(ctx, rawURL)
+		if webErr != nil {
+			fmt.Println("  TikTok API unavailable, trying browser...")
+			var browserErr error
+			item, browserErr = e.fetchFromBrowser(ctx, rawURL)
+			if browserErr != nil {
+				return nil, fmt.Errorf("failed to fetch TikTok video (api: %v, web: %v, browser: %w)", apiErr, webErr, browserErr)
+			}
+		}
+	}
+
+	return item.toMedia(map[string]string{
+		"Referer":    "https://www.tiktok.com/",
+		"User-Agent": tiktokUserAgent,
+	})
+}
+
+// fetchFromAppAPI queries the mobile app feed endpoint for a single video
+func (e *TikTokExtractor) fetchFromAppAPI(ctx context.Context, videoID string) (*awemeDetail, error) {
+	now := time.Now()
+	params := url.Values{}
+	params.Set("aweme_id", videoID)
+	params.Set("iid", randomDigits(19))
+	params.Set("device_id", randomDigits(19))
+	params.Set("openudid", randomHex(8))
+	params.Set("version_name", "26.1.3")
+	params.Set("version_code", "260103")
+	params.Set("build_number", "26.1.3")
+	params.Set("manifest_version_code", "260103")
+	params.Set("update_version_code", "260103")
+	params.Set("app_name", "trill")
+	params.Set("aid", "1180")
+	params.Set("channel", "googleplay")
+	params.Set("device_platform", "android")
+	params.Set("device_brand", "Google")
+	params.Set("device_type", "Pixel 7")
+	params.Set("os_version", "13")
+	params.Set("os_api", "29")
+	params.Set("resolution", "1080*2400")
+	params.Set("dpi", "420")
+	params.Set("region", "US")
+	params.Set("carrier_region", "US")
+	params.Set("sys_region", "US")
+	params.Set("app_language", "en")
+	params.Set("language", "en")
+	params.Set("timezone_name", "America/New_York")
+	params.Set("ac", "wifi")
+	params.Set("ts", strconv.FormatInt(now.Unix(), 10))
+	params.Set("_rticket", strconv.FormatInt(now.UnixMilli(), 10))
+
+	req, err := http.NewRequestWithContext(ctx, "GET", tiktokFeedAPIURL+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "com.zhiliaoapp.musically/2022600030 (Linux; U; Android 13; en_US; Pixel 7; Build/TD1A.220804.031; Cronet/58.0.2991.0)")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("feed API returned status %d", resp.StatusCode)
+	}
+
+	var data struct {
+		AwemeList []awemeDetail `json:"aweme_list"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return nil, fmt.Errorf("failed to parse feed API response: %w", err)
+	}
+
+	// The feed endpoint may return unrelated recommendations, so match the ID
+	for i := range data.AwemeList {
+		if data.AwemeList[i].AwemeID == videoID {
+			return &data.AwemeList[i], nil
+		}
+	}
+	return nil, fmt.Errorf("video %s not found in feed API response", videoID)
+}
+
+// fetchFromWebPage reads the __UNIVERSAL_DATA_FOR_REHYDRATION__ JSON embedded in the video page
+func (e *TikTokExtractor) fetchFromWebPage(ctx context.Context, pageURL string) (*awemeDetail, error) {
+	body, err := fetchPage(ctx, e.client, pageURL, tiktokUserAgent)
+	if err != nil {
+		return nil, err
+	}
+
+	state := extractScriptJSON(body, "__UNIVERSAL_DATA_FOR_REHYDRATION__")
+	if state == "" {
+		return nil, fmt.Errorf("could not find video data in page")
+	}
+	return parseTikTokRehydration(state)
+}
+
+// fetchFromBrowser renders the page in a browser and reads the rehydration data
+func (e *TikTokExtractor) fetchFromBrowser(ctx context.Context, pageURL string) (*awemeDetail, error) {
+	state, err := fetchPageState(ctx, pageURL, `() => {
+		const el = document.getElementById('__UNIVERSAL_DATA_FOR_REHYDRATION__');
+		return el ? el.textContent : '';
+	}`, e.opts)
+	if err != nil {
+		return nil, err
+	}
+	return parseTikTokRehydration(state)
+}
+
+// extractMusic returns the original sound of a music page as audio
+func (e *TikTokExtractor) extractMusic(ctx context.Context, pageURL, musicID string) (Media, error) {
+	body, err := fetchPage(ctx, e.client, pageURL, tiktokUserAgent)
+	if err != nil {
+		return nil, err
+	}
+
+	state := extractScriptJSON(body, "__UNIVERSAL_DATA_FOR_REHYDRATION__")
+	if state == "" {
+		return nil, fmt.Errorf("could not find music data in page")
+	}
+
+	var data struct {
+		DefaultScope struct {
+			MusicDetail struct {
+				MusicInfo struct {
+					Music tiktokMusic `json:"music"`
+				} `json:"musicInfo"`
+			} `json:"webapp.music-detail"`
+		} `json:"__DEFAULT_SCOPE__"`
+	}
+	if err := json.Unmarshal([]byte(state), &data); err != nil {
+		return nil, fmt.Errorf("failed to parse music data: %w", err)
+	}
+
+	music := data.DefaultScope.MusicDetail.MusicInfo.Music
+	if music.PlayURL == "" {
+		return nil, fmt.Errorf("no audio URL found for music %s", musicID)
+	}
+
+	return &AudioMedia{
+		ID:       musicID,
+		Title:    music.Title,
+		Uploader: music.AuthorName,
+		Duration: music.Duration,
+		URL:      music.PlayURL,
+		Ext:      audioExtFromURL(music.PlayURL),
+	}, nil
+}
+
+// tiktokItemStruct is the camelCase post model used by TikTok's web pages
+type tiktokItemStruct struct {
+	ID     string `json:"id"`
+	Desc   string `json:"desc"`
+	Author struct {
+		UniqueID string `json:"uniqueId"`
+		Nickname string `json:"nickname"`
+	} `json:"author"`
+	Video struct {
+		PlayAddr    string `json:"playAddr"`
+		Width       int    `json:"width"`
+		Height      int    `json:"height"`
+		Duration    int    `json:"duration"` // seconds
+		Bitrate     int    `json:"bitrate"`
+		BitrateInfo []struct {
+			GearName string `json:"GearName"`
+			Bitrate  int    `json:"Bitrate"`
+			PlayAddr struct {
+				URLList []string `json:"UrlList"`
+				Width   int      `json:"Width"`
+				Height  int      `json:"Height"`
+			} `json:"PlayAddr"`
+		} `json:"bitrateInfo"`
+	} `json:"video"`
+	Music     tiktokMusic `json:"music"`
+	ImagePost *struct {
+		Images []struct {
+			ImageURL struct {
+				URLList []string `json:"urlList"`
+			} `json:"imageURL"`
+			ImageWidth  int `json:"imageWidth"`
+			ImageHeight int `json:"imageHeight"`
+		} `json:"images"`
+	} `json:"imagePost"`
+}
+
+type tiktokMusic struct {
+	ID         string `json:"id"`
+	Title      string `json:"title"`
+	AuthorName string `json:"authorName"`
+	PlayURL    string `json:"playUrl"`
+	Duration   int    `json:"duration"` // seconds
+}
+
+// parseTikTokRehydration converts the web rehydration JSON into the shared aweme model
+func parseTikTokRehydration(state string) (*awemeDetail, error) {
+	var data struct {
+		DefaultScope struct {
+			VideoDetail struct {
+				StatusCode int `json:"statusCode"`
+				ItemInfo   struct {
+					ItemStruct *tiktokItemStruct `json:"itemStruct"`
+				} `json:"itemInfo"`
+			} `json:"webapp.video-detail"`
+		} `json:"__DEFAULT_SCOPE__"`
+	}
+	if err := json.Unmarshal([]byte(state), &data); err != nil {
+		return nil, fmt.Errorf("failed to parse video data: %w", err)
+	}
+
+	item := data.DefaultScope.VideoDetail.ItemInfo.ItemStruct
+	if item == nil {
+		return nil, fmt.Errorf("video not found or not accessible (status %d)", data.DefaultScope.VideoDetail.StatusCode)
+	}
+
+	a := &awemeDetail{AwemeID: item.ID, Desc: item.Desc}
+	a.Author.Nickname = item.Author.Nickname
+	a.Author.UniqueID = item.Author.UniqueID
+	a.Video.Width = item.Video.Width
+	a.Video.Height = item.Video.Height
+	a.Video.Duration = item.Video.Duration * 1000
+	if item.Video.PlayAddr != "" {
+		a.Video.PlayAddr.URLList = []string{item.Video.PlayAddr}
+	}
+	for _, b := range item.Video.BitrateInfo {
+		var br awemeBitRate
+		br.GearName = b.GearName
+		br.BitRate = b.Bitrate
+		br.PlayAddr.URLList = b.PlayAddr.URLList
+		br.PlayAddr.Width = b.PlayAddr.Width
+		br.PlayAddr.Height = b.PlayAddr.Height
+		a.Video.BitRate = append(a.Video.BitRate, br)
+	}
+	a.Music.Title = item.Music.Title
+	a.Music.Author = item.Music.AuthorName
+	a.Music.Duration = item.Music.Duration
+	if item.Music.PlayURL != "" {
+		a.Music.PlayURL.URLList = []string{item.Music.PlayURL}
+	}
+	if item.ImagePost != nil {
+		for _, img := range item.ImagePost.Images {
+			a.Images = append(a.Images, awemeImage{
+				URLList: img.ImageURL.URLList,
+				Width:   img.ImageWidth,
+				Height:  img.ImageHeight,
+			})
+		}
+	}
+
+	return a, nil
+}
+
+// awemeDetail is the post ("aweme") model shared by TikTok's app API and Douyin's share pages
+type awemeDetail struct {
+	AwemeID string `json:"aweme_id"`
+	Desc    string `json:"desc"`
+	Author  struct {
+		Nickname string `json:"nickname"`
+		UniqueID string `json:"unique_id"`
+	} `json:"author"`
+	Video struct {
+		PlayAddr awemeURLList   `json:"play_addr"`
+		Width    int            `json:"width"`
+		Height   int            `json:"height"`
+		Duration int            `json:"duration"` // milliseconds
+		BitRate  []awemeBitRate `json:"bit_rate"`
+	} `json:"video"`
+	Music struct {
+		Title    string       `json:"title"`
+		Author   string       `json:"author"`
+		PlayURL  awemeURLList `json:"play_url"`
+		Duration int          `json:"duration"` // seconds
+	} `json:"music"`
+	Images        []awemeImage `json:"images"` // Douyin image posts
+	ImagePostInfo *struct {
+		Images []struct {
+			DisplayImage awemeImage `json:"display_image"`
+		} `json:"images"`
+	} `json:"image_post_info"` // TikTok app API image posts
+}
+
+type awemeURLList struct {
+	URLList []string `json:"url_list"`
+	Width   int      `json:"width"`
+	Height  int      `json:"height"`
+}
+
+type awemeBitRate struct {
+	GearName string       `json:"gear_name"`
+	BitRate  int          `json:"bit_rate"`
+	PlayAddr awemeURLList `json:"play_addr"`
+}
+
+type awemeImage struct {
+	URLList []string `json:"url_list"`
+	Width   int      `json:"width"`
+	Height  int      `json:"height"`
+}
+
+// toMedia converts an aweme into ImageMedia (carousel) or VideoMedia
+func (a *awemeDetail) toMedia(headers map[string]string) (Media, error) {
+	title := truncateText(a.Desc, 100)
+	if title == "" {
+		title = a.AwemeID
+	}
+	uploader := a.Author.Nickname
+	if uploader == "" {
+		uploader = a.Author.UniqueID
+	}
+
+	// Image carousel posts
+	images := a.Images
+	if len(images) == 0 && a.ImagePostInfo != nil {
+		for _, img := range a.ImagePostInfo.Images {
+			images = append(images, img.DisplayImage)
+		}
+	}
+	if len(images) > 0 {
+		var result []Image
+		for _, img := range images {
+			imgURL := pickAwemeImageURL(img.URLList)
+			if imgURL == "" {
+				continue
+			}
+			result = append(result, Image{
+				URL:    imgURL,
+				Ext:    imageExtFromURL(imgURL),
+				Width:  img.Width,
+				Height: img.Height,
+			})
+		}
+		if len(result) > 0 {
+			return &ImageMedia{
+				ID:       a.AwemeID,
+				Title:    title,
+				Uploader: uploader,
+				Images:   result,
+			}, nil
+		}
+	}
+
+	// Video posts: the default play address first, then each bitrate variant
+	var formats []VideoFormat
+	if len(a.Video.PlayAddr.URLList) > 0 {
+		formats = append(formats, VideoFormat{
+			URL:     unwatermarkedAwemeURL(a.Video.PlayAddr.URLList[0]),
+			Quality: qualityFromHeight(a.Video.Height),
+			Ext:     "mp4",
+			Width:   a.Video.Width,
+			Height:  a.Video.Height,
+			Headers: headers,
+		})
+	}
+	for _, b := range a.Video.BitRate {
+		if len(b.PlayAddr.URLList) == 0 {
+			continue
+		}
+		formats = append(formats, VideoFormat{
+			URL:     unwatermarkedAwemeURL(b.PlayAddr.URLList[0]),
+			Quality: qualityFromHeight(b.PlayAddr.Height),
+			Ext:     "mp4",
+			Width:   b.PlayAddr.Width,
+			Height:  b.PlayAddr.Height,
+			Bitrate: b.BitRate,
+			Headers: headers,
+		})
+	}
+
+	if len(formats) == 0 {
+		return nil, fmt.Errorf("no video or images found in post")
+	}
+
+	return &VideoMedia{
+		ID:       a.AwemeID,
+		Title:    title,
+		Uploader: uploader,
+		Duration: a.Video.Duration / 1000,
+		Formats:  formats,
+	}, nil
+}
+
+// toAudio returns the post's background music as audio
+func (a *awemeDetail) toAudio() (Media, error) {
+	if len(a.Music.PlayURL.URLList) == 0 {
+		return nil, fmt.Errorf("no music found in post")
+	}
+	musicURL := a.Music.PlayURL.URLList[0]
+	title := a.Music.Title
+	if title == "" {
+		title = a.AwemeID
+	}
+	return &AudioMedia{
+		ID:       a.AwemeID,
+		Title:    title,
+		Uploader: a.Music.Author,
+		Duration: a.Music.Duration,
+		URL:      musicURL,
+		Ext:      audioExtFromURL(musicURL),
+	}, nil
+}
+
+// Helper functions
+
+func isTikTokShortLink(u *url.URL) bool {
+	host := strings.ToLower(u.Hostname())
+	return host == "vm.tiktok.com" || host == "vt.tiktok.com" || strings.HasPrefix(u.Path, "/t/")
+}
+
+// unwatermarkedAwemeURL swaps Douyin's watermarked "playwm" endpoint for the clean "play" one
+func unwatermarkedAwemeURL(u string) string {
+	return strings.Replace(u, "/playwm/", "/play/", 1)
+}
+
+// pickAwemeImageURL prefers a JPEG variant since the first entry is often HEIC/WebP
+func pickAwemeImageURL(urls []string) string {
+	for _, u := range urls {
+		lower := strings.ToLower(u)
+		if strings.Contains(lower, ".jpeg") || strings.Contains(lower, ".jpg") {
+			return u
+		}
+	}
+	if len(urls) > 0 {
+		return urls[0]
+	}
+	return ""
+}
+
+func qualityFromHeight(height int) string {
+	if height <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("%dp", height)
+}
+
+func imageExtFromURL(u string) string {
+	lower := strings.ToLower(strings.Split(u, "?")[0])
+	switch {
+	case strings.Contains(lower, ".png"):
+		return "png"
+	case strings.Contains(lower, ".webp"):
+		return "webp"
+	case strings.Contains(lower, ".heic"):
+		return "heic"
+	default:
+		return "jpg"
+	}
+}
+
+func audioExtFromURL(u string) string {
+	lower := strings.ToLower(strings.Split(u, "?")[0])
+	switch {
+	case strings.HasSuffix(lower, ".m4a"):
+		return "m4a"
+	case strings.HasSuffix(lower, ".aac"):
+		return "aac"
+	default:
+		return "mp3"
+	}
+}
+
+// resolveRedirect follows redirects of a short link and returns the final URL
+func resolveRedirect(ctx context.Context, client *http.Client, rawURL, userAgent string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", rawURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	return resp.Request.URL.String(), nil
+}
+
+// fetchPage downloads an HTML page with the given user agent
+func fetchPage(ctx context.Context, client *http.Client, pageURL, userAgent string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", pageURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Accept-Language", "en-US,en;q=0.9,zh-CN;q=0.8")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("page request failed with status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	return string(body), nil
+}
+
+// extractScriptJSON returns the contents of <script id="{id}" ...>...</script>
+func extractScriptJSON(html, id string) string {
+	start := strings.Index(html, `id="`+id+`"`)
+	if start == -1 {
+		return ""
+	}
+	start = strings.Index(html[start:], ">") + start + 1
+	end := strings.Index(html[start:], "</script>")
+	if end == -1 {
+		return ""
+	}
+	return html[start : start+end]
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
 }
 
-func (e *TikTokExtractor) Extract(url string) (Media, error) {
-	return nil, fmt.Errorf("TikTok support coming soon")
+func randomDigits(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	for i := range b {
+		b[i] = '0' + b[i]%10
+	}
+	if b[0] == '0' {
+		b[0] = '7'
+	}
+	return string(b)
 }
 
 func init() {
 	Register(&TikTokExtractor{},
 		"tiktok.com",
+		"m.tiktok.com",
 		"vm.tiktok.com",
+		"vt.tiktok.com",
 	)
 }
@@ -1,6 +1,7 @@
 package extractor
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -20,11 +21,15 @@ const (
 	twitterGuestTokenURL  = "https://api.x.com/1.1/guest/activate.json"
 	twitterGraphQLURL     = "https://x.com/i/api/graphql/2ICDjqPd81tulZcYrtpTuQ/TweetResultByRestId"
 	twitterSyndicationURL = "https://cdn.syndication.twimg.com/tweet-result"
+	twitterAudioSpaceURL  = "https://x.com/i/api/graphql/HPEisOmj1epUNLCWTYhUWw/AudioSpaceById"
+	twitterLiveStreamURL  = "https://x.com/i/api/1.1/live_video_stream/status/"
 )
 
 var (
 	// Matches twitter.com and x.com URLs with status
 	twitterURLRegex = regexp.MustCompile(`(?:twitter\.com|x\.com)/(?:[^/]+)/status/(\d+)`)
+	// Matches Spaces URLs (e.g., x.com/i/spaces/1eaKbrPAqbwKX)
+	twitterSpaceURLRegex = regexp.MustCompile(`(?:twitter\.com|x\.com)/i/spaces/([a-zA-Z0-9]+)`)
 )
 
 // Twitter-specific error types for i18n support
@@ -57,15 +62,23 @@ func (t *TwitterExtractor) Name() string {
 	return "twitter"
 }
 
-// Match checks if URL is a Twitter/X status URL
-func (t *TwitterExtractor) Match(u *url.URL) bool {
-	// Host matching is done by registry, check path pattern
-	return twitterURLRegex.MatchString(u.String())
+// Info describes the URLs and media this extractor handles
+func (t *TwitterExtractor) Info() Info {
+	return Info{
+		Name:       "twitter",
+		Title:      "Twitter/X",
+		Kinds:      []URLKind{URLKindSingle, URLKindLive},
+		MediaTypes: []MediaType{MediaTypeVideo, MediaTypeImage, MediaTypeAudio},
+		Requires: []Requirement{
+			{Kind: RequiresCookie, Optional: true, Detail: "vget config set twitter.auth_token <token> (for age-restricted and protected tweets)"},
+		},
+	}
 }
 
-// SetAuth sets authentication credentials for accessing restricted content
-func (t *TwitterExtractor) SetAuth(authToken string) {
-	t.authToken = authToken
+// Match checks if URL is a Twitter/X status or Spaces URL
+func (t *TwitterExtractor) Match(u *url.URL) bool {
+	// Host matching is done by registry, check path pattern
+	return twitterURLRegex.MatchString(u.String()) || twitterSpaceURLRegex.MatchString(u.String())
 }
 
 // IsAuthenticated returns true if auth credentials are set
@@ -75,14 +88,25 @@ func (t *TwitterExtractor) IsAuthenticated() bool {
 
 // Extract retrieves media from a Twitter/X URL
 func (t *TwitterExtractor) Extract(urlStr string) (Media, error) {
-	// Initialize HTTP client
-	if t.client == nil {
-		t.client = &http.Client{
-			Timeout: 30 * time.Second,
-			Transport: &http.Transport{
-				Proxy: http.ProxyFromEnvironment,
-			},
-		}
+	return t.ExtractContext(context.Background(), urlStr, Options{})
+}
+
+// ExtractContext retrieves media from a Twitter/X URL, authenticating with
+// opts.AuthToken if set. The tokens obtained along the way belong to the call.
+func (t *TwitterExtractor) ExtractContext(ctx context.Context, urlStr string, opts Options) (Media, error) {
+	client := t.client
+	if client == nil {
+		client = newHTTPClient(opts, 30*time.Second)
+	}
+	call := &TwitterExtractor{client: client, authToken: opts.AuthToken}
+	return call.extract(ctx, urlStr)
+}
+
+// extract resolves a URL using the call's client and tokens
+func (t *TwitterExtractor) extract(ctx context.Context, urlStr string) (Media, error) {
+	// Spaces are audio-only and resolved through a separate API
+	if matches := twitterSpaceURLRegex.FindStringSubmatch(urlStr); len(matches) >= 2 {
+		return t.extractSpace(ctx, matches[1])
 	}
 
 	// Extract tweet ID from URL
@@ -94,7 +118,7 @@ func (t *TwitterExtractor) Extract(urlStr string) (Media, error) {
 
 	// If authenticated, use GraphQL API directly (supports NSFW content)
 	if t.IsAuthenticated() {
-		media, err := t.fetchFromGraphQLAuth(tweetID)
+		media, err := t.fetchFromGraphQLAuth(ctx, tweetID)
 		if err != nil {
 			return nil, fmt.Errorf("failed to fetch tweet: %w", err)
 		}
@@ -102,17 +126,17 @@ func (t *TwitterExtractor) Extract(urlStr string) (Media, error) {
 	}
 
 	// Try syndication API first (simpler, no auth needed for public tweets)
-	media, err := t.fetchFromSyndication(tweetID)
+	media, err := t.fetchFromSyndication(ctx, tweetID)
 	if err == nil {
 		return media, nil
 	}
 
 	// Fallback to GraphQL API with guest token
-	if err := t.fetchGuestToken(); err != nil {
+	if err := t.fetchGuestToken(ctx); err != nil {
 		return nil, fmt.Errorf("failed to get guest token: %w", err)
 	}
 
-	media, err = t.fetchFromGraphQL(tweetID)
+	media, err = t.fetchFromGraphQL(ctx, tweetID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch tweet: %w", err)
 	}
@@ -121,14 +145,14 @@ func (t *TwitterExtractor) Extract(urlStr string) (Media, error) {
 }
 
 // fetchFromSyndication tries the syndication endpoint (works for public tweets)
-func (t *TwitterExtractor) fetchFromSyndication(tweetID string) (Media, error) {
+func (t *TwitterExtractor) fetchFromSyndication(ctx context.Context, tweetID string) (Media, error) {
 	params := url.Values{}
 	params.Set("id", tweetID)
 	params.Set("token", "x") // Required but value doesn't matter
 
 	reqURL := twitterSyndicationURL + "?" + params.Encode()
 
-	req, err := http.NewRequest("GET", reqURL, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -156,8 +180,8 @@ func (t *TwitterExtractor) fetchFromSyndication(tweetID string) (Media, error) {
 }
 
 // fetchGuestToken obtains a guest token for API access
-func (t *TwitterExtractor) fetchGuestToken() error {
-	req, err := http.NewRequest("POST", twitterGuestTokenURL, nil)
+func (t *TwitterExtractor) fetchGuestToken(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, "POST", twitterGuestTokenURL, nil)
 	if err != nil {
 		return err
 	}
@@ -187,7 +211,7 @@ func (t *TwitterExtractor) fetchGuestToken() error {
 }
 
 // fetchFromGraphQL uses the GraphQL API
-func (t *TwitterExtractor) fetchFromGraphQL(tweetID string) (Media, error) {
+func (t *TwitterExtractor) fetchFromGraphQL(ctx context.Context, tweetID string) (Media, error) {
 	variables := map[string]interface{}{
 		"tweetId":                tweetID,
 		"withCommunity":          false,
@@ -227,7 +251,7 @@ func (t *TwitterExtractor) fetchFromGraphQL(tweetID string) (Media, error) {
 
 	reqURL := twitterGraphQLURL + "?" + params.Encode()
 
-	req, err := http.NewRequest("GET", reqURL, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -257,8 +281,8 @@ func (t *TwitterExtractor) fetchFromGraphQL(tweetID string) (Media, error) {
 }
 
 // fetchCsrfToken fetches the ct0 CSRF token by making a request to Twitter
-func (t *TwitterExtractor) fetchCsrfToken() error {
-	req, err := http.NewRequest("GET", "https://x.com", nil)
+func (t *TwitterExtractor) fetchCsrfToken(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", "https://x.com", nil)
 	if err != nil {
 		return err
 	}
@@ -283,10 +307,10 @@ func (t *TwitterExtractor) fetchCsrfToken() error {
 }
 
 // fetchFromGraphQLAuth uses the GraphQL API with authentication (for NSFW content)
-func (t *TwitterExtractor) fetchFromGraphQLAuth(tweetID string) (Media, error) {
+func (t *TwitterExtractor) fetchFromGraphQLAuth(ctx context.Context, tweetID string) (Media, error) {
 	// Fetch CSRF token if not already set
 	if t.csrfToken == "" {
-		if err := t.fetchCsrfToken(); err != nil {
+		if err := t.fetchCsrfToken(ctx); err != nil {
 			return nil, fmt.Errorf("failed to get CSRF token: %w", err)
 		}
 	}
@@ -330,7 +354,7 @@ func (t *TwitterExtractor) fetchFromGraphQLAuth(tweetID string) (Media, error) {
 
 	reqURL := twitterGraphQLURL + "?" + params.Encode()
 
-	req, err := http.NewRequest("GET", reqURL, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -655,6 +679,149 @@ func (t *TwitterExtractor) parseGraphQLResponse(body []byte, tweetID string) (Me
 	return nil, fmt.Errorf("no media found in tweet")
 }
 
+// extractSpace resolves a Space's metadata and its live/replay HLS stream
+func (t *TwitterExtractor) extractSpace(ctx context.Context, spaceID string) (Media, error) {
+	if t.IsAuthenticated() {
+		if t.csrfToken == "" {
+			if err := t.fetchCsrfToken(ctx); err != nil {
+				return nil, fmt.Errorf("failed to get CSRF token: %w", err)
+			}
+		}
+	} else if t.guestToken == "" {
+		if err := t.fetchGuestToken(ctx); err != nil {
+			return nil, fmt.Errorf("failed to get guest token: %w", err)
+		}
+	}
+
+	variables := map[string]interface{}{
+		"id":              spaceID,
+		"isMetatagsQuery": false,
+		"withReplays":     true,
+		"withListeners":   true,
+	}
+	features := map[string]interface{}{
+		"spaces_2022_h2_spaces_communities":               true,
+		"spaces_2022_h2_clipping":                         true,
+		"creator_subscriptions_tweet_preview_api_enabled": true,
+	}
+
+	variablesJSON, _ := json.Marshal(variables)
+	featuresJSON, _ := json.Marshal(features)
+
+	params := url.Values{}
+	params.Set("variables", string(variablesJSON))
+	params.Set("features", string(featuresJSON))
+
+	body, err := t.apiGet(ctx, twitterAudioSpaceURL+"?"+params.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch space: %w", err)
+	}
+
+	var spaceResp audioSpaceResponse
+	if err := json.Unmarshal(body, &spaceResp); err != nil {
+		return nil, fmt.Errorf("failed to parse space response: %w", err)
+	}
+
+	meta := spaceResp.Data.AudioSpace.Metadata
+	if meta.MediaKey == "" {
+		return nil, &TwitterError{Code: TwitterErrorUnavailable, Message: "space not found or not accessible"}
+	}
+	if meta.State == "Ended" && !meta.IsSpaceAvailableForReplay {
+		return nil, &TwitterError{Code: TwitterErrorUnavailable, Message: "space has ended and has no replay"}
+	}
+
+	// Resolve the HLS playlist for the Space audio
+	body, err = t.apiGet(ctx, twitterLiveStreamURL+meta.MediaKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch space stream: %w", err)
+	}
+
+	var streamResp struct {
+		Source struct {
+			Location           string `json:"location"`
+			NoRedirectLocation string `json:"noRedirectLocation"`
+		} `json:"source"`
+	}
+	if err := json.Unmarshal(body, &streamResp); err != nil {
+		return nil, fmt.Errorf("failed to parse space stream response: %w", err)
+	}
+
+	streamURL := streamResp.Source.NoRedirectLocation
+	if streamURL == "" {
+		streamURL = streamResp.Source.Location
+	}
+	if streamURL == "" {
+		return nil, fmt.Errorf("no stream found for space")
+	}
+
+	title := meta.Title
+	var uploader string
+	if host := meta.CreatorResults.Result; host != nil {
+		uploader = host.Legacy.ScreenName
+		if title == "" {
+			title = fmt.Sprintf("%s's Space", host.Legacy.Name)
+		}
+	}
+	if title == "" {
+		title = spaceID
+	}
+
+	var duration int
+	startedAt, _ := meta.StartedAt.Int64()
+	endedAt, _ := meta.EndedAt.Int64()
+	if startedAt > 0 && endedAt > startedAt {
+		duration = int((endedAt - startedAt) / 1000)
+	}
+
+	return &AudioMedia{
+		ID:       spaceID,
+		Title:    title,
+		Uploader: uploader,
+		Duration: duration,
+		URL:      streamURL,
+		Ext:      "m4a",
+	}, nil
+}
+
+// apiGet performs a GET request against the Twitter API using auth cookies or the guest token
+func (t *TwitterExtractor) apiGet(ctx context.Context, reqURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+twitterBearerToken)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36")
+
+	if t.IsAuthenticated() {
+		req.Header.Set("x-twitter-auth-type", "OAuth2Session")
+		req.Header.Set("x-twitter-active-user", "yes")
+		req.Header.Set("x-csrf-token", t.csrfToken)
+		req.AddCookie(&http.Cookie{Name: "auth_token", Value: t.authToken})
+		req.AddCookie(&http.Cookie{Name: "ct0", Value: t.csrfToken})
+	} else {
+		req.Header.Set("x-guest-token", t.guestToken)
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	return body, nil
+}
+
 // Syndication API response structures
 type syndicationResponse struct {
 	Text string `json:"text"`
@@ -737,6 +904,31 @@ type graphQLLegacy struct {
 	} `json:"extended_entities"`
 }
 
+// AudioSpaceById response structures
+type audioSpaceResponse struct {
+	Data struct {
+		AudioSpace struct {
+			Metadata struct {
+				RestID                    string      `json:"rest_id"`
+				State                     string      `json:"state"` // "Running", "Ended"
+				Title                     string      `json:"title"`
+				MediaKey                  string      `json:"media_key"`
+				StartedAt                 json.Number `json:"started_at"` // milliseconds, number or string
+				EndedAt                   json.Number `json:"ended_at"`
+				IsSpaceAvailableForReplay bool        `json:"is_space_available_for_replay"`
+				CreatorResults            struct {
+					Result *struct {
+						Legacy struct {
+							ScreenName string `json:"screen_name"`
+							Name       string `json:"name"`
+						} `json:"legacy"`
+					} `json:"result"`
+				} `json:"creator_results"`
+			} `json:"metadata"`
+		} `json:"audioSpace"`
+	} `json:"data"`
+}
+
 // Helper functions
 
 func truncateText(s string, maxLen int) string {
@@ -0,0 +1,76 @@
+package extractor
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/guiyumin/vget/internal/testutil/replay"
+)
+
+func TestTwitterSyndication(t *testing.T) {
+	e := &TwitterExtractor{client: replay.Client(t, filepath.Join("testdata", "replay", "twitter-syndication"))}
+
+	media, err := e.Extract("https://x.com/vget_test/status/1790000000000000001")
+	if err != nil {
+		t.Fatalf("Extract: %v", err)
+	}
+	multi, ok := media.(*MultiVideoMedia)
+	if !ok {
+		t.Fatalf("expected *MultiVideoMedia, got %T", media)
+	}
+	if multi.Uploader != "vget_test" || multi.Title != "Launch day footage from both cameras https://t.co/abc" {
+		t.Errorf("unexpected metadata: %q by %q", multi.Title, multi.Uploader)
+	}
+	if len(multi.Videos) != 2 {
+		t.Fatalf("expected 2 videos, got %d", len(multi.Videos))
+	}
+	first := multi.Videos[0]
+	if first.ID != "1790000000000000001_1" || len(first.Formats) != 2 {
+		t.Fatalf("unexpected first video: %s with %d formats", first.ID, len(first.Formats))
+	}
+	if f := first.Formats[0]; f.Quality != "720p" || f.Width != 1280 || f.Bitrate != 2176000 {
+		t.Errorf("expected best format first, got %+v", f)
+	}
+
+	media, err = e.Extract("https://twitter.com/vget_test/status/1790000000000000002")
+	if err != nil {
+		t.Fatalf("Extract: %v", err)
+	}
+	images, ok := media.(*ImageMedia)
+	if !ok {
+		t.Fatalf("expected *ImageMedia, got %T", media)
+	}
+	if len(images.Images) != 2 {
+		t.Fatalf("expected 2 images, got %d", len(images.Images))
+	}
+	if img := images.Images[0]; img.URL != "https://pbs.twimg.com/media/GAbc.jpg?format=jpg&name=orig" || img.Width != 2048 {
+		t.Errorf("unexpected image: %+v", img)
+	}
+	if images.Images[1].Ext != "png" {
+		t.Errorf("expected png, got %s", images.Images[1].Ext)
+	}
+}
+
+func TestTwitterGraphQLFallback(t *testing.T) {
+	e := &TwitterExtractor{client: replay.Client(t, filepath.Join("testdata", "replay", "twitter-graphql"))}
+
+	// extract keeps the tokens on e, where ExtractContext would keep them per call
+	media, err := e.extract(context.Background(), "https://x.com/vget_test/status/1790000000000000003")
+	if err != nil {
+		t.Fatalf("extract: %v", err)
+	}
+	video, ok := media.(*VideoMedia)
+	if !ok {
+		t.Fatalf("expected *VideoMedia, got %T", media)
+	}
+	if e.guestToken != "1790000000000000999" {
+		t.Errorf("guest token not stored: %q", e.guestToken)
+	}
+	if video.ID != "1790000000000000003" || video.Uploader != "vget_test" || video.Duration != 42 {
+		t.Errorf("unexpected metadata: %+v", video)
+	}
+	if len(video.Formats) != 2 || video.Formats[0].Height != 1080 {
+		t.Errorf("expected 2 mp4 formats with 1080p first, got %+v", video.Formats)
+	}
+}
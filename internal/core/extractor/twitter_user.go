@@ -0,0 +1,133 @@
+package extractor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// twitterTimelineURL serves a user's recent tweets as embedded in timeline widgets
+const twitterTimelineURL = "https://syndication.twitter.com/srv/timeline-profile/screen-name/"
+
+// twitterUserRegex matches a profile URL, x.com/{name} or x.com/{name}/media
+var twitterUserRegex = regexp.MustCompile(`^(?:https?://)?(?:www\.|mobile\.)?(?:twitter\.com|x\.com)/([A-Za-z0-9_]{1,15})(?:/media)?/?(?:\?.*)?$`)
+
+// twitterReservedPaths are top-level pages that are not profiles
+var twitterReservedPaths = map[string]bool{
+	"home": true, "explore": true, "search": true, "notifications": true,
+	"messages": true, "settings": true, "compose": true, "login": true,
+	"signup": true, "i": true, "intent": true, "share": true, "hashtag": true,
+	"tos": true, "privacy": true,
+}
+
+// MatchTwitterUser returns the screen name of a Twitter/X profile URL
+func MatchTwitterUser(rawURL string) (string, bool) {
+	m := twitterUserRegex.FindStringSubmatch(rawURL)
+	if m == nil || twitterReservedPaths[strings.ToLower(m[1])] {
+		return "", false
+	}
+	return m[1], true
+}
+
+// FetchTwitterUserPosts lists the newest tweets with media of a Twitter/X
+// user. Retweets are left out.
+func FetchTwitterUserPosts(ctx context.Context, screenName string, opts Options) (*Uploads, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", twitterTimelineURL+screenName, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36")
+	if opts.AuthToken != "" {
+		req.AddCookie(&http.Cookie{Name: "auth_token", Value: opts.AuthToken})
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("timeline request failed with status %d", resp.StatusCode)
+	}
+	return parseTwitterTimeline(string(body), screenName)
+}
+
+// parseTwitterTimeline reads the tweets with media from a timeline page
+func parseTwitterTimeline(html, screenName string) (*Uploads, error) {
+	jsonData := extractScriptJSON(html, "__NEXT_DATA__")
+	if jsonData == "" {
+		return nil, fmt.Errorf("timeline data not found")
+	}
+
+	var nextData struct {
+		Props struct {
+			PageProps struct {
+				Timeline struct {
+					Entries []struct {
+						Content struct {
+							Tweet struct {
+								IDStr     string `json:"id_str"`
+								Text      string `json:"full_text"`
+								CreatedAt string `json:"created_at"`
+								User      struct {
+									Name       string `json:"name"`
+									ScreenName string `json:"screen_name"`
+								} `json:"user"`
+								ExtendedEntities struct {
+									Media []struct {
+										Type      string `json:"type"` // "photo", "video" or "animated_gif"
+										VideoInfo struct {
+											DurationMillis int `json:"duration_millis"`
+										} `json:"video_info"`
+									} `json:"media"`
+								} `json:"extended_entities"`
+							} `json:"tweet"`
+						} `json:"content"`
+					} `json:"entries"`
+				} `json:"timeline"`
+			} `json:"pageProps"`
+		} `json:"props"`
+	}
+	if err := json.Unmarshal([]byte(jsonData), &nextData); err != nil {
+		return nil, fmt.Errorf("failed to parse timeline: %w", err)
+	}
+
+	uploads := &Uploads{ID: screenName, Title: screenName}
+	for _, entry := range nextData.Props.PageProps.Timeline.Entries {
+		tweet := entry.Content.Tweet
+		media := tweet.ExtendedEntities.Media
+		if tweet.IDStr == "" || len(media) == 0 || !strings.EqualFold(tweet.User.ScreenName, screenName) {
+			continue
+		}
+		if tweet.User.Name != "" {
+			uploads.Title = tweet.User.Name
+		}
+
+		upload := Upload{
+			ID:    tweet.IDStr,
+			URL:   fmt.Sprintf("https://x.com/%s/status/%s", tweet.User.ScreenName, tweet.IDStr),
+			Title: truncateText(tweet.Text, 100),
+			Type:  MediaTypeImage,
+		}
+		for _, m := range media {
+			if m.Type == "video" || m.Type == "animated_gif" {
+				upload.Type = MediaTypeVideo
+				upload.Duration = max(upload.Duration, m.VideoInfo.DurationMillis/1000)
+			}
+		}
+		upload.PublishedAt, _ = time.Parse(time.RubyDate, tweet.CreatedAt)
+		uploads.Items = append(uploads.Items, upload)
+	}
+	return uploads, nil
+}
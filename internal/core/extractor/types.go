@@ -3,9 +3,11 @@ package extractor
 import (
 	"fmt"
 	"net/url"
+	"path/filepath"
 	"regexp"
 	"slices"
 	"strings"
+	"time"
 )
 
 // MediaType represents the type of media being downloaded
@@ -46,6 +48,7 @@ type VideoMedia struct {
 	Duration  int // seconds
 	Thumbnail string
 	Formats   []VideoFormat
+	Subtitles []Subtitle
 }
 
 func (v *VideoMedia) GetID() string       { return v.ID }
@@ -65,6 +68,24 @@ type VideoFormat struct {
 	AudioURL string            // Separate audio stream URL (for adaptive formats that need merging)
 }
 
+// Subtitle is a text track published alongside a video
+type Subtitle struct {
+	URL  string
+	Lang string // language code, e.g. "en", "pt-BR"
+	Name string // display label, e.g. "English (auto-generated)"
+	Ext  string // "vtt", "srt"
+}
+
+// Path returns the sidecar path "{video base}.{lang}.{ext}" for a video saved at videoPath
+func (s Subtitle) Path(videoPath string) string {
+	base := strings.TrimSuffix(videoPath, filepath.Ext(videoPath))
+	lang := s.Lang
+	if lang == "" {
+		lang = "und"
+	}
+	return fmt.Sprintf("%s.%s.%s", base, SanitizeFilename(lang), s.Ext)
+}
+
 // QualityLabel returns a human-readable quality label
 func (f *VideoFormat) QualityLabel() string {
 	if f.Quality != "" {
@@ -78,12 +99,25 @@ func (f *VideoFormat) QualityLabel() string {
 
 // AudioMedia represents audio content (podcasts, music)
 type AudioMedia struct {
-	ID       string
-	Title    string
-	Uploader string
-	Duration int // seconds
-	URL      string
-	Ext      string // "mp3", "m4a", etc.
+	ID          string
+	Title       string
+	Uploader    string
+	Duration    int // seconds
+	URL         string
+	Ext         string    // "mp3", "m4a", etc.
+	PublishedAt time.Time // Zero if unknown
+	Description string    // Show notes (plain text or markdown)
+	CoverURL    string    // Episode artwork
+	Attachments []Attachment
+	Album       string // Album or playlist the track belongs to, for tagging
+	TrackNumber int    // 1-based position in the album; 0 if not part of one
+}
+
+// Attachment is a side file published alongside an episode (transcript, chapters)
+type Attachment struct {
+	Kind string // "transcript" or "chapters"
+	URL  string
+	Ext  string // e.g. "vtt", "srt", "chapters.json"
 }
 
 func (a *AudioMedia) GetID() string       { return a.ID }
@@ -91,6 +125,32 @@ func (a *AudioMedia) GetTitle() string    { return a.Title }
 func (a *AudioMedia) GetUploader() string { return a.Uploader }
 func (a *AudioMedia) Type() MediaType     { return MediaTypeAudio }
 
+// TrackFilename returns "{NN} {title}.{ext}" for album tracks, so files sort in
+// album order, and "{title}.{ext}" otherwise
+func (a *AudioMedia) TrackFilename() string {
+	name := SanitizeFilename(a.Title)
+	if name == "" {
+		name = a.ID
+	}
+	if a.TrackNumber > 0 {
+		name = fmt.Sprintf("%02d %s", a.TrackNumber, name)
+	}
+	return fmt.Sprintf("%s.%s", name, a.Ext)
+}
+
+// EpisodeFilename returns "{date} {title}.{ext}" for podcast episodes, so files
+// sort chronologically
+func (a *AudioMedia) EpisodeFilename() string {
+	name := SanitizeFilename(a.Title)
+	if name == "" {
+		name = a.ID
+	}
+	if !a.PublishedAt.IsZero() {
+		name = a.PublishedAt.Format("2006-01-02") + " " + name
+	}
+	return fmt.Sprintf("%s.%s", name, a.Ext)
+}
+
 // ImageMedia represents one or more images from a single source
 type ImageMedia struct {
 	ID       string
@@ -110,6 +170,7 @@ type MultiVideoMedia struct {
 	Title    string
 	Uploader string
 	Videos   []*VideoMedia
+	Images   []Image // Images mixed in with the videos (e.g., Instagram carousels)
 }
 
 func (m *MultiVideoMedia) GetID() string       { return m.ID }
@@ -117,12 +178,69 @@ func (m *MultiVideoMedia) GetTitle() string    { return m.Title }
 func (m *MultiVideoMedia) GetUploader() string { return m.Uploader }
 func (m *MultiVideoMedia) Type() MediaType     { return MediaTypeVideo }
 
+// PodcastMedia represents a podcast show expanded into its episodes (newest first)
+type PodcastMedia struct {
+	ID          string
+	Title       string
+	Uploader    string
+	Description string
+	CoverURL    string
+	Episodes    []*AudioMedia
+}
+
+func (p *PodcastMedia) GetID() string       { return p.ID }
+func (p *PodcastMedia) GetTitle() string    { return p.Title }
+func (p *PodcastMedia) GetUploader() string { return p.Uploader }
+func (p *PodcastMedia) Type() MediaType     { return MediaTypeAudio }
+
+// AlbumMedia represents an album or playlist expanded into its tracks, in order
+type AlbumMedia struct {
+	ID       string
+	Title    string
+	Uploader string
+	CoverURL string
+	Tracks   []*AudioMedia
+}
+
+func (a *AlbumMedia) GetID() string       { return a.ID }
+func (a *AlbumMedia) GetTitle() string    { return a.Title }
+func (a *AlbumMedia) GetUploader() string { return a.Uploader }
+func (a *AlbumMedia) Type() MediaType     { return MediaTypeAudio }
+
+// CollectionMedia represents a user profile or collection expanded into its posts,
+// each of which is a VideoMedia, ImageMedia or MultiVideoMedia
+type CollectionMedia struct {
+	ID       string
+	Title    string
+	Uploader string
+	Items    []Media
+}
+
+func (c *CollectionMedia) GetID() string       { return c.ID }
+func (c *CollectionMedia) GetTitle() string    { return c.Title }
+func (c *CollectionMedia) GetUploader() string { return c.Uploader }
+func (c *CollectionMedia) Type() MediaType     { return MediaTypeVideo }
+
 // Image represents a single image to download
 type Image struct {
-	URL    string
-	Ext    string // "jpg", "png", "webp"
-	Width  int
-	Height int
+	URL     string
+	Ext     string // "jpg", "png", "webp"
+	Width   int
+	Height  int
+	Headers map[string]string // Custom headers for download (e.g., Referer)
+
+	// LiveVideoURL is the motion part of a Live Photo, saved next to the image
+	LiveVideoURL string
+	LiveVideoExt string // "mov", "mp4"
+}
+
+// LiveVideoPath returns where the Live Photo video of an image saved at imagePath goes
+func (i *Image) LiveVideoPath(imagePath string) string {
+	ext := i.LiveVideoExt
+	if ext == "" {
+		ext = "mov"
+	}
+	return strings.TrimSuffix(imagePath, filepath.Ext(imagePath)) + "." + ext
 }
 
 // SanitizeFilename removes or replaces characters that are invalid in filenames
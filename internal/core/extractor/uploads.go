@@ -0,0 +1,21 @@
+package extractor
+
+import "time"
+
+// Upload is a post listed on an uploader's page. Only what a listing shows is
+// known; the post itself is extracted from its URL when it is downloaded.
+type Upload struct {
+	ID          string
+	URL         string
+	Title       string
+	Duration    int       // seconds; 0 if unknown
+	Type        MediaType // "" if unknown
+	PublishedAt time.Time // Zero if unknown
+}
+
+// Uploads is the newest posts of an uploader, newest first
+type Uploads struct {
+	ID    string
+	Title string // the uploader's name
+	Items []Upload
+}
@@ -0,0 +1,75 @@
+package extractor
+
+import "testing"
+
+func TestMatchUploaders(t *testing.T) {
+	spaces := map[string]string{
+		"https://space.bilibili.com/946974":             "946974",
+		"https://space.bilibili.com/946974/video":       "946974",
+		"https://space.bilibili.com/946974/?spm_id=333": "946974",
+		"https://www.bilibili.com/video/BV1GJ411x7h7":   "",
+	}
+	for rawURL, want := range spaces {
+		if mid, _ := MatchBilibiliSpace(rawURL); mid != want {
+			t.Errorf("MatchBilibiliSpace(%q) = %q, want %q", rawURL, mid, want)
+		}
+	}
+
+	users := map[string]string{
+		"https://x.com/NASA":                   "NASA",
+		"https://twitter.com/NASA/media":       "NASA",
+		"https://x.com/NASA/status/1234567890": "",
+		"https://x.com/explore":                "",
+		"https://x.com/i/spaces/1eaKbrPAqbwKX": "",
+	}
+	for rawURL, want := range users {
+		if name, _ := MatchTwitterUser(rawURL); name != want {
+			t.Errorf("MatchTwitterUser(%q) = %q, want %q", rawURL, name, want)
+		}
+	}
+}
+
+func TestParseBilibiliUploads(t *testing.T) {
+	body := `{"code":0,"data":{"list":{"vlist":[
+		{"bvid":"BV1xx411c7mD","title":"Newest","author":"UP主","length":"1:02:03","created":1700000000},
+		{"bvid":"BV1GJ411x7h7","title":"Older","author":"UP主","length":"03:32","created":1600000000}
+	]}}}`
+	uploads, err := parseBilibiliUploads([]byte(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if uploads.Title != "UP主" || len(uploads.Items) != 2 {
+		t.Fatalf("uploads = %+v", uploads)
+	}
+	first := uploads.Items[0]
+	if first.URL != "https://www.bilibili.com/video/BV1xx411c7mD" || first.Duration != 3723 || first.PublishedAt.Unix() != 1700000000 {
+		t.Errorf("first upload = %+v", first)
+	}
+
+	if _, err := parseBilibiliUploads([]byte(`{"code":-352,"message":"风控校验失败"}`)); err == nil {
+		t.Error("expected an error for a refused request")
+	}
+}
+
+func TestParseTwitterTimeline(t *testing.T) {
+	html := `<html><script id="__NEXT_DATA__" type="application/json">{"props":{"pageProps":{"timeline":{"entries":[
+		{"content":{"tweet":{"id_str":"3","full_text":"Launch replay","created_at":"Tue Mar 05 18:00:00 +0000 2024",
+			"user":{"name":"NASA","screen_name":"NASA"},
+			"extended_entities":{"media":[{"type":"video","video_info":{"duration_millis":61500}}]}}}},
+		{"content":{"tweet":{"id_str":"2","full_text":"Text only","user":{"screen_name":"NASA"}}}},
+		{"content":{"tweet":{"id_str":"1","full_text":"Retweeted photo","user":{"screen_name":"ESA"},
+			"extended_entities":{"media":[{"type":"photo"}]}}}}
+	]}}}}</script></html>`
+
+	uploads, err := parseTwitterTimeline(html, "nasa")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if uploads.Title != "NASA" || len(uploads.Items) != 1 {
+		t.Fatalf("uploads = %+v", uploads)
+	}
+	item := uploads.Items[0]
+	if item.URL != "https://x.com/NASA/status/3" || item.Type != MediaTypeVideo || item.Duration != 61 || item.PublishedAt.IsZero() {
+		t.Errorf("item = %+v", item)
+	}
+}
@@ -0,0 +1,361 @@
+package extractor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/guiyumin/vget/internal/core/downloader"
+)
+
+const (
+	vimeoConfigURL   = "https://player.vimeo.com/video/%s/config"
+	vimeoPlayerURL   = "https://player.vimeo.com/"
+	vimeoDefaultRef  = "https://vimeo.com/"
+	vimeoUserAgent   = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
+	vimeoPrivacyText = "because of its privacy settings"
+)
+
+var (
+	// vimeo.com/<id>, vimeo.com/<id>/<hash> (unlisted), and videos inside channels, groups, albums and showcases
+	vimeoPageRegex = regexp.MustCompile(`^/(?:channels/[^/]+/|groups/[^/]+/videos/|album/\d+/video/|showcase/\d+/video/)?(\d+)(?:/([0-9a-f]+))?/?$`)
+	// player.vimeo.com/video/<id>?h=<hash>
+	vimeoPlayerRegex = regexp.MustCompile(`^/video/(\d+)/?$`)
+)
+
+// VimeoExtractor handles Vimeo videos, including unlisted links and player embeds.
+// Formats come from the player config: progressive MP4s plus the HLS renditions,
+// whose separate audio track is merged after download.
+type VimeoExtractor struct {
+	client *http.Client
+}
+
+func (e *VimeoExtractor) Name() string {
+	return "vimeo"
+}
+
+// Info describes the URLs and media this extractor handles
+func (e *VimeoExtractor) Info() Info {
+	return Info{
+		Name:       "vimeo",
+		Title:      "Vimeo",
+		Kinds:      []URLKind{URLKindSingle},
+		MediaTypes: []MediaType{MediaTypeVideo},
+	}
+}
+
+func (e *VimeoExtractor) Match(u *url.URL) bool {
+	if u.Hostname() == "player.vimeo.com" {
+		return vimeoPlayerRegex.MatchString(u.Path)
+	}
+	return vimeoPageRegex.MatchString(u.Path)
+}
+
+func (e *VimeoExtractor) Extract(rawURL string) (Media, error) {
+	return e.ExtractContext(context.Background(), rawURL, Options{})
+}
+
+func (e *VimeoExtractor) ExtractContext(ctx context.Context, rawURL string, opts Options) (Media, error) {
+	client := e.client
+	if client == nil {
+		client = newHTTPClient(opts, 30*time.Second)
+	}
+
+	id, hash, err := parseVimeoURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	// Embeds restricted to certain domains only play with one of them as the Referer
+	referer := opts.Referer
+	if referer == "" {
+		referer = vimeoDefaultRef
+	}
+
+	config, err := e.fetchConfig(ctx, client, id, hash, referer)
+	if err != nil {
+		return nil, err
+	}
+	return e.videoMedia(ctx, client, id, config)
+}
+
+// parseVimeoURL returns the video ID and, for unlisted videos, the hash from the URL
+func parseVimeoURL(rawURL string) (id, hash string, err error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid URL: %w", err)
+	}
+
+	if u.Hostname() == "player.vimeo.com" {
+		if m := vimeoPlayerRegex.FindStringSubmatch(u.Path); m != nil {
+			return m[1], u.Query().Get("h"), nil
+		}
+	} else if m := vimeoPageRegex.FindStringSubmatch(u.Path); m != nil {
+		hash = m[2]
+		if hash == "" {
+			hash = u.Query().Get("h")
+		}
+		return m[1], hash, nil
+	}
+	return "", "", fmt.Errorf("could not extract Vimeo video ID from URL")
+}
+
+// vimeoConfig is the player config served for each video
+type vimeoConfig struct {
+	Request struct {
+		Files struct {
+			Progressive []struct {
+				URL     string `json:"url"`
+				Quality string `json:"quality"`
+				Width   int    `json:"width"`
+				Height  int    `json:"height"`
+				FPS     int    `json:"fps"`
+			} `json:"progressive"`
+			HLS vimeoStreams `json:"hls"`
+		} `json:"files"`
+		TextTracks []struct {
+			URL   string `json:"url"`
+			Lang  string `json:"lang"`
+			Label string `json:"label"`
+			Kind  string `json:"kind"`
+		} `json:"text_tracks"`
+	} `json:"request"`
+	Video struct {
+		ID       int64  `json:"id"`
+		Title    string `json:"title"`
+		Duration int    `json:"duration"`
+		Owner    struct {
+			Name string `json:"name"`
+		} `json:"owner"`
+		Thumbs map[string]string `json:"thumbs"`
+	} `json:"video"`
+}
+
+// vimeoStreams lists a streaming protocol's manifests per CDN
+type vimeoStreams struct {
+	DefaultCDN string `json:"default_cdn"`
+	CDNs       map[string]struct {
+		URL    string `json:"url"`
+		AVCURL string `json:"avc_url"` // H.264 only, which every player can decode
+	} `json:"cdns"`
+}
+
+// manifestURL returns the default CDN's manifest, preferring the H.264 one
+func (s vimeoStreams) manifestURL() string {
+	cdn, ok := s.CDNs[s.DefaultCDN]
+	if !ok {
+		names := slices.Sorted(maps.Keys(s.CDNs))
+		if len(names) == 0 {
+			return ""
+		}
+		cdn = s.CDNs[names[0]]
+	}
+	if cdn.AVCURL != "" {
+		return cdn.AVCURL
+	}
+	return cdn.URL
+}
+
+// fetchConfig downloads the player config, turning privacy refusals into readable errors
+func (e *VimeoExtractor) fetchConfig(ctx context.Context, client *http.Client, id, hash, referer string) (*vimeoConfig, error) {
+	configURL := fmt.Sprintf(vimeoConfigURL, id)
+	if hash != "" {
+		configURL += "?h=" + url.QueryEscape(hash)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", configURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", vimeoUserAgent)
+	req.Header.Set("Referer", referer)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch player config: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read player config: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		_ = json.Unmarshal(body, &apiErr)
+
+		switch {
+		case bytes.Contains(body, []byte(vimeoPrivacyText)):
+			return nil, fmt.Errorf("video %s can only be played where it is embedded; pass that page with --referer", id)
+		case resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusNotFound:
+			if hash == "" {
+				return nil, fmt.Errorf("video %s is private or unlisted; use its full share link (vimeo.com/%s/<hash>)", id, id)
+			}
+			return nil, fmt.Errorf("video %s is private or was removed", id)
+		case apiErr.Message != "":
+			return nil, fmt.Errorf("vimeo: %s", apiErr.Message)
+		}
+		return nil, fmt.Errorf("player config request failed with status %d", resp.StatusCode)
+	}
+
+	var config vimeoConfig
+	if err := json.Unmarshal(body, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse player config: %w", err)
+	}
+	return &config, nil
+}
+
+// videoMedia converts the player config to a VideoMedia
+func (e *VimeoExtractor) videoMedia(ctx context.Context, client *http.Client, id string, config *vimeoConfig) (*VideoMedia, error) {
+	media := &VideoMedia{
+		ID:        id,
+		Title:     config.Video.Title,
+		Uploader:  config.Video.Owner.Name,
+		Duration:  config.Video.Duration,
+		Thumbnail: vimeoThumbnail(config.Video.Thumbs),
+	}
+
+	for _, p := range config.Request.Files.Progressive {
+		if p.URL == "" {
+			continue
+		}
+		quality := p.Quality
+		if quality == "" && p.Height > 0 {
+			quality = fmt.Sprintf("%dp", p.Height)
+		}
+		media.Formats = append(media.Formats, VideoFormat{
+			URL:     p.URL,
+			Quality: quality,
+			Ext:     "mp4",
+			Width:   p.Width,
+			Height:  p.Height,
+		})
+	}
+
+	// The DASH manifests are segmented JSON rather than MPDs; HLS carries the same renditions
+	if manifest := config.Request.Files.HLS.manifestURL(); manifest != "" {
+		formats, err := e.hlsFormats(ctx, client, manifest)
+		if err != nil && len(media.Formats) == 0 {
+			return nil, err
+		}
+		media.Formats = append(media.Formats, formats...)
+	}
+
+	if len(media.Formats) == 0 {
+		return nil, fmt.Errorf("no playable formats found for video %s", id)
+	}
+
+	// Tallest first, progressive before HLS at the same height
+	sort.SliceStable(media.Formats, func(i, j int) bool {
+		return media.Formats[i].Height > media.Formats[j].Height
+	})
+
+	base, _ := url.Parse(vimeoPlayerURL)
+	for _, track := range config.Request.TextTracks {
+		if track.URL == "" {
+			continue
+		}
+		trackURL, err := base.Parse(track.URL)
+		if err != nil {
+			continue
+		}
+		ext := strings.TrimPrefix(path.Ext(trackURL.Path), ".")
+		if ext == "" {
+			ext = "vtt"
+		}
+		media.Subtitles = append(media.Subtitles, Subtitle{
+			URL:  trackURL.String(),
+			Lang: track.Lang,
+			Name: track.Label,
+			Ext:  ext,
+		})
+	}
+
+	return media, nil
+}
+
+// hlsFormats lists the variants of an HLS master playlist, each paired with its
+// separate audio rendition
+func (e *VimeoExtractor) hlsFormats(ctx context.Context, client *http.Client, masterURL string) ([]VideoFormat, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", masterURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", vimeoUserAgent)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch HLS playlist: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HLS playlist request failed with status %d", resp.StatusCode)
+	}
+
+	playlist, err := downloader.ParseM3U8Content(resp.Body, masterURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse HLS playlist: %w", err)
+	}
+	if !playlist.IsMaster {
+		return []VideoFormat{{URL: masterURL, Quality: "hls", Ext: "m3u8"}}, nil
+	}
+
+	var formats []VideoFormat
+	for _, v := range playlist.Variants {
+		var width, height int
+		fmt.Sscanf(v.Resolution, "%dx%d", &width, &height)
+
+		f := VideoFormat{
+			URL:     v.URL,
+			Ext:     "m3u8",
+			Width:   width,
+			Height:  height,
+			Bitrate: v.Bandwidth / 1000,
+		}
+		if height > 0 {
+			f.Quality = fmt.Sprintf("%dp", height)
+		}
+		if audio := playlist.AudioRendition(v.AudioGroup); audio != nil {
+			f.AudioURL = audio.URL
+		}
+		formats = append(formats, f)
+	}
+	return formats, nil
+}
+
+// vimeoThumbnail returns the largest thumbnail; keys are widths plus "base"
+func vimeoThumbnail(thumbs map[string]string) string {
+	best, bestWidth := "", -1
+	for key, thumb := range thumbs {
+		var width int
+		if _, err := fmt.Sscanf(key, "%d", &width); err != nil {
+			width = 0
+		}
+		if width > bestWidth {
+			best, bestWidth = thumb, width
+		}
+	}
+	return best
+}
+
+func init() {
+	Register(&VimeoExtractor{},
+		"vimeo.com",
+		"player.vimeo.com",
+	)
+}
@@ -0,0 +1,121 @@
+package extractor
+
+import (
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/guiyumin/vget/internal/testutil/replay"
+)
+
+func newVimeoTestExtractor(t *testing.T) *VimeoExtractor {
+	return &VimeoExtractor{client: replay.Client(t, filepath.Join("testdata", "replay", "vimeo"))}
+}
+
+func TestVimeoMatch(t *testing.T) {
+	tests := []struct {
+		url      string
+		match    bool
+		id, hash string
+	}{
+		{"https://vimeo.com/76979871", true, "76979871", ""},
+		{"https://vimeo.com/123456789/abcdef1234", true, "123456789", "abcdef1234"},
+		{"https://vimeo.com/channels/staffpicks/76979871", true, "76979871", ""},
+		{"https://vimeo.com/showcase/1234/video/76979871", true, "76979871", ""},
+		{"https://player.vimeo.com/video/123456789?h=abcdef1234&badge=0", true, "123456789", "abcdef1234"},
+		{"https://vimeo.com/staff", false, "", ""},
+		{"https://vimeo.com/channels/staffpicks", false, "", ""},
+	}
+	e := &VimeoExtractor{}
+	for _, tt := range tests {
+		u, _ := url.Parse(tt.url)
+		if got := e.Match(u); got != tt.match {
+			t.Errorf("Match(%q) = %v, want %v", tt.url, got, tt.match)
+		}
+		if !tt.match {
+			continue
+		}
+		if id, hash, err := parseVimeoURL(tt.url); err != nil || id != tt.id || hash != tt.hash {
+			t.Errorf("parseVimeoURL(%q) = %q, %q, %v; want %q, %q", tt.url, id, hash, err, tt.id, tt.hash)
+		}
+	}
+}
+
+func TestVimeoFormatsAndSubtitles(t *testing.T) {
+	media, err := newVimeoTestExtractor(t).Extract("https://vimeo.com/76979871")
+	if err != nil {
+		t.Fatalf("Extract: %v", err)
+	}
+	video := media.(*VideoMedia)
+	if video.Title != "The New Vimeo Player (You Know, For Videos)" || video.Uploader != "Vimeo Staff" || video.Duration != 62 {
+		t.Errorf("unexpected metadata: %+v", video)
+	}
+	if video.Thumbnail != "https://i.vimeocdn.com/video/452001751-1280" {
+		t.Errorf("expected the largest thumbnail, got %s", video.Thumbnail)
+	}
+
+	// 2 progressive MP4s and 3 HLS renditions, tallest first
+	if len(video.Formats) != 5 {
+		t.Fatalf("expected 5 formats, got %d", len(video.Formats))
+	}
+	if f := video.Formats[0]; f.Ext != "mp4" || f.Quality != "1080p" || f.AudioURL != "" {
+		t.Errorf("expected the 1080p progressive MP4 first, got %+v", f)
+	}
+	hls := video.Formats[1]
+	if hls.Ext != "m3u8" || hls.Quality != "1080p" || hls.Bitrate != 5214 ||
+		hls.URL != "https://vod-adaptive-ak.vimeocdn.com/exp=1700000000/76979871/video/v1080/playlist.m3u8" {
+		t.Errorf("unexpected HLS format: %+v", hls)
+	}
+	if hls.AudioURL != "https://vod-adaptive-ak.vimeocdn.com/exp=1700000000/76979871/audio/a1/playlist.m3u8" {
+		t.Errorf("expected the high audio rendition, got %s", hls.AudioURL)
+	}
+	if low := video.Formats[4]; low.Height != 360 || !strings.HasSuffix(low.AudioURL, "/audio/a2/playlist.m3u8") {
+		t.Errorf("unexpected 360p format: %+v", low)
+	}
+
+	if len(video.Subtitles) != 2 {
+		t.Fatalf("expected 2 subtitles, got %d", len(video.Subtitles))
+	}
+	sub := video.Subtitles[1]
+	if sub.URL != "https://player.vimeo.com/texttrack/1002.vtt?token=def" || sub.Lang != "pt-BR" || sub.Ext != "vtt" {
+		t.Errorf("unexpected subtitle: %+v", sub)
+	}
+	if got := sub.Path("out/video.mp4"); got != "out/video.pt-BR.vtt" {
+		t.Errorf("Subtitle.Path = %s", got)
+	}
+}
+
+func TestVimeoUnlisted(t *testing.T) {
+	e := newVimeoTestExtractor(t)
+
+	for _, rawURL := range []string{
+		"https://vimeo.com/123456789/abcdef1234",
+		"https://player.vimeo.com/video/123456789?h=abcdef1234",
+	} {
+		media, err := e.Extract(rawURL)
+		if err != nil {
+			t.Fatalf("Extract(%s): %v", rawURL, err)
+		}
+		video := media.(*VideoMedia)
+		if video.Title != "Client review cut" || len(video.Formats) != 1 {
+			t.Fatalf("%s: unexpected media: %+v", rawURL, video)
+		}
+		if f := video.Formats[0]; f.Quality != "720p" || f.AudioURL != "https://skyfire.vimeocdn.com/1700000000-abc/123456789/audio/playlist.m3u8" {
+			t.Errorf("%s: unexpected format: %+v", rawURL, f)
+		}
+	}
+
+	// Without the hash the config is refused
+	_, err := e.Extract("https://vimeo.com/123456789")
+	if err == nil || !strings.Contains(err.Error(), "unlisted") {
+		t.Errorf("expected a hint about the share link, got %v", err)
+	}
+}
+
+func TestVimeoDomainRestricted(t *testing.T) {
+	_, err := newVimeoTestExtractor(t).Extract("https://player.vimeo.com/video/222222222")
+	if err == nil || !strings.Contains(err.Error(), "--referer") {
+		t.Errorf("expected a hint about --referer, got %v", err)
+	}
+}
@@ -0,0 +1,602 @@
+package extractor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	weiboVisitorURL   = "https://passport.weibo.com/visitor/genvisitor2"
+	weiboStatusURL    = "https://weibo.com/ajax/statuses/show"
+	weiboComponentURL = "https://weibo.com/tv/api/component"
+	weiboUserAgent    = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
+)
+
+var (
+	// weibo.com/<uid>/<mid>, where mid is numeric or base62 (e.g., "O8DM0BLLm")
+	weiboPostRegex = regexp.MustCompile(`^/\d+/([A-Za-z0-9]+)/?$`)
+	// m.weibo.cn/detail/<id>, m.weibo.cn/status/<id>, weibo.com/detail/<id>
+	weiboDetailRegex = regexp.MustCompile(`^/(?:detail|status)/([A-Za-z0-9]+)/?$`)
+	// weibo.com/tv/show/1034:<id>
+	weiboTVRegex = regexp.MustCompile(`^/tv/show/(\d+:\w+)`)
+	// video.weibo.com/show?fid=1034:<id>
+	weiboFIDRegex = regexp.MustCompile(`^\d+:\w+$`)
+	// "高清 1080P", "标清 480P"
+	weiboQualityRegex = regexp.MustCompile(`(\d{3,4})[Pp]`)
+	// JSONP wrapper around the visitor response
+	weiboJSONPRegex = regexp.MustCompile(`(?s)\((\{.*\})\)`)
+)
+
+// weiboVisitorTTL is how long a visitor cookie is reused before a new one is requested
+const weiboVisitorTTL = time.Hour
+
+// WeiboExtractor handles Weibo posts (videos, multi-image posts, Live Photos)
+// and video.weibo.com pages. A visitor cookie is requested automatically.
+type WeiboExtractor struct {
+	client *http.Client
+	cookie string // the call's cookie: the user's from Options, or the visitor cookie
+
+	mu             sync.Mutex // guards the visitor cookie shared by calls
+	visitor        string
+	visitorExpires time.Time
+}
+
+func (e *WeiboExtractor) Name() string {
+	return "weibo"
+}
+
+// Info describes the URLs and media this extractor handles
+func (e *WeiboExtractor) Info() Info {
+	return Info{
+		Name:       "weibo",
+		Title:      "Weibo (微博)",
+		Kinds:      []URLKind{URLKindSingle},
+		MediaTypes: []MediaType{MediaTypeVideo, MediaTypeImage},
+	}
+}
+
+func (e *WeiboExtractor) Match(u *url.URL) bool {
+	return weiboTarget(u) != ""
+}
+
+// weiboTarget returns the post ID ("mid") or video object ID ("1034:...") in a URL
+func weiboTarget(u *url.URL) string {
+	if fid := u.Query().Get("fid"); strings.HasPrefix(u.Path, "/show") && weiboFIDRegex.MatchString(fid) {
+		return fid
+	}
+	for _, re := range []*regexp.Regexp{weiboTVRegex, weiboDetailRegex, weiboPostRegex} {
+		if m := re.FindStringSubmatch(u.Path); len(m) > 1 {
+			return m[1]
+		}
+	}
+	return ""
+}
+
+func (e *WeiboExtractor) Extract(rawURL string) (Media, error) {
+	return e.ExtractContext(context.Background(), rawURL, Options{})
+}
+
+func (e *WeiboExtractor) ExtractContext(ctx context.Context, rawURL string, opts Options) (Media, error) {
+	client := e.client
+	if client == nil {
+		client = newHTTPClient(opts, 30*time.Second)
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL: %w", err)
+	}
+	target := weiboTarget(u)
+	if target == "" {
+		return nil, fmt.Errorf("could not extract Weibo post ID from URL")
+	}
+
+	call := &WeiboExtractor{client: client, cookie: opts.Cookie}
+	if call.cookie == "" {
+		if call.cookie, err = e.visitorCookie(ctx, call); err != nil {
+			return nil, fmt.Errorf("failed to get visitor cookie: %w", err)
+		}
+	}
+
+	if weiboFIDRegex.MatchString(target) {
+		return call.extractTV(ctx, target)
+	}
+	return call.extractStatus(ctx, target)
+}
+
+// visitorCookie returns the cached visitor cookie, registering a new visitor
+// session through call when there is none or it has expired
+func (e *WeiboExtractor) visitorCookie(ctx context.Context, call *WeiboExtractor) (string, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.visitor != "" && time.Now().Before(e.visitorExpires) {
+		return e.visitor, nil
+	}
+	cookie, err := call.fetchVisitorCookie(ctx)
+	if err != nil {
+		return "", err
+	}
+	e.visitor = cookie
+	e.visitorExpires = time.Now().Add(weiboVisitorTTL)
+	return cookie, nil
+}
+
+// fetchVisitorCookie registers a visitor session, which the ajax APIs require
+func (e *WeiboExtractor) fetchVisitorCookie(ctx context.Context) (string, error) {
+	form := url.Values{}
+	form.Set("cb", "visitor_gray_callback")
+	form.Set("tid", "")
+	form.Set("from", "weibo")
+	form.Set("webdriver", "false")
+
+	req, err := http.NewRequestWithContext(ctx, "POST", weiboVisitorURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("User-Agent", weiboUserAgent)
+	req.Header.Set("Referer", "https://passport.weibo.com/visitor/visitor")
+
+	body, err := e.do(req)
+	if err != nil {
+		return "", err
+	}
+
+	m := weiboJSONPRegex.FindSubmatch(body)
+	if m == nil {
+		return "", fmt.Errorf("unexpected visitor response")
+	}
+	var result struct {
+		Retcode int    `json:"retcode"`
+		Msg     string `json:"msg"`
+		Data    struct {
+			Sub  string `json:"sub"`
+			Subp string `json:"subp"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(m[1], &result); err != nil {
+		return "", fmt.Errorf("failed to parse visitor response: %w", err)
+	}
+	if result.Data.Sub == "" {
+		return "", fmt.Errorf("visitor request failed: %s (retcode: %d)", result.Msg, result.Retcode)
+	}
+
+	return "SUB=" + result.Data.Sub + "; SUBP=" + result.Data.Subp, nil
+}
+
+// do sends an API request and returns the body, failing on non-200 and non-JSON
+// (login redirect) responses
+func (e *WeiboExtractor) do(req *http.Request) ([]byte, error) {
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("request failed with status %d", resp.StatusCode)
+	}
+	return body, nil
+}
+
+// apiRequest builds a request to a weibo.com ajax API with the session cookie
+func (e *WeiboExtractor) apiRequest(ctx context.Context, method, apiURL string, body io.Reader, referer string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, apiURL, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", weiboUserAgent)
+	req.Header.Set("Referer", referer)
+	req.Header.Set("Accept", "application/json, text/plain, */*")
+	req.Header.Set("X-Requested-With", "XMLHttpRequest")
+	req.Header.Set("Cookie", e.cookie)
+	return req, nil
+}
+
+// extractStatus fetches a post and converts its video, images and Live Photos
+func (e *WeiboExtractor) extractStatus(ctx context.Context, mid string) (Media, error) {
+	params := url.Values{}
+	params.Set("id", mid)
+	params.Set("locale", "zh-CN")
+
+	req, err := e.apiRequest(ctx, "GET", weiboStatusURL+"?"+params.Encode(), nil, "https://weibo.com/")
+	if err != nil {
+		return nil, err
+	}
+	body, err := e.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch post: %w", err)
+	}
+
+	var status weiboStatus
+	if err := json.Unmarshal(body, &status); err != nil {
+		return nil, fmt.Errorf("failed to parse post (visitor cookie may have been rejected): %w", err)
+	}
+	if status.Ok != nil && *status.Ok != 1 {
+		msg := status.Message
+		if msg == "" {
+			msg = "post not found or not accessible"
+		}
+		return nil, fmt.Errorf("%s", msg)
+	}
+
+	return status.toMedia()
+}
+
+// weiboStatus is a post from /ajax/statuses/show
+type weiboStatus struct {
+	Ok      *int   `json:"ok"` // only present on errors
+	Message string `json:"message"`
+	IDStr   string `json:"idstr"`
+	MblogID string `json:"mblogid"`
+	TextRaw string `json:"text_raw"`
+	User    *struct {
+		ScreenName string `json:"screen_name"`
+	} `json:"user"`
+	PicIDs       []string                `json:"pic_ids"`
+	PicInfos     map[string]weiboPicInfo `json:"pic_infos"`
+	PageInfo     *weiboPageInfo          `json:"page_info"`
+	MixMediaInfo *struct {
+		Items []struct {
+			Type string          `json:"type"` // "pic" or "video"
+			Data json.RawMessage `json:"data"`
+		} `json:"items"`
+	} `json:"mix_media_info"`
+	Retweeted *weiboStatus `json:"retweeted_status"`
+}
+
+type weiboPicInfo struct {
+	Type     string       `json:"type"` // "pic", "gif" or "livephoto"
+	Largest  weiboPicSize `json:"largest"`
+	Original weiboPicSize `json:"original"`
+	Large    weiboPicSize `json:"large"`
+	Video    string       `json:"video"` // Live Photo / GIF motion part
+}
+
+type weiboPicSize struct {
+	URL    string `json:"url"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+}
+
+type weiboPageInfo struct {
+	ObjectType string          `json:"object_type"`
+	MediaInfo  *weiboMediaInfo `json:"media_info"`
+	PagePic    struct {
+		URL string `json:"url"`
+	} `json:"page_pic"`
+}
+
+type weiboMediaInfo struct {
+	Name         string  `json:"name"`
+	NextTitle    string  `json:"next_title"`
+	Duration     float64 `json:"duration"`
+	StreamURL    string  `json:"stream_url"`
+	StreamURLHD  string  `json:"stream_url_hd"`
+	MP4SDURL     string  `json:"mp4_sd_url"`
+	MP4HDURL     string  `json:"mp4_hd_url"`
+	MP4720PURL   string  `json:"mp4_720p_mp4"`
+	PlaybackList []struct {
+		Meta struct {
+			Label        string `json:"label"`
+			QualityLabel string `json:"quality_label"`
+		} `json:"meta"`
+		PlayInfo struct {
+			URL     string `json:"url"`
+			Width   int    `json:"width"`
+			Height  int    `json:"height"`
+			Bitrate int    `json:"bitrate"`
+		} `json:"play_info"`
+	} `json:"playback_list"`
+}
+
+// toMedia converts a post into VideoMedia, ImageMedia or (for mixed posts) MultiVideoMedia.
+// Reposts without media of their own use the original post's media.
+func (s *weiboStatus) toMedia() (Media, error) {
+	id := s.MblogID
+	if id == "" {
+		id = s.IDStr
+	}
+	title := truncateText(s.TextRaw, 100)
+	var uploader string
+	if s.User != nil {
+		uploader = s.User.ScreenName
+	}
+
+	videos, images := s.collect()
+	if len(videos) == 0 && len(images) == 0 && s.Retweeted != nil {
+		videos, images = s.Retweeted.collect()
+	}
+
+	for i, v := range videos {
+		v.ID = id
+		if len(videos) > 1 {
+			v.ID = fmt.Sprintf("%s_%d", id, i+1)
+		}
+		v.Uploader = uploader
+		if v.Title == "" {
+			v.Title = title
+		}
+	}
+
+	switch {
+	case len(videos) == 1 && len(images) == 0:
+		return videos[0], nil
+	case len(videos) > 0:
+		return &MultiVideoMedia{ID: id, Title: title, Uploader: uploader, Videos: videos, Images: images}, nil
+	case len(images) > 0:
+		return &ImageMedia{ID: id, Title: title, Uploader: uploader, Images: images}, nil
+	}
+	return nil, fmt.Errorf("no media found in post")
+}
+
+// collect returns the videos and images attached directly to a post
+func (s *weiboStatus) collect() ([]*VideoMedia, []Image) {
+	var videos []*VideoMedia
+	var images []Image
+
+	if s.MixMediaInfo != nil {
+		for _, item := range s.MixMediaInfo.Items {
+			switch item.Type {
+			case "pic":
+				var pic weiboPicInfo
+				if json.Unmarshal(item.Data, &pic) == nil {
+					if img, ok := pic.toImage(); ok {
+						images = append(images, img)
+					}
+				}
+			case "video":
+				var page weiboPageInfo
+				if json.Unmarshal(item.Data, &page) == nil {
+					if v := page.toVideo(); v != nil {
+						videos = append(videos, v)
+					}
+				}
+			}
+		}
+		return videos, images
+	}
+
+	for _, pid := range s.PicIDs {
+		if img, ok := s.PicInfos[pid].toImage(); ok {
+			images = append(images, img)
+		}
+	}
+	if s.PageInfo != nil {
+		if v := s.PageInfo.toVideo(); v != nil {
+			videos = append(videos, v)
+		}
+	}
+	return videos, images
+}
+
+// toImage returns the original-resolution image, paired with its motion video for Live Photos
+func (p weiboPicInfo) toImage() (Image, bool) {
+	size := p.Largest
+	if size.URL == "" {
+		size = p.Original
+	}
+	if size.URL == "" {
+		size = p.Large
+	}
+	if size.URL == "" {
+		return Image{}, false
+	}
+
+	img := Image{
+		URL:     weiboLargeURL(size.URL),
+		Ext:     weiboExt(size.URL, "jpg"),
+		Width:   size.Width,
+		Height:  size.Height,
+		Headers: weiboMediaHeaders(),
+	}
+	if p.Type == "livephoto" && p.Video != "" {
+		img.LiveVideoURL = p.Video
+		img.LiveVideoExt = weiboExt(p.Video, "mov")
+	}
+	return img, true
+}
+
+// weiboMediaHeaders returns the headers sinaimg.cn and weibocdn.com need to serve media
+func weiboMediaHeaders() map[string]string {
+	return map[string]string{
+		"Referer":    "https://weibo.com/",
+		"User-Agent": weiboUserAgent,
+	}
+}
+
+// weiboLargeURL rewrites a sinaimg.cn URL to the uncompressed "large" size
+func weiboLargeURL(imageURL string) string {
+	u, err := url.Parse(imageURL)
+	if err != nil || !strings.HasSuffix(u.Hostname(), "sinaimg.cn") {
+		return imageURL
+	}
+	parts := strings.SplitN(strings.TrimPrefix(u.Path, "/"), "/", 2)
+	if len(parts) == 2 {
+		u.Path = "/large/" + parts[1]
+	}
+	return u.String()
+}
+
+// weiboExt returns the extension of a media URL's path, or def
+func weiboExt(mediaURL, def string) string {
+	u, err := url.Parse(mediaURL)
+	if err != nil {
+		return def
+	}
+	if ext := strings.TrimPrefix(strings.ToLower(path.Ext(u.Path)), "."); ext != "" {
+		return ext
+	}
+	// Live Photo videos are served from /livephoto/...?livephoto=<url-encoded .mov URL>
+	if inner := u.Query().Get("livephoto"); inner != "" {
+		return weiboExt(inner, def)
+	}
+	return def
+}
+
+// toVideo returns the video of a post's page_info with every quality variant
+func (p *weiboPageInfo) toVideo() *VideoMedia {
+	if p.ObjectType != "video" || p.MediaInfo == nil {
+		return nil
+	}
+	info := p.MediaInfo
+
+	headers := weiboMediaHeaders()
+	var formats []VideoFormat
+	seen := map[string]bool{}
+	add := func(f VideoFormat) {
+		if f.URL == "" || seen[f.URL] {
+			return
+		}
+		seen[f.URL] = true
+		f.Ext = "mp4"
+		f.Headers = headers
+		formats = append(formats, f)
+	}
+
+	for _, pb := range info.PlaybackList {
+		quality := strings.ToLower(pb.Meta.QualityLabel)
+		if quality == "" {
+			quality = pb.Meta.Label
+		}
+		add(VideoFormat{
+			URL:     pb.PlayInfo.URL,
+			Quality: quality,
+			Width:   pb.PlayInfo.Width,
+			Height:  pb.PlayInfo.Height,
+			Bitrate: pb.PlayInfo.Bitrate,
+		})
+	}
+
+	// Older posts only have the fixed-quality fields
+	for _, f := range []VideoFormat{
+		{URL: info.MP4720PURL, Quality: "720p", Height: 720},
+		{URL: info.MP4HDURL, Quality: "hd"},
+		{URL: info.StreamURLHD, Quality: "hd"},
+		{URL: info.MP4SDURL, Quality: "sd"},
+		{URL: info.StreamURL, Quality: "sd"},
+	} {
+		add(f)
+	}
+	if len(formats) == 0 {
+		return nil
+	}
+
+	sort.SliceStable(formats, func(i, j int) bool {
+		if formats[i].Height != formats[j].Height {
+			return formats[i].Height > formats[j].Height
+		}
+		return formats[i].Bitrate > formats[j].Bitrate
+	})
+
+	title := info.NextTitle
+	if title == "" {
+		title = info.Name
+	}
+	return &VideoMedia{
+		Title:     title,
+		Duration:  int(info.Duration),
+		Thumbnail: p.PagePic.URL,
+		Formats:   formats,
+	}
+}
+
+// extractTV fetches a video.weibo.com / weibo.com/tv video by object ID (e.g., "1034:4938...")
+func (e *WeiboExtractor) extractTV(ctx context.Context, oid string) (Media, error) {
+	page := "/tv/show/" + oid
+	payload, _ := json.Marshal(map[string]any{
+		"Component_Play_Playinfo": map[string]string{"oid": oid},
+	})
+	form := url.Values{}
+	form.Set("data", string(payload))
+
+	apiURL := weiboComponentURL + "?page=" + url.QueryEscape(page)
+	req, err := e.apiRequest(ctx, "POST", apiURL, strings.NewReader(form.Encode()), "https://weibo.com"+page)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	body, err := e.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch video: %w", err)
+	}
+
+	var result struct {
+		Code string `json:"code"`
+		Msg  string `json:"msg"`
+		Data struct {
+			PlayInfo *struct {
+				Title        string            `json:"title"`
+				Author       string            `json:"author"`
+				CoverImage   string            `json:"cover_image"`
+				DurationTime float64           `json:"duration_time"`
+				URLs         map[string]string `json:"urls"`
+			} `json:"Component_Play_Playinfo"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse video info: %w", err)
+	}
+	info := result.Data.PlayInfo
+	if info == nil || len(info.URLs) == 0 {
+		msg := result.Msg
+		if msg == "" {
+			msg = "video not found or not accessible"
+		}
+		return nil, fmt.Errorf("%s", msg)
+	}
+
+	headers := weiboMediaHeaders()
+	var formats []VideoFormat
+	for label, u := range info.URLs {
+		if strings.HasPrefix(u, "//") {
+			u = "https:" + u
+		}
+		f := VideoFormat{URL: u, Quality: label, Ext: "mp4", Headers: headers}
+		if m := weiboQualityRegex.FindStringSubmatch(label); len(m) > 1 {
+			f.Height, _ = strconv.Atoi(m[1])
+			f.Quality = m[1] + "p"
+		}
+		formats = append(formats, f)
+	}
+	sort.Slice(formats, func(i, j int) bool {
+		if formats[i].Height != formats[j].Height {
+			return formats[i].Height > formats[j].Height
+		}
+		return formats[i].Quality < formats[j].Quality
+	})
+
+	return &VideoMedia{
+		ID:        oid,
+		Title:     info.Title,
+		Uploader:  info.Author,
+		Duration:  int(info.DurationTime),
+		Thumbnail: info.CoverImage,
+		Formats:   formats,
+	}, nil
+}
+
+func init() {
+	Register(&WeiboExtractor{},
+		"weibo.com",
+		"m.weibo.cn",
+		"video.weibo.com",
+	)
+}
@@ -0,0 +1,146 @@
+package extractor
+
+import (
+	"context"
+	"net/url"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/guiyumin/vget/internal/testutil/replay"
+)
+
+func TestWeiboMatch(t *testing.T) {
+	tests := map[string]string{
+		"https://weibo.com/1234567890/O8DM0BLLm":                   "O8DM0BLLm",
+		"https://m.weibo.cn/detail/4938000000000001":               "4938000000000001",
+		"https://m.weibo.cn/status/O8DM0BLLm":                      "O8DM0BLLm",
+		"https://video.weibo.com/show?fid=1034:4938000000000002":   "1034:4938000000000002",
+		"https://weibo.com/tv/show/1034:4938000000000002?from=old": "1034:4938000000000002",
+		"https://weibo.com/u/1234567890":                           "",
+		"https://weibo.com/hot/search":                             "",
+	}
+	for rawURL, want := range tests {
+		u, _ := url.Parse(rawURL)
+		if got := weiboTarget(u); got != want {
+			t.Errorf("weiboTarget(%q) = %q, want %q", rawURL, got, want)
+		}
+	}
+}
+
+func TestWeiboImagesAndLivePhoto(t *testing.T) {
+	e := &WeiboExtractor{client: replay.Client(t, filepath.Join("testdata", "replay", "weibo"))}
+
+	media, err := e.Extract("https://weibo.com/1234567890/O8DM0BLLm")
+	if err != nil {
+		t.Fatalf("Extract: %v", err)
+	}
+	if e.visitor != "SUB=_2AkMQvisitorSUB; SUBP=0033WrSXqPxfM72-Ws9jqgMF55529P9D9WvisitorSUBP" {
+		t.Errorf("visitor cookie not cached: %q", e.visitor)
+	}
+
+	images, ok := media.(*ImageMedia)
+	if !ok {
+		t.Fatalf("expected *ImageMedia, got %T", media)
+	}
+	if images.ID != "O8DM0BLLm" || images.Uploader != "微博测试" || len(images.Images) != 2 {
+		t.Fatalf("unexpected media: %+v", images)
+	}
+
+	first := images.Images[0]
+	if first.URL != "https://wx1.sinaimg.cn/large/006abcly1.jpg" || first.Width != 3024 || first.LiveVideoURL != "" {
+		t.Errorf("unexpected image: %+v", first)
+	}
+	if first.Headers["Referer"] != "https://weibo.com/" {
+		t.Errorf("missing Referer: %v", first.Headers)
+	}
+
+	live := images.Images[1]
+	if live.URL != "https://wx3.sinaimg.cn/large/006defly1.jpg" {
+		t.Errorf("expected large URL, got %s", live.URL)
+	}
+	if live.LiveVideoExt != "mov" || live.LiveVideoURL == "" {
+		t.Errorf("expected live photo video, got %q (%s)", live.LiveVideoURL, live.LiveVideoExt)
+	}
+	if got := live.LiveVideoPath("/tmp/post_2.jpg"); got != "/tmp/post_2.mov" {
+		t.Errorf("LiveVideoPath = %s", got)
+	}
+}
+
+func TestWeiboVideo(t *testing.T) {
+	e := &WeiboExtractor{client: replay.Client(t, filepath.Join("testdata", "replay", "weibo"))}
+
+	media, err := e.Extract("https://m.weibo.cn/detail/4938000000000001")
+	if err != nil {
+		t.Fatalf("Extract: %v", err)
+	}
+	video, ok := media.(*VideoMedia)
+	if !ok {
+		t.Fatalf("expected *VideoMedia, got %T", media)
+	}
+	if video.ID != "O8DM0VIDE" || video.Title != "新歌 MV 首发" || video.Duration != 215 {
+		t.Errorf("unexpected metadata: %+v", video)
+	}
+
+	// 3 playback variants plus the two legacy URLs not already listed
+	if len(video.Formats) != 5 {
+		t.Fatalf("expected 5 formats, got %d: %+v", len(video.Formats), video.Formats)
+	}
+	if f := video.Formats[0]; f.Quality != "1080p" || f.Height != 1080 {
+		t.Errorf("expected 1080p first, got %+v", f)
+	}
+}
+
+func TestWeiboTV(t *testing.T) {
+	e := &WeiboExtractor{client: replay.Client(t, filepath.Join("testdata", "replay", "weibo"))}
+
+	media, err := e.Extract("https://video.weibo.com/show?fid=1034:4938000000000002")
+	if err != nil {
+		t.Fatalf("Extract: %v", err)
+	}
+	video, ok := media.(*VideoMedia)
+	if !ok {
+		t.Fatalf("expected *VideoMedia, got %T", media)
+	}
+	if video.Title != "纪录片预告" || video.Uploader != "央视频" || video.Duration != 95 {
+		t.Errorf("unexpected metadata: %+v", video)
+	}
+	if len(video.Formats) != 3 || video.Formats[0].Quality != "1080p" {
+		t.Fatalf("unexpected formats: %+v", video.Formats)
+	}
+	if video.Formats[0].URL != "https://f.video.weibocdn.com/o0/tv1080.mp4?label=mp4_1080p" {
+		t.Errorf("protocol-relative URL not resolved: %s", video.Formats[0].URL)
+	}
+}
+
+func TestWeiboUnavailable(t *testing.T) {
+	e := &WeiboExtractor{client: replay.Client(t, filepath.Join("testdata", "replay", "weibo"))}
+
+	_, err := e.ExtractContext(context.Background(), "https://weibo.com/1234567890/O8DMmissing", Options{Cookie: "SUB=user"})
+	if err == nil || err.Error() != "暂无查看权限" {
+		t.Errorf("expected API message as error, got %v", err)
+	}
+	if e.visitor != "" || e.cookie != "" {
+		t.Errorf("user cookie kept on the extractor: visitor=%q cookie=%q", e.visitor, e.cookie)
+	}
+}
+
+func TestWeiboVisitorCookieExpires(t *testing.T) {
+	e := &WeiboExtractor{client: replay.Client(t, filepath.Join("testdata", "replay", "weibo"))}
+	e.visitor = "SUB=stale"
+	e.visitorExpires = time.Now().Add(-time.Minute)
+
+	if _, err := e.Extract("https://weibo.com/1234567890/O8DM0BLLm"); err != nil {
+		t.Fatalf("Extract: %v", err)
+	}
+	if e.visitor == "SUB=stale" || !e.visitorExpires.After(time.Now()) {
+		t.Errorf("expired visitor cookie not renewed: %q until %v", e.visitor, e.visitorExpires)
+	}
+
+	// A cached cookie is reused until it expires
+	e.visitor = "SUB=cached"
+	call := &WeiboExtractor{}
+	if cookie, err := e.visitorCookie(context.Background(), call); err != nil || cookie != "SUB=cached" {
+		t.Errorf("visitorCookie() = %q, %v, want the cached cookie", cookie, err)
+	}
+}
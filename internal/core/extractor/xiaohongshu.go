@@ -1,8 +1,10 @@
 package extractor
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"math/rand/v2"
 	"net/url"
 	"os"
 	"path/filepath"
@@ -12,29 +14,64 @@ import (
 
 	"github.com/go-rod/rod"
 	"github.com/go-rod/rod/lib/launcher"
-	"github.com/go-rod/rod/lib/proto"
 	"github.com/go-rod/stealth"
 	"github.com/guiyumin/vget/internal/core/config"
 )
 
 // XiaohongshuExtractor handles Xiaohongshu video/image downloads using browser automation
-type XiaohongshuExtractor struct {
-	visible bool
-}
-
-// SetVisible configures whether to show the browser window
-func (e *XiaohongshuExtractor) SetVisible(visible bool) {
-	e.visible = visible
-}
+type XiaohongshuExtractor struct{}
 
 func (e *XiaohongshuExtractor) Name() string {
 	return "xiaohongshu"
 }
 
+// Info describes the URLs and media this extractor handles
+func (e *XiaohongshuExtractor) Info() Info {
+	return Info{
+		Name:       "xiaohongshu",
+		Title:      "Xiaohongshu (小红书)",
+		Kinds:      []URLKind{URLKindSingle, URLKindUser, URLKindPlaylist},
+		MediaTypes: []MediaType{MediaTypeVideo, MediaTypeImage},
+		Requires: []Requirement{
+			{Kind: RequiresBrowser, Detail: "notes are read from the rendered page"},
+			{Kind: RequiresLogin, Optional: true, Detail: "log in once with vget --visible <url>; profiles and boards reuse the saved session"},
+		},
+	}
+}
+
 func (e *XiaohongshuExtractor) Match(u *url.URL) bool {
 	return true
 }
 
+// xhsImageCDN serves images as uploaded, without the resizing, WebP conversion and
+// watermark applied to urlDefault
+const xhsImageCDN = "https://sns-img-bd.xhscdn.com/"
+
+// xhsStream lists the encodings of a video stream
+type xhsStream struct {
+	H264 []struct {
+		MasterURL string `json:"masterUrl"`
+	} `json:"h264"`
+	H265 []struct {
+		MasterURL string `json:"masterUrl"`
+	} `json:"h265"`
+}
+
+// url returns the H.264 stream if there is one, as it plays everywhere
+func (s xhsStream) url() string {
+	for _, h := range s.H264 {
+		if h.MasterURL != "" {
+			return h.MasterURL
+		}
+	}
+	for _, h := range s.H265 {
+		if h.MasterURL != "" {
+			return h.MasterURL
+		}
+	}
+	return ""
+}
+
 // xhsNoteDetail represents the note detail from __INITIAL_STATE__
 type xhsNoteDetail struct {
 	Note struct {
@@ -47,9 +84,12 @@ type xhsNoteDetail struct {
 			UserID   string `json:"userId"`
 		} `json:"user"`
 		ImageList []struct {
-			URLDefault string `json:"urlDefault"`
-			Width      int    `json:"width"`
-			Height     int    `json:"height"`
+			URLDefault string    `json:"urlDefault"`
+			TraceID    string    `json:"traceId"`
+			Width      int       `json:"width"`
+			Height     int       `json:"height"`
+			LivePhoto  bool      `json:"livePhoto"`
+			Stream     xhsStream `json:"stream"` // motion part of a Live Photo
 		} `json:"imageList"`
 		Video struct {
 			Media struct {
@@ -63,32 +103,137 @@ type xhsNoteDetail struct {
 	} `json:"note"`
 }
 
+// Profile and board URLs; their notes are opened with the listing's xsec_token
+var (
+	xhsProfileRegex = regexp.MustCompile(`^/user/profile/([a-zA-Z0-9]+)/?$`)
+	xhsBoardRegex   = regexp.MustCompile(`^/board/([a-zA-Z0-9]+)/?$`)
+)
+
+// Collection kinds
+const (
+	xhsKindUser  = "user"
+	xhsKindBoard = "board"
+)
+
+// Waits and pacing. Profile and board downloads open one note at a time with a
+// randomized pause in between, so a backup doesn't trip the anti-bot checks.
+const (
+	xhsLoginWait      = 120 * time.Second // first page of a session, which may need a QR login
+	xhsNoteWait       = 15 * time.Second  // later notes, once the session is logged in
+	xhsNoteDelay      = 3 * time.Second   // pause between notes, plus up to xhsNoteJitter
+	xhsNoteJitter     = 3 * time.Second
+	xhsScrollDelay    = 2 * time.Second // pause after each scroll for the next page to load
+	xhsMaxIdleScrolls = 3               // scrolls without new notes before the list is complete
+)
+
+// xhsNoteStateScript reads the note detail map from __INITIAL_STATE__
+const xhsNoteStateScript = `() => {
+	if (window.__INITIAL_STATE__ &&
+	    window.__INITIAL_STATE__.note &&
+	    window.__INITIAL_STATE__.note.noteDetailMap) {
+		const noteDetailMap = window.__INITIAL_STATE__.note.noteDetailMap;
+		return JSON.stringify(noteDetailMap);
+	}
+	return "";
+}`
+
+// xhsListingScript reads the notes loaded so far on a profile or board page as an xhsListing
+const xhsListingScript = `(kind, id) => {
+	const state = window.__INITIAL_STATE__;
+	if (!state) return "";
+
+	// Store fields are Vue refs
+	const raw = (v) => v && typeof v === "object" && ("_rawValue" in v || "_value" in v) ? (v._rawValue ?? v._value) : v;
+	const ref = (item) => {
+		const card = item.noteCard || item;
+		return {
+			id: item.id || card.noteId || "",
+			xsecToken: item.xsecToken || card.xsecToken || "",
+			title: card.displayTitle || card.title || "",
+		};
+	};
+
+	if (kind === "user") {
+		const user = raw(state.user);
+		const tabs = user && raw(user.notes);
+		if (!tabs || !Array.isArray(tabs[0])) return "";
+		const queries = raw(user.noteQueries) || [];
+		const basic = (raw(user.userPageData) || {}).basicInfo || {};
+		return JSON.stringify({
+			title: basic.nickname || document.title,
+			uploader: basic.nickname || "",
+			hasMore: !queries[0] || queries[0].hasMore !== false,
+			notes: tabs[0].map(ref),
+		});
+	}
+
+	const board = raw(state.board);
+	const feed = board && (raw(board.boardFeedsMap) || {})[id];
+	if (!feed || !Array.isArray(feed.notes)) return "";
+	const detail = (raw(board.boardDetails) || {})[id] || {};
+	return JSON.stringify({
+		title: detail.name || document.title,
+		uploader: (detail.user && detail.user.nickname) || "",
+		hasMore: feed.hasMore !== false,
+		notes: feed.notes.map(ref),
+	});
+}`
+
+// xhsNoteRef is a note listed on a profile or board, with the token needed to open it
+type xhsNoteRef struct {
+	ID        string `json:"id"`
+	XsecToken string `json:"xsecToken"`
+	Title     string `json:"title"`
+}
+
+// xhsListing is what xhsListingScript reads from a profile or board page
+type xhsListing struct {
+	Title    string       `json:"title"`
+	Uploader string       `json:"uploader"`
+	HasMore  bool         `json:"hasMore"`
+	Notes    []xhsNoteRef `json:"notes"`
+}
+
 func (e *XiaohongshuExtractor) Extract(rawURL string) (Media, error) {
+	return e.ExtractContext(context.Background(), rawURL, Options{})
+}
+
+// ExtractContext extracts a note, or every note on a user profile or board.
+// The browser stops as soon as ctx is done.
+func (e *XiaohongshuExtractor) ExtractContext(ctx context.Context, rawURL string, opts Options) (Media, error) {
 	// Resolve short URL if needed
 	finalURL := rawURL
 	if strings.Contains(rawURL, "xhslink.com") {
-		resolved, err := e.resolveShortURL(rawURL)
+		resolved, err := e.resolveShortURL(rawURL, opts.Proxy)
 		if err != nil {
 			return nil, fmt.Errorf("failed to resolve short URL: %w", err)
 		}
 		finalURL = resolved
 	}
 
+	if kind, id := xhsCollection(finalURL); kind != "" {
+		return e.extractWithBrowser(ctx, opts, func(page *rod.Page) (Media, error) {
+			return e.extractCollection(ctx, page, finalURL, kind, id)
+		})
+	}
+
 	// Extract note ID from URL
 	noteID := e.extractNoteID(finalURL)
 	if noteID == "" {
 		return nil, fmt.Errorf("could not extract note ID from URL: %s", finalURL)
 	}
 
-	// Launch browser and extract data
-	return e.extractWithBrowser(finalURL, noteID)
+	return e.extractWithBrowser(ctx, opts, func(page *rod.Page) (Media, error) {
+		return e.extractNote(page, finalURL, noteID, xhsLoginWait)
+	})
 }
 
 func (e *XiaohongshuExtractor) extractNoteID(rawURL string) string {
-	// Pattern: /explore/{noteId} or /discovery/item/{noteId}
+	// Pattern: /explore/{noteId}, /discovery/item/{noteId} or /user/profile/{userId}/{noteId}
 	patterns := []string{
 		`/explore/([a-zA-Z0-9]+)`,
 		`/discovery/item/([a-zA-Z0-9]+)`,
+		`/user/profile/[a-zA-Z0-9]+/([a-zA-Z0-9]+)`,
 	}
 
 	for _, pattern := range patterns {
@@ -101,9 +246,24 @@ func (e *XiaohongshuExtractor) extractNoteID(rawURL string) string {
 	return ""
 }
 
-func (e *XiaohongshuExtractor) resolveShortURL(shortURL string) (string, error) {
+// xhsCollection returns the kind ("user" or "board") and ID of a profile or board URL
+func xhsCollection(rawURL string) (kind, id string) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", ""
+	}
+	if m := xhsProfileRegex.FindStringSubmatch(u.Path); m != nil {
+		return xhsKindUser, m[1]
+	}
+	if m := xhsBoardRegex.FindStringSubmatch(u.Path); m != nil {
+		return xhsKindBoard, m[1]
+	}
+	return "", ""
+}
+
+func (e *XiaohongshuExtractor) resolveShortURL(shortURL, proxy string) (string, error) {
 	// Use browser to follow redirect
-	l := e.createLauncher(true) // headless for redirect resolution
+	l := e.createLauncher(true, proxy) // headless for redirect resolution
 	defer l.Cleanup()
 
 	u := l.MustLaunch()
@@ -134,9 +294,11 @@ func (e *XiaohongshuExtractor) resolveShortURL(shortURL string) (string, error)
 	return finalURL, nil
 }
 
-func (e *XiaohongshuExtractor) extractWithBrowser(targetURL, noteID string) (Media, error) {
+// extractWithBrowser launches the browser with the saved session, runs fn on a
+// stealth page and saves the session's cookies for next time
+func (e *XiaohongshuExtractor) extractWithBrowser(ctx context.Context, opts Options, fn func(page *rod.Page) (Media, error)) (Media, error) {
 	// Launch browser (headless by default, visible with --visible flag)
-	l := e.createLauncher(!e.visible)
+	l := e.createLauncher(!opts.Visible, opts.Proxy)
 	defer l.Cleanup()
 
 	fmt.Println("Launching browser...")
@@ -148,75 +310,101 @@ func (e *XiaohongshuExtractor) extractWithBrowser(targetURL, noteID string) (Med
 	fmt.Printf("Browser launched, connecting to: %s\n", u)
 
 	browser := rod.New().ControlURL(u).MustConnect()
-	defer browser.MustClose()
+	defer browser.Close()
 	fmt.Println("Connected to browser")
 
 	// Load cookies if available
 	e.loadCookies(browser)
 
-	page := stealth.MustPage(browser)
-	defer page.MustClose()
+	// Page operations fail once ctx is done
+	page := stealth.MustPage(browser).Context(ctx)
 	fmt.Println("Created stealth page")
 
-	fmt.Printf("Navigating to: %s\n", targetURL)
+	// Must* page calls panic on failure; rod.Try turns that into an error
+	var media Media
+	var extractErr error
+	if err := rod.Try(func() {
+		media, extractErr = fn(page)
+	}); err != nil {
+		extractErr = err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 
-	// Navigate to the page
-	page.MustNavigate(targetURL)
-	fmt.Println("Waiting for page to stabilize...")
-	page.MustWaitDOMStable()
-	time.Sleep(2 * time.Second) // Extra wait for JS rendering
-	fmt.Println("Page loaded, checking for data...")
+	// Save cookies for future sessions
+	e.saveCookies(browser)
 
-	// Wait for login if needed (up to 120 seconds)
-	var result string
-	maxWait := 120 * time.Second
+	if extractErr != nil {
+		return nil, extractErr
+	}
+	return media, nil
+}
+
+// waitForState polls read until it returns page data. With the login wait, a QR
+// login prompt is shown while waiting.
+func (e *XiaohongshuExtractor) waitForState(page *rod.Page, read func() string, maxWait time.Duration) (string, error) {
 	checkInterval := 2 * time.Second
 	startTime := time.Now()
+	prompted := false
 
 	for {
-		// Try to extract __INITIAL_STATE__
-		result = page.MustEval(`() => {
-			if (window.__INITIAL_STATE__ &&
-			    window.__INITIAL_STATE__.note &&
-			    window.__INITIAL_STATE__.note.noteDetailMap) {
-				const noteDetailMap = window.__INITIAL_STATE__.note.noteDetailMap;
-				return JSON.stringify(noteDetailMap);
+		if result := read(); result != "" {
+			if prompted {
+				fmt.Println() // newline after progress
 			}
-			return "";
-		}`).String()
-
-		if result != "" {
-			fmt.Println("Data extracted successfully!")
-			break
+			return result, nil
 		}
 
 		elapsed := time.Since(startTime)
 		if elapsed >= maxWait {
-			return nil, fmt.Errorf("timeout waiting for note data (login may be required)")
+			if maxWait < xhsLoginWait {
+				return "", fmt.Errorf("timeout waiting for page data")
+			}
+			return "", fmt.Errorf("timeout waiting for page data (login may be required)")
 		}
 
-		// Check if this is the first iteration - show login prompt
-		if elapsed < checkInterval*2 {
-			fmt.Println("\n┌────────────────────────────────────────────────────┐")
-			fmt.Println("│  Login required! Please scan the QR code in the   │")
-			fmt.Println("│  browser window to log in to Xiaohongshu.         │")
-			fmt.Println("│                                                    │")
-			fmt.Println("│  Waiting up to 2 minutes for login...             │")
-			fmt.Println("└────────────────────────────────────────────────────┘")
-		}
+		if maxWait >= xhsLoginWait {
+			// Check if this is the first iteration - show login prompt
+			if !prompted {
+				fmt.Println("\n┌────────────────────────────────────────────────────┐")
+				fmt.Println("│  Login required! Please scan the QR code in the   │")
+				fmt.Println("│  browser window to log in to Xiaohongshu.         │")
+				fmt.Println("│                                                    │")
+				fmt.Println("│  Waiting up to 2 minutes for login...             │")
+				fmt.Println("└────────────────────────────────────────────────────┘")
+				prompted = true
+			}
 
-		remaining := maxWait - elapsed
-		fmt.Printf("\rWaiting for login... %d seconds remaining", int(remaining.Seconds()))
+			remaining := maxWait - elapsed
+			fmt.Printf("\rWaiting for login... %d seconds remaining", int(remaining.Seconds()))
+		}
 
 		time.Sleep(checkInterval)
 
 		// Refresh the page state after waiting
 		page.MustWaitDOMStable()
 	}
-	fmt.Println() // newline after progress
+}
 
-	// Save cookies for future sessions
-	e.saveCookies(browser)
+// navigate opens targetURL and waits for it to render
+func (e *XiaohongshuExtractor) navigate(page *rod.Page, targetURL string) {
+	fmt.Printf("Navigating to: %s\n", targetURL)
+	page.MustNavigate(targetURL)
+	page.MustWaitDOMStable()
+	time.Sleep(2 * time.Second) // Extra wait for JS rendering
+}
+
+// extractNote opens a note page and converts its __INITIAL_STATE__ into media
+func (e *XiaohongshuExtractor) extractNote(page *rod.Page, targetURL, noteID string, maxWait time.Duration) (Media, error) {
+	e.navigate(page, targetURL)
+
+	result, err := e.waitForState(page, func() string {
+		return page.MustEval(xhsNoteStateScript).String()
+	}, maxWait)
+	if err != nil {
+		return nil, fmt.Errorf("note %s: %w", noteID, err)
+	}
 
 	// Parse the response
 	var noteDetailMap map[string]xhsNoteDetail
@@ -224,19 +412,11 @@ func (e *XiaohongshuExtractor) extractWithBrowser(targetURL, noteID string) (Med
 		return nil, fmt.Errorf("failed to parse note data: %w", err)
 	}
 
-	// Debug: print available keys
-	fmt.Printf("Looking for noteID: %s\n", noteID)
-	fmt.Printf("Available keys in noteDetailMap:\n")
-	for key := range noteDetailMap {
-		fmt.Printf("  - %s\n", key)
-	}
-
 	noteDetail, exists := noteDetailMap[noteID]
 	if !exists {
 		// Try to find any key that contains the noteID
 		for key, detail := range noteDetailMap {
 			if strings.Contains(key, noteID) {
-				fmt.Printf("Found matching key: %s\n", key)
 				noteDetail = detail
 				exists = true
 				break
@@ -246,12 +426,9 @@ func (e *XiaohongshuExtractor) extractWithBrowser(targetURL, noteID string) (Med
 
 	// If still not found, just use the first entry if there's only one
 	if !exists && len(noteDetailMap) == 1 {
-		for key, detail := range noteDetailMap {
-			fmt.Printf("Using single available key: %s\n", key)
+		for _, detail := range noteDetailMap {
 			noteDetail = detail
 			exists = true
-			_ = key
-			break
 		}
 	}
 
@@ -277,6 +454,151 @@ func (e *XiaohongshuExtractor) extractWithBrowser(targetURL, noteID string) (Med
 	return e.extractImages(note.NoteID, title, note.User.Nickname, noteDetail)
 }
 
+// extractCollection scrolls a profile or board until every note is listed, then
+// opens the notes one by one with their xsec_token, pausing between them
+func (e *XiaohongshuExtractor) extractCollection(ctx context.Context, page *rod.Page, targetURL, kind, id string) (Media, error) {
+	e.navigate(page, targetURL)
+
+	read := func() string {
+		return page.MustEval(xhsListingScript, kind, id).String()
+	}
+	result, err := e.waitForState(page, read, xhsLoginWait)
+	if err != nil {
+		return nil, err
+	}
+
+	listing, err := parseXhsListing(result)
+	if err != nil {
+		return nil, err
+	}
+	refs := mergeXhsNoteRefs(nil, listing.Notes)
+
+	// Each scroll loads the next page of notes into the store
+	for idle := 0; listing.HasMore && idle < xhsMaxIdleScrolls; {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		page.MustEval(`() => window.scrollTo(0, document.body.scrollHeight)`)
+		time.Sleep(xhsScrollDelay)
+
+		next, err := parseXhsListing(read())
+		if err != nil {
+			idle++
+			continue
+		}
+		listing = next
+
+		n := len(refs)
+		refs = mergeXhsNoteRefs(refs, next.Notes)
+		if len(refs) == n {
+			idle++
+		} else {
+			idle = 0
+		}
+		fmt.Printf("\rFound %d notes...", len(refs))
+	}
+	fmt.Println()
+
+	if len(refs) == 0 {
+		return nil, fmt.Errorf("no notes found on %s", targetURL)
+	}
+
+	source := "pc_user"
+	if kind == xhsKindBoard {
+		source = "pc_collect"
+	}
+
+	title := listing.Title
+	if title == "" {
+		title = id
+	}
+	collection := &CollectionMedia{ID: id, Title: title, Uploader: listing.Uploader}
+
+	var failed int
+	for i, ref := range refs {
+		if i > 0 {
+			if err := xhsPause(ctx); err != nil {
+				return nil, err
+			}
+		}
+
+		fmt.Printf("[%d/%d] %s\n", i+1, len(refs), ref.Title)
+		var media Media
+		var noteErr error
+		if err := rod.Try(func() {
+			media, noteErr = e.extractNote(page, xhsNoteURL(ref, source), ref.ID, xhsNoteWait)
+		}); err != nil {
+			noteErr = err
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if noteErr != nil {
+			fmt.Printf("Warning: skipping note %s: %v\n", ref.ID, noteErr)
+			failed++
+			continue
+		}
+		collection.Items = append(collection.Items, media)
+	}
+
+	if len(collection.Items) == 0 {
+		return nil, fmt.Errorf("failed to extract any of the %d notes", len(refs))
+	}
+	if failed > 0 {
+		fmt.Printf("Extracted %d of %d notes\n", len(collection.Items), len(refs))
+	}
+	return collection, nil
+}
+
+// parseXhsListing parses the JSON returned by xhsListingScript
+func parseXhsListing(result string) (*xhsListing, error) {
+	if result == "" {
+		return nil, fmt.Errorf("note list not loaded")
+	}
+	var listing xhsListing
+	if err := json.Unmarshal([]byte(result), &listing); err != nil {
+		return nil, fmt.Errorf("failed to parse note list: %w", err)
+	}
+	return &listing, nil
+}
+
+// mergeXhsNoteRefs appends the notes in more that aren't in refs yet, keeping page order
+func mergeXhsNoteRefs(refs, more []xhsNoteRef) []xhsNoteRef {
+	seen := make(map[string]bool, len(refs))
+	for _, ref := range refs {
+		seen[ref.ID] = true
+	}
+	for _, ref := range more {
+		if ref.ID == "" || seen[ref.ID] {
+			continue
+		}
+		seen[ref.ID] = true
+		refs = append(refs, ref)
+	}
+	return refs
+}
+
+// xhsNoteURL is a listed note's page URL. It carries the listing's xsec_token,
+// without which notes opened outside the listing are refused.
+func xhsNoteURL(ref xhsNoteRef, source string) string {
+	noteURL := "https://www.xiaohongshu.com/explore/" + url.PathEscape(ref.ID)
+	if ref.XsecToken == "" {
+		return noteURL
+	}
+	query := url.Values{"xsec_token": {ref.XsecToken}, "xsec_source": {source}}
+	return noteURL + "?" + query.Encode()
+}
+
+// xhsPause waits between notes, randomized so requests don't arrive at a fixed rate
+func xhsPause(ctx context.Context) error {
+	select {
+	case <-time.After(xhsNoteDelay + rand.N(xhsNoteJitter)):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 func (e *XiaohongshuExtractor) extractVideo(id, title, uploader string, detail xhsNoteDetail) (Media, error) {
 	var videoURL string
 
@@ -304,11 +626,7 @@ func (e *XiaohongshuExtractor) extractVideo(id, title, uploader string, detail x
 				URL:     videoURL,
 				Quality: "best",
 				Ext:     "mp4",
-				Headers: map[string]string{
-					"Referer":    "https://www.xiaohongshu.com/",
-					"Origin":     "https://www.xiaohongshu.com",
-					"User-Agent": "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
-				},
+				Headers: xhsMediaHeaders(),
 			},
 		},
 	}, nil
@@ -333,12 +651,33 @@ func (e *XiaohongshuExtractor) extractImages(id, title, uploader string, detail
 			ext = "webp"
 		}
 
-		images = append(images, Image{
-			URL:    imgURL,
-			Ext:    ext,
-			Width:  img.Width,
-			Height: img.Height,
-		})
+		// Prefer the original upload, named by its trace ID. Most uploads are
+		// JPEG; other formats are renamed by their first bytes once downloaded.
+		if token := xhsImageToken(img.TraceID, imgURL); token != "" {
+			imgURL = xhsImageCDN + token
+			ext = "jpg"
+		}
+
+		image := Image{
+			URL:     imgURL,
+			Ext:     ext,
+			Width:   img.Width,
+			Height:  img.Height,
+			Headers: xhsMediaHeaders(),
+		}
+
+		// Live Photos: the motion part is an MP4 stream
+		if img.LivePhoto {
+			if videoURL := img.Stream.url(); videoURL != "" {
+				if strings.HasPrefix(videoURL, "//") {
+					videoURL = "https:" + videoURL
+				}
+				image.LiveVideoURL = videoURL
+				image.LiveVideoExt = "mp4"
+			}
+		}
+
+		images = append(images, image)
 	}
 
 	return &ImageMedia{
@@ -349,7 +688,35 @@ func (e *XiaohongshuExtractor) extractImages(id, title, uploader string, detail
 	}, nil
 }
 
-func (e *XiaohongshuExtractor) createLauncher(headless bool) *launcher.Launcher {
+// xhsImageToken returns the trace ID naming an image upload, either given directly
+// or taken from a CDN URL of the form https://sns-webpic-qc.xhscdn.com/{time}/{signature}/{token}!{style}
+func xhsImageToken(traceID, imgURL string) string {
+	if traceID != "" {
+		return traceID
+	}
+	u, err := url.Parse(imgURL)
+	if err != nil || !strings.HasSuffix(u.Hostname(), "xhscdn.com") {
+		return ""
+	}
+	parts := strings.SplitN(strings.TrimPrefix(u.Path, "/"), "/", 3)
+	if len(parts) < 3 {
+		return ""
+	}
+	token, _, _ := strings.Cut(parts[2], "!")
+	return token
+}
+
+// xhsMediaHeaders are the headers the Xiaohongshu CDN expects for images and videos
+func xhsMediaHeaders() map[string]string {
+	return map[string]string{
+		"Referer":    "https://www.xiaohongshu.com/",
+		"Origin":     "https://www.xiaohongshu.com",
+		"User-Agent": "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+	}
+}
+
+// createLauncher returns a launcher for the browser, through proxy if set
+func (e *XiaohongshuExtractor) createLauncher(headless bool, proxy string) *launcher.Launcher {
 	// Use Rod's auto-downloaded Chromium with persistent user data
 	// This keeps login state between runs
 	userDataDir := e.getUserDataDir()
@@ -377,6 +744,9 @@ func (e *XiaohongshuExtractor) createLauncher(headless bool) *launcher.Launcher
 	if browserPath != "" {
 		l = l.Bin(browserPath)
 	}
+	if proxy != "" {
+		l = l.Proxy(proxy)
+	}
 
 	return l
 }
@@ -394,67 +764,31 @@ func (e *XiaohongshuExtractor) getUserDataDir() string {
 }
 
 func (e *XiaohongshuExtractor) loadCookies(browser *rod.Browser) {
-	// Try to load cookies from ~/.config/vget/xhs_cookies.json
-	configDir, err := config.ConfigDir()
-	if err != nil {
-		return
-	}
-
-	cookiePath := filepath.Join(configDir, "xhs_cookies.json")
-	data, err := os.ReadFile(cookiePath)
-	if err != nil {
+	// Load cookies from the shared store (~/.config/vget/xhs_cookies.json)
+	cookies := loadSiteCookies("xhs")
+	if len(cookies) == 0 {
 		return // No cookies file, that's fine
 	}
 
-	var cookies []*proto.NetworkCookie
-	if err := json.Unmarshal(data, &cookies); err != nil {
-		return
-	}
-
 	fmt.Println("Loaded saved cookies from previous session")
 	browser.MustSetCookies(cookies...)
 }
 
 func (e *XiaohongshuExtractor) saveCookies(browser *rod.Browser) {
-	// Save cookies to ~/.config/vget/xhs_cookies.json
-	configDir, err := config.ConfigDir()
-	if err != nil {
-		return
-	}
-
-	// Ensure directory exists
-	if err := os.MkdirAll(configDir, 0755); err != nil {
-		return
-	}
-
 	cookies, err := browser.GetCookies()
 	if err != nil {
 		return
 	}
 
-	// Filter to only XHS-related cookies
-	var xhsCookies []*proto.NetworkCookie
-	for _, c := range cookies {
-		if strings.Contains(c.Domain, "xiaohongshu") || strings.Contains(c.Domain, "xhscdn") {
-			xhsCookies = append(xhsCookies, c)
-		}
-	}
-
-	if len(xhsCookies) == 0 {
-		return
-	}
-
-	data, err := json.MarshalIndent(xhsCookies, "", "  ")
+	// Only keep XHS-related cookies
+	n, err := saveSiteCookies("xhs", cookies, "xiaohongshu", "xhscdn")
 	if err != nil {
-		return
-	}
-
-	cookiePath := filepath.Join(configDir, "xhs_cookies.json")
-	if err := os.WriteFile(cookiePath, data, 0600); err != nil {
 		fmt.Printf("Warning: failed to save cookies: %v\n", err)
 		return
 	}
-	fmt.Printf("Saved %d cookies for future sessions\n", len(xhsCookies))
+	if n > 0 {
+		fmt.Printf("Saved %d cookies for future sessions\n", n)
+	}
 }
 
 func init() {
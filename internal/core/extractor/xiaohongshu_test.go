@@ -0,0 +1,127 @@
+package extractor
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestXhsCollection(t *testing.T) {
+	tests := []struct {
+		url      string
+		kind, id string
+	}{
+		{"https://www.xiaohongshu.com/user/profile/5f1a2b3c000000000101d8e1", xhsKindUser, "5f1a2b3c000000000101d8e1"},
+		{"https://www.xiaohongshu.com/user/profile/5f1a2b3c000000000101d8e1?xsec_token=ABC&xsec_source=pc_note", xhsKindUser, "5f1a2b3c000000000101d8e1"},
+		{"https://www.xiaohongshu.com/board/64b0c1d2000000001e00a1b2", xhsKindBoard, "64b0c1d2000000001e00a1b2"},
+		// A note opened from a profile is still a single note
+		{"https://www.xiaohongshu.com/user/profile/5f1a2b3c000000000101d8e1/6650a1b2000000001e03c4d5", "", ""},
+		{"https://www.xiaohongshu.com/explore/6650a1b2000000001e03c4d5", "", ""},
+	}
+	for _, tt := range tests {
+		kind, id := xhsCollection(tt.url)
+		if kind != tt.kind || id != tt.id {
+			t.Errorf("xhsCollection(%q) = %q, %q; want %q, %q", tt.url, kind, id, tt.kind, tt.id)
+		}
+	}
+
+	e := &XiaohongshuExtractor{}
+	if got := e.extractNoteID("https://www.xiaohongshu.com/user/profile/5f1a2b3c000000000101d8e1/6650a1b2000000001e03c4d5?xsec_token=ABC"); got != "6650a1b2000000001e03c4d5" {
+		t.Errorf("extractNoteID = %q", got)
+	}
+}
+
+func TestXhsListingPagination(t *testing.T) {
+	first, err := parseXhsListing(`{"title":"小红薯","uploader":"小红薯","hasMore":true,"notes":[
+		{"id":"n1","xsecToken":"tok1","title":"第一篇"},
+		{"id":"n2","xsecToken":"tok2","title":"第二篇"}]}`)
+	if err != nil {
+		t.Fatalf("parseXhsListing: %v", err)
+	}
+	refs := mergeXhsNoteRefs(nil, first.Notes)
+
+	// The store keeps earlier pages, so a scroll returns them again with the new notes
+	next, err := parseXhsListing(`{"hasMore":false,"notes":[
+		{"id":"n1","xsecToken":"tok1"},{"id":"n2","xsecToken":"tok2"},
+		{"id":"n3","xsecToken":"tok3","title":"第三篇"},{"id":"","xsecToken":"x"}]}`)
+	if err != nil {
+		t.Fatalf("parseXhsListing: %v", err)
+	}
+	refs = mergeXhsNoteRefs(refs, next.Notes)
+
+	if len(refs) != 3 || refs[0].ID != "n1" || refs[2].ID != "n3" || refs[2].XsecToken != "tok3" {
+		t.Errorf("unexpected refs: %+v", refs)
+	}
+
+	if _, err := parseXhsListing(""); err == nil {
+		t.Error("expected an error for an unloaded list")
+	}
+}
+
+func TestXhsNoteURL(t *testing.T) {
+	ref := xhsNoteRef{ID: "6650a1b2000000001e03c4d5", XsecToken: "ABx+/y="}
+	want := "https://www.xiaohongshu.com/explore/6650a1b2000000001e03c4d5?xsec_source=pc_user&xsec_token=ABx%2B%2Fy%3D"
+	if got := xhsNoteURL(ref, "pc_user"); got != want {
+		t.Errorf("xhsNoteURL = %s, want %s", got, want)
+	}
+
+	ref.XsecToken = ""
+	if got := xhsNoteURL(ref, "pc_user"); got != "https://www.xiaohongshu.com/explore/6650a1b2000000001e03c4d5" {
+		t.Errorf("xhsNoteURL without token = %s", got)
+	}
+}
+
+func TestXhsPauseCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	start := time.Now()
+	if err := xhsPause(ctx); err != context.Canceled {
+		t.Errorf("xhsPause = %v, want context.Canceled", err)
+	}
+	if time.Since(start) >= xhsNoteDelay {
+		t.Error("xhsPause did not return when cancelled")
+	}
+}
+
+func TestXhsImagesOriginalAndLivePhoto(t *testing.T) {
+	var detail xhsNoteDetail
+	err := json.Unmarshal([]byte(`{"note":{"noteId":"6650a1b2000000001e03c4d5","type":"normal","imageList":[
+		{"urlDefault":"http://sns-webpic-qc.xhscdn.com/202406011200/0d1c2b3a/1040g2sg31abcdef!nd_dft_wlteh_webp_3","width":1080,"height":1440},
+		{"urlDefault":"//sns-webpic-qc.xhscdn.com/202406011200/0d1c2b3a/spectrum/1040g0k031live!nd_dft_wlteh_webp_3","width":1080,"height":1440,
+		 "livePhoto":true,"stream":{"h264":[{"masterUrl":"http://sns-video-bd.xhscdn.com/stream/live.mp4"}],"h265":[{"masterUrl":"http://sns-video-bd.xhscdn.com/stream/live_265.mp4"}]}},
+		{"urlDefault":"https://sns-webpic-qc.xhscdn.com/202406011200/0d1c2b3a/ignored!nd_dft_wlteh_webp_3","traceId":"1040g2sg31trace","width":800,"height":600},
+		{"urlDefault":"https://example.com/image.webp","width":100,"height":100}]}}`), &detail)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	media, err := (&XiaohongshuExtractor{}).extractImages("6650a1b2000000001e03c4d5", "title", "", detail)
+	if err != nil {
+		t.Fatalf("extractImages: %v", err)
+	}
+	images := media.(*ImageMedia).Images
+	if len(images) != 4 {
+		t.Fatalf("expected 4 images, got %d", len(images))
+	}
+
+	want := []struct{ url, ext, live string }{
+		{"https://sns-img-bd.xhscdn.com/1040g2sg31abcdef", "jpg", ""},
+		{"https://sns-img-bd.xhscdn.com/spectrum/1040g0k031live", "jpg", "http://sns-video-bd.xhscdn.com/stream/live.mp4"},
+		{"https://sns-img-bd.xhscdn.com/1040g2sg31trace", "jpg", ""},
+		{"https://example.com/image.webp", "webp", ""},
+	}
+	for i, w := range want {
+		img := images[i]
+		if img.URL != w.url || img.Ext != w.ext || img.LiveVideoURL != w.live {
+			t.Errorf("image %d = %s (%s) live %q; want %s (%s) live %q", i, img.URL, img.Ext, img.LiveVideoURL, w.url, w.ext, w.live)
+		}
+		if img.Headers["Referer"] != "https://www.xiaohongshu.com/" {
+			t.Errorf("image %d missing Referer", i)
+		}
+	}
+	if images[1].LiveVideoExt != "mp4" {
+		t.Errorf("LiveVideoExt = %q", images[1].LiveVideoExt)
+	}
+}
@@ -1,89 +1,145 @@
 package extractor
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"html"
 	"io"
 	"net/http"
 	"net/url"
 	"regexp"
+	"sort"
 	"strings"
+	"time"
 )
 
 // XiaoyuzhouExtractor handles xiaoyuzhoufm.com podcast downloads
-type XiaoyuzhouExtractor struct{}
+type XiaoyuzhouExtractor struct {
+	client *http.Client // tests inject a replay client; nil uses a fresh one per call
+}
 
 func (e *XiaoyuzhouExtractor) Name() string {
 	return "xiaoyuzhou"
 }
 
+// Info describes the URLs and media this extractor handles
+func (e *XiaoyuzhouExtractor) Info() Info {
+	return Info{
+		Name:       "xiaoyuzhou",
+		Title:      "Xiaoyuzhou FM (小宇宙)",
+		Kinds:      []URLKind{URLKindSingle, URLKindPlaylist},
+		MediaTypes: []MediaType{MediaTypeAudio},
+	}
+}
+
 func (e *XiaoyuzhouExtractor) Match(u *url.URL) bool {
 	// Host matching is done by registry, check path pattern
 	return strings.HasPrefix(u.Path, "/episode/") || strings.HasPrefix(u.Path, "/podcast/")
 }
 
 func (e *XiaoyuzhouExtractor) Extract(url string) (Media, error) {
+	return e.ExtractContext(context.Background(), url, Options{})
+}
+
+// ExtractContext retrieves a Xiaoyuzhou episode or podcast
+func (e *XiaoyuzhouExtractor) ExtractContext(ctx context.Context, url string, opts Options) (Media, error) {
+	client := e.client
+	if client == nil {
+		client = newHTTPClient(opts, 30*time.Second)
+	}
+
 	if strings.Contains(url, "/episode/") {
-		return e.extractEpisode(url)
+		return extractXiaoyuzhouEpisode(ctx, client, url)
 	}
 	if strings.Contains(url, "/podcast/") {
-		return e.extractPodcast(url)
+		return extractXiaoyuzhouPodcast(ctx, client, url)
 	}
 	return nil, fmt.Errorf("unsupported URL format")
 }
 
-// extractEpisode extracts a single episode
-func (e *XiaoyuzhouExtractor) extractEpisode(url string) (*AudioMedia, error) {
-	// Extract episode ID from URL
-	re := regexp.MustCompile(`/episode/([a-zA-Z0-9]+)`)
-	matches := re.FindStringSubmatch(url)
-	if len(matches) < 2 {
-		return nil, fmt.Errorf("could not extract episode ID from URL")
-	}
-	episodeID := matches[1]
+// xyzEpisode is an episode as embedded in __NEXT_DATA__
+type xyzEpisode struct {
+	Eid         string `json:"eid"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Shownotes   string `json:"shownotes"` // HTML
+	Duration    int    `json:"duration"`
+	PubDate     string `json:"pubDate"` // RFC 3339
+	Enclosure   struct {
+		URL string `json:"url"`
+	} `json:"enclosure"`
+	Image *xyzImage `json:"image"`
+}
 
-	// Fetch the episode page to get JSON data
-	resp, err := http.Get(url)
-	if err != nil {
-		return nil, err
+type xyzImage struct {
+	PicURL      string `json:"picUrl"`
+	LargePicURL string `json:"largePicUrl"`
+}
+
+func (i *xyzImage) url() string {
+	if i == nil {
+		return ""
 	}
-	defer resp.Body.Close()
+	if i.LargePicURL != "" {
+		return i.LargePicURL
+	}
+	return i.PicURL
+}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
+// toAudio converts an episode into AudioMedia
+func (ep *xyzEpisode) toAudio(title, podcastTitle string) *AudioMedia {
+	// Determine file extension
+	ext := "m4a"
+	if strings.Contains(ep.Enclosure.URL, ".mp3") {
+		ext = "mp3"
 	}
 
-	// Parse the embedded JSON data from the page
-	// Look for the script tag with __NEXT_DATA__
-	content := string(body)
-	jsonStart := strings.Index(content, `<script id="__NEXT_DATA__" type="application/json">`)
-	if jsonStart == -1 {
-		return nil, fmt.Errorf("could not find episode data in page")
+	publishedAt, _ := time.Parse(time.RFC3339, ep.PubDate)
+
+	notes := ep.Description
+	if ep.Shownotes != "" {
+		notes = stripHTML(ep.Shownotes)
 	}
 
-	jsonStart = strings.Index(content[jsonStart:], ">") + jsonStart + 1
-	jsonEnd := strings.Index(content[jsonStart:], "</script>") + jsonStart
+	return &AudioMedia{
+		ID:          ep.Eid,
+		Title:       title,
+		Uploader:    podcastTitle,
+		Duration:    ep.Duration,
+		URL:         ep.Enclosure.URL,
+		Ext:         ext,
+		PublishedAt: publishedAt,
+		Description: notes,
+		CoverURL:    ep.Image.url(),
+	}
+}
 
-	if jsonEnd <= jsonStart {
-		return nil, fmt.Errorf("could not parse episode data")
+// extractXiaoyuzhouEpisode extracts a single episode
+func extractXiaoyuzhouEpisode(ctx context.Context, client *http.Client, url string) (*AudioMedia, error) {
+	// Extract episode ID from URL
+	re := regexp.MustCompile(`/episode/([a-zA-Z0-9]+)`)
+	matches := re.FindStringSubmatch(url)
+	if len(matches) < 2 {
+		return nil, fmt.Errorf("could not extract episode ID from URL")
 	}
+	episodeID := matches[1]
 
-	jsonData := content[jsonStart:jsonEnd]
+	jsonData, err := fetchXiaoyuzhouNextData(ctx, client, url)
+	if err != nil {
+		return nil, fmt.Errorf("could not find episode data in page: %w", err)
+	}
 
 	// Parse the JSON
 	var pageData struct {
 		Props struct {
 			PageProps struct {
 				Episode struct {
-					Eid       string `json:"eid"`
-					Title     string `json:"title"`
-					Duration  int    `json:"duration"`
-					Enclosure struct {
-						URL string `json:"url"`
-					} `json:"enclosure"`
+					xyzEpisode
 					Podcast struct {
-						Title string `json:"title"`
+						Title string    `json:"title"`
+						Image *xyzImage `json:"image"`
 					} `json:"podcast"`
 				} `json:"episode"`
 			} `json:"pageProps"`
@@ -99,32 +155,208 @@ func (e *XiaoyuzhouExtractor) extractEpisode(url string) (*AudioMedia, error) {
 		return nil, fmt.Errorf("no audio URL found for episode")
 	}
 
-	// Determine file extension
-	ext := "m4a"
-	if strings.Contains(episode.Enclosure.URL, ".mp3") {
-		ext = "mp3"
-	}
-
 	// Create filename: {podcast} - {title}
 	filename := SanitizeFilename(fmt.Sprintf("%s - %s", episode.Podcast.Title, episode.Title))
 
-	return &AudioMedia{
-		ID:       episodeID,
-		Title:    filename,
-		Uploader: episode.Podcast.Title,
-		Duration: episode.Duration,
-		URL:      episode.Enclosure.URL,
-		Ext:      ext,
-	}, nil
+	media := episode.toAudio(filename, episode.Podcast.Title)
+	media.ID = episodeID
+	if media.CoverURL == "" {
+		media.CoverURL = episode.Podcast.Image.url()
+	}
+	return media, nil
 }
 
-// extractPodcast lists all episodes from a podcast
-func (e *XiaoyuzhouExtractor) extractPodcast(_ string) (*AudioMedia, error) {
-	// For now, return an error suggesting to use search
-	// Full podcast download can be implemented later
-	return nil, fmt.Errorf("podcast download not yet implemented. Use 'vget search --podcast <name>' to find specific episodes")
+// extractXiaoyuzhouPodcast expands a podcast page into its episodes
+func extractXiaoyuzhouPodcast(ctx context.Context, client *http.Client, url string) (*PodcastMedia, error) {
+	re := regexp.MustCompile(`/podcast/([a-zA-Z0-9]+)`)
+	matches := re.FindStringSubmatch(url)
+	if len(matches) < 2 {
+		return nil, fmt.Errorf("could not extract podcast ID from URL")
+	}
+	media, warnings, err := fetchXiaoyuzhouPodcast(ctx, client, matches[1], xiaoyuzhouMaxPages)
+	for _, w := range warnings {
+		fmt.Printf("Warning: %v\n", w)
+	}
+	return media, err
 }
 
+// FetchXiaoyuzhouPodcast fetches a podcast and its episodes, newest first.
+// The podcast page only embeds the most recent episodes, so the rest are paged
+// from the episode list API, at most maxPages pages of them (0 pages back to
+// the first episode). A failure to list the older episodes is returned as a
+// warning, since the embedded ones are still usable.
+func FetchXiaoyuzhouPodcast(ctx context.Context, podcastID string, maxPages int) (*PodcastMedia, []error, error) {
+	if maxPages <= 0 {
+		maxPages = xiaoyuzhouMaxPages
+	}
+	return fetchXiaoyuzhouPodcast(ctx, newHTTPClient(Options{}, 30*time.Second), podcastID, maxPages)
+}
+
+func fetchXiaoyuzhouPodcast(ctx context.Context, client *http.Client, podcastID string, maxPages int) (media *PodcastMedia, warnings []error, err error) {
+	pageURL := fmt.Sprintf("https://www.xiaoyuzhoufm.com/podcast/%s", podcastID)
+
+	jsonData, err := fetchXiaoyuzhouNextData(ctx, client, pageURL)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not find episode data on page: %w", err)
+	}
+
+	var nextData struct {
+		Props struct {
+			PageProps struct {
+				Podcast struct {
+					Pid         string       `json:"pid"`
+					Title       string       `json:"title"`
+					Author      string       `json:"author"`
+					Description string       `json:"description"`
+					Image       *xyzImage    `json:"image"`
+					Episodes    []xyzEpisode `json:"episodes"`
+				} `json:"podcast"`
+			} `json:"pageProps"`
+		} `json:"props"`
+	}
+
+	if err := json.Unmarshal([]byte(jsonData), &nextData); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse episode data: %v", err)
+	}
+
+	podcast := nextData.Props.PageProps.Podcast
+	if len(podcast.Episodes) == 0 {
+		return nil, nil, fmt.Errorf("no episodes found")
+	}
+
+	media = &PodcastMedia{
+		ID:          podcastID,
+		Title:       podcast.Title,
+		Uploader:    podcast.Author,
+		Description: podcast.Description,
+		CoverURL:    podcast.Image.url(),
+	}
+
+	episodes := podcast.Episodes
+	older, err := fetchXiaoyuzhouEpisodeList(ctx, client, podcastID, maxPages)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, nil, ctx.Err()
+		}
+		warnings = append(warnings, fmt.Errorf("failed to list older episodes: %w", err))
+	}
+	episodes = append(episodes, older...)
+
+	seen := make(map[string]bool)
+	for i := range episodes {
+		ep := &episodes[i]
+		if ep.Enclosure.URL == "" || seen[ep.Eid] {
+			continue
+		}
+		seen[ep.Eid] = true
+		media.Episodes = append(media.Episodes, ep.toAudio(ep.Title, podcast.Title))
+	}
+
+	// Newest first
+	sort.SliceStable(media.Episodes, func(i, j int) bool {
+		return media.Episodes[i].PublishedAt.After(media.Episodes[j].PublishedAt)
+	})
+
+	return media, warnings, nil
+}
+
+const (
+	xiaoyuzhouEpisodeListURL = "https://api.xiaoyuzhoufm.com/v1/episode/list"
+	xiaoyuzhouPageSize       = 25
+	xiaoyuzhouMaxPages       = 200 // guards against a cursor that never ends
+)
+
+// fetchXiaoyuzhouEpisodeList pages through a podcast's episodes, newest first,
+// until the API has no more to load or maxPages pages have been read
+func fetchXiaoyuzhouEpisodeList(ctx context.Context, client *http.Client, podcastID string, maxPages int) ([]xyzEpisode, error) {
+	var episodes []xyzEpisode
+	var loadMoreKey json.RawMessage
+	for page := 0; page < maxPages; page++ {
+		payload, err := json.Marshal(map[string]any{
+			"pid":         podcastID,
+			"order":       "desc",
+			"limit":       xiaoyuzhouPageSize,
+			"loadMoreKey": loadMoreKey,
+		})
+		if err != nil {
+			return episodes, err
+		}
+
+		req, err := http.NewRequestWithContext(ctx, "POST", xiaoyuzhouEpisodeListURL, bytes.NewReader(payload))
+		if err != nil {
+			return episodes, err
+		}
+		req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36")
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Origin", "https://www.xiaoyuzhoufm.com")
+		req.Header.Set("Referer", "https://www.xiaoyuzhoufm.com/")
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return episodes, err
+		}
+		var result struct {
+			Data        []xyzEpisode    `json:"data"`
+			LoadMoreKey json.RawMessage `json:"loadMoreKey"`
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return episodes, fmt.Errorf("episode list returned status %d", resp.StatusCode)
+		}
+		err = json.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if err != nil {
+			return episodes, fmt.Errorf("failed to parse episode list: %w", err)
+		}
+
+		episodes = append(episodes, result.Data...)
+		if len(result.Data) == 0 || len(result.LoadMoreKey) == 0 || string(result.LoadMoreKey) == "null" {
+			break
+		}
+		loadMoreKey = result.LoadMoreKey
+	}
+	return episodes, nil
+}
+
+// fetchXiaoyuzhouNextData fetches a page and returns its __NEXT_DATA__ JSON
+func fetchXiaoyuzhouNextData(ctx context.Context, client *http.Client, pageURL string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", pageURL, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	// Look for the script tag with __NEXT_DATA__
+	jsonData := extractScriptJSON(string(body), "__NEXT_DATA__")
+	if jsonData == "" {
+		return "", fmt.Errorf("__NEXT_DATA__ not found")
+	}
+	return jsonData, nil
+}
+
+var (
+	htmlBreakRegex = regexp.MustCompile(`(?i)<br\s*/?>|</p>|</li>|</h\d>`)
+	htmlTagRegex   = regexp.MustCompile(`<[^>]+>`)
+	blankLineRegex = regexp.MustCompile(`\n{3,}`)
+)
+
+// stripHTML converts show notes HTML into plain text, keeping paragraph breaks
+func stripHTML(s string) string {
+	s = htmlBreakRegex.ReplaceAllString(s, "\n")
+	s = htmlTagRegex.ReplaceAllString(s, "")
+	s = html.UnescapeString(s)
+	s = blankLineRegex.ReplaceAllString(s, "\n\n")
+	return strings.TrimSpace(s)
+}
 
 func init() {
 	Register(&XiaoyuzhouExtractor{},
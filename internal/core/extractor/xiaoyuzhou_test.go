@@ -0,0 +1,127 @@
+package extractor
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/guiyumin/vget/internal/testutil/replay"
+)
+
+func TestXiaoyuzhouEpisode(t *testing.T) {
+	e := &XiaoyuzhouExtractor{client: replay.Client(t, filepath.Join("testdata", "replay", "xiaoyuzhou"))}
+
+	media, err := e.Extract("https://www.xiaoyuzhoufm.com/episode/65a1b2c3d4e5f60718293a4b")
+	if err != nil {
+		t.Fatalf("Extract: %v", err)
+	}
+	audio, ok := media.(*AudioMedia)
+	if !ok {
+		t.Fatalf("expected *AudioMedia, got %T", media)
+	}
+	if audio.ID != "65a1b2c3d4e5f60718293a4b" || audio.Title != SanitizeFilename("测试播客 - 第42期：离线测试") || audio.Uploader != "测试播客" {
+		t.Errorf("unexpected metadata: %+v", audio)
+	}
+	if audio.Ext != "m4a" || audio.Duration != 3725 {
+		t.Errorf("unexpected ext/duration: %s %d", audio.Ext, audio.Duration)
+	}
+	if audio.Description != "本期嘉宾：张三\n时间轴\n00:00 开场" {
+		t.Errorf("unexpected show notes: %q", audio.Description)
+	}
+	if audio.CoverURL != "https://image.xyzcdn.net/cover.jpg@large" {
+		t.Errorf("expected podcast cover fallback, got %q", audio.CoverURL)
+	}
+}
+
+func TestXiaoyuzhouPodcast(t *testing.T) {
+	e := &XiaoyuzhouExtractor{client: replay.Client(t, filepath.Join("testdata", "replay", "xiaoyuzhou"))}
+
+	media, err := e.Extract("https://www.xiaoyuzhoufm.com/podcast/5e280fab418a84a0461fa1b5")
+	if err != nil {
+		t.Fatalf("Extract: %v", err)
+	}
+	podcast, ok := media.(*PodcastMedia)
+	if !ok {
+		t.Fatalf("expected *PodcastMedia, got %T", media)
+	}
+	if podcast.Title != "测试播客" || podcast.Uploader != "测试主播" {
+		t.Errorf("unexpected metadata: %q by %q", podcast.Title, podcast.Uploader)
+	}
+
+	// The paid episode has no enclosure and is skipped, ep40 is both embedded and
+	// listed, and ep1 only comes from the episode list; all are newest first
+	if len(podcast.Episodes) != 3 {
+		t.Fatalf("expected 3 episodes, got %d", len(podcast.Episodes))
+	}
+	if podcast.Episodes[0].ID != "ep42" || podcast.Episodes[1].ID != "ep40" || podcast.Episodes[2].ID != "ep1" {
+		t.Errorf("unexpected order: %s, %s, %s", podcast.Episodes[0].ID, podcast.Episodes[1].ID, podcast.Episodes[2].ID)
+	}
+	if podcast.Episodes[1].Ext != "mp3" {
+		t.Errorf("expected mp3, got %s", podcast.Episodes[1].Ext)
+	}
+}
+
+// episodeListTransport serves episode list pages keyed by the request's loadMoreKey
+type episodeListTransport struct {
+	pages    map[string]string // loadMoreKey id -> response body
+	requests int
+}
+
+func (tr *episodeListTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	tr.requests++
+	var body struct {
+		Pid         string `json:"pid"`
+		LoadMoreKey *struct {
+			ID string `json:"id"`
+		} `json:"loadMoreKey"`
+	}
+	if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+	key := ""
+	if body.LoadMoreKey != nil {
+		key = body.LoadMoreKey.ID
+	}
+	page, ok := tr.pages[key]
+	if !ok || body.Pid != "pod" {
+		return &http.Response{StatusCode: http.StatusNotFound, Body: io.NopCloser(strings.NewReader("")), Request: req}, nil
+	}
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(page)), Request: req}, nil
+}
+
+func TestXiaoyuzhouEpisodeListPaging(t *testing.T) {
+	tr := &episodeListTransport{pages: map[string]string{
+		"":    `{"data":[{"eid":"ep3","enclosure":{"url":"https://media.xyzcdn.net/3.m4a"}},{"eid":"ep2","enclosure":{"url":"https://media.xyzcdn.net/2.m4a"}}],"loadMoreKey":{"id":"ep2","direction":"NEXT"}}`,
+		"ep2": `{"data":[{"eid":"ep1","enclosure":{"url":"https://media.xyzcdn.net/1.m4a"}}],"loadMoreKey":null}`,
+	}}
+
+	episodes, err := fetchXiaoyuzhouEpisodeList(context.Background(), &http.Client{Transport: tr}, "pod", xiaoyuzhouMaxPages)
+	if err != nil {
+		t.Fatalf("fetchXiaoyuzhouEpisodeList: %v", err)
+	}
+	if tr.requests != 2 {
+		t.Errorf("expected 2 requests, got %d", tr.requests)
+	}
+	if len(episodes) != 3 || episodes[0].Eid != "ep3" || episodes[2].Eid != "ep1" {
+		t.Errorf("unexpected episodes: %+v", episodes)
+	}
+}
+
+func TestXiaoyuzhouEpisodeListMaxPages(t *testing.T) {
+	tr := &episodeListTransport{pages: map[string]string{
+		"":    `{"data":[{"eid":"ep3","enclosure":{"url":"https://media.xyzcdn.net/3.m4a"}},{"eid":"ep2","enclosure":{"url":"https://media.xyzcdn.net/2.m4a"}}],"loadMoreKey":{"id":"ep2","direction":"NEXT"}}`,
+		"ep2": `{"data":[{"eid":"ep1","enclosure":{"url":"https://media.xyzcdn.net/1.m4a"}}],"loadMoreKey":null}`,
+	}}
+
+	episodes, err := fetchXiaoyuzhouEpisodeList(context.Background(), &http.Client{Transport: tr}, "pod", 1)
+	if err != nil {
+		t.Fatalf("fetchXiaoyuzhouEpisodeList: %v", err)
+	}
+	if tr.requests != 1 || len(episodes) != 2 {
+		t.Errorf("expected one page of 2 episodes, got %d requests and %d episodes", tr.requests, len(episodes))
+	}
+}
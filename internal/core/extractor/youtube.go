@@ -42,6 +42,19 @@ func (e *ytdlpExtractor) Name() string {
 	return "YouTube (yt-dlp)"
 }
 
+// Info describes the URLs and media this extractor handles
+func (e *ytdlpExtractor) Info() Info {
+	return Info{
+		Name:       e.Name(),
+		Title:      "YouTube",
+		Kinds:      []URLKind{URLKindSingle},
+		MediaTypes: []MediaType{MediaTypeVideo},
+		Requires: []Requirement{
+			{Kind: RequiresDocker, Detail: "downloaded with yt-dlp, which is only bundled in the Docker image"},
+		},
+	}
+}
+
 func (e *ytdlpExtractor) Match(u *url.URL) bool {
 	host := strings.ToLower(u.Host)
 	return host == "youtube.com" ||
@@ -52,6 +65,11 @@ func (e *ytdlpExtractor) Match(u *url.URL) bool {
 }
 
 func (e *ytdlpExtractor) Extract(urlStr string) (Media, error) {
+	return e.ExtractContext(context.Background(), urlStr, Options{})
+}
+
+// ExtractContext returns a marker for yt-dlp; nothing is fetched until the download
+func (e *ytdlpExtractor) ExtractContext(ctx context.Context, urlStr string, opts Options) (Media, error) {
 	if !config.IsRunningInDocker() {
 		return nil, &YouTubeDockerRequiredError{URL: urlStr}
 	}
@@ -156,6 +156,7 @@ type UITranslations struct {
 	Completed        string `yaml:"completed" json:"completed"`
 	Failed           string `yaml:"failed" json:"failed"`
 	Cancelled        string `yaml:"cancelled" json:"cancelled"`
+	Paused           string `yaml:"paused" json:"paused"`
 	Settings         string `yaml:"settings" json:"settings"`
 	Language         string `yaml:"language" json:"language"`
 	Format           string `yaml:"format" json:"format"`
@@ -0,0 +1,51 @@
+package subscription
+
+import (
+	"context"
+	"slices"
+)
+
+// Check lists a subscription's source and returns the new items that pass
+// its filter, oldest first. They are not marked seen; callers do that once an
+// item is queued or downloaded. The first successful check of a subscription
+// without Backfill returns nothing and marks what is already posted as seen,
+// so only later posts are downloaded.
+func Check(ctx context.Context, store *Store, lister Lister, sub *Subscription) ([]Item, error) {
+	title, items, err := lister.List(ctx, sub)
+	return Record(store, sub, title, items, err)
+}
+
+// Record is the second half of Check: it stores the result of listing a
+// subscription's source, which failed if err is set, and returns the new
+// items. Callers that serialize checks can list without holding their lock.
+func Record(store *Store, sub *Subscription, title string, items []Item, err error) ([]Item, error) {
+	first := sub.LastChecked == 0
+
+	if recordErr := store.RecordCheck(sub, title, err); recordErr != nil && err == nil {
+		err = recordErr
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if first && !sub.Backfill {
+		ids := make([]string, 0, len(items))
+		for _, item := range items {
+			ids = append(ids, item.ID)
+		}
+		return nil, store.MarkSeen(sub.ID, ids...)
+	}
+
+	seen, err := store.Seen(sub.ID)
+	if err != nil {
+		return nil, err
+	}
+	var pending []Item
+	for _, item := range items {
+		if !seen[item.ID] && sub.Filter.Match(item) {
+			pending = append(pending, item)
+		}
+	}
+	slices.Reverse(pending)
+	return pending, nil
+}
@@ -0,0 +1,197 @@
+package subscription
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/guiyumin/vget/internal/core/config"
+	"github.com/guiyumin/vget/internal/core/extractor"
+	"github.com/guiyumin/vget/internal/core/extractor/telegram"
+)
+
+// Source kinds
+const (
+	SourceBilibili   = "bilibili"   // an uploader's space, space.bilibili.com/{mid}
+	SourceTwitter    = "twitter"    // a user's profile, x.com/{name}
+	SourceXiaoyuzhou = "xiaoyuzhou" // a podcast, xiaoyuzhoufm.com/podcast/{id}
+	SourceTelegram   = "telegram"   // a channel, t.me/{name} or @name
+	SourceFeed       = "feed"       // a podcast RSS or Atom feed on any host
+)
+
+var xiaoyuzhouPodcastRegex = regexp.MustCompile(`^https?://(?:www\.)?xiaoyuzhoufm\.com/podcast/([a-zA-Z0-9]+)`)
+
+// DetectSource returns the source kind of a subscription URL and the URL in
+// normal form. URLs of other sites are taken to be feeds; the first check
+// tells whether they are.
+func DetectSource(rawURL string) (string, string, error) {
+	rawURL = strings.TrimSpace(rawURL)
+	if rawURL == "" {
+		return "", "", fmt.Errorf("URL is required")
+	}
+
+	// Telegram channels are also given as @name
+	if strings.HasPrefix(rawURL, "@") || strings.Contains(rawURL, "t.me/") || strings.Contains(rawURL, "telegram.me/") {
+		ref, err := telegram.ParseChannel(rawURL)
+		if err != nil {
+			return "", "", err
+		}
+		if ref.IsPrivate {
+			return SourceTelegram, fmt.Sprintf("https://t.me/c/%d", ref.ChannelID), nil
+		}
+		return SourceTelegram, "https://t.me/" + ref.ChannelUsername, nil
+	}
+
+	normalized, err := extractor.NormalizeURL(rawURL)
+	if err != nil {
+		return "", "", err
+	}
+	if mid, ok := extractor.MatchBilibiliSpace(normalized); ok {
+		return SourceBilibili, "https://space.bilibili.com/" + mid, nil
+	}
+	if name, ok := extractor.MatchTwitterUser(normalized); ok {
+		return SourceTwitter, "https://x.com/" + name, nil
+	}
+	if m := xiaoyuzhouPodcastRegex.FindStringSubmatch(normalized); m != nil {
+		return SourceXiaoyuzhou, "https://www.xiaoyuzhoufm.com/podcast/" + m[1], nil
+	}
+
+	u, err := url.Parse(normalized)
+	if err != nil || u.Host == "" {
+		return "", "", fmt.Errorf("not a channel, podcast, user or feed URL: %s", rawURL)
+	}
+	return SourceFeed, normalized, nil
+}
+
+// Lister lists the newest items of a subscription's source
+type Lister interface {
+	// List returns the source's title and its newest items, newest first
+	List(ctx context.Context, sub *Subscription) (string, []Item, error)
+}
+
+// Sources lists the sources of every kind
+type Sources struct {
+	Config   *config.Config
+	Telegram *telegram.SharedClient // lists Telegram channels
+}
+
+// List returns the title of a subscription's source and its newest items,
+// newest first
+func (l *Sources) List(ctx context.Context, sub *Subscription) (string, []Item, error) {
+	switch sub.Source {
+	case SourceBilibili:
+		mid, _ := extractor.MatchBilibiliSpace(sub.URL)
+		uploads, err := extractor.FetchBilibiliUploads(ctx, mid, extractor.Options{Cookie: l.Config.Bilibili.Cookie})
+		if err != nil {
+			return "", nil, err
+		}
+		return uploads.Title, uploadItems(uploads), nil
+
+	case SourceTwitter:
+		name, _ := extractor.MatchTwitterUser(sub.URL)
+		uploads, err := extractor.FetchTwitterUserPosts(ctx, name, extractor.Options{AuthToken: l.Config.Twitter.AuthToken})
+		if err != nil {
+			return "", nil, err
+		}
+		return uploads.Title, uploadItems(uploads), nil
+
+	case SourceXiaoyuzhou:
+		m := xiaoyuzhouPodcastRegex.FindStringSubmatch(sub.URL)
+		if m == nil {
+			return "", nil, fmt.Errorf("could not extract podcast ID from URL")
+		}
+		// Without the older episodes the newest ones on the page still show
+		// what is new, so a listing warning does not fail the check
+		podcast, _, err := extractor.FetchXiaoyuzhouPodcast(ctx, m[1], 0)
+		if err != nil {
+			return "", nil, err
+		}
+		items := episodeItems(podcast)
+		// Episode pages give a fresh audio link when the job runs
+		for i := range items {
+			items[i].URL = "https://www.xiaoyuzhoufm.com/episode/" + items[i].ID
+		}
+		return podcast.Title, items, nil
+
+	case SourceTelegram:
+		title, posts, err := l.Telegram.RecentPosts(ctx, sub.URL)
+		if err != nil {
+			return "", nil, err
+		}
+		items := make([]Item, 0, len(posts))
+		for _, p := range posts {
+			items = append(items, Item{
+				ID:        fmt.Sprint(p.ID),
+				URL:       p.Link,
+				Title:     p.Caption,
+				Duration:  p.Duration,
+				Type:      telegramMediaType(p.Type),
+				Published: p.Date,
+			})
+		}
+		slices.Reverse(items)
+		return title, items, nil
+
+	case SourceFeed:
+		media, err := extractor.ExtractContext(ctx, &extractor.FeedExtractor{}, sub.URL, extractor.Options{})
+		if err != nil {
+			return "", nil, fmt.Errorf("not a readable feed: %w", err)
+		}
+		podcast, ok := media.(*extractor.PodcastMedia)
+		if !ok {
+			return "", nil, fmt.Errorf("not a podcast feed")
+		}
+		return podcast.Title, episodeItems(podcast), nil
+	}
+	return "", nil, fmt.Errorf("unknown source %q", sub.Source)
+}
+
+// uploadItems converts an uploader's listing into items
+func uploadItems(uploads *extractor.Uploads) []Item {
+	items := make([]Item, 0, len(uploads.Items))
+	for _, u := range uploads.Items {
+		items = append(items, Item{
+			ID:        u.ID,
+			URL:       u.URL,
+			Title:     u.Title,
+			Duration:  u.Duration,
+			Type:      u.Type,
+			Published: u.PublishedAt,
+		})
+	}
+	return items
+}
+
+// episodeItems converts a podcast's episodes into items
+func episodeItems(podcast *extractor.PodcastMedia) []Item {
+	items := make([]Item, 0, len(podcast.Episodes))
+	for _, ep := range podcast.Episodes {
+		items = append(items, Item{
+			ID:        ep.ID,
+			URL:       ep.URL,
+			Title:     ep.Title,
+			Duration:  ep.Duration,
+			Type:      extractor.MediaTypeAudio,
+			Published: ep.PublishedAt,
+			Audio:     ep,
+		})
+	}
+	return items
+}
+
+// telegramMediaType maps a Telegram post type to a media type; documents
+// have none, so they pass any media type filter
+func telegramMediaType(kind string) extractor.MediaType {
+	switch kind {
+	case telegram.DumpTypeVideo:
+		return extractor.MediaTypeVideo
+	case telegram.DumpTypeAudio:
+		return extractor.MediaTypeAudio
+	case telegram.DumpTypePhoto:
+		return extractor.MediaTypeImage
+	}
+	return ""
+}
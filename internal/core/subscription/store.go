@@ -0,0 +1,268 @@
+package subscription
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/guiyumin/vget/internal/core/config"
+	_ "modernc.org/sqlite"
+)
+
+// dbFile is shared with the server's download history
+const dbFile = "history.db"
+
+// ErrNotFound is returned for an unknown subscription
+var ErrNotFound = errors.New("subscription not found")
+
+// Store keeps subscriptions and the items seen of each in SQLite
+type Store struct {
+	db *sql.DB
+}
+
+// OpenDefault opens the store in the config directory
+func OpenDefault() (*Store, error) {
+	configDir, err := config.ConfigDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get config dir: %w", err)
+	}
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create config dir: %w", err)
+	}
+	return Open(filepath.Join(configDir, dbFile))
+}
+
+// Open opens the store in the database at path, creating its tables if needed
+func Open(path string) (*Store, error) {
+	// The server and the CLI may write at the same time
+	db, err := sql.Open("sqlite", "file:"+path+"?_pragma=busy_timeout(5000)")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open subscription database: %w", err)
+	}
+
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS subscriptions (
+			id TEXT PRIMARY KEY,
+			url TEXT NOT NULL UNIQUE,
+			source TEXT NOT NULL,
+			title TEXT,
+			interval_minutes INTEGER NOT NULL,
+			filter_title TEXT,
+			min_duration INTEGER DEFAULT 0,
+			media_type TEXT,
+			quality TEXT,
+			folder TEXT,
+			backfill INTEGER DEFAULT 0,
+			created_at INTEGER NOT NULL,
+			last_checked INTEGER DEFAULT 0,
+			last_attempt INTEGER DEFAULT 0,
+			last_error TEXT
+		);
+		CREATE TABLE IF NOT EXISTS subscription_seen (
+			subscription_id TEXT NOT NULL,
+			item_id TEXT NOT NULL,
+			seen_at INTEGER NOT NULL,
+			PRIMARY KEY (subscription_id, item_id)
+		);
+	`)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create subscription tables: %w", err)
+	}
+
+	return &Store{db: db}, nil
+}
+
+// Close closes the database connection
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Add validates and saves a new subscription, filling in its ID
+func (s *Store) Add(sub *Subscription) error {
+	if err := sub.Validate(); err != nil {
+		return err
+	}
+
+	bytes := make([]byte, 8)
+	if _, err := rand.Read(bytes); err != nil {
+		return fmt.Errorf("failed to generate ID: %w", err)
+	}
+	sub.ID = hex.EncodeToString(bytes)
+	sub.CreatedAt = time.Now().Unix()
+
+	_, err := s.db.Exec(`
+		INSERT INTO subscriptions
+		(id, url, source, title, interval_minutes, filter_title, min_duration, media_type, quality, folder, backfill, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`,
+		sub.ID,
+		sub.URL,
+		sub.Source,
+		sub.Title,
+		sub.IntervalMinutes,
+		sub.Filter.Title,
+		sub.Filter.MinDuration,
+		sub.Filter.MediaType,
+		sub.Quality,
+		sub.Folder,
+		sub.Backfill,
+		sub.CreatedAt,
+	)
+	if err != nil {
+		if strings.Contains(err.Error(), "UNIQUE") {
+			return fmt.Errorf("already subscribed to %s", sub.URL)
+		}
+		return fmt.Errorf("failed to save subscription: %w", err)
+	}
+	return nil
+}
+
+const selectColumns = `
+	SELECT id, url, source, title, interval_minutes, filter_title, min_duration, media_type,
+		quality, folder, backfill, created_at, last_checked, last_attempt, last_error
+	FROM subscriptions`
+
+func scanSubscription(row interface{ Scan(...any) error }) (*Subscription, error) {
+	var sub Subscription
+	var title, filterTitle, mediaType, quality, folder, lastError sql.NullString
+	err := row.Scan(
+		&sub.ID, &sub.URL, &sub.Source, &title, &sub.IntervalMinutes, &filterTitle,
+		&sub.Filter.MinDuration, &mediaType, &quality, &folder, &sub.Backfill,
+		&sub.CreatedAt, &sub.LastChecked, &sub.LastAttempt, &lastError,
+	)
+	if err != nil {
+		return nil, err
+	}
+	sub.Title = title.String
+	sub.Filter.Title = filterTitle.String
+	sub.Filter.MediaType = mediaType.String
+	// Saved patterns were validated on add; one that no longer compiles
+	// matches nothing
+	sub.Filter.compile()
+	sub.Quality = quality.String
+	sub.Folder = folder.String
+	sub.LastError = lastError.String
+	return &sub, nil
+}
+
+// List returns all subscriptions, oldest first
+func (s *Store) List() ([]*Subscription, error) {
+	rows, err := s.db.Query(selectColumns + " ORDER BY created_at")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query subscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	subs := make([]*Subscription, 0)
+	for rows.Next() {
+		sub, err := scanSubscription(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan subscription: %w", err)
+		}
+		subs = append(subs, sub)
+	}
+	return subs, rows.Err()
+}
+
+// Get returns a subscription by ID or URL
+func (s *Store) Get(ref string) (*Subscription, error) {
+	row := s.db.QueryRow(selectColumns+" WHERE id = ? OR url = ?", ref, ref)
+	sub, err := scanSubscription(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		if _, normalized, derr := DetectSource(ref); derr == nil && normalized != ref {
+			return s.Get(normalized)
+		}
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get subscription: %w", err)
+	}
+	return sub, nil
+}
+
+// Remove deletes a subscription and the record of its seen items
+func (s *Store) Remove(id string) error {
+	res, err := s.db.Exec("DELETE FROM subscriptions WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete subscription: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+	if _, err := s.db.Exec("DELETE FROM subscription_seen WHERE subscription_id = ?", id); err != nil {
+		return fmt.Errorf("failed to delete seen items: %w", err)
+	}
+	return nil
+}
+
+// Seen returns the IDs of the items of a subscription already seen
+func (s *Store) Seen(id string) (map[string]bool, error) {
+	rows, err := s.db.Query("SELECT item_id FROM subscription_seen WHERE subscription_id = ?", id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query seen items: %w", err)
+	}
+	defer rows.Close()
+
+	seen := make(map[string]bool)
+	for rows.Next() {
+		var itemID string
+		if err := rows.Scan(&itemID); err != nil {
+			return nil, fmt.Errorf("failed to scan seen item: %w", err)
+		}
+		seen[itemID] = true
+	}
+	return seen, rows.Err()
+}
+
+// MarkSeen records items of a subscription as seen, so they are not
+// downloaded again
+func (s *Store) MarkSeen(id string, itemIDs ...string) error {
+	if len(itemIDs) == 0 {
+		return nil
+	}
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to mark items seen: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now().Unix()
+	for _, itemID := range itemIDs {
+		if _, err := tx.Exec("INSERT OR IGNORE INTO subscription_seen (subscription_id, item_id, seen_at) VALUES (?, ?, ?)", id, itemID, now); err != nil {
+			return fmt.Errorf("failed to mark items seen: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// RecordCheck saves the outcome of a check: the time, the error if it
+// failed, and the source's title if it succeeded
+func (s *Store) RecordCheck(sub *Subscription, title string, checkErr error) error {
+	now := time.Now().Unix()
+	sub.LastAttempt = now
+	if checkErr != nil {
+		sub.LastError = checkErr.Error()
+	} else {
+		sub.LastChecked = now
+		sub.LastError = ""
+		if title != "" {
+			sub.Title = title
+		}
+	}
+
+	_, err := s.db.Exec(`
+		UPDATE subscriptions SET title = ?, last_checked = ?, last_attempt = ?, last_error = ?
+		WHERE id = ?
+	`, sub.Title, sub.LastChecked, sub.LastAttempt, sub.LastError, sub.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update subscription: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+// Package subscription watches channels, podcasts and users for new posts.
+// Subscriptions and the IDs of the items already seen are kept in the same
+// SQLite database as the server's download history.
+package subscription
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/guiyumin/vget/internal/core/extractor"
+)
+
+// DefaultInterval is how often a source is checked unless set otherwise
+const DefaultInterval = 60 * time.Minute
+
+// MinInterval keeps sources from being polled more often than sites tolerate
+const MinInterval = 5 * time.Minute
+
+// MediaTypes lists the media types a subscription can be filtered by
+var MediaTypes = []string{
+	string(extractor.MediaTypeVideo),
+	string(extractor.MediaTypeAudio),
+	string(extractor.MediaTypeImage),
+}
+
+// Subscription is a watched source and what to download from it
+type Subscription struct {
+	ID              string `json:"id"`
+	URL             string `json:"url"`
+	Source          string `json:"source"` // one of the Source* kinds
+	Title           string `json:"title,omitempty"`
+	IntervalMinutes int    `json:"interval_minutes"`
+	Filter          Filter `json:"filter"`
+	Quality         string `json:"quality,omitempty"` // preferred video quality (e.g., "1080p")
+	Folder          string `json:"folder,omitempty"`  // directory under the output directory
+	Backfill        bool   `json:"backfill"`          // download what is already posted on the first check
+	CreatedAt       int64  `json:"created_at"`        // Unix timestamp
+	LastChecked     int64  `json:"last_checked"`      // last successful check; 0 if never
+	LastAttempt     int64  `json:"last_attempt"`      // last check, successful or not
+	LastError       string `json:"last_error,omitempty"`
+}
+
+// Filter selects which new items of a source are downloaded
+type Filter struct {
+	Title       string `json:"title,omitempty"`        // regular expression the title must match
+	MinDuration int    `json:"min_duration,omitempty"` // seconds; items of unknown length pass
+	MediaType   string `json:"media_type,omitempty"`   // one of MediaTypes; items of unknown type pass
+
+	titleRe *regexp.Regexp // Title compiled by Validate or on load
+}
+
+// compile compiles the title pattern once, for Match to reuse
+func (f *Filter) compile() error {
+	f.titleRe = nil
+	if f.Title == "" {
+		return nil
+	}
+	re, err := regexp.Compile(f.Title)
+	if err != nil {
+		return err
+	}
+	f.titleRe = re
+	return nil
+}
+
+// Item is a post or episode of a source
+type Item struct {
+	ID        string
+	URL       string
+	Title     string
+	Duration  int                 // seconds; 0 if unknown
+	Type      extractor.MediaType // "" if unknown
+	Published time.Time           // Zero if unknown
+
+	// Audio is set for podcast episodes, which download without extraction
+	Audio *extractor.AudioMedia
+}
+
+// Interval is how often the subscription is checked
+func (s *Subscription) Interval() time.Duration {
+	if s.IntervalMinutes <= 0 {
+		return DefaultInterval
+	}
+	return time.Duration(s.IntervalMinutes) * time.Minute
+}
+
+// Due reports whether the subscription should be checked at now
+func (s *Subscription) Due(now time.Time) bool {
+	if s.LastAttempt == 0 {
+		return true
+	}
+	return !now.Before(time.Unix(s.LastAttempt, 0).Add(s.Interval()))
+}
+
+// Name is the subscription's title, or its URL before the first check
+func (s *Subscription) Name() string {
+	if s.Title != "" {
+		return s.Title
+	}
+	return s.URL
+}
+
+// Dir is the folder new items are saved in: the configured one, or one named
+// after the source
+func (s *Subscription) Dir() string {
+	if s.Folder != "" {
+		return s.Folder
+	}
+	if dir := extractor.SanitizeFilename(s.Title); dir != "" {
+		return dir
+	}
+	return s.ID
+}
+
+// Validate checks the settings of a subscription and fills in its source kind
+func (s *Subscription) Validate() error {
+	source, normalized, err := DetectSource(s.URL)
+	if err != nil {
+		return err
+	}
+	s.Source = source
+	s.URL = normalized
+
+	if s.IntervalMinutes == 0 {
+		s.IntervalMinutes = int(DefaultInterval / time.Minute)
+	}
+	if s.Interval() < MinInterval {
+		return fmt.Errorf("interval must be at least %s", MinInterval)
+	}
+	if err := s.Filter.compile(); err != nil {
+		return fmt.Errorf("invalid title filter: %w", err)
+	}
+	if s.Filter.MinDuration < 0 {
+		return fmt.Errorf("minimum duration can't be negative")
+	}
+	if s.Filter.MediaType != "" && !slices.Contains(MediaTypes, s.Filter.MediaType) {
+		return fmt.Errorf("unknown media type %q (expected %s)", s.Filter.MediaType, strings.Join(MediaTypes, ", "))
+	}
+	return nil
+}
+
+// Match reports whether an item passes the filter
+func (f *Filter) Match(item Item) bool {
+	if f.Title != "" {
+		re := f.titleRe
+		if re == nil {
+			var err error
+			if re, err = regexp.Compile(f.Title); err != nil {
+				return false
+			}
+		}
+		if !re.MatchString(item.Title) {
+			return false
+		}
+	}
+	if f.MinDuration > 0 && item.Duration > 0 && item.Duration < f.MinDuration {
+		return false
+	}
+	if f.MediaType != "" && item.Type != "" && string(item.Type) != f.MediaType {
+		return false
+	}
+	return true
+}
+
+// String describes the filter, or returns "" if it passes everything
+func (f *Filter) String() string {
+	var parts []string
+	if f.Title != "" {
+		parts = append(parts, fmt.Sprintf("title ~ %q", f.Title))
+	}
+	if f.MinDuration > 0 {
+		parts = append(parts, fmt.Sprintf("at least %s", time.Duration(f.MinDuration)*time.Second))
+	}
+	if f.MediaType != "" {
+		parts = append(parts, f.MediaType+" only")
+	}
+	return strings.Join(parts, ", ")
+}
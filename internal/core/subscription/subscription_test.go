@@ -0,0 +1,192 @@
+package subscription
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/guiyumin/vget/internal/core/extractor"
+)
+
+func TestDetectSource(t *testing.T) {
+	tests := []struct {
+		url, source, normalized string
+	}{
+		{"https://space.bilibili.com/946974/video", SourceBilibili, "https://space.bilibili.com/946974"},
+		{"x.com/NASA", SourceTwitter, "https://x.com/NASA"},
+		{"https://www.xiaoyuzhoufm.com/podcast/5e280fab418a84a0461fa6c1", SourceXiaoyuzhou, "https://www.xiaoyuzhoufm.com/podcast/5e280fab418a84a0461fa6c1"},
+		{"@durov", SourceTelegram, "https://t.me/durov"},
+		{"https://t.me/c/1234567890", SourceTelegram, "https://t.me/c/1234567890"},
+		{"https://feeds.example.com/show", SourceFeed, "https://feeds.example.com/show"},
+	}
+	for _, tt := range tests {
+		source, normalized, err := DetectSource(tt.url)
+		if err != nil {
+			t.Errorf("DetectSource(%q): %v", tt.url, err)
+			continue
+		}
+		if source != tt.source || normalized != tt.normalized {
+			t.Errorf("DetectSource(%q) = %s %s, want %s %s", tt.url, source, normalized, tt.source, tt.normalized)
+		}
+	}
+
+	if _, _, err := DetectSource("not a url"); err == nil {
+		t.Error("expected an error for an invalid URL")
+	}
+}
+
+func TestFilterMatch(t *testing.T) {
+	filter := Filter{Title: `(?i)lecture`, MinDuration: 600, MediaType: "video"}
+	tests := []struct {
+		item Item
+		want bool
+	}{
+		{Item{Title: "Lecture 3", Duration: 3000, Type: extractor.MediaTypeVideo}, true},
+		{Item{Title: "Lecture 3"}, true}, // unknown length and type pass
+		{Item{Title: "Trailer", Duration: 3000, Type: extractor.MediaTypeVideo}, false},
+		{Item{Title: "Lecture 3 teaser", Duration: 45, Type: extractor.MediaTypeVideo}, false},
+		{Item{Title: "Lecture 3 slides", Type: extractor.MediaTypeImage}, false},
+	}
+	for _, tt := range tests {
+		if got := filter.Match(tt.item); got != tt.want {
+			t.Errorf("Match(%+v) = %v, want %v", tt.item, got, tt.want)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	bad := []Subscription{
+		{URL: "x.com/NASA", IntervalMinutes: 1},
+		{URL: "x.com/NASA", Filter: Filter{Title: "("}},
+		{URL: "x.com/NASA", Filter: Filter{MediaType: "gif"}},
+	}
+	for _, sub := range bad {
+		if err := sub.Validate(); err == nil {
+			t.Errorf("Validate(%+v) succeeded", sub)
+		}
+	}
+
+	sub := Subscription{URL: "x.com/NASA"}
+	if err := sub.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if sub.Interval() != DefaultInterval || sub.Source != SourceTwitter {
+		t.Errorf("defaults not applied: %+v", sub)
+	}
+}
+
+func TestStoreCompilesFilter(t *testing.T) {
+	store, err := Open(filepath.Join(t.TempDir(), "history.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	if err := store.Add(&Subscription{URL: "x.com/NASA", Filter: Filter{Title: "("}}); err == nil {
+		t.Error("adding an invalid title filter succeeded")
+	}
+
+	sub := &Subscription{URL: "x.com/NASA", Filter: Filter{Title: `(?i)launch`}}
+	if err := store.Add(sub); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := store.Get(sub.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Filter.titleRe == nil {
+		t.Fatal("loaded filter was not compiled")
+	}
+	if !loaded.Filter.Match(Item{Title: "Launch day"}) || loaded.Filter.Match(Item{Title: "Landing"}) {
+		t.Error("loaded filter matches the wrong titles")
+	}
+}
+
+// fakeLister lists a fixed set of items, newest first
+type fakeLister struct {
+	items []Item
+}
+
+func (f *fakeLister) List(ctx context.Context, sub *Subscription) (string, []Item, error) {
+	return "Course Channel", f.items, nil
+}
+
+func TestCheck(t *testing.T) {
+	store, err := Open(filepath.Join(t.TempDir(), "history.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	sub := &Subscription{URL: "https://t.me/coursechannel", Filter: Filter{MinDuration: 60}}
+	if err := store.Add(sub); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Add(&Subscription{URL: "t.me/coursechannel"}); err == nil {
+		t.Error("subscribing twice succeeded")
+	}
+
+	lister := &fakeLister{items: []Item{{ID: "2", Duration: 300}, {ID: "1", Duration: 300}}}
+
+	// The first check only marks what is already posted
+	items, err := Check(context.Background(), store, lister, sub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 0 {
+		t.Errorf("first check returned %d items", len(items))
+	}
+
+	lister.items = append([]Item{{ID: "4", Duration: 300}, {ID: "3", Duration: 20}}, lister.items...)
+	items, err = Check(context.Background(), store, lister, sub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 || items[0].ID != "4" {
+		t.Fatalf("second check returned %+v, want item 4", items)
+	}
+
+	saved, err := store.Get("@coursechannel")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.Title != "Course Channel" || saved.LastChecked == 0 || !saved.Due(time.Now().Add(time.Hour)) || saved.Due(time.Now()) {
+		t.Errorf("check not recorded: %+v", saved)
+	}
+
+	if err := store.MarkSeen(sub.ID, "4"); err != nil {
+		t.Fatal(err)
+	}
+	if items, _ := Check(context.Background(), store, lister, sub); len(items) != 0 {
+		t.Errorf("seen item returned again: %+v", items)
+	}
+
+	if err := store.Remove(sub.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Get(sub.ID); err != ErrNotFound {
+		t.Errorf("Get after Remove: %v", err)
+	}
+}
+
+func TestCheckBackfill(t *testing.T) {
+	store, err := Open(filepath.Join(t.TempDir(), "history.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	sub := &Subscription{URL: "https://feeds.example.com/show.rss", Backfill: true}
+	if err := store.Add(sub); err != nil {
+		t.Fatal(err)
+	}
+	lister := &fakeLister{items: []Item{{ID: "b"}, {ID: "a"}}}
+	items, err := Check(context.Background(), store, lister, sub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 2 || items[0].ID != "a" {
+		t.Errorf("backfill returned %+v, want a then b", items)
+	}
+}
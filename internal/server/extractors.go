@@ -0,0 +1,48 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/guiyumin/vget/internal/core/config"
+	"github.com/guiyumin/vget/internal/core/extractor"
+)
+
+// handleGetExtractors lists the built-in extractors and their capabilities
+func (s *Server) handleGetExtractors(c *gin.Context) {
+	c.JSON(http.StatusOK, Response{
+		Code:    200,
+		Data:    gin.H{"extractors": extractor.Infos()},
+		Message: "extractors retrieved",
+	})
+}
+
+// handleMatchExtractor reports which extractor, sites.yml rule or fallback would handle ?url=
+func (s *Server) handleMatchExtractor(c *gin.Context) {
+	rawURL := c.Query("url")
+	if rawURL == "" {
+		c.JSON(http.StatusBadRequest, Response{
+			Code:    400,
+			Data:    nil,
+			Message: "url is required",
+		})
+		return
+	}
+
+	sitesConfig, _ := config.LoadSites()
+	res, err := extractor.Resolve(rawURL, sitesConfig)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Response{
+			Code:    400,
+			Data:    nil,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Code:    200,
+		Data:    res,
+		Message: res.Reason,
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"sync"
+	"time"
 
 	"github.com/guiyumin/vget/internal/core/config"
 	_ "modernc.org/sqlite"
@@ -47,8 +48,8 @@ func NewHistoryDB() (*HistoryDB, error) {
 
 	dbPath := filepath.Join(configDir, historyDBFile)
 
-	// Open database
-	db, err := sql.Open("sqlite", dbPath)
+	// Open database; subscriptions and the CLI write to it too
+	db, err := sql.Open("sqlite", "file:"+dbPath+"?_pragma=busy_timeout(5000)")
 	if err != nil {
 		return nil, fmt.Errorf("failed to open history database: %w", err)
 	}
@@ -68,6 +69,30 @@ func NewHistoryDB() (*HistoryDB, error) {
 		);
 		CREATE INDEX IF NOT EXISTS idx_completed_at ON download_history(completed_at DESC);
 		CREATE INDEX IF NOT EXISTS idx_status ON download_history(status);
+		CREATE TABLE IF NOT EXISTS jobs (
+			id TEXT PRIMARY KEY,
+			seq INTEGER NOT NULL,
+			url TEXT NOT NULL,
+			filename TEXT,
+			requested_filename TEXT,
+			folder TEXT,
+			quality TEXT,
+			status TEXT NOT NULL,
+			progress REAL DEFAULT 0,
+			downloaded INTEGER DEFAULT 0,
+			total INTEGER DEFAULT 0,
+			error_message TEXT,
+			priority INTEGER DEFAULT 0,
+			held INTEGER DEFAULT 0,
+			subscription_id TEXT,
+			subscription_item TEXT,
+			created_at INTEGER NOT NULL,
+			updated_at INTEGER NOT NULL
+		);
+		CREATE TABLE IF NOT EXISTS settings (
+			key TEXT PRIMARY KEY,
+			value TEXT
+		);
 	`)
 	if err != nil {
 		db.Close()
@@ -213,3 +238,145 @@ func (h *HistoryDB) ClearHistory() (int64, error) {
 
 	return result.RowsAffected()
 }
+
+// SaveJob stores the current state of a queue job, so it survives a restart
+func (h *HistoryDB) SaveJob(job *Job) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	_, err := h.db.Exec(`
+		INSERT OR REPLACE INTO jobs
+		(id, seq, priority, held, url, filename, requested_filename, folder, quality, subscription_id, subscription_item, status, progress, downloaded, total, error_message, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`,
+		job.ID,
+		job.seq,
+		job.Priority,
+		job.held,
+		job.URL,
+		job.Filename,
+		job.opts.Filename,
+		job.opts.Folder,
+		job.opts.Quality,
+		job.opts.Subscription,
+		job.opts.SubscriptionItem,
+		string(job.Status),
+		job.Progress,
+		job.Downloaded,
+		job.Total,
+		job.Error,
+		job.CreatedAt.Unix(),
+		job.UpdatedAt.Unix(),
+	)
+
+	return err
+}
+
+// LoadJobs returns the saved queue jobs in queue order
+func (h *HistoryDB) LoadJobs() ([]*Job, error) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	rows, err := h.db.Query(`
+		SELECT id, seq, priority, held, url, filename, requested_filename, folder, quality, subscription_id, subscription_item, status, progress, downloaded, total, error_message, created_at, updated_at
+		FROM jobs
+		ORDER BY seq
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query jobs: %w", err)
+	}
+	defer rows.Close()
+
+	jobs := make([]*Job, 0)
+	for rows.Next() {
+		var job Job
+		var filename, requestedFilename, folder, quality, subscriptionID, subscriptionItem, errorMsg sql.NullString
+		var status string
+		var createdAt, updatedAt int64
+
+		err := rows.Scan(
+			&job.ID,
+			&job.seq,
+			&job.Priority,
+			&job.held,
+			&job.URL,
+			&filename,
+			&requestedFilename,
+			&folder,
+			&quality,
+			&subscriptionID,
+			&subscriptionItem,
+			&status,
+			&job.Progress,
+			&job.Downloaded,
+			&job.Total,
+			&errorMsg,
+			&createdAt,
+			&updatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan job row: %w", err)
+		}
+
+		job.Filename = filename.String
+		job.Folder = folder.String
+		job.Quality = quality.String
+		job.opts = JobOptions{
+			Filename: requestedFilename.String,
+			Folder:   folder.String,
+			Quality:  quality.String,
+			Priority: job.Priority,
+
+			Subscription:     subscriptionID.String,
+			SubscriptionItem: subscriptionItem.String,
+		}
+		job.Status = JobStatus(status)
+		job.Error = errorMsg.String
+		job.CreatedAt = time.Unix(createdAt, 0)
+		job.UpdatedAt = time.Unix(updatedAt, 0)
+		jobs = append(jobs, &job)
+	}
+
+	return jobs, rows.Err()
+}
+
+// DeleteJobs removes saved queue jobs
+func (h *HistoryDB) DeleteJobs(ids ...string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	tx, err := h.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, id := range ids {
+		if _, err := tx.Exec("DELETE FROM jobs WHERE id = ?", id); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// QueuePaused returns whether the job queue was paused with "pause all"
+func (h *HistoryDB) QueuePaused() (bool, error) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	var value string
+	err := h.db.QueryRow("SELECT value FROM settings WHERE key = 'queue_paused'").Scan(&value)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	return value == "true", err
+}
+
+// SetQueuePaused saves whether the job queue is paused with "pause all"
+func (h *HistoryDB) SetQueuePaused(paused bool) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	_, err := h.db.Exec("INSERT OR REPLACE INTO settings (key, value) VALUES ('queue_paused', ?)", fmt.Sprint(paused))
+	return err
+}
@@ -4,8 +4,10 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"log"
+	"slices"
 	"sync"
 	"time"
 
@@ -21,13 +23,40 @@ const (
 	JobStatusCompleted   JobStatus = "completed"
 	JobStatusFailed      JobStatus = "failed"
 	JobStatusCancelled   JobStatus = "cancelled"
+	JobStatusPaused      JobStatus = "paused" // paused by the user, or waiting to be retried (see RetryAfterError)
 )
 
+var (
+	// ErrJobNotFound is returned for an unknown job ID
+	ErrJobNotFound = errors.New("job not found")
+	// ErrJobNotQueued is returned when moving a job that is not waiting in the queue
+	ErrJobNotQueued = errors.New("only queued jobs can be moved")
+)
+
+// RetryAfterError is returned by a DownloadFunc when the site asked to wait
+// (e.g. a Telegram FLOOD_WAIT). The job is paused and queued again after Wait
+// instead of failing.
+type RetryAfterError struct {
+	Wait time.Duration
+	Err  error
+}
+
+func (e *RetryAfterError) Error() string {
+	return fmt.Sprintf("%v (retry after %s)", e.Err, e.Wait)
+}
+
+func (e *RetryAfterError) Unwrap() error {
+	return e.Err
+}
+
 // Job represents a download job
 type Job struct {
 	ID         string    `json:"id"`
 	URL        string    `json:"url"`
 	Filename   string    `json:"filename,omitempty"`
+	Folder     string    `json:"folder,omitempty"`  // subdirectory of the output directory
+	Quality    string    `json:"quality,omitempty"` // preferred video quality
+	Priority   int       `json:"priority"`          // higher runs first
 	Status     JobStatus `json:"status"`
 	Progress   float64   `json:"progress"`
 	Downloaded int64     `json:"downloaded"` // bytes downloaded
@@ -37,27 +66,58 @@ type Job struct {
 	UpdatedAt  time.Time `json:"updated_at"`
 
 	// Internal fields (not serialized)
-	cancel context.CancelFunc `json:"-"`
-	ctx    context.Context    `json:"-"`
+	cancel  context.CancelFunc `json:"-"`
+	ctx     context.Context    `json:"-"`
+	opts    JobOptions         // as requested; Filename above becomes the output path
+	seq     int64              // position among jobs of the same priority; lower runs first
+	savedAt time.Time          // when the job was last saved to the database
+	held    bool               // paused with PauseJob; stays paused until ResumeJob
+	running bool               // a worker is downloading it (or still stopping)
+}
+
+// finished reports whether a job in this status will not run again
+func (s JobStatus) finished() bool {
+	return s == JobStatusCompleted || s == JobStatusFailed || s == JobStatusCancelled
 }
 
-// JobQueue manages download jobs with a worker pool
+// progressSaveInterval limits how often download progress is saved
+const progressSaveInterval = 5 * time.Second
+
+// JobQueue manages download jobs with a worker pool. Jobs are saved to the
+// history database as they change, so unfinished jobs survive a restart.
 type JobQueue struct {
 	jobs          map[string]*Job
 	mu            sync.RWMutex
-	queue         chan *Job
+	ready         *sync.Cond // signalled when a job is queued or the queue stops
+	nextSeq       int64      // queue position of the next job added
+	paused        bool       // set by PauseAll; no downloads start until ResumeAll
 	maxConcurrent int
 	outputDir     string
 	downloadFn    DownloadFunc
 	wg            sync.WaitGroup
 	cleanupTicker *time.Ticker
 	stopCleanup   chan struct{}
-	historyDB     *HistoryDB // Optional: for persisting download history
+	historyDB     *HistoryDB // Optional: for persisting jobs and download history
+	stopped       bool       // set by Stop; paused jobs are not queued again
+	onComplete    func(opts JobOptions)
+}
+
+// JobOptions are the per-job settings of a download
+type JobOptions struct {
+	Filename string // output filename; empty names the file after the media
+	Folder   string // subdirectory of the output directory to save into
+	Quality  string // preferred video quality (e.g., "1080p"); empty for the best
+	Priority int    // higher runs first; jobs of equal priority run in the order added
+
+	// Subscription and SubscriptionItem link a job queued by a subscription to
+	// its item, which is marked seen once the job completes
+	Subscription     string
+	SubscriptionItem string
 }
 
 // DownloadFunc is the function signature for downloading a URL
-// It receives the job context, URL, output path, and a progress callback
-type DownloadFunc func(ctx context.Context, url, outputPath string, progressFn func(downloaded, total int64)) error
+// It receives the job context, URL, job options, and a progress callback
+type DownloadFunc func(ctx context.Context, url string, opts JobOptions, progressFn func(downloaded, total int64)) error
 
 // NewJobQueue creates a new job queue with the specified concurrency
 func NewJobQueue(maxConcurrent int, outputDir string, downloadFn DownloadFunc) *JobQueue {
@@ -67,24 +127,32 @@ func NewJobQueue(maxConcurrent int, outputDir string, downloadFn DownloadFunc) *
 
 	jq := &JobQueue{
 		jobs:          make(map[string]*Job),
-		queue:         make(chan *Job, 100),
 		maxConcurrent: maxConcurrent,
 		outputDir:     outputDir,
 		downloadFn:    downloadFn,
 		stopCleanup:   make(chan struct{}),
 		historyDB:     nil,
 	}
+	jq.ready = sync.NewCond(&jq.mu)
 
 	return jq
 }
 
-// SetHistoryDB sets the history database for persisting completed downloads
+// SetHistoryDB sets the history database for persisting jobs and completed downloads
 func (jq *JobQueue) SetHistoryDB(db *HistoryDB) {
 	jq.historyDB = db
 }
 
-// Start begins the worker pool and cleanup routine
+// OnComplete sets a function called with the options of each job that completes
+func (jq *JobQueue) OnComplete(fn func(opts JobOptions)) {
+	jq.onComplete = fn
+}
+
+// Start restores the jobs saved before a restart, then begins the worker pool
+// and cleanup routine
 func (jq *JobQueue) Start() {
+	jq.restoreJobs()
+
 	// Start workers
 	for i := 0; i < jq.maxConcurrent; i++ {
 		jq.wg.Add(1)
@@ -96,9 +164,18 @@ func (jq *JobQueue) Start() {
 	go jq.cleanupLoop()
 }
 
-// Stop gracefully shuts down the job queue
+// Stop shuts down the job queue. Running downloads are interrupted and saved
+// as queued, so they continue after a restart.
 func (jq *JobQueue) Stop() {
-	close(jq.queue)
+	jq.mu.Lock()
+	jq.stopped = true
+	for _, job := range jq.jobs {
+		if job.Status == JobStatusDownloading {
+			job.cancel()
+		}
+	}
+	jq.ready.Broadcast()
+	jq.mu.Unlock()
 	close(jq.stopCleanup)
 	if jq.cleanupTicker != nil {
 		jq.cleanupTicker.Stop()
@@ -106,16 +183,125 @@ func (jq *JobQueue) Stop() {
 	jq.wg.Wait()
 }
 
+// restoreJobs loads the jobs saved in the history database. Jobs that were
+// queued, downloading or paused are queued again; their downloads continue
+// from the partial files they left behind.
+func (jq *JobQueue) restoreJobs() {
+	if jq.historyDB == nil {
+		return
+	}
+
+	jobs, err := jq.historyDB.LoadJobs()
+	if err != nil {
+		log.Printf("Warning: failed to restore jobs: %v", err)
+		return
+	}
+	paused, err := jq.historyDB.QueuePaused()
+	if err != nil {
+		log.Printf("Warning: failed to restore queue state: %v", err)
+	}
+
+	jq.mu.Lock()
+	defer jq.mu.Unlock()
+
+	jq.paused = paused
+	restored := 0
+	for _, job := range jobs {
+		job.ctx, job.cancel = context.WithCancel(context.Background())
+		if !job.Status.finished() && !job.held {
+			job.Status = JobStatusQueued
+			job.Error = ""
+			restored++
+		}
+		jq.jobs[job.ID] = job
+		jq.nextSeq = max(jq.nextSeq, job.seq+1)
+	}
+	if restored > 0 {
+		log.Printf("Restored %d unfinished job(s)", restored)
+	}
+	if jq.paused {
+		log.Printf("Job queue is paused; resume it with POST /api/queue/resume")
+	}
+}
+
 func (jq *JobQueue) worker() {
 	defer jq.wg.Done()
 
-	for job := range jq.queue {
-		jq.processJob(job)
+	for {
+		job, ctx := jq.nextJob()
+		if job == nil {
+			return
+		}
+		jq.processJob(job, ctx)
 	}
 }
 
-func (jq *JobQueue) processJob(job *Job) {
-	jq.updateJobStatus(job.ID, JobStatusDownloading, 0, "")
+// nextJob waits for the queued job that runs first and marks it downloading.
+// It returns the job with the context of this run, or nil once the queue is
+// stopped.
+func (jq *JobQueue) nextJob() (*Job, context.Context) {
+	jq.mu.Lock()
+	defer jq.mu.Unlock()
+
+	for !jq.stopped {
+		if job := jq.firstRunnableJob(); job != nil && !jq.paused {
+			job.Status = JobStatusDownloading
+			job.running = true
+			job.UpdatedAt = time.Now()
+			jq.saveJob(job)
+			return job, job.ctx
+		}
+		jq.ready.Wait()
+	}
+	return nil, nil
+}
+
+// runsBefore reports whether queued job a runs before b: higher priority
+// first, then in queue order
+func runsBefore(a, b *Job) bool {
+	if a.Priority != b.Priority {
+		return a.Priority > b.Priority
+	}
+	if a.seq != b.seq {
+		return a.seq < b.seq
+	}
+	return a.CreatedAt.Before(b.CreatedAt)
+}
+
+// queuedJobs returns the queued jobs in the order they run. Must hold jq.mu.
+func (jq *JobQueue) queuedJobs() []*Job {
+	var queue []*Job
+	for _, job := range jq.jobs {
+		if job.Status == JobStatusQueued {
+			queue = append(queue, job)
+		}
+	}
+	slices.SortFunc(queue, func(a, b *Job) int {
+		if runsBefore(a, b) {
+			return -1
+		}
+		if runsBefore(b, a) {
+			return 1
+		}
+		return 0
+	})
+	return queue
+}
+
+// firstRunnableJob returns the queued job that runs first, skipping jobs
+// whose previous run is still stopping. Must hold jq.mu.
+func (jq *JobQueue) firstRunnableJob() *Job {
+	var first *Job
+	for _, job := range jq.jobs {
+		if job.Status == JobStatusQueued && !job.running && (first == nil || runsBefore(job, first)) {
+			first = job
+		}
+	}
+	return first
+}
+
+func (jq *JobQueue) processJob(job *Job, ctx context.Context) {
+	defer jq.endRun(job)
 
 	// Create progress callback
 	progressFn := func(downloaded, total int64) {
@@ -123,10 +309,20 @@ func (jq *JobQueue) processJob(job *Job) {
 	}
 
 	// Execute download
-	err := jq.downloadFn(job.ctx, job.URL, job.Filename, progressFn)
+	err := jq.downloadFn(ctx, job.URL, job.opts, progressFn)
+
+	if err != nil && jq.interrupted(job) {
+		return
+	}
+
+	var retry *RetryAfterError
+	if errors.As(err, &retry) && ctx.Err() == nil {
+		jq.pauseJob(job, ctx, retry)
+		return
+	}
 
 	if err != nil {
-		if job.ctx.Err() == context.Canceled {
+		if ctx.Err() == context.Canceled {
 			jq.updateJobStatus(job.ID, JobStatusCancelled, 0, "cancelled by user")
 		} else {
 			jq.updateJobStatus(job.ID, JobStatusFailed, 0, err.Error())
@@ -137,6 +333,100 @@ func (jq *JobQueue) processJob(job *Job) {
 
 	jq.updateJobStatus(job.ID, JobStatusCompleted, 100, "")
 	jq.recordJobToHistory(job.ID)
+	if jq.onComplete != nil {
+		jq.onComplete(job.opts)
+	}
+}
+
+// endRun marks a job's run over, so it can run again
+func (jq *JobQueue) endRun(job *Job) {
+	jq.mu.Lock()
+	job.running = false
+	jq.mu.Unlock()
+	jq.ready.Signal()
+}
+
+// interrupted reports whether a failed download was stopped by PauseJob,
+// PauseAll or Stop rather than failing. The job keeps its partial file and
+// runs again later; one interrupted by Stop is saved as queued, so it runs
+// after a restart.
+func (jq *JobQueue) interrupted(job *Job) bool {
+	jq.mu.Lock()
+	defer jq.mu.Unlock()
+
+	switch job.Status {
+	case JobStatusPaused, JobStatusQueued:
+		return true
+	case JobStatusDownloading:
+		if jq.stopped {
+			job.Status = JobStatusQueued
+			job.UpdatedAt = time.Now()
+			jq.saveJob(job)
+			return true
+		}
+	}
+	return false
+}
+
+// pauseJob marks a job paused until retry.Wait has passed, then queues it again
+func (jq *JobQueue) pauseJob(job *Job, ctx context.Context, retry *RetryAfterError) {
+	resumeAt := time.Now().Add(retry.Wait)
+	jq.mu.Lock()
+	if j, ok := jq.jobs[job.ID]; ok {
+		j.Status = JobStatusPaused
+		j.Error = fmt.Sprintf("%v; resuming at %s", retry.Err, resumeAt.Format("15:04:05"))
+		j.UpdatedAt = time.Now()
+		jq.saveJob(j)
+	}
+	jq.mu.Unlock()
+
+	go func() {
+		timer := time.NewTimer(retry.Wait)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+			jq.requeueJob(job)
+		case <-ctx.Done():
+		case <-jq.stopCleanup:
+		}
+	}()
+}
+
+// requeueJob puts a paused job back in the queue
+func (jq *JobQueue) requeueJob(job *Job) {
+	jq.mu.Lock()
+	defer jq.mu.Unlock()
+
+	j, ok := jq.jobs[job.ID]
+	if !ok || j.Status != JobStatusPaused || j.held || jq.stopped {
+		return
+	}
+	j.Status = JobStatusQueued
+	j.Error = ""
+	j.UpdatedAt = time.Now()
+	jq.saveJob(j)
+	jq.ready.Signal()
+}
+
+// saveJob saves a job to the history database, if there is one. Must hold jq.mu.
+func (jq *JobQueue) saveJob(job *Job) {
+	if jq.historyDB == nil {
+		return
+	}
+	job.savedAt = time.Now()
+	if err := jq.historyDB.SaveJob(job); err != nil {
+		log.Printf("Warning: failed to save job %s: %v", job.ID, err)
+	}
+}
+
+// deleteSavedJobs removes jobs from the history database, if there is one
+func (jq *JobQueue) deleteSavedJobs(ids ...string) {
+	if jq.historyDB == nil || len(ids) == 0 {
+		return
+	}
+	if err := jq.historyDB.DeleteJobs(ids...); err != nil {
+		log.Printf("Warning: failed to delete saved jobs: %v", err)
+	}
 }
 
 // recordJobToHistory saves a completed/failed job to the history database
@@ -180,13 +470,16 @@ func (jq *JobQueue) cleanupOldJobs() {
 	defer jq.mu.Unlock()
 
 	cutoff := time.Now().Add(-1 * time.Hour)
+	var removed []string
 	for id, job := range jq.jobs {
 		// Only cleanup completed, failed, or cancelled jobs older than 1 hour
 		if (job.Status == JobStatusCompleted || job.Status == JobStatusFailed || job.Status == JobStatusCancelled) &&
 			job.UpdatedAt.Before(cutoff) {
 			delete(jq.jobs, id)
+			removed = append(removed, id)
 		}
 	}
+	jq.deleteSavedJobs(removed...)
 }
 
 // ClearHistory removes all completed, failed, and cancelled jobs
@@ -194,14 +487,15 @@ func (jq *JobQueue) ClearHistory() int {
 	jq.mu.Lock()
 	defer jq.mu.Unlock()
 
-	count := 0
+	var removed []string
 	for id, job := range jq.jobs {
 		if job.Status == JobStatusCompleted || job.Status == JobStatusFailed || job.Status == JobStatusCancelled {
 			delete(jq.jobs, id)
-			count++
+			removed = append(removed, id)
 		}
 	}
-	return count
+	jq.deleteSavedJobs(removed...)
+	return len(removed)
 }
 
 // RemoveJob removes a single completed, failed, or cancelled job by ID
@@ -220,6 +514,7 @@ func (jq *JobQueue) RemoveJob(id string) bool {
 	}
 
 	delete(jq.jobs, id)
+	jq.deleteSavedJobs(id)
 	return true
 }
 
@@ -238,7 +533,10 @@ func (jq *JobQueue) AddFailedJob(rawURL, errorMsg string) *Job {
 	}
 
 	jq.mu.Lock()
+	job.seq = jq.nextSeq
+	jq.nextSeq++
 	jq.jobs[id] = job
+	jq.saveJob(job)
 	jq.mu.Unlock()
 
 	return job
@@ -246,6 +544,11 @@ func (jq *JobQueue) AddFailedJob(rawURL, errorMsg string) *Job {
 
 // AddJob creates and queues a new download job
 func (jq *JobQueue) AddJob(rawURL, filename string) (*Job, error) {
+	return jq.AddJobWithOptions(rawURL, JobOptions{Filename: filename})
+}
+
+// AddJobWithOptions creates and queues a new download job with per-job settings
+func (jq *JobQueue) AddJobWithOptions(rawURL string, opts JobOptions) (*Job, error) {
 	// Normalize URL: add https:// if missing
 	url, err := extractor.NormalizeURL(rawURL)
 	if err != nil {
@@ -262,31 +565,34 @@ func (jq *JobQueue) AddJob(rawURL, filename string) (*Job, error) {
 	job := &Job{
 		ID:        id,
 		URL:       url,
-		Filename:  filename,
+		Filename:  opts.Filename,
+		Folder:    opts.Folder,
+		Quality:   opts.Quality,
+		Priority:  opts.Priority,
 		Status:    JobStatusQueued,
 		Progress:  0,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 		ctx:       ctx,
 		cancel:    cancel,
+		opts:      opts,
 	}
 
 	jq.mu.Lock()
-	jq.jobs[id] = job
-	jq.mu.Unlock()
+	defer jq.mu.Unlock()
 
-	// Queue the job (non-blocking with buffered channel)
-	select {
-	case jq.queue <- job:
-		return job, nil
-	default:
-		// Queue is full
-		jq.mu.Lock()
-		delete(jq.jobs, id)
-		jq.mu.Unlock()
+	// Subscriptions may still add jobs while the server shuts down
+	if jq.stopped {
 		cancel()
-		return nil, fmt.Errorf("job queue is full")
+		return nil, fmt.Errorf("job queue is stopped")
 	}
+
+	job.seq = jq.nextSeq
+	jq.nextSeq++
+	jq.jobs[id] = job
+	jq.saveJob(job)
+	jq.ready.Signal()
+	return job, nil
 }
 
 // GetJob returns a job by ID
@@ -302,7 +608,7 @@ func (jq *JobQueue) GetJob(id string) *Job {
 	return nil
 }
 
-// GetAllJobs returns all jobs
+// GetAllJobs returns all jobs, queued ones in the order they run
 func (jq *JobQueue) GetAllJobs() []*Job {
 	jq.mu.RLock()
 	defer jq.mu.RUnlock()
@@ -312,6 +618,15 @@ func (jq *JobQueue) GetAllJobs() []*Job {
 		jobCopy := *job
 		jobs = append(jobs, &jobCopy)
 	}
+	slices.SortFunc(jobs, func(a, b *Job) int {
+		if runsBefore(a, b) {
+			return -1
+		}
+		if runsBefore(b, a) {
+			return 1
+		}
+		return 0
+	})
 	return jobs
 }
 
@@ -325,17 +640,211 @@ func (jq *JobQueue) CancelJob(id string) bool {
 		return false
 	}
 
-	// Can only cancel queued or downloading jobs
-	if job.Status != JobStatusQueued && job.Status != JobStatusDownloading {
+	// Can only cancel queued, downloading or paused jobs
+	if job.Status != JobStatusQueued && job.Status != JobStatusDownloading && job.Status != JobStatusPaused {
 		return false
 	}
 
 	job.cancel()
 	job.Status = JobStatusCancelled
 	job.UpdatedAt = time.Now()
+	jq.saveJob(job)
 	return true
 }
 
+// PauseJob pauses a queued, downloading or waiting job until ResumeJob. A
+// running download is stopped; its partial file is kept and continued when
+// the job is resumed.
+func (jq *JobQueue) PauseJob(id string) bool {
+	jq.mu.Lock()
+	defer jq.mu.Unlock()
+
+	job, ok := jq.jobs[id]
+	if !ok || job.Status.finished() || job.held {
+		return false
+	}
+
+	if job.Status == JobStatusDownloading {
+		job.cancel()
+	}
+	job.Status = JobStatusPaused
+	job.held = true
+	job.Error = ""
+	job.UpdatedAt = time.Now()
+	jq.saveJob(job)
+	return true
+}
+
+// ResumeJob queues a paused job again, in its old place in the queue. A job
+// waiting to be retried is retried right away.
+func (jq *JobQueue) ResumeJob(id string) bool {
+	jq.mu.Lock()
+	defer jq.mu.Unlock()
+
+	job, ok := jq.jobs[id]
+	if !ok || job.Status != JobStatusPaused {
+		return false
+	}
+
+	// The old context may be cancelled, and stops a pending retry timer
+	job.cancel()
+	job.ctx, job.cancel = context.WithCancel(context.Background())
+	job.Status = JobStatusQueued
+	job.held = false
+	job.Error = ""
+	job.UpdatedAt = time.Now()
+	jq.saveJob(job)
+	jq.ready.Signal()
+	return true
+}
+
+// PauseAll stops the queue: no downloads start until ResumeAll, and running
+// ones are stopped and queued again, keeping their partial files. The switch
+// is saved, so the queue stays paused after a restart.
+func (jq *JobQueue) PauseAll() {
+	jq.mu.Lock()
+	defer jq.mu.Unlock()
+
+	jq.paused = true
+	for _, job := range jq.jobs {
+		if job.Status == JobStatusDownloading {
+			job.cancel()
+			job.ctx, job.cancel = context.WithCancel(context.Background())
+			job.Status = JobStatusQueued
+			job.UpdatedAt = time.Now()
+			jq.saveJob(job)
+		}
+	}
+	jq.saveQueuePaused()
+}
+
+// ResumeAll starts the queue again after PauseAll. Jobs paused one by one
+// with PauseJob stay paused.
+func (jq *JobQueue) ResumeAll() {
+	jq.mu.Lock()
+	defer jq.mu.Unlock()
+
+	jq.paused = false
+	jq.saveQueuePaused()
+	jq.ready.Broadcast()
+}
+
+// Paused reports whether the queue is paused with PauseAll
+func (jq *JobQueue) Paused() bool {
+	jq.mu.RLock()
+	defer jq.mu.RUnlock()
+	return jq.paused
+}
+
+// saveQueuePaused saves the PauseAll switch, if there is a history database.
+// Must hold jq.mu.
+func (jq *JobQueue) saveQueuePaused() {
+	if jq.historyDB == nil {
+		return
+	}
+	if err := jq.historyDB.SetQueuePaused(jq.paused); err != nil {
+		log.Printf("Warning: failed to save queue state: %v", err)
+	}
+}
+
+// MoveJob moves a queued job to the top or bottom of the queue, or one place
+// up or down. A job moved past one of another priority takes that priority.
+// It returns the job's new position, counting from 1.
+func (jq *JobQueue) MoveJob(id, to string) (int, error) {
+	jq.mu.Lock()
+	defer jq.mu.Unlock()
+
+	job, ok := jq.jobs[id]
+	if !ok {
+		return 0, ErrJobNotFound
+	}
+	if job.Status != JobStatusQueued {
+		return 0, ErrJobNotQueued
+	}
+
+	queue := jq.queuedJobs()
+	from := slices.Index(queue, job)
+	var target int
+	switch to {
+	case "top":
+		target = 0
+	case "up":
+		target = max(from-1, 0)
+	case "down":
+		target = min(from+1, len(queue)-1)
+	case "bottom":
+		target = len(queue) - 1
+	default:
+		return 0, fmt.Errorf("invalid move %q: use top, up, down or bottom", to)
+	}
+	if target == from {
+		return from + 1, nil
+	}
+
+	other := queue[target]
+	job.Priority = other.Priority
+	job.UpdatedAt = time.Now()
+	switch to {
+	case "top":
+		job.seq = other.seq - 1
+	case "bottom":
+		job.seq = jq.nextSeq
+		jq.nextSeq++
+	default:
+		// Renumber the jobs of the neighbour's priority in their new order,
+		// reusing their positions and the moved job's
+		queue = slices.Insert(slices.Delete(queue, from, from+1), target, job)
+		var group []*Job
+		var seqs []int64
+		for _, j := range queue {
+			if j.Priority == job.Priority {
+				group = append(group, j)
+				seqs = append(seqs, j.seq)
+			}
+		}
+		slices.Sort(seqs)
+		for i, j := range group {
+			if j.seq != seqs[i] {
+				j.seq = seqs[i]
+				if j != job {
+					jq.saveJob(j)
+				}
+			}
+		}
+	}
+	jq.saveJob(job)
+	return target + 1, nil
+}
+
+// SubscriptionItemsPending returns the items of a subscription whose jobs have
+// not finished yet
+func (jq *JobQueue) SubscriptionItemsPending(subscriptionID string) map[string]bool {
+	jq.mu.RLock()
+	defer jq.mu.RUnlock()
+
+	pending := make(map[string]bool)
+	for _, job := range jq.jobs {
+		if job.opts.Subscription == subscriptionID && !job.Status.finished() {
+			pending[job.opts.SubscriptionItem] = true
+		}
+	}
+	return pending
+}
+
+// setJobFilename records where the running job for url saved its output
+func (jq *JobQueue) setJobFilename(url, filename string) {
+	jq.mu.Lock()
+	defer jq.mu.Unlock()
+
+	for _, job := range jq.jobs {
+		if job.URL == url && job.Status == JobStatusDownloading {
+			job.Filename = filename
+			jq.saveJob(job)
+			return
+		}
+	}
+}
+
 func (jq *JobQueue) updateJobStatus(id string, status JobStatus, progress float64, errMsg string) {
 	jq.mu.Lock()
 	defer jq.mu.Unlock()
@@ -349,6 +858,7 @@ func (jq *JobQueue) updateJobStatus(id string, status JobStatus, progress float6
 			job.Error = errMsg
 		}
 		job.UpdatedAt = time.Now()
+		jq.saveJob(job)
 	}
 }
 
@@ -363,6 +873,9 @@ func (jq *JobQueue) updateJobProgressBytes(id string, downloaded, total int64) {
 			job.Progress = float64(downloaded) / float64(total) * 100
 		}
 		job.UpdatedAt = time.Now()
+		if time.Since(job.savedAt) >= progressSaveInterval {
+			jq.saveJob(job)
+		}
 	}
 }
 
@@ -0,0 +1,403 @@
+package server
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeDownloads is a DownloadFunc whose runs block until their context is
+// cancelled or the test lets them finish
+type fakeDownloads struct {
+	mu      sync.Mutex
+	runs    []string      // URLs in the order their runs started
+	running int           // runs in progress
+	overlap bool          // set if two runs were in progress at once
+	started chan string   // receives the URL of each run as it starts
+	finish  chan struct{} // each receive lets one run complete
+	stopped chan struct{} // interrupted runs wait for a receive before returning
+}
+
+func newFakeDownloads() *fakeDownloads {
+	return &fakeDownloads{
+		started: make(chan string, 16),
+		finish:  make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+}
+
+func (f *fakeDownloads) download(ctx context.Context, url string, opts JobOptions, progressFn func(downloaded, total int64)) error {
+	f.mu.Lock()
+	f.runs = append(f.runs, url)
+	f.running++
+	if f.running > 1 {
+		f.overlap = true
+	}
+	f.mu.Unlock()
+	defer func() {
+		f.mu.Lock()
+		f.running--
+		f.mu.Unlock()
+	}()
+
+	f.started <- url
+	select {
+	case <-f.finish:
+		return nil
+	case <-ctx.Done():
+		<-f.stopped
+		return ctx.Err()
+	}
+}
+
+func (f *fakeDownloads) startedRuns() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.runs...)
+}
+
+// waitStarted waits for the next run to start and returns its URL
+func (f *fakeDownloads) waitStarted(t *testing.T) string {
+	t.Helper()
+	select {
+	case url := <-f.started:
+		return url
+	case <-time.After(5 * time.Second):
+		t.Fatal("no download started")
+		return ""
+	}
+}
+
+// expectNoStart fails if a run starts within a short while
+func (f *fakeDownloads) expectNoStart(t *testing.T) {
+	t.Helper()
+	select {
+	case url := <-f.started:
+		t.Fatalf("download of %s started unexpectedly", url)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+// waitStatus waits for a job to reach status
+func waitStatus(t *testing.T, jq *JobQueue, id string, status JobStatus) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		job := jq.GetJob(id)
+		if job != nil && job.Status == status {
+			return
+		}
+		if job == nil {
+			t.Fatalf("job %s not found", id)
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("job %s: status %s, want %s", id, job.Status, status)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func addJob(t *testing.T, jq *JobQueue, url string, opts JobOptions) *Job {
+	t.Helper()
+	job, err := jq.AddJobWithOptions(url, opts)
+	if err != nil {
+		t.Fatalf("AddJobWithOptions(%s): %v", url, err)
+	}
+	return job
+}
+
+// queueOrder returns the URLs of the queued jobs in the order they run
+func queueOrder(jq *JobQueue) []string {
+	var urls []string
+	for _, job := range jq.GetAllJobs() {
+		if job.Status == JobStatusQueued {
+			urls = append(urls, job.URL)
+		}
+	}
+	return urls
+}
+
+func equalURLs(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestJobQueueRunsByPriority(t *testing.T) {
+	fake := newFakeDownloads()
+	jq := NewJobQueue(1, t.TempDir(), fake.download)
+	jq.PauseAll()
+	jq.Start()
+	defer jq.Stop()
+	defer close(fake.stopped)
+
+	a := addJob(t, jq, "https://example.com/a", JobOptions{})
+	b := addJob(t, jq, "https://example.com/b", JobOptions{})
+	c := addJob(t, jq, "https://example.com/c", JobOptions{Priority: 5})
+	d := addJob(t, jq, "https://example.com/d", JobOptions{Priority: -1})
+	fake.expectNoStart(t)
+
+	jq.ResumeAll()
+	for _, job := range []*Job{c, a, b, d} {
+		if url := fake.waitStarted(t); url != job.URL {
+			t.Fatalf("started %s, want %s", url, job.URL)
+		}
+		fake.finish <- struct{}{}
+		waitStatus(t, jq, job.ID, JobStatusCompleted)
+	}
+	if fake.overlap {
+		t.Error("downloads overlapped with one worker")
+	}
+}
+
+func TestJobQueueMoveJob(t *testing.T) {
+	jq := NewJobQueue(1, t.TempDir(), newFakeDownloads().download)
+
+	a := addJob(t, jq, "https://example.com/a", JobOptions{})
+	b := addJob(t, jq, "https://example.com/b", JobOptions{})
+	c := addJob(t, jq, "https://example.com/c", JobOptions{Priority: 5})
+	d := addJob(t, jq, "https://example.com/d", JobOptions{})
+
+	if got, want := queueOrder(jq), []string{c.URL, a.URL, b.URL, d.URL}; !equalURLs(got, want) {
+		t.Fatalf("initial order = %v, want %v", got, want)
+	}
+
+	tests := []struct {
+		id       string
+		to       string
+		position int
+		priority int
+		order    []string
+	}{
+		// Within a priority: d swaps places with b
+		{d.ID, "up", 3, 0, []string{c.URL, a.URL, d.URL, b.URL}},
+		// Past a job of another priority: a takes priority 5
+		{a.ID, "top", 1, 5, []string{a.URL, c.URL, d.URL, b.URL}},
+		// c drops to priority 0, ahead of d's old place
+		{c.ID, "down", 3, 0, []string{a.URL, d.URL, c.URL, b.URL}},
+		{c.ID, "down", 4, 0, []string{a.URL, d.URL, b.URL, c.URL}},
+		{c.ID, "down", 4, 0, []string{a.URL, d.URL, b.URL, c.URL}},
+		{a.ID, "bottom", 4, 0, []string{d.URL, b.URL, c.URL, a.URL}},
+		{b.ID, "up", 1, 0, []string{b.URL, d.URL, c.URL, a.URL}},
+	}
+	for _, tt := range tests {
+		position, err := jq.MoveJob(tt.id, tt.to)
+		if err != nil {
+			t.Fatalf("MoveJob(%s, %s): %v", tt.id, tt.to, err)
+		}
+		if position != tt.position {
+			t.Errorf("MoveJob(%s, %s) = %d, want %d", tt.id, tt.to, position, tt.position)
+		}
+		if got := jq.GetJob(tt.id).Priority; got != tt.priority {
+			t.Errorf("after MoveJob(%s, %s): priority = %d, want %d", tt.id, tt.to, got, tt.priority)
+		}
+		if got := queueOrder(jq); !equalURLs(got, tt.order) {
+			t.Fatalf("after MoveJob(%s, %s): order = %v, want %v", tt.id, tt.to, got, tt.order)
+		}
+	}
+
+	// Renumbering reuses the positions of the jobs moved, so the queue keeps
+	// distinct positions and new jobs still go last
+	seen := make(map[int64]bool)
+	for _, job := range jq.jobs {
+		if seen[job.seq] {
+			t.Errorf("two jobs at position %d", job.seq)
+		}
+		seen[job.seq] = true
+	}
+	e := addJob(t, jq, "https://example.com/e", JobOptions{})
+	if order := queueOrder(jq); order[len(order)-1] != e.URL {
+		t.Errorf("new job not last: %v", order)
+	}
+
+	if _, err := jq.MoveJob("missing", "top"); err != ErrJobNotFound {
+		t.Errorf("MoveJob(missing) error = %v, want %v", err, ErrJobNotFound)
+	}
+	if _, err := jq.MoveJob(a.ID, "sideways"); err == nil {
+		t.Error("MoveJob(sideways) succeeded")
+	}
+	jq.PauseJob(b.ID)
+	if _, err := jq.MoveJob(b.ID, "top"); err != ErrJobNotQueued {
+		t.Errorf("MoveJob(paused) error = %v, want %v", err, ErrJobNotQueued)
+	}
+}
+
+func TestJobQueuePauseResumeRunningJob(t *testing.T) {
+	fake := newFakeDownloads()
+	jq := NewJobQueue(1, t.TempDir(), fake.download)
+	jq.Start()
+	defer jq.Stop()
+
+	job := addJob(t, jq, "https://example.com/a", JobOptions{})
+	fake.waitStarted(t)
+
+	if !jq.PauseJob(job.ID) {
+		t.Fatal("PauseJob failed")
+	}
+	waitStatus(t, jq, job.ID, JobStatusPaused)
+	if jq.PauseJob(job.ID) {
+		t.Error("PauseJob of a paused job succeeded")
+	}
+
+	// Resumed while its first run is still stopping: the job is queued, but
+	// must not run again until that run returns
+	if !jq.ResumeJob(job.ID) {
+		t.Fatal("ResumeJob failed")
+	}
+	waitStatus(t, jq, job.ID, JobStatusQueued)
+	fake.expectNoStart(t)
+
+	fake.stopped <- struct{}{}
+	fake.waitStarted(t)
+	waitStatus(t, jq, job.ID, JobStatusDownloading)
+	fake.finish <- struct{}{}
+	waitStatus(t, jq, job.ID, JobStatusCompleted)
+
+	if runs := fake.startedRuns(); len(runs) != 2 {
+		t.Errorf("ran %d times, want 2", len(runs))
+	}
+	if fake.overlap {
+		t.Error("runs of the paused job overlapped")
+	}
+	if jq.ResumeJob(job.ID) {
+		t.Error("ResumeJob of a completed job succeeded")
+	}
+}
+
+func TestJobQueuePauseAll(t *testing.T) {
+	fake := newFakeDownloads()
+	jq := NewJobQueue(1, t.TempDir(), fake.download)
+	jq.Start()
+	defer jq.Stop()
+
+	a := addJob(t, jq, "https://example.com/a", JobOptions{})
+	fake.waitStarted(t)
+	b := addJob(t, jq, "https://example.com/b", JobOptions{})
+	held := addJob(t, jq, "https://example.com/held", JobOptions{Priority: 1})
+	jq.PauseJob(held.ID)
+
+	jq.PauseAll()
+	if !jq.Paused() {
+		t.Fatal("queue not paused")
+	}
+	// The running download is stopped and queued again, ahead of b
+	waitStatus(t, jq, a.ID, JobStatusQueued)
+	fake.stopped <- struct{}{}
+	fake.expectNoStart(t)
+	if got, want := queueOrder(jq), []string{a.URL, b.URL}; !equalURLs(got, want) {
+		t.Fatalf("order = %v, want %v", got, want)
+	}
+
+	jq.ResumeAll()
+	for _, job := range []*Job{a, b} {
+		if url := fake.waitStarted(t); url != job.URL {
+			t.Fatalf("started %s, want %s", url, job.URL)
+		}
+		fake.finish <- struct{}{}
+		waitStatus(t, jq, job.ID, JobStatusCompleted)
+	}
+
+	// ResumeAll leaves jobs paused one by one alone
+	fake.expectNoStart(t)
+	if got := jq.GetJob(held.ID).Status; got != JobStatusPaused {
+		t.Errorf("held job status = %s, want %s", got, JobStatusPaused)
+	}
+}
+
+func TestJobQueueRestore(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", home)
+
+	db, err := NewHistoryDB()
+	if err != nil {
+		t.Fatalf("NewHistoryDB: %v", err)
+	}
+	defer db.Close()
+
+	// The first queue never starts its workers; the jobs are only saved
+	jq := NewJobQueue(1, t.TempDir(), newFakeDownloads().download)
+	jq.SetHistoryDB(db)
+
+	a := addJob(t, jq, "https://example.com/a", JobOptions{Folder: "shows", Quality: "720p"})
+	b := addJob(t, jq, "https://example.com/b", JobOptions{Priority: 2, Subscription: "sub", SubscriptionItem: "item"})
+	c := addJob(t, jq, "https://example.com/c", JobOptions{Filename: "c.mp4"})
+	held := addJob(t, jq, "https://example.com/held", JobOptions{})
+	done := addJob(t, jq, "https://example.com/done", JobOptions{})
+	if _, err := jq.MoveJob(c.ID, "top"); err != nil {
+		t.Fatalf("MoveJob: %v", err)
+	}
+	jq.PauseJob(held.ID)
+	jq.PauseAll()
+
+	// A download cut off by a crash, and a finished one
+	jq.mu.Lock()
+	jq.jobs[a.ID].Status = JobStatusDownloading
+	jq.saveJob(jq.jobs[a.ID])
+	jq.jobs[done.ID].Status = JobStatusCompleted
+	jq.saveJob(jq.jobs[done.ID])
+	jq.mu.Unlock()
+
+	fake := newFakeDownloads()
+	restored := NewJobQueue(1, t.TempDir(), fake.download)
+	restored.SetHistoryDB(db)
+	restored.Start()
+	defer restored.Stop()
+	defer close(fake.stopped)
+
+	if !restored.Paused() {
+		t.Error("paused queue not restored as paused")
+	}
+	if got, want := queueOrder(restored), []string{c.URL, b.URL, a.URL}; !equalURLs(got, want) {
+		t.Errorf("restored order = %v, want %v", got, want)
+	}
+	for id, status := range map[string]JobStatus{
+		a.ID:    JobStatusQueued,
+		held.ID: JobStatusPaused,
+		done.ID: JobStatusCompleted,
+	} {
+		if got := restored.GetJob(id).Status; got != status {
+			t.Errorf("job %s status = %s, want %s", id, got, status)
+		}
+	}
+
+	restored.mu.RLock()
+	optsA, optsB, optsC := restored.jobs[a.ID].opts, restored.jobs[b.ID].opts, restored.jobs[c.ID].opts
+	restored.mu.RUnlock()
+	if optsA.Folder != "shows" || optsA.Quality != "720p" {
+		t.Errorf("job a options = %+v", optsA)
+	}
+	if optsB.Priority != 2 || optsB.Subscription != "sub" || optsB.SubscriptionItem != "item" {
+		t.Errorf("job b options = %+v", optsB)
+	}
+	if optsC.Filename != "c.mp4" {
+		t.Errorf("job c options = %+v", optsC)
+	}
+	if pending := restored.SubscriptionItemsPending("sub"); !pending["item"] {
+		t.Errorf("subscription items pending = %v", pending)
+	}
+
+	// New jobs go after the restored ones
+	e := addJob(t, restored, "https://example.com/e", JobOptions{})
+	if order := queueOrder(restored); order[len(order)-1] != e.URL {
+		t.Errorf("new job not last: %v", order)
+	}
+
+	// A held job stays paused through a restart and ResumeAll
+	restored.ResumeAll()
+	for _, job := range []*Job{c, b, a, e} {
+		if url := fake.waitStarted(t); url != job.URL {
+			t.Fatalf("started %s, want %s", url, job.URL)
+		}
+		fake.finish <- struct{}{}
+		waitStatus(t, restored, job.ID, JobStatusCompleted)
+	}
+	fake.expectNoStart(t)
+}
@@ -2,6 +2,7 @@ package server
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"io/fs"
@@ -17,7 +18,9 @@ import (
 	"github.com/guiyumin/vget/internal/core/config"
 	"github.com/guiyumin/vget/internal/core/downloader"
 	"github.com/guiyumin/vget/internal/core/extractor"
+	"github.com/guiyumin/vget/internal/core/extractor/telegram"
 	"github.com/guiyumin/vget/internal/core/i18n"
+	"github.com/guiyumin/vget/internal/core/subscription"
 	"github.com/guiyumin/vget/internal/core/tracker"
 	"github.com/guiyumin/vget/internal/core/version"
 	"github.com/guiyumin/vget/internal/core/webdav"
@@ -35,24 +38,41 @@ type Response struct {
 type DownloadRequest struct {
 	URL        string `json:"url" binding:"required"`
 	Filename   string `json:"filename,omitempty"`
+	Folder     string `json:"folder,omitempty"`   // subdirectory of the output directory
+	Quality    string `json:"quality,omitempty"`  // preferred video quality, e.g. "1080p"
+	Priority   int    `json:"priority,omitempty"` // higher runs first
 	ReturnFile bool   `json:"return_file,omitempty"`
 }
 
 // BulkDownloadRequest is the request body for POST /bulk-download
 type BulkDownloadRequest struct {
-	URLs []string `json:"urls" binding:"required"`
+	URLs     []string `json:"urls" binding:"required"`
+	Priority int      `json:"priority,omitempty"`
+}
+
+// MoveJobRequest is the request body for POST /jobs/:id/move
+type MoveJobRequest struct {
+	To string `json:"to" binding:"required"` // top, up, down or bottom
 }
 
 // Server is the HTTP server for vget
 type Server struct {
-	port       int
-	outputDir  string
-	apiKey     string
+	port      int
+	outputDir string
+	apiKey    string
 	jobQueue  *JobQueue
 	historyDB *HistoryDB
-	cfg        *config.Config
-	server     *http.Server
-	engine     *gin.Engine
+	cfg       *config.Config
+	server    *http.Server
+	engine    *gin.Engine
+
+	tgLoginMu sync.Mutex
+	tgLogin   *telegram.LoginSession // Telegram login in progress, if any
+	tgClient  *telegram.SharedClient // connection shared by Telegram download jobs
+
+	subs    *subscription.Store // nil if the database could not be opened
+	subMu   sync.Mutex          // serializes recording and queuing check results
+	subStop chan struct{}
 }
 
 // NewServer creates a new HTTP server
@@ -64,6 +84,8 @@ func NewServer(port int, outputDir, apiKey string, maxConcurrent int) *Server {
 		outputDir: outputDir,
 		apiKey:    apiKey,
 		cfg:       cfg,
+		tgClient:  telegram.NewSharedClient(),
+		subStop:   make(chan struct{}),
 	}
 
 	// Create job queue with download function
@@ -78,6 +100,15 @@ func NewServer(port int, outputDir, apiKey string, maxConcurrent int) *Server {
 		s.jobQueue.SetHistoryDB(historyDB)
 	}
 
+	// Subscriptions live in the same database as the history
+	subs, err := subscription.OpenDefault()
+	if err != nil {
+		log.Printf("Warning: failed to open subscriptions: %v", err)
+	} else {
+		s.subs = subs
+		s.jobQueue.OnComplete(s.markSubscriptionItemSeen)
+	}
+
 	return s
 }
 
@@ -102,6 +133,11 @@ func (s *Server) Start() error {
 	// Start job queue workers
 	s.jobQueue.Start()
 
+	// Check subscriptions for new items as they become due
+	if s.subs != nil {
+		go s.runSubscriptions(s.subStop)
+	}
+
 	// Set Gin mode
 	gin.SetMode(gin.ReleaseMode)
 
@@ -130,12 +166,23 @@ func (s *Server) Start() error {
 	api.GET("/jobs", s.handleGetJobs)
 	api.DELETE("/jobs", s.handleClearJobs)
 	api.DELETE("/jobs/:id", s.handleDeleteJob)
+	api.POST("/jobs/:id/pause", s.handlePauseJob)
+	api.POST("/jobs/:id/resume", s.handleResumeJob)
+	api.POST("/jobs/:id/move", s.handleMoveJob)
+	api.POST("/queue/pause", s.handlePauseQueue)
+	api.POST("/queue/resume", s.handleResumeQueue)
 
 	// History routes
 	api.GET("/history", s.handleGetHistory)
 	api.DELETE("/history", s.handleClearHistory)
 	api.DELETE("/history/:id", s.handleDeleteHistory)
 
+	// Subscription routes
+	api.GET("/subscriptions", s.handleGetSubscriptions)
+	api.POST("/subscriptions", s.handleAddSubscription)
+	api.DELETE("/subscriptions/:id", s.handleDeleteSubscription)
+	api.POST("/subscriptions/:id/check", s.handleCheckSubscription)
+
 	api.GET("/config", s.handleGetConfig)
 	api.POST("/config", s.handleSetConfig)
 	api.PUT("/config", s.handleUpdateConfig)
@@ -143,6 +190,8 @@ func (s *Server) Start() error {
 	api.POST("/config/webdav", s.handleAddWebDAV)
 	api.DELETE("/config/webdav/:name", s.handleDeleteWebDAV)
 	api.GET("/i18n", s.handleI18n)
+	api.GET("/extractors", s.handleGetExtractors)
+	api.GET("/extractors/match", s.handleMatchExtractor)
 	api.POST("/kuaidi100", s.handleKuaidi100)
 
 	// WebDAV browsing routes
@@ -166,6 +215,15 @@ func (s *Server) Start() error {
 	api.GET("/bilibili/qr/poll", s.handleBilibiliQRPoll)
 	api.GET("/bilibili/status", s.handleBilibiliStatus)
 
+	// Telegram login routes
+	api.POST("/telegram/login/phone", s.handleTelegramPhoneLogin)
+	api.POST("/telegram/login/qr", s.handleTelegramQRLogin)
+	api.GET("/telegram/login/status", s.handleTelegramLoginStatus)
+	api.POST("/telegram/login/code", s.handleTelegramLoginCode)
+	api.POST("/telegram/login/password", s.handleTelegramLoginPassword)
+	api.GET("/telegram/status", s.handleTelegramStatus)
+	api.POST("/telegram/logout", s.handleTelegramLogout)
+
 	// Serve embedded UI if available
 	if distFS := GetDistFS(); distFS != nil {
 		s.setupStaticFiles(distFS)
@@ -191,10 +249,15 @@ func (s *Server) Start() error {
 
 // Stop gracefully shuts down the server
 func (s *Server) Stop(ctx context.Context) error {
+	close(s.subStop)
 	s.jobQueue.Stop()
+	s.tgClient.Close()
 	if s.historyDB != nil {
 		s.historyDB.Close()
 	}
+	if s.subs != nil {
+		s.subs.Close()
+	}
 	return s.server.Shutdown(ctx)
 }
 
@@ -326,7 +389,12 @@ func (s *Server) handleDownload(c *gin.Context) {
 	}
 
 	// Otherwise, queue the download
-	job, err := s.jobQueue.AddJob(req.URL, req.Filename)
+	job, err := s.jobQueue.AddJobWithOptions(req.URL, JobOptions{
+		Filename: req.Filename,
+		Folder:   req.Folder,
+		Quality:  req.Quality,
+		Priority: req.Priority,
+	})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, Response{
 			Code:    500,
@@ -377,7 +445,7 @@ func (s *Server) handleBulkDownload(c *gin.Context) {
 			continue
 		}
 
-		job, err := s.jobQueue.AddJob(url, "")
+		job, err := s.jobQueue.AddJobWithOptions(url, JobOptions{Priority: req.Priority})
 		if err != nil {
 			// Create a failed job so it shows in the UI
 			failedJob := s.jobQueue.AddFailedJob(url, err.Error())
@@ -449,13 +517,15 @@ func (s *Server) handleGetJobs(c *gin.Context) {
 			"total":      job.Total,
 			"filename":   job.Filename,
 			"error":      job.Error,
+			"priority":   job.Priority,
 		}
 	}
 
 	c.JSON(http.StatusOK, Response{
 		Code: 200,
 		Data: gin.H{
-			"jobs": jobList,
+			"jobs":   jobList,
+			"paused": s.jobQueue.Paused(),
 		},
 		Message: fmt.Sprintf("%d jobs found", len(jobs)),
 	})
@@ -503,6 +573,102 @@ func (s *Server) handleDeleteJob(c *gin.Context) {
 	}
 }
 
+func (s *Server) handlePauseJob(c *gin.Context) {
+	id := c.Param("id")
+
+	if !s.jobQueue.PauseJob(id) {
+		c.JSON(http.StatusNotFound, Response{
+			Code:    404,
+			Data:    nil,
+			Message: "job not found or cannot be paused",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Code:    200,
+		Data:    gin.H{"id": id},
+		Message: "job paused",
+	})
+}
+
+func (s *Server) handleResumeJob(c *gin.Context) {
+	id := c.Param("id")
+
+	if !s.jobQueue.ResumeJob(id) {
+		c.JSON(http.StatusNotFound, Response{
+			Code:    404,
+			Data:    nil,
+			Message: "job not found or not paused",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Code:    200,
+		Data:    gin.H{"id": id},
+		Message: "job resumed",
+	})
+}
+
+func (s *Server) handleMoveJob(c *gin.Context) {
+	id := c.Param("id")
+
+	var req MoveJobRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, Response{
+			Code:    400,
+			Data:    nil,
+			Message: "invalid request body: to is required",
+		})
+		return
+	}
+
+	position, err := s.jobQueue.MoveJob(id, req.To)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, ErrJobNotFound) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, Response{
+			Code:    status,
+			Data:    nil,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	data := gin.H{"id": id, "position": position}
+	if job := s.jobQueue.GetJob(id); job != nil {
+		data["priority"] = job.Priority
+	}
+	c.JSON(http.StatusOK, Response{
+		Code:    200,
+		Data:    data,
+		Message: fmt.Sprintf("job moved to position %d", position),
+	})
+}
+
+func (s *Server) handlePauseQueue(c *gin.Context) {
+	s.jobQueue.PauseAll()
+
+	c.JSON(http.StatusOK, Response{
+		Code:    200,
+		Data:    gin.H{"paused": true},
+		Message: "queue paused",
+	})
+}
+
+func (s *Server) handleResumeQueue(c *gin.Context) {
+	s.jobQueue.ResumeAll()
+
+	c.JSON(http.StatusOK, Response{
+		Code:    200,
+		Data:    gin.H{"paused": false},
+		Message: "queue resumed",
+	})
+}
+
 // ConfigSetRequest is the request body for POST /config
 type ConfigSetRequest struct {
 	Key   string `json:"key" binding:"required"`
@@ -543,7 +709,7 @@ func (s *Server) handleGetConfig(c *gin.Context) {
 			"torrent_enabled":       cfg.Torrent.Enabled,
 			"bilibili_cookie":       cfg.Bilibili.Cookie,
 			"telegram_tdata_path":   cfg.Telegram.TDataPath,
-			},
+		},
 		Message: "config retrieved",
 	})
 }
@@ -1180,7 +1346,7 @@ func (s *Server) setConfigValue(cfg *config.Config, key, value string) error {
 }
 
 // downloadWebDAV handles WebDAV URL downloads using multi-stream for better performance
-func (s *Server) downloadWebDAV(ctx context.Context, rawURL, filename string, progressFn func(downloaded, total int64)) error {
+func (s *Server) downloadWebDAV(ctx context.Context, rawURL, filename, outputDir string, progressFn func(downloaded, total int64)) error {
 	var client *webdav.Client
 	var filePath string
 	var err error
@@ -1218,7 +1384,7 @@ func (s *Server) downloadWebDAV(ctx context.Context, rawURL, filename string, pr
 			outputFile = webdav.ExtractFilename(filePath)
 		}
 		// Sanitize the filename to remove invalid path characters
-		outputPath := filepath.Join(s.outputDir, extractor.SanitizeFilename(outputFile))
+		outputPath := filepath.Join(outputDir, extractor.SanitizeFilename(outputFile))
 
 		// Update job filename
 		s.updateJobFilename(rawURL, outputPath)
@@ -1255,7 +1421,7 @@ func (s *Server) downloadWebDAV(ctx context.Context, rawURL, filename string, pr
 		outputFile = webdav.ExtractFilename(filePath)
 	}
 	// Sanitize the filename to remove invalid path characters
-	outputPath := filepath.Join(s.outputDir, extractor.SanitizeFilename(outputFile))
+	outputPath := filepath.Join(outputDir, extractor.SanitizeFilename(outputFile))
 
 	s.updateJobFilename(rawURL, outputPath)
 
@@ -1271,11 +1437,36 @@ func downloadWebDAVMultiStream(ctx context.Context, url, authHeader, outputPath
 	return downloader.RunMultiStreamDownloadWithAuthCallback(ctx, url, authHeader, outputPath, totalSize, msConfig, progressFn)
 }
 
+// jobOutputDir returns the directory a job saves into: the output directory,
+// or a folder inside it, which is created if needed
+func (s *Server) jobOutputDir(folder string) (string, error) {
+	if folder == "" {
+		return s.outputDir, nil
+	}
+	// Rooting the folder first keeps ".." from leaving the output directory
+	dir := filepath.Join(s.outputDir, filepath.Clean("/"+folder))
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create directory: %w", err)
+	}
+	return dir, nil
+}
+
 // downloadWithExtractor is the download function used by the job queue
-func (s *Server) downloadWithExtractor(ctx context.Context, url, filename string, progressFn func(downloaded, total int64)) error {
+func (s *Server) downloadWithExtractor(ctx context.Context, url string, opts JobOptions, progressFn func(downloaded, total int64)) error {
+	filename := opts.Filename
+	outputDir, err := s.jobOutputDir(opts.Folder)
+	if err != nil {
+		return err
+	}
+
 	// Handle WebDAV URLs specially
 	if webdav.IsWebDAVURL(url) {
-		return s.downloadWebDAV(ctx, url, filename, progressFn)
+		return s.downloadWebDAV(ctx, url, filename, outputDir, progressFn)
+	}
+
+	// Telegram media is downloaded by the logged-in client, not from a URL
+	if telegram.MatchURL(url) {
+		return s.downloadTelegram(ctx, url, filename, outputDir, progressFn)
 	}
 
 	// Find matching extractor
@@ -1287,20 +1478,17 @@ func (s *Server) downloadWithExtractor(ctx context.Context, url, filename string
 				ext = extractor.NewBrowserExtractor(site, false)
 			}
 		}
+		// Podcast RSS/Atom feeds served from any URL (self-hosted, private-token)
 		if ext == nil {
-			ext = extractor.NewGenericBrowserExtractor(false)
+			ext = extractor.DetectFeed(ctx, url, extractor.Options{})
 		}
-	}
-
-	// Configure Twitter extractor with auth if available
-	if twitterExt, ok := ext.(*extractor.TwitterExtractor); ok {
-		if s.cfg.Twitter.AuthToken != "" {
-			twitterExt.SetAuth(s.cfg.Twitter.AuthToken)
+		if ext == nil {
+			ext = extractor.WithFallback(extractor.NewStaticPageExtractor(), extractor.NewGenericBrowserExtractor(false))
 		}
 	}
 
-	// Extract media info
-	media, err := ext.Extract(url)
+	// Extract media info (aborted when the job is cancelled)
+	media, err := extractor.ExtractContext(ctx, ext, url, extractor.ConfigOptions(s.cfg, ext))
 	if err != nil {
 		return fmt.Errorf("extraction failed: %w", err)
 	}
@@ -1312,20 +1500,14 @@ func (s *Server) downloadWithExtractor(ctx context.Context, url, filename string
 
 	switch m := media.(type) {
 	case *extractor.YouTubeDirectDownload:
-		return extractor.DownloadWithYtdlpProgress(ctx, m.URL, s.outputDir, progressFn)
+		return extractor.DownloadWithYtdlpProgress(ctx, m.URL, outputDir, progressFn)
 
 	case *extractor.VideoMedia:
 		if len(m.Formats) == 0 {
 			return fmt.Errorf("no video formats available")
 		}
-		format := selectBestFormat(m.Formats)
-		downloadURL = format.URL
-		headers = format.Headers
-
-		ext := format.Ext
-		if ext == "m3u8" {
-			ext = "ts"
-		}
+		format := selectFormat(m.Formats, opts.Quality)
+		ext := videoExt(format)
 
 		if filename != "" {
 			// Sanitize the provided filename to remove invalid path characters
@@ -1334,22 +1516,36 @@ func (s *Server) downloadWithExtractor(ctx context.Context, url, filename string
 			if !strings.HasSuffix(strings.ToLower(sanitized), "."+ext) {
 				sanitized = fmt.Sprintf("%s.%s", sanitized, ext)
 			}
-			outputPath = filepath.Join(s.outputDir, sanitized)
+			outputPath = filepath.Join(outputDir, sanitized)
 		} else {
 			title := extractor.SanitizeFilename(m.Title)
 			if title != "" {
-				outputPath = filepath.Join(s.outputDir, fmt.Sprintf("%s.%s", title, ext))
+				outputPath = filepath.Join(outputDir, fmt.Sprintf("%s.%s", title, ext))
 			} else {
-				outputPath = filepath.Join(s.outputDir, fmt.Sprintf("%s.%s", m.ID, ext))
+				outputPath = filepath.Join(outputDir, fmt.Sprintf("%s.%s", m.ID, ext))
 			}
 		}
 
 		s.updateJobFilename(url, outputPath)
 
-		// Handle separate audio stream (e.g., Bilibili DASH)
-		if format.AudioURL != "" {
-			return s.downloadVideoWithAudio(ctx, format, outputPath, progressFn)
+		savedPath, err := s.downloadVideoFormat(ctx, m, format, outputPath, progressFn)
+		if err != nil {
+			return err
+		}
+		if savedPath != outputPath {
+			s.updateJobFilename(url, savedPath)
 		}
+		return nil
+
+	case *extractor.MultiVideoMedia:
+		if len(m.Videos) == 0 && len(m.Images) == 0 {
+			return fmt.Errorf("no videos available")
+		}
+		filenames, err := s.downloadMultiVideo(ctx, m, opts.Quality, outputDir, progressFn)
+		if len(filenames) > 0 {
+			s.updateJobFilename(url, strings.Join(filenames, ", "))
+		}
+		return err
 
 	case *extractor.AudioMedia:
 		downloadURL = m.URL
@@ -1361,49 +1557,181 @@ func (s *Server) downloadWithExtractor(ctx context.Context, url, filename string
 			if !strings.HasSuffix(strings.ToLower(sanitized), "."+m.Ext) {
 				sanitized = fmt.Sprintf("%s.%s", sanitized, m.Ext)
 			}
-			outputPath = filepath.Join(s.outputDir, sanitized)
+			outputPath = filepath.Join(outputDir, sanitized)
 		} else {
 			title := extractor.SanitizeFilename(m.Title)
 			if title != "" {
-				outputPath = filepath.Join(s.outputDir, fmt.Sprintf("%s.%s", title, m.Ext))
+				outputPath = filepath.Join(outputDir, fmt.Sprintf("%s.%s", title, m.Ext))
 			} else {
-				outputPath = filepath.Join(s.outputDir, fmt.Sprintf("%s.%s", m.ID, m.Ext))
+				outputPath = filepath.Join(outputDir, fmt.Sprintf("%s.%s", m.ID, m.Ext))
 			}
 		}
 
+		outputPath = hlsAudioPath(m, outputPath)
+
 		s.updateJobFilename(url, outputPath)
 
-	case *extractor.ImageMedia:
-		if len(m.Images) == 0 {
-			return fmt.Errorf("no images available")
+	case *extractor.AlbumMedia:
+		if len(m.Tracks) == 0 {
+			return fmt.Errorf("no tracks available")
 		}
 
-		title := extractor.SanitizeFilename(m.Title)
-		var filenames []string
+		dir := extractor.SanitizeFilename(m.Title)
+		if m.Uploader != "" {
+			dir = extractor.SanitizeFilename(m.Uploader + " - " + m.Title)
+		}
+		if dir == "" {
+			dir = m.ID
+		}
+		dir = filepath.Join(outputDir, dir)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create directory: %w", err)
+		}
+		s.updateJobFilename(url, dir)
 
-		for i, img := range m.Images {
-			var imgPath string
-			if len(m.Images) == 1 {
-				if title != "" {
-					imgPath = filepath.Join(s.outputDir, fmt.Sprintf("%s.%s", title, img.Ext))
-				} else {
-					imgPath = filepath.Join(s.outputDir, fmt.Sprintf("%s.%s", m.ID, img.Ext))
+		if m.CoverURL != "" {
+			coverPath := filepath.Join(dir, "cover."+extractor.CoverExt(m.CoverURL))
+			if _, err := os.Stat(coverPath); os.IsNotExist(err) {
+				if err := downloadFile(ctx, m.CoverURL, coverPath, nil, nil); err != nil {
+					log.Printf("Warning: failed to download cover: %v", err)
 				}
-			} else {
-				if title != "" {
-					imgPath = filepath.Join(s.outputDir, fmt.Sprintf("%s_%d.%s", title, i+1, img.Ext))
-				} else {
-					imgPath = filepath.Join(s.outputDir, fmt.Sprintf("%s_%d.%s", m.ID, i+1, img.Ext))
+			}
+		}
+
+		// Tracks in the folder's archive were downloaded before, by the server or the CLI
+		archive := downloader.LoadArchive(dir)
+		var pending []*extractor.AudioMedia
+		for _, track := range m.Tracks {
+			if !archive[track.ID] {
+				pending = append(pending, track)
+			}
+		}
+
+		// Tracks count as progress, since their sizes aren't known up front
+		for i, track := range pending {
+			trackPath := hlsAudioPath(track, filepath.Join(dir, track.TrackFilename()))
+			if err := downloadStreamFile(ctx, track.URL, trackPath, nil, nil); err != nil {
+				return fmt.Errorf("failed to download track %q: %w", track.Title, err)
+			}
+			downloader.AppendArchive(dir, track.ID)
+			if progressFn != nil {
+				progressFn(int64(i+1), int64(len(pending)))
+			}
+		}
+		return nil
+
+	case *extractor.PodcastMedia:
+		if len(m.Episodes) == 0 {
+			return fmt.Errorf("no episodes available")
+		}
+
+		dir := extractor.SanitizeFilename(m.Title)
+		if dir == "" {
+			dir = m.ID
+		}
+		dir = filepath.Join(outputDir, dir)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create directory: %w", err)
+		}
+		s.updateJobFilename(url, dir)
+
+		if m.CoverURL != "" {
+			coverPath := filepath.Join(dir, "cover."+extractor.CoverExt(m.CoverURL))
+			if _, err := os.Stat(coverPath); os.IsNotExist(err) {
+				if err := downloadFile(ctx, m.CoverURL, coverPath, nil, nil); err != nil {
+					log.Printf("Warning: failed to download cover: %v", err)
 				}
 			}
+		}
 
-			filenames = append(filenames, imgPath)
+		// Episodes in the folder's archive were downloaded before, by the server or the CLI
+		archive := downloader.LoadArchive(dir)
+		var pending []*extractor.AudioMedia
+		for _, ep := range m.Episodes {
+			if archive[ep.ID] {
+				continue
+			}
+			if _, err := os.Stat(ep.EpisodePath(dir)); err == nil {
+				// Downloaded before the archive existed, record it
+				downloader.AppendArchive(dir, ep.ID)
+				continue
+			}
+			pending = append(pending, ep)
+		}
 
-			if err := downloadFile(ctx, img.URL, imgPath, nil, nil); err != nil {
-				return fmt.Errorf("failed to download image %d: %w", i+1, err)
+		// Episodes are saved like the CLI saves them; they count as progress,
+		// since their sizes aren't known up front
+		for i, ep := range pending {
+			warnings, err := extractor.SaveEpisode(ep, dir, func(url, path string) error {
+				return downloadStreamFile(ctx, url, path, nil, nil)
+			})
+			if err != nil {
+				return fmt.Errorf("failed to download episode %q: %w", ep.Title, err)
 			}
+			for _, w := range warnings {
+				log.Printf("Warning: episode %q: %v", ep.Title, w)
+			}
+			downloader.AppendArchive(dir, ep.ID)
+			if progressFn != nil {
+				progressFn(int64(i+1), int64(len(pending)))
+			}
+		}
+		return nil
+
+	case *extractor.CollectionMedia:
+		if len(m.Items) == 0 {
+			return fmt.Errorf("no posts available")
 		}
 
+		dir := extractor.SanitizeFilename(m.Title)
+		if dir == "" {
+			dir = m.ID
+		}
+		dir = filepath.Join(outputDir, dir)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create directory: %w", err)
+		}
+		s.updateJobFilename(url, dir)
+
+		// Posts in the folder's archive were downloaded before, by the server or the CLI
+		archive := downloader.LoadArchive(dir)
+		var pending []extractor.Media
+		for _, item := range m.Items {
+			if !archive[item.GetID()] {
+				pending = append(pending, item)
+			}
+		}
+
+		// Posts count as progress, and one failing doesn't stop the rest
+		var failed int
+		for i, item := range pending {
+			if err := s.downloadCollectionItem(ctx, item, opts.Quality, dir); err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				log.Printf("Failed to download post %s: %v", item.GetID(), err)
+				failed++
+				continue
+			}
+			downloader.AppendArchive(dir, item.GetID())
+			if progressFn != nil {
+				progressFn(int64(i+1), int64(len(pending)))
+			}
+		}
+		if failed > 0 {
+			return fmt.Errorf("%d of %d post(s) failed", failed, len(pending))
+		}
+		return nil
+
+	case *extractor.ImageMedia:
+		if len(m.Images) == 0 {
+			return fmt.Errorf("no images available")
+		}
+
+		filenames, err := downloadImages(ctx, m, outputDir)
+		if err != nil {
+			return err
+		}
 		s.updateJobFilename(url, strings.Join(filenames, ", "))
 		return nil
 
@@ -1411,9 +1739,14 @@ func (s *Server) downloadWithExtractor(ctx context.Context, url, filename string
 		return fmt.Errorf("unsupported media type")
 	}
 
+	return s.downloadToPath(ctx, url, downloadURL, outputPath, headers, progressFn)
+}
+
+// downloadToPath downloads a file or HLS stream, updating the job's filename if
+// the stream was remuxed into another container
+func (s *Server) downloadToPath(ctx context.Context, url, downloadURL, outputPath string, headers map[string]string, progressFn func(downloaded, total int64)) error {
 	// Check if this is an HLS stream
-	if strings.HasSuffix(strings.ToLower(downloadURL), ".m3u8") ||
-		strings.Contains(strings.ToLower(downloadURL), ".m3u8?") {
+	if downloader.IsHLSURL(downloadURL) {
 		finalPath, err := downloader.DownloadHLSWithProgress(ctx, downloadURL, outputPath, headers, progressFn)
 		if err != nil {
 			return err
@@ -1428,17 +1761,155 @@ func (s *Server) downloadWithExtractor(ctx context.Context, url, filename string
 }
 
 func (s *Server) updateJobFilename(url, filename string) {
-	jobs := s.jobQueue.GetAllJobs()
-	for _, job := range jobs {
-		if job.URL == url {
-			s.jobQueue.mu.Lock()
-			if j, ok := s.jobQueue.jobs[job.ID]; ok {
-				j.Filename = filename
+	s.jobQueue.setJobFilename(url, filename)
+}
+
+// videoExt returns the extension a video format is saved with: m3u8 is saved as
+// .ts, or .mp4 when a separate audio track is merged in
+func videoExt(format *extractor.VideoFormat) string {
+	if format.Ext != "m3u8" {
+		return format.Ext
+	}
+	if format.AudioURL != "" {
+		return "mp4"
+	}
+	return "ts"
+}
+
+// downloadVideoFormat downloads one format of a video, merging in its separate audio
+// stream if it has one, and saves the subtitles next to it. It returns where the video
+// was saved, which differs from outputPath when an HLS stream was remuxed.
+func (s *Server) downloadVideoFormat(ctx context.Context, m *extractor.VideoMedia, format *extractor.VideoFormat, outputPath string, progressFn func(downloaded, total int64)) (string, error) {
+	var err error
+	switch {
+	case format.AudioURL != "":
+		// Handle separate audio stream (e.g., Bilibili DASH, Vimeo HLS)
+		err = s.downloadVideoWithAudio(ctx, format, outputPath, progressFn)
+	case downloader.IsHLSURL(format.URL):
+		outputPath, err = downloader.DownloadHLSWithProgress(ctx, format.URL, outputPath, format.Headers, progressFn)
+	default:
+		err = downloadFile(ctx, format.URL, outputPath, format.Headers, progressFn)
+	}
+	if err != nil {
+		return "", err
+	}
+	downloadSubtitles(ctx, m, outputPath)
+	return outputPath, nil
+}
+
+// downloadMultiVideo downloads every video of a post as "{title}_{n}.{ext}", like the
+// CLI, then the images mixed in with them. Videos count as progress, since their sizes
+// aren't known up front. It returns the saved paths, including those saved before a failure.
+func (s *Server) downloadMultiVideo(ctx context.Context, m *extractor.MultiVideoMedia, quality, dir string, progressFn func(downloaded, total int64)) ([]string, error) {
+	var filenames []string
+	for i, video := range m.Videos {
+		if len(video.Formats) == 0 {
+			return filenames, fmt.Errorf("no formats available for video %d", i+1)
+		}
+		format := selectFormat(video.Formats, quality)
+
+		name := extractor.SanitizeFilename(video.Title)
+		if name == "" {
+			name = video.ID
+		}
+		if len(m.Videos) > 1 {
+			name = fmt.Sprintf("%s_%d", name, i+1)
+		}
+
+		savedPath, err := s.downloadVideoFormat(ctx, video, format, filepath.Join(dir, name+"."+videoExt(format)), nil)
+		if err != nil {
+			return filenames, fmt.Errorf("failed to download video %d: %w", i+1, err)
+		}
+		filenames = append(filenames, savedPath)
+		if progressFn != nil {
+			progressFn(int64(i+1), int64(len(m.Videos)))
+		}
+	}
+
+	// Images mixed in with the videos (e.g., Instagram carousels)
+	if len(m.Images) > 0 {
+		images, err := downloadImages(ctx, &extractor.ImageMedia{
+			ID:       m.ID,
+			Title:    m.Title,
+			Uploader: m.Uploader,
+			Images:   m.Images,
+		}, dir)
+		filenames = append(filenames, images...)
+		if err != nil {
+			return filenames, err
+		}
+	}
+	return filenames, nil
+}
+
+// downloadCollectionItem downloads one post of a collection into dir. Posts often
+// share titles, so the ID is added to keep their files apart, like the CLI does.
+func (s *Server) downloadCollectionItem(ctx context.Context, item extractor.Media, quality, dir string) error {
+	name := func(title, id string) string {
+		if title == "" {
+			return id
+		}
+		return fmt.Sprintf("%s [%s]", title, id)
+	}
+
+	switch m := item.(type) {
+	case *extractor.VideoMedia:
+		if len(m.Formats) == 0 {
+			return fmt.Errorf("no video formats available")
+		}
+		format := selectFormat(m.Formats, quality)
+		outputPath := filepath.Join(dir, extractor.SanitizeFilename(name(m.Title, m.ID))+"."+videoExt(format))
+		_, err := s.downloadVideoFormat(ctx, m, format, outputPath, nil)
+		return err
+	case *extractor.ImageMedia:
+		m.Title = name(m.Title, m.ID)
+		_, err := downloadImages(ctx, m, dir)
+		return err
+	case *extractor.MultiVideoMedia:
+		m.Title = name(m.Title, m.ID)
+		for _, video := range m.Videos {
+			video.Title = name(video.Title, m.ID)
+		}
+		_, err := s.downloadMultiVideo(ctx, m, quality, dir, nil)
+		return err
+	default:
+		return fmt.Errorf("unsupported media type")
+	}
+}
+
+// downloadImages saves the images of m as "{title}.{ext}", or "{title}_{n}.{ext}" when
+// there are several, with the motion part of Live Photos next to their stills. It
+// returns the saved paths, including those saved before a failure.
+func downloadImages(ctx context.Context, m *extractor.ImageMedia, dir string) ([]string, error) {
+	title := extractor.SanitizeFilename(m.Title)
+	if title == "" {
+		title = m.ID
+	}
+
+	var filenames []string
+	for i, img := range m.Images {
+		imgPath := filepath.Join(dir, fmt.Sprintf("%s.%s", title, img.Ext))
+		if len(m.Images) > 1 {
+			imgPath = filepath.Join(dir, fmt.Sprintf("%s_%d.%s", title, i+1, img.Ext))
+		}
+
+		if err := downloadFile(ctx, img.URL, imgPath, img.Headers, nil); err != nil {
+			return filenames, fmt.Errorf("failed to download image %d: %w", i+1, err)
+		}
+		// The extension is a guess for some sites; the file's first bytes tell
+		imgPath = downloader.RenameByMagicBytes(imgPath)
+		filenames = append(filenames, imgPath)
+
+		// Live Photos: save the motion part next to the still
+		if img.LiveVideoURL != "" {
+			videoPath := img.LiveVideoPath(imgPath)
+			if err := downloadFile(ctx, img.LiveVideoURL, videoPath, img.Headers, nil); err != nil {
+				return filenames, fmt.Errorf("failed to download live photo video %d: %w", i+1, err)
 			}
-			s.jobQueue.mu.Unlock()
-			break
+			filenames = append(filenames, videoPath)
 		}
 	}
+	return filenames, nil
 }
 
 // downloadVideoWithAudio downloads video and audio in parallel then merges them with ffmpeg
@@ -1481,7 +1952,7 @@ func (s *Server) downloadVideoWithAudio(ctx context.Context, format *extractor.V
 	// Download video stream
 	go func() {
 		defer wg.Done()
-		videoErr = downloadFile(ctx, format.URL, videoFile, format.Headers, func(downloaded, total int64) {
+		videoErr = downloadStreamFile(ctx, format.URL, videoFile, format.Headers, func(downloaded, total int64) {
 			mu.Lock()
 			videoDownloaded = downloaded
 			videoTotal = total
@@ -1493,7 +1964,7 @@ func (s *Server) downloadVideoWithAudio(ctx context.Context, format *extractor.V
 	// Download audio stream
 	go func() {
 		defer wg.Done()
-		audioErr = downloadFile(ctx, format.AudioURL, audioFile, format.Headers, func(downloaded, total int64) {
+		audioErr = downloadStreamFile(ctx, format.AudioURL, audioFile, format.Headers, func(downloaded, total int64) {
 			mu.Lock()
 			audioDownloaded = downloaded
 			audioTotal = total
@@ -1527,6 +1998,33 @@ func (s *Server) downloadVideoWithAudio(ctx context.Context, format *extractor.V
 	return nil
 }
 
+// downloadStreamFile downloads one stream of a split format, through the HLS downloader for playlists
+func downloadStreamFile(ctx context.Context, url, outputPath string, headers map[string]string, progressFn func(downloaded, total int64)) error {
+	if downloader.IsHLSURL(url) {
+		_, err := downloader.DownloadHLSWithProgress(ctx, url, outputPath, headers, progressFn)
+		return err
+	}
+	return downloadFile(ctx, url, outputPath, headers, progressFn)
+}
+
+// hlsAudioPath returns where to save a track: HLS AAC audio (e.g., Twitter Spaces)
+// is downloaded as raw AAC and remuxed to m4a, other streams keep their extension
+func hlsAudioPath(m *extractor.AudioMedia, outputPath string) string {
+	if downloader.IsHLSURL(m.URL) && (m.Ext == "m4a" || m.Ext == "aac") {
+		return strings.TrimSuffix(outputPath, filepath.Ext(outputPath)) + ".aac"
+	}
+	return outputPath
+}
+
+// downloadSubtitles saves a video's text tracks next to it; failures are only logged
+func downloadSubtitles(ctx context.Context, m *extractor.VideoMedia, videoPath string) {
+	for _, sub := range m.Subtitles {
+		if err := downloadFile(ctx, sub.URL, sub.Path(videoPath), nil, nil); err != nil {
+			log.Printf("Warning: failed to download %s subtitles: %v", sub.Lang, err)
+		}
+	}
+}
+
 // downloadAndStream extracts and streams the file directly to the response
 func (s *Server) downloadAndStream(c *gin.Context, url, filename string) {
 	ext := extractor.Match(url)
@@ -1538,17 +2036,12 @@ func (s *Server) downloadAndStream(c *gin.Context, url, filename string) {
 			}
 		}
 		if ext == nil {
-			ext = extractor.NewGenericBrowserExtractor(false)
+			ext = extractor.WithFallback(extractor.NewStaticPageExtractor(), extractor.NewGenericBrowserExtractor(false))
 		}
 	}
 
-	if twitterExt, ok := ext.(*extractor.TwitterExtractor); ok {
-		if s.cfg.Twitter.AuthToken != "" {
-			twitterExt.SetAuth(s.cfg.Twitter.AuthToken)
-		}
-	}
-
-	media, err := ext.Extract(url)
+	// Extraction stops if the client disconnects
+	media, err := extractor.ExtractContext(c.Request.Context(), ext, url, extractor.ConfigOptions(s.cfg, ext))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, Response{
 			Code:    500,
@@ -1623,6 +2116,7 @@ func (s *Server) downloadAndStream(c *gin.Context, url, filename string) {
 		}
 		img := m.Images[0]
 		downloadURL = img.URL
+		headers = img.Headers
 		if filename != "" {
 			outputFilename = filename
 		} else {
@@ -1646,6 +2140,24 @@ func (s *Server) downloadAndStream(c *gin.Context, url, filename string) {
 	streamFile(c.Writer, downloadURL, outputFilename, headers)
 }
 
+// selectFormat returns the format matching a preferred quality (e.g., "1080p",
+// or "1080" for "1080p60"), or the best format when there is none
+func selectFormat(formats []extractor.VideoFormat, quality string) *extractor.VideoFormat {
+	if quality != "" {
+		for i := range formats {
+			if formats[i].Quality == quality {
+				return &formats[i]
+			}
+		}
+		for i := range formats {
+			if strings.Contains(formats[i].Quality, quality) {
+				return &formats[i]
+			}
+		}
+	}
+	return selectBestFormat(formats)
+}
+
 func selectBestFormat(formats []extractor.VideoFormat) *extractor.VideoFormat {
 	if len(formats) == 0 {
 		return nil
@@ -1673,7 +2185,16 @@ func selectBestFormat(formats []extractor.VideoFormat) *extractor.VideoFormat {
 	return best
 }
 
+// downloadFile downloads url into outputPath. The data is written to
+// outputPath.part first; a .part left by an interrupted download is continued
+// when the server supports range requests.
 func downloadFile(ctx context.Context, url, outputPath string, headers map[string]string, progressFn func(downloaded, total int64)) error {
+	partPath := outputPath + ".part"
+	var offset int64
+	if info, err := os.Stat(partPath); err == nil {
+		offset = info.Size()
+	}
+
 	client := &http.Client{
 		Timeout: 0,
 		Transport: &http.Transport{
@@ -1693,6 +2214,9 @@ func downloadFile(ctx context.Context, url, outputPath string, headers map[strin
 	} else {
 		req.Header.Set("User-Agent", downloader.DefaultUserAgent)
 	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
 
 	resp, err := client.Do(req)
 	if err != nil {
@@ -1700,20 +2224,37 @@ func downloadFile(ctx context.Context, url, outputPath string, headers map[strin
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
+	flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	switch {
+	case offset > 0 && resp.StatusCode == http.StatusPartialContent:
+		flags = os.O_WRONLY | os.O_APPEND
+	case offset > 0 && resp.StatusCode == http.StatusRequestedRangeNotSatisfiable:
+		// The partial file doesn't fit the remote file; start over
+		resp.Body.Close()
+		if err := os.Remove(partPath); err != nil {
+			return fmt.Errorf("failed to remove partial file: %w", err)
+		}
+		return downloadFile(ctx, url, outputPath, headers, progressFn)
+	case resp.StatusCode != http.StatusOK:
 		return fmt.Errorf("download failed with status %d", resp.StatusCode)
+	default:
+		// The server ignored the range; download everything again
+		offset = 0
 	}
 
 	total := resp.ContentLength
+	if total > 0 {
+		total += offset
+	}
 
-	file, err := os.Create(outputPath)
+	file, err := os.OpenFile(partPath, flags, 0644)
 	if err != nil {
 		return fmt.Errorf("failed to create output file: %w", err)
 	}
 	defer file.Close()
 
 	buf := make([]byte, 32*1024)
-	var downloaded int64
+	downloaded := offset
 
 	for {
 		select {
@@ -1741,7 +2282,10 @@ func downloadFile(ctx context.Context, url, outputPath string, headers map[strin
 		}
 	}
 
-	return nil
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	return os.Rename(partPath, outputPath)
 }
 
 func streamFile(w http.ResponseWriter, url, filename string, headers map[string]string) {
@@ -0,0 +1,264 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/guiyumin/vget/internal/core/subscription"
+)
+
+const (
+	// subscriptionTick is how often the scheduler looks for due subscriptions
+	subscriptionTick = time.Minute
+	// subscriptionCheckTimeout bounds listing one source
+	subscriptionCheckTimeout = 2 * time.Minute
+)
+
+// SubscriptionRequest is the request body for POST /subscriptions
+type SubscriptionRequest struct {
+	URL             string              `json:"url" binding:"required"`
+	IntervalMinutes int                 `json:"interval_minutes,omitempty"`
+	Filter          subscription.Filter `json:"filter"`
+	Quality         string              `json:"quality,omitempty"`
+	Folder          string              `json:"folder,omitempty"`
+	Backfill        bool                `json:"backfill,omitempty"`
+}
+
+// runSubscriptions checks due subscriptions every subscriptionTick until stop is closed
+func (s *Server) runSubscriptions(stop <-chan struct{}) {
+	ticker := time.NewTicker(subscriptionTick)
+	defer ticker.Stop()
+
+	for {
+		subs, err := s.subs.List()
+		if err != nil {
+			log.Printf("Warning: failed to list subscriptions: %v", err)
+		}
+		for _, sub := range subs {
+			if sub.Due(time.Now()) {
+				s.checkSubscription(sub)
+			}
+		}
+
+		select {
+		case <-ticker.C:
+		case <-stop:
+			return
+		}
+	}
+}
+
+// checkSubscription checks a subscription and queues its new items. Items are
+// marked seen once their jobs complete; until then they are not queued again,
+// and those whose jobs fail or are cancelled are tried again on the next check.
+func (s *Server) checkSubscription(sub *subscription.Subscription) (int, error) {
+	// The subscription may have been changed or removed meanwhile
+	fresh, err := s.subs.Get(sub.ID)
+	if err != nil {
+		return 0, err
+	}
+	*sub = *fresh
+
+	// List the source without the lock, so a slow site doesn't hold up other checks
+	ctx, cancel := context.WithTimeout(context.Background(), subscriptionCheckTimeout)
+	defer cancel()
+
+	sources := &subscription.Sources{Config: s.cfg, Telegram: s.tgClient}
+	title, listed, listErr := sources.List(ctx, sub)
+
+	s.subMu.Lock()
+	defer s.subMu.Unlock()
+
+	// Another check may have recorded its results while this one listed
+	if fresh, err = s.subs.Get(sub.ID); err != nil {
+		return 0, err
+	}
+	*sub = *fresh
+
+	items, err := subscription.Record(s.subs, sub, title, listed, listErr)
+	if err != nil {
+		log.Printf("Subscription %s: check failed: %v", sub.Name(), err)
+		return 0, err
+	}
+
+	pending := s.jobQueue.SubscriptionItemsPending(sub.ID)
+	queued := 0
+	for _, item := range items {
+		if pending[item.ID] {
+			continue
+		}
+		opts := JobOptions{
+			Folder:           sub.Dir(),
+			Quality:          sub.Quality,
+			Subscription:     sub.ID,
+			SubscriptionItem: item.ID,
+		}
+		if item.Audio != nil {
+			opts.Filename = item.Audio.TrackFilename()
+		}
+		if _, err := s.jobQueue.AddJobWithOptions(item.URL, opts); err != nil {
+			log.Printf("Subscription %s: failed to queue %s: %v", sub.Name(), item.URL, err)
+			break
+		}
+		queued++
+	}
+	if queued > 0 {
+		log.Printf("Subscription %s: queued %d new item(s)", sub.Name(), queued)
+	}
+	return queued, nil
+}
+
+// markSubscriptionItemSeen marks the subscription item of a completed job seen
+func (s *Server) markSubscriptionItemSeen(opts JobOptions) {
+	if opts.Subscription == "" {
+		return
+	}
+	if err := s.subs.MarkSeen(opts.Subscription, opts.SubscriptionItem); err != nil {
+		log.Printf("Subscription %s: %v", opts.Subscription, err)
+	}
+}
+
+// subscriptionsAvailable responds with an error if the subscription store
+// could not be opened
+func (s *Server) subscriptionsAvailable(c *gin.Context) bool {
+	if s.subs == nil {
+		c.JSON(http.StatusServiceUnavailable, Response{
+			Code:    503,
+			Data:    nil,
+			Message: "subscription database not available",
+		})
+		return false
+	}
+	return true
+}
+
+func (s *Server) handleGetSubscriptions(c *gin.Context) {
+	if !s.subscriptionsAvailable(c) {
+		return
+	}
+
+	subs, err := s.subs.List()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, Response{
+			Code:    500,
+			Data:    nil,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Code:    200,
+		Data:    gin.H{"subscriptions": subs},
+		Message: "subscriptions retrieved",
+	})
+}
+
+func (s *Server) handleAddSubscription(c *gin.Context) {
+	if !s.subscriptionsAvailable(c) {
+		return
+	}
+
+	var req SubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, Response{
+			Code:    400,
+			Data:    nil,
+			Message: "invalid request body: url is required",
+		})
+		return
+	}
+
+	sub := &subscription.Subscription{
+		URL:             req.URL,
+		IntervalMinutes: req.IntervalMinutes,
+		Filter:          req.Filter,
+		Quality:         req.Quality,
+		Folder:          req.Folder,
+		Backfill:        req.Backfill,
+	}
+	if err := s.subs.Add(sub); err != nil {
+		c.JSON(http.StatusBadRequest, Response{
+			Code:    400,
+			Data:    nil,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	// The first check runs right away rather than on the next tick
+	go s.checkSubscription(&subscription.Subscription{ID: sub.ID})
+
+	c.JSON(http.StatusOK, Response{
+		Code:    200,
+		Data:    sub,
+		Message: "subscription added",
+	})
+}
+
+func (s *Server) handleDeleteSubscription(c *gin.Context) {
+	if !s.subscriptionsAvailable(c) {
+		return
+	}
+
+	id := c.Param("id")
+	if err := s.subs.Remove(id); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, subscription.ErrNotFound) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, Response{
+			Code:    status,
+			Data:    nil,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Code:    200,
+		Data:    gin.H{"id": id},
+		Message: "subscription removed",
+	})
+}
+
+// handleCheckSubscription checks a subscription now, whether or not it is due
+func (s *Server) handleCheckSubscription(c *gin.Context) {
+	if !s.subscriptionsAvailable(c) {
+		return
+	}
+
+	sub, err := s.subs.Get(c.Param("id"))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, subscription.ErrNotFound) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, Response{
+			Code:    status,
+			Data:    nil,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	queued, err := s.checkSubscription(sub)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, Response{
+			Code:    502,
+			Data:    gin.H{"subscription": sub},
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Code:    200,
+		Data:    gin.H{"subscription": sub, "queued": queued},
+		Message: "subscription checked",
+	})
+}
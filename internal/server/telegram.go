@@ -0,0 +1,249 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/guiyumin/vget/internal/core/extractor"
+	"github.com/guiyumin/vget/internal/core/extractor/telegram"
+)
+
+type TelegramPhoneRequest struct {
+	Phone string `json:"phone" binding:"required"` // international format, e.g. +15551234567
+}
+
+type TelegramCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type TelegramPasswordRequest struct {
+	Password string `json:"password" binding:"required"`
+}
+
+// startTelegramLogin replaces any login in progress and waits for it to
+// reach its first step, so the response already has the QR code or code hint
+func (s *Server) startTelegramLogin(start func() *telegram.LoginSession) telegram.LoginStatus {
+	s.tgLoginMu.Lock()
+	if s.tgLogin != nil {
+		s.tgLogin.Cancel()
+	}
+	login := start()
+	s.tgLogin = login
+	s.tgLoginMu.Unlock()
+	go s.resetTelegramOnLogin(login)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+	st, _ := login.Next(ctx, 0)
+	return st
+}
+
+// resetTelegramOnLogin reconnects download jobs with the new session once
+// the login succeeds
+func (s *Server) resetTelegramOnLogin(login *telegram.LoginSession) {
+	st := login.Status()
+	for st.State != telegram.LoginDone && st.State != telegram.LoginFailed {
+		var err error
+		if st, err = login.Next(context.Background(), st.Step); err != nil {
+			return
+		}
+	}
+	if st.State == telegram.LoginDone {
+		s.tgClient.Reset()
+	}
+}
+
+func (s *Server) currentTelegramLogin() *telegram.LoginSession {
+	s.tgLoginMu.Lock()
+	defer s.tgLoginMu.Unlock()
+	return s.tgLogin
+}
+
+func telegramLoginResponse(c *gin.Context, st telegram.LoginStatus) {
+	if st.State == telegram.LoginDone {
+		log.Printf("[Telegram] Login successful for user: %s", st.User)
+	}
+	c.JSON(http.StatusOK, Response{
+		Code:    200,
+		Data:    st,
+		Message: string(st.State),
+	})
+}
+
+// handleTelegramPhoneLogin sends a login code to a phone number
+func (s *Server) handleTelegramPhoneLogin(c *gin.Context) {
+	var req TelegramPhoneRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, Response{
+			Code:    400,
+			Data:    nil,
+			Message: "phone is required",
+		})
+		return
+	}
+
+	telegramLoginResponse(c, s.startTelegramLogin(func() *telegram.LoginSession {
+		return telegram.StartPhoneLogin(req.Phone)
+	}))
+}
+
+// handleTelegramQRLogin starts a QR code login; qr_url is renewed every
+// 30 seconds or so, so the UI should redraw it from the login status
+func (s *Server) handleTelegramQRLogin(c *gin.Context) {
+	telegramLoginResponse(c, s.startTelegramLogin(telegram.StartQRLogin))
+}
+
+// handleTelegramLoginStatus returns the state of the login in progress
+func (s *Server) handleTelegramLoginStatus(c *gin.Context) {
+	login := s.currentTelegramLogin()
+	if login == nil {
+		c.JSON(http.StatusNotFound, Response{
+			Code:    404,
+			Data:    nil,
+			Message: "no Telegram login in progress",
+		})
+		return
+	}
+	telegramLoginResponse(c, login.Status())
+}
+
+// handleTelegramLoginCode submits the code sent by Telegram
+func (s *Server) handleTelegramLoginCode(c *gin.Context) {
+	var req TelegramCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, Response{
+			Code:    400,
+			Data:    nil,
+			Message: "code is required",
+		})
+		return
+	}
+	s.submitTelegramLogin(c, func(login *telegram.LoginSession) error {
+		return login.SubmitCode(req.Code)
+	})
+}
+
+// handleTelegramLoginPassword submits the two-step verification password
+func (s *Server) handleTelegramLoginPassword(c *gin.Context) {
+	var req TelegramPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, Response{
+			Code:    400,
+			Data:    nil,
+			Message: "password is required",
+		})
+		return
+	}
+	s.submitTelegramLogin(c, func(login *telegram.LoginSession) error {
+		return login.SubmitPassword(req.Password)
+	})
+}
+
+// submitTelegramLogin hands an answer to the login in progress and waits
+// for Telegram to check it
+func (s *Server) submitTelegramLogin(c *gin.Context, submit func(*telegram.LoginSession) error) {
+	login := s.currentTelegramLogin()
+	if login == nil {
+		c.JSON(http.StatusNotFound, Response{
+			Code:    404,
+			Data:    nil,
+			Message: "no Telegram login in progress",
+		})
+		return
+	}
+	step := login.Status().Step
+	if err := submit(login); err != nil {
+		c.JSON(http.StatusConflict, Response{
+			Code:    409,
+			Data:    login.Status(),
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 20*time.Second)
+	defer cancel()
+	st, _ := login.Next(ctx, step)
+	telegramLoginResponse(c, st)
+}
+
+// handleTelegramStatus returns whether the server has a working Telegram session
+func (s *Server) handleTelegramStatus(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 15*time.Second)
+	defer cancel()
+
+	user, err := telegram.CurrentUser(ctx)
+	if err != nil {
+		c.JSON(http.StatusOK, Response{
+			Code: 200,
+			Data: gin.H{
+				"logged_in": false,
+				"error":     err.Error(),
+			},
+			Message: "not logged in",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Code: 200,
+		Data: gin.H{
+			"logged_in": true,
+			"username":  user,
+		},
+		Message: "logged in",
+	})
+}
+
+// handleTelegramLogout removes the server's Telegram session
+func (s *Server) handleTelegramLogout(c *gin.Context) {
+	if err := os.Remove(telegram.SessionFile()); err != nil && !os.IsNotExist(err) {
+		c.JSON(http.StatusInternalServerError, Response{
+			Code:    500,
+			Data:    nil,
+			Message: "failed to remove session: " + err.Error(),
+		})
+		return
+	}
+	s.tgClient.Reset()
+	c.JSON(http.StatusOK, Response{
+		Code:    200,
+		Data:    gin.H{"logged_in": false},
+		Message: "logged out",
+	})
+}
+
+// downloadTelegram downloads a t.me message link through the shared client.
+// A FLOOD_WAIT from Telegram pauses the job until the wait is over.
+func (s *Server) downloadTelegram(ctx context.Context, url, filename, outputDir string, progressFn func(downloaded, total int64)) error {
+	opts := telegram.DownloadOptions{
+		URL:        url,
+		OutputDir:  outputDir,
+		ProgressFn: progressFn,
+	}
+	if filename != "" {
+		opts.OutputPath = filepath.Join(outputDir, extractor.SanitizeFilename(filename))
+	}
+
+	result, err := s.tgClient.Download(ctx, opts)
+	if err != nil {
+		if wait, ok := telegram.FloodWait(err); ok {
+			return &RetryAfterError{Wait: wait, Err: fmt.Errorf("telegram rate limit: %w", err)}
+		}
+		return err
+	}
+
+	if len(result.Files) > 1 {
+		s.updateJobFilename(url, strings.Join(result.Files, ", "))
+	} else {
+		s.updateJobFilename(url, result.Filename)
+	}
+	return nil
+}
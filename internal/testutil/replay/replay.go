@@ -0,0 +1,243 @@
+// Package replay records HTTP exchanges to golden files and replays them offline
+// from an httptest server, so extractor tests run without network access and
+// site changes show up as fixture diffs.
+//
+// Tests get a client with Client(t, dir). By default it replays dir/fixtures.json;
+// with VGET_RECORD=1 it talks to the real sites and rewrites the fixtures:
+//
+//	VGET_RECORD=1 go test ./internal/core/extractor/ -run TestTwitter
+package replay
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// FixtureFile is the golden file inside a fixture directory
+const FixtureFile = "fixtures.json"
+
+// RecordEnv enables recording when set to 1
+const RecordEnv = "VGET_RECORD"
+
+// originalURLHeader carries the request's real URL to the replay server
+const originalURLHeader = "X-Replay-Url"
+
+// localHost is the host used for requests made directly to the replay server
+const localHost = "local"
+
+// Fixture is one recorded HTTP exchange
+type Fixture struct {
+	Method string            `json:"method"`
+	URL    string            `json:"url"`
+	Status int               `json:"status"`
+	Header map[string]string `json:"header,omitempty"`
+	Body   string            `json:"body"`
+}
+
+// matches reports whether a fixture answers a request. Query parameters in the
+// fixture must all be present in the request with equal values; extra request
+// parameters (timestamps, signatures) are ignored.
+func (f *Fixture) matches(method string, u *url.URL) bool {
+	if !strings.EqualFold(f.Method, method) {
+		return false
+	}
+	fu, err := url.Parse(f.URL)
+	if err != nil || fu.Host != u.Host || fu.Path != u.Path {
+		return false
+	}
+	query := u.Query()
+	for key, values := range fu.Query() {
+		if query.Get(key) != values[0] {
+			return false
+		}
+	}
+	return true
+}
+
+// Load reads the fixtures in dir
+func Load(dir string) ([]Fixture, error) {
+	data, err := os.ReadFile(filepath.Join(dir, FixtureFile))
+	if err != nil {
+		return nil, err
+	}
+	var fixtures []Fixture
+	if err := json.Unmarshal(data, &fixtures); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", filepath.Join(dir, FixtureFile), err)
+	}
+	return fixtures, nil
+}
+
+// Recorder is an http.RoundTripper that forwards requests to Next and saves
+// every exchange to Dir. Query parameters listed in Ignore (timestamps,
+// signatures) are dropped from the recorded URL so replays still match.
+type Recorder struct {
+	Dir    string
+	Next   http.RoundTripper
+	Ignore []string
+
+	mu       sync.Mutex
+	fixtures []Fixture
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	next := r.Next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	resp, err := next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	recorded := *req.URL
+	query := recorded.Query()
+	for _, key := range r.Ignore {
+		query.Del(key)
+	}
+	recorded.RawQuery = query.Encode()
+
+	header := make(map[string]string)
+	for _, key := range []string{"Content-Type", "Location"} {
+		if v := resp.Header.Get(key); v != "" {
+			header[key] = v
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.fixtures = append(r.fixtures, Fixture{
+		Method: req.Method,
+		URL:    recorded.String(),
+		Status: resp.StatusCode,
+		Header: header,
+		Body:   string(body),
+	})
+	return resp, r.save()
+}
+
+func (r *Recorder) save() error {
+	if err := os.MkdirAll(r.Dir, 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(r.fixtures, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(r.Dir, FixtureFile), append(data, '\n'), 0644)
+}
+
+// Server replays fixtures over HTTP
+type Server struct {
+	*httptest.Server
+
+	t        testing.TB
+	fixtures []Fixture
+}
+
+// NewServer starts a replay server for the fixtures in dir; it is closed when the test ends.
+// Requests made directly to the server (not through Client) are looked up as http://local/<path>.
+func NewServer(t testing.TB, dir string) *Server {
+	t.Helper()
+
+	fixtures, err := Load(dir)
+	if err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+
+	s := &Server{t: t, fixtures: fixtures}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	rawURL := r.Header.Get(originalURLHeader)
+	if rawURL == "" {
+		rawURL = "http://" + localHost + r.URL.RequestURI()
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	for i := range s.fixtures {
+		f := &s.fixtures[i]
+		if !f.matches(r.Method, u) {
+			continue
+		}
+		for key, value := range f.Header {
+			w.Header().Set(key, value)
+		}
+		status := f.Status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		w.WriteHeader(status)
+		io.WriteString(w, f.Body)
+		return
+	}
+
+	s.t.Errorf("replay: no fixture for %s %s", r.Method, rawURL)
+	http.Error(w, "no fixture", http.StatusNotImplemented)
+}
+
+// Client returns a client that sends every request to the replay server,
+// whatever its original host. Redirects are returned rather than followed so
+// recorded 3xx responses replay as-is.
+func (s *Server) Client() *http.Client {
+	target, _ := url.Parse(s.URL)
+	return &http.Client{
+		Transport: &rewriteTransport{target: target},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// rewriteTransport redirects requests to the replay server, keeping the original URL in a header
+type rewriteTransport struct {
+	target *url.URL
+}
+
+func (rt *rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	clone := req.Clone(req.Context())
+	clone.Header.Set(originalURLHeader, req.URL.String())
+	clone.URL.Scheme = rt.target.Scheme
+	clone.URL.Host = rt.target.Host
+	clone.Host = rt.target.Host
+	return http.DefaultTransport.RoundTrip(clone)
+}
+
+// Recording reports whether fixtures are being recorded (VGET_RECORD=1)
+func Recording() bool {
+	return os.Getenv(RecordEnv) == "1"
+}
+
+// Client returns a client replaying the fixtures in dir, or, when recording,
+// a client that hits the live sites and rewrites dir's fixtures.
+func Client(t testing.TB, dir string, ignore ...string) *http.Client {
+	t.Helper()
+	if Recording() {
+		return &http.Client{Transport: &Recorder{Dir: dir, Ignore: ignore}}
+	}
+	return NewServer(t, dir).Client()
+}
@@ -17,7 +17,10 @@ export function DownloadJobCard({
   onClear,
   t,
 }: DownloadJobCardProps) {
-  const canCancel = job.status === "queued" || job.status === "downloading";
+  const canCancel =
+    job.status === "queued" ||
+    job.status === "downloading" ||
+    job.status === "paused";
   const canClear =
     job.status === "completed" ||
     job.status === "failed" ||
@@ -68,6 +71,7 @@ export function DownloadJobCard({
     completed: t.completed,
     failed: t.failed,
     cancelled: t.cancelled,
+    paused: t.paused,
   };
 
   const statusStyles: Record<JobStatus, string> = {
@@ -78,6 +82,8 @@ export function DownloadJobCard({
       "bg-green-100 dark:bg-green-900/50 text-green-600 dark:text-green-500",
     failed: "bg-red-100 dark:bg-red-900/50 text-red-600 dark:text-red-500",
     cancelled: "bg-zinc-300 dark:bg-zinc-700 text-zinc-500 dark:text-zinc-600",
+    paused:
+      "bg-amber-100 dark:bg-amber-900/50 text-amber-600 dark:text-amber-400",
   };
 
   return (
@@ -149,6 +155,11 @@ export function DownloadJobCard({
           {job.error}
         </div>
       )}
+      {job.status === "paused" && job.error && (
+        <div className="mt-2 p-2 bg-amber-100 dark:bg-amber-900/30 rounded text-xs text-amber-700 dark:text-amber-300">
+          {job.error}
+        </div>
+      )}
     </div>
   );
 }
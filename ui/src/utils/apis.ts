@@ -5,7 +5,8 @@ export type JobStatus =
   | "downloading"
   | "completed"
   | "failed"
-  | "cancelled";
+  | "cancelled"
+  | "paused";
 
 export interface Job {
   id: string;
@@ -16,6 +17,7 @@ export interface Job {
   total: number;
   filename?: string;
   error?: string;
+  priority?: number;
 }
 
 export interface ApiResponse<T> {
@@ -70,6 +72,7 @@ export interface TorrentAddResult {
 
 export interface JobsData {
   jobs: Job[];
+  paused?: boolean;
 }
 
 export interface I18nData {
@@ -25,6 +25,7 @@ export interface UITranslations {
   completed: string;
   failed: string;
   cancelled: string;
+  paused: string;
   settings: string;
   language: string;
   format: string;
@@ -133,6 +134,7 @@ export const defaultTranslations: UITranslations = {
   completed: "completed",
   failed: "failed",
   cancelled: "cancelled",
+  paused: "paused",
   settings: "Settings",
   language: "Language",
   format: "Format",